@@ -0,0 +1,21 @@
+package api
+
+import (
+	"fmt"
+
+	"github.com/laincloud/lainlet/ipaddr"
+)
+
+// AppName return the app of the container by the `ip` argument, the ip of the client by default.
+// It is the /appname api, served by both the http server and the grpc server.
+func AppName(r *Request) (map[string]string, error) {
+	ip := ipaddr.Host(r.GetString("ip", r.RemoteAddr))
+	if !r.Allowed("/appname", ip) {
+		return nil, fmt.Errorf("authorize failed, no permission")
+	}
+	appname, err := r.Auth.AppName(ip)
+	if err != nil {
+		return nil, err
+	}
+	return map[string]string{"appname": appname}, nil
+}
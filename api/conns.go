@@ -0,0 +1,142 @@
+package api
+
+import (
+	"errors"
+	"net"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/laincloud/lainlet/ipaddr"
+	"github.com/laincloud/lainlet/watcher"
+	"golang.org/x/net/context"
+)
+
+// ErrDisconnected is returned by Watch when the watch connection was disconnected by Registry.Disconnect
+var ErrDisconnected = errors.New("disconnected by administrator")
+
+// Conn is the snapshot of a watch connection
+type Conn struct {
+	ID         uint64    `json:"id"`
+	RemoteAddr string    `json:"remote_addr"`
+	AppName    string    `json:"appname"`
+	API        string    `json:"api"`
+	Protocol   string    `json:"protocol"`
+	Key        string    `json:"key"`
+	Since      time.Time `json:"since"`
+	Sent       uint64    `json:"sent"`
+	Dropped    uint64    `json:"dropped"`
+	LastSend   time.Time `json:"last_send,omitempty"`
+}
+
+// conn is a active watch connection
+type conn struct {
+	sync.Mutex
+	info   Conn
+	stats  watcher.ReceiverStats
+	cancel context.CancelFunc
+	kicked bool
+}
+
+// Registry is the active watch connections of a lainlet, shared by its http server and grpc server
+type Registry struct {
+	lock   sync.Mutex
+	conns  map[uint64]*conn
+	lastID uint64
+}
+
+// NewRegistry create a empty registry
+func NewRegistry() *Registry {
+	return &Registry{conns: make(map[uint64]*conn)}
+}
+
+// add register a watch connection, the returned context is canceled when the connection was disconnected.
+func (reg *Registry) add(ctx context.Context, api API, r *Request, key string) (*conn, context.Context) {
+	appname, _ := r.AppName() // empty if the client is not a container
+	ctx, cancel := context.WithCancel(ctx)
+	c := &conn{
+		info: Conn{
+			RemoteAddr: r.RemoteAddr,
+			AppName:    appname,
+			API:        api.URI(),
+			Protocol:   r.Protocol,
+			Key:        key,
+			Since:      time.Now(),
+		},
+		cancel: cancel,
+	}
+	reg.lock.Lock()
+	reg.lastID++
+	c.info.ID = reg.lastID
+	reg.conns[c.info.ID] = c
+	reg.lock.Unlock()
+	return c, watcher.WithReceiverStats(ctx, &c.stats)
+}
+
+func (reg *Registry) remove(c *conn) {
+	reg.lock.Lock()
+	delete(reg.conns, c.info.ID)
+	reg.lock.Unlock()
+	c.cancel()
+}
+
+// sent record a event was sent to the client
+func (c *conn) sent() {
+	c.Lock()
+	c.info.Sent++
+	c.info.LastSend = time.Now()
+	c.Unlock()
+}
+
+func (c *conn) isKicked() bool {
+	c.Lock()
+	defer c.Unlock()
+	return c.kicked
+}
+
+// Conns return all the active watch connections of both http and grpc, sorted by id.
+func (reg *Registry) Conns() []Conn {
+	reg.lock.Lock()
+	ret := make([]Conn, 0, len(reg.conns))
+	for _, c := range reg.conns {
+		c.Lock()
+		info := c.info
+		c.Unlock()
+		info.Dropped = c.stats.Dropped()
+		ret = append(ret, info)
+	}
+	reg.lock.Unlock()
+	sort.Slice(ret, func(i, j int) bool { return ret[i].ID < ret[j].ID })
+	return ret
+}
+
+// Disconnect close the watch connections whose id is the given id, or whose client is the given client,
+// client can be a ip, which matches all the connections from it, or a "ip:port" address. It return the number of the connections closed.
+func (reg *Registry) Disconnect(id uint64, client string) int {
+	reg.lock.Lock()
+	defer reg.lock.Unlock()
+	n := 0
+	for _, c := range reg.conns {
+		if !(id != 0 && c.info.ID == id) && !(client != "" && matchClient(c.info.RemoteAddr, client)) {
+			continue
+		}
+		c.Lock()
+		c.kicked = true
+		c.Unlock()
+		c.cancel()
+		n++
+	}
+	return n
+}
+
+// matchClient check if the connection is from the client, a ip or a "ip:port" address
+func matchClient(remoteAddr, client string) bool {
+	if remoteAddr == client {
+		return true
+	}
+	if _, port, err := net.SplitHostPort(client); err == nil {
+		_, remotePort, _ := net.SplitHostPort(remoteAddr)
+		return port == remotePort && ipaddr.Equal(remoteAddr, client)
+	}
+	return ipaddr.Equal(remoteAddr, client)
+}
@@ -0,0 +1,95 @@
+package api
+
+import (
+	"testing"
+	"time"
+
+	"github.com/laincloud/lainlet/fieldmask"
+	"github.com/laincloud/lainlet/store"
+	"github.com/laincloud/lainlet/watcher"
+	"golang.org/x/net/context"
+)
+
+func TestRegistry(t *testing.T) {
+	a := &fakeAuth{apps: map[string]string{"172.20.1.2": "hello"}}
+	reg := NewRegistry()
+	addrs := []string{"172.20.1.2:1234", "172.20.1.2:5678", "[fe80::1]:1234", "10.0.0.1:1234"}
+	var ctxs []context.Context
+	for _, addr := range addrs {
+		_, ctx := reg.add(context.Background(), &fakeAPI{uri: "/configwatcher"}, newTestRequest(a, addr), "vips")
+		ctxs = append(ctxs, ctx)
+	}
+
+	conns := reg.Conns()
+	if len(conns) != len(addrs) {
+		t.Fatalf("got %d connections, want %d", len(conns), len(addrs))
+	}
+	for i, c := range conns {
+		if c.ID != uint64(i+1) || c.RemoteAddr != addrs[i] || c.API != "/configwatcher" || c.Key != "vips" || c.Protocol != ProtocolHTTP {
+			t.Errorf("got the connection %+v, want the id %d from %s", c, i+1, addrs[i])
+		}
+	}
+	if conns[0].AppName != "hello" || conns[3].AppName != "" {
+		t.Errorf("got the apps %q and %q, want hello and none", conns[0].AppName, conns[3].AppName)
+	}
+
+	cases := []struct {
+		id     uint64
+		client string
+		closed []int // the indexes in addrs
+	}{
+		{0, "172.20.1.3", nil},
+		{4, "", []int{3}},
+		{0, "172.20.1.2:5678", []int{1}},
+		{0, "[fe80:0::1]:5678", nil},
+		{0, "fe80:0::1", []int{2}},
+		{0, "172.20.1.2", []int{0, 1}}, // the closed one is matched again
+	}
+	for _, c := range cases {
+		if n := reg.Disconnect(c.id, c.client); n != len(c.closed) {
+			t.Errorf("Disconnect(%d, %q) = %d, want %d", c.id, c.client, n, len(c.closed))
+		}
+		for _, i := range c.closed {
+			if ctxs[i].Err() == nil {
+				t.Errorf("Disconnect(%d, %q) should close the connection from %s", c.id, c.client, addrs[i])
+			}
+		}
+	}
+}
+
+func TestWatchDisconnected(t *testing.T) {
+	a := &fakeAuth{apps: map[string]string{"172.20.1.2": "hello"}}
+	w := &stubWatcher{data: map[string]interface{}{"a": "1"}, ch: make(chan *watcher.Event)}
+	reg := NewRegistry()
+	sent := make(chan store.Action, 1)
+	done := make(chan error, 1)
+	go func() {
+		done <- Watch(context.Background(), reg, w, &mapAPI{}, newTestRequest(a, "172.20.1.2:1234"), "*", fieldmask.Mask(nil),
+			func(id uint64, action store.Action, instance API, content []byte) error {
+				sent <- action
+				return nil
+			})
+	}()
+	select {
+	case <-sent:
+	case <-time.After(5 * time.Second):
+		t.Fatal("no init event received")
+	}
+	conns := reg.Conns()
+	if len(conns) != 1 || conns[0].Sent != 1 || conns[0].API != "/map" {
+		t.Fatalf("got the connections %+v, want the watch of /map", conns)
+	}
+
+	reg.Disconnect(conns[0].ID, "")
+	select {
+	case err := <-done:
+		if err != ErrDisconnected {
+			t.Errorf("Watch = %v, want ErrDisconnected", err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("the watch is not stopped by Disconnect")
+	}
+	if conns := reg.Conns(); len(conns) != 0 {
+		t.Errorf("got the connections %+v after the watch stopped", conns)
+	}
+}
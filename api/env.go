@@ -0,0 +1,39 @@
+package api
+
+import (
+	"sync/atomic"
+
+	"github.com/laincloud/lainlet/conf"
+	"github.com/laincloud/lainlet/unixsock"
+)
+
+// Env is the state shared by the http and the grpc servers besides the watchers and the authorizer,
+// which is the configuration, the limits of the clients, the peers of the unix sockets and the watch connections.
+type Env struct {
+	cfg atomic.Value // *conf.Config, set by NewEnv and Reload
+
+	Limiter *Limiter
+	Peers   *unixsock.Peers // the unix socket listeners of the servers record their peers in it
+	Conns   *Registry
+}
+
+// NewEnv create the env of the servers by the configuration
+func NewEnv(cfg *conf.Config) *Env {
+	env := &Env{
+		Peers: unixsock.NewPeers(),
+		Conns: NewRegistry(),
+	}
+	env.cfg.Store(cfg)
+	env.Limiter = NewLimiter(func() conf.LimitsConfig { return env.Config().Limits })
+	return env
+}
+
+// Config return the effective configuration, it must not be modified.
+func (env *Env) Config() *conf.Config {
+	return env.cfg.Load().(*conf.Config)
+}
+
+// Reload apply the reloaded configuration, like the limits and the trusted proxies, the watches and the peers are kept.
+func (env *Env) Reload(cfg *conf.Config) {
+	env.cfg.Store(cfg)
+}
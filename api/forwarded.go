@@ -0,0 +1,40 @@
+package api
+
+import (
+	"net"
+	"net/http"
+	"strings"
+
+	"github.com/laincloud/lainlet/conf"
+	"github.com/laincloud/lainlet/ipaddr"
+	"github.com/mijia/sweb/log"
+)
+
+// ForwardedAddr return the address of the client forwarded by the trusted proxies, empty if r did not come from a trusted proxy.
+// X-Forwarded-For is read from right to left, the first address not trusted is the client, X-Real-IP is used if there is no X-Forwarded-For.
+// Only the headers from the trusted proxies are used, since any client can set them.
+func ForwardedAddr(r *http.Request, proxy conf.WebProxyConfig) string {
+	if len(proxy.Trusted) == 0 || !proxy.Trusts(r.RemoteAddr) {
+		return ""
+	}
+	if values := r.Header["X-Forwarded-For"]; len(values) > 0 {
+		hops := strings.Split(strings.Join(values, ","), ",")
+		for i := len(hops) - 1; i >= 0; i-- {
+			hop := ipaddr.Host(strings.TrimSpace(hops[i]))
+			if net.ParseIP(hop) == nil {
+				log.Warnf("Invalid address %q in X-Forwarded-For from %s", hops[i], r.RemoteAddr)
+				return ""
+			}
+			if i == 0 || !proxy.Trusts(hop) {
+				return hop
+			}
+		}
+	}
+	if value := r.Header.Get("X-Real-IP"); value != "" {
+		if ip := ipaddr.Host(strings.TrimSpace(value)); net.ParseIP(ip) != nil {
+			return ip
+		}
+		log.Warnf("Invalid address %q in X-Real-IP from %s", value, r.RemoteAddr)
+	}
+	return ""
+}
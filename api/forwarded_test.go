@@ -0,0 +1,55 @@
+package api
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/laincloud/lainlet/conf"
+)
+
+func TestForwardedAddr(t *testing.T) {
+	proxy := conf.WebProxyConfig{Trusted: []string{"10.0.0.0/8", "fd00:1::/64", "192.168.77.9"}}
+	cases := []struct {
+		name       string
+		remoteAddr string
+		xff        []string
+		realIP     string
+		want       string
+	}{
+		{"direct client", "172.20.0.2:1234", nil, "", ""},
+		{"untrusted peer with xff", "172.20.0.2:1234", []string{"1.2.3.4"}, "", ""},
+		{"untrusted peer with x-real-ip", "172.20.0.2:1234", nil, "1.2.3.4", ""},
+		{"trusted peer without headers", "10.0.0.1:1234", nil, "", ""},
+		{"one hop", "10.0.0.1:1234", []string{"1.2.3.4"}, "", "1.2.3.4"},
+		{"single trusted ip", "192.168.77.9:1234", []string{"1.2.3.4"}, "", "1.2.3.4"},
+		{"ipv6 proxy", "[fd00:1::5]:1234", []string{"1.2.3.4"}, "", "1.2.3.4"},
+		{"trusted hops skipped", "10.0.0.1:1234", []string{"1.2.3.4, 10.0.0.3, 10.0.0.2"}, "", "1.2.3.4"},
+		{"spoofed hops ignored", "10.0.0.1:1234", []string{"5.6.7.8, 1.2.3.4, 10.0.0.2"}, "", "1.2.3.4"},
+		{"multiple headers", "10.0.0.1:1234", []string{"5.6.7.8", "1.2.3.4, 10.0.0.2"}, "", "1.2.3.4"},
+		{"all hops trusted", "10.0.0.1:1234", []string{"10.0.0.3,10.0.0.2"}, "", "10.0.0.3"},
+		{"ipv6 client with port", "10.0.0.1:1234", []string{"[fd00:20::2]:5678"}, "", "fd00:20::2"},
+		{"invalid hop after the client", "10.0.0.1:1234", []string{"bad, 1.2.3.4"}, "", "1.2.3.4"},
+		{"invalid hop", "10.0.0.1:1234", []string{"1.2.3.4, bad"}, "", ""},
+		{"xff preferred to x-real-ip", "10.0.0.1:1234", []string{"1.2.3.4"}, "5.6.7.8", "1.2.3.4"},
+		{"x-real-ip", "10.0.0.1:1234", nil, " 5.6.7.8 ", "5.6.7.8"},
+		{"x-real-ip ipv6", "10.0.0.1:1234", nil, "fd00:20::2", "fd00:20::2"},
+		{"invalid x-real-ip", "10.0.0.1:1234", nil, "unknown", ""},
+	}
+	for _, c := range cases {
+		r := &http.Request{RemoteAddr: c.remoteAddr, Header: make(http.Header)}
+		for _, v := range c.xff {
+			r.Header.Add("X-Forwarded-For", v)
+		}
+		if c.realIP != "" {
+			r.Header.Set("X-Real-IP", c.realIP)
+		}
+		if got := ForwardedAddr(r, proxy); got != c.want {
+			t.Errorf("%s: ForwardedAddr = %q, want %q", c.name, got, c.want)
+		}
+	}
+
+	r := &http.Request{RemoteAddr: "10.0.0.1:1234", Header: http.Header{"X-Forwarded-For": {"1.2.3.4"}}}
+	if got := ForwardedAddr(r, conf.WebProxyConfig{}); got != "" {
+		t.Errorf("the headers should be ignored without trusted proxies, got %q", got)
+	}
+}
@@ -0,0 +1,189 @@
+package api
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/laincloud/lainlet/conf"
+	"github.com/laincloud/lainlet/ipaddr"
+)
+
+// LimitError is returned by Admit when a request exceeds the limits of the client
+type LimitError struct {
+	API    string
+	Client string
+	Reason string // "rate" or "watches"
+}
+
+func (e *LimitError) Error() string {
+	if e.Reason == "watches" {
+		return fmt.Sprintf("too many watches of %s to %s", e.Client, e.API)
+	}
+	return fmt.Sprintf("too many requests of %s to %s", e.Client, e.API)
+}
+
+// IsLimitError check if the error is returned by Admit because of the limits
+func IsLimitError(err error) bool {
+	_, ok := err.(*LimitError)
+	return ok
+}
+
+// Usage is the usage of the limits of a client to a api
+type Usage struct {
+	API        string  `json:"api"`
+	Client     string  `json:"client"`
+	Tokens     float64 `json:"tokens"` // the requests can be made now
+	Rate       float64 `json:"rate"`
+	Burst      float64 `json:"burst"`
+	Watches    int     `json:"watches"`
+	MaxWatches int     `json:"max_watches"`
+}
+
+type limitKey struct {
+	api    string
+	client string
+}
+
+// clientLimit is the token bucket and the watches of a client to a api
+type clientLimit struct {
+	tokens     float64
+	last       time.Time
+	rate       float64
+	burst      float64
+	watches    int
+	maxWatches int
+}
+
+// refill add the tokens since the last time, the limit may be changed by reloading the configuration
+func (cl *clientLimit) refill(now time.Time, limit conf.LimitConfig) {
+	burst := math.Max(float64(limit.Burst), 1)
+	if cl.rate > 0 {
+		cl.tokens = math.Min(burst, cl.tokens+now.Sub(cl.last).Seconds()*cl.rate)
+	} else {
+		cl.tokens = burst
+	}
+	cl.last, cl.rate, cl.burst, cl.maxWatches = now, limit.Rate, burst, limit.Watches
+}
+
+// idle check if the client limit can be forgotten without changing the behavior
+func (cl *clientLimit) idle(now time.Time) bool {
+	return cl.watches == 0 && (cl.rate <= 0 || cl.tokens+now.Sub(cl.last).Seconds()*cl.rate >= cl.burst)
+}
+
+// Limiter keep the token buckets and the watches of the clients, the limits are read from the configuration by the limits function,
+// so they can be changed by reloading the configuration.
+type Limiter struct {
+	limits func() conf.LimitsConfig
+
+	lock      sync.Mutex
+	clients   map[limitKey]*clientLimit
+	lastSweep time.Time
+}
+
+// NewLimiter create a limiter, limits return the limits in the current configuration
+func NewLimiter(limits func() conf.LimitsConfig) *Limiter {
+	return &Limiter{
+		limits:  limits,
+		clients: make(map[limitKey]*clientLimit),
+	}
+}
+
+// Admit check the limits of the client of r to api, watch represents if it is a watch request.
+// It return a LimitError if the client exceed the limits, otherwise the returned function must be called when the request finished.
+func (l *Limiter) Admit(api API, r *Request, watch bool) (func(), error) {
+	uri := api.URI()
+	limit := l.limits().For(uri)
+	if limit.Rate <= 0 && (!watch || limit.Watches <= 0) {
+		return func() {}, nil
+	}
+	key := limitKey{api: uri, client: clientOf(r)}
+	now := time.Now()
+
+	l.lock.Lock()
+	defer l.lock.Unlock()
+	if now.Sub(l.lastSweep) > time.Minute {
+		for k, cl := range l.clients {
+			if cl.idle(now) {
+				delete(l.clients, k)
+			}
+		}
+		l.lastSweep = now
+	}
+	cl, ok := l.clients[key]
+	if !ok {
+		cl = &clientLimit{last: now}
+		l.clients[key] = cl
+	}
+	cl.refill(now, limit)
+	if limit.Rate > 0 {
+		if cl.tokens < 1 {
+			limitedCounter.Inc(uri, r.Protocol, "rate")
+			return nil, &LimitError{API: uri, Client: key.client, Reason: "rate"}
+		}
+		cl.tokens--
+	}
+	if !watch {
+		return func() {}, nil
+	}
+	if limit.Watches > 0 && cl.watches >= limit.Watches {
+		limitedCounter.Inc(uri, r.Protocol, "watches")
+		return nil, &LimitError{API: uri, Client: key.client, Reason: "watches"}
+	}
+	cl.watches++
+	var once sync.Once
+	return func() {
+		once.Do(func() {
+			l.lock.Lock()
+			cl.watches--
+			l.lock.Unlock()
+		})
+	}, nil
+}
+
+// Usages return the usages of the limits of all the clients, sorted by api and client.
+func (l *Limiter) Usages() []Usage {
+	now := time.Now()
+	l.lock.Lock()
+	ret := make([]Usage, 0, len(l.clients))
+	for k, cl := range l.clients {
+		tokens := cl.burst
+		if cl.rate > 0 {
+			tokens = math.Min(cl.burst, cl.tokens+now.Sub(cl.last).Seconds()*cl.rate)
+		}
+		ret = append(ret, Usage{
+			API:        k.api,
+			Client:     k.client,
+			Tokens:     tokens,
+			Rate:       cl.rate,
+			Burst:      cl.burst,
+			Watches:    cl.watches,
+			MaxWatches: cl.maxWatches,
+		})
+	}
+	l.lock.Unlock()
+	sort.Slice(ret, func(i, j int) bool {
+		if ret[i].API != ret[j].API {
+			return ret[i].API < ret[j].API
+		}
+		return ret[i].Client < ret[j].Client
+	})
+	return ret
+}
+
+// clientOf return the identity of the client the limits applied to, which is the app name if it can be resolved, otherwise the ip.
+// The app name is resolved once for each request, see Request.Cached.
+func clientOf(r *Request) string {
+	if r.Peer != nil {
+		if r.Peer.Cred != nil {
+			return fmt.Sprintf("uid:%d", r.Peer.Cred.UID)
+		}
+		return "unix:" + r.Peer.Path
+	}
+	if appname, err := r.AppName(); err == nil && appname != "" {
+		return appname
+	}
+	return ipaddr.Host(r.RemoteAddr)
+}
@@ -0,0 +1,274 @@
+package api
+
+import (
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/laincloud/lainlet/auth"
+	"github.com/laincloud/lainlet/conf"
+	"github.com/laincloud/lainlet/ipaddr"
+	"gopkg.in/yaml.v2"
+)
+
+// fakeAuth identify the clients by the ips of the apps, and count the lookups
+type fakeAuth struct {
+	apps map[string]string // ip -> app
+
+	lock    sync.Mutex
+	lookups int
+}
+
+func (a *fakeAuth) lookup(remoteAddr string) (string, bool) {
+	a.lock.Lock()
+	a.lookups++
+	a.lock.Unlock()
+	app, ok := a.apps[ipaddr.Host(remoteAddr)]
+	return app, ok
+}
+
+func (a *fakeAuth) Lookups() int {
+	a.lock.Lock()
+	defer a.lock.Unlock()
+	return a.lookups
+}
+
+func (a *fakeAuth) IPSubject(remoteAddr string) auth.Subject {
+	if app, ok := a.lookup(remoteAddr); ok {
+		return auth.Subject{App: app, Addr: remoteAddr, Via: "podgroup"}
+	}
+	return auth.Subject{Addr: remoteAddr, Via: "unknown ip"}
+}
+
+func (a *fakeAuth) AppName(remoteIP string) (string, error) {
+	if app, ok := a.lookup(remoteIP); ok {
+		return app, nil
+	}
+	return "", fmt.Errorf("unkown address %s", remoteIP)
+}
+
+func (a *fakeAuth) VerifyToken(token string) (*auth.Claims, error) {
+	return nil, fmt.Errorf("tokens are not accepted")
+}
+
+// Evaluate allow the apps to visit their own keys and "*"
+func (a *fakeAuth) Evaluate(s auth.Subject, api, key string) auth.Decision {
+	return auth.Decision{Allowed: s.App != "" && (key == s.App || key == "*")}
+}
+
+func (a *fakeAuth) Explain(s auth.Subject, api, key string) *auth.Explanation {
+	return &auth.Explanation{}
+}
+
+// fakeAPI is a api serving nothing, only the uri is used
+type fakeAPI struct {
+	uri string
+}
+
+func (f *fakeAPI) Decode([]byte) error                            { return nil }
+func (f *fakeAPI) Encode() ([]byte, error)                        { return []byte("{}"), nil }
+func (f *fakeAPI) URI() string                                    { return f.uri }
+func (f *fakeAPI) WatcherName() string                            { return "fakewatcher" }
+func (f *fakeAPI) Key(r *Request) (string, error)                 { return "*", nil }
+func (f *fakeAPI) Make(map[string]interface{}) (API, bool, error) { return f, true, nil }
+
+// newTestEnv return the env with the configuration for the test, which is the default one changed by content
+func newTestEnv(t *testing.T, content string) *Env {
+	cfg := conf.Default()
+	if err := yaml.UnmarshalStrict([]byte(content), cfg); err != nil {
+		t.Fatal(err)
+	}
+	return NewEnv(cfg)
+}
+
+func newTestRequest(a *fakeAuth, addr string) *Request {
+	return (&Request{RemoteAddr: addr, Protocol: ProtocolHTTP, Auth: a}).Cached()
+}
+
+func TestAdmitRate(t *testing.T) {
+	l := newTestEnv(t, `
+limits:
+  default:
+    rate: 1
+    burst: 2
+  apis:
+    /unlimited:
+      rate: 0
+`).Limiter
+	a := &fakeAuth{apps: map[string]string{"172.20.0.2": "hello", "172.20.0.3": "hello"}}
+	api := &fakeAPI{uri: "/limited"}
+
+	// the burst is shared by the containers of the app
+	for i, addr := range []string{"172.20.0.2:1234", "172.20.0.3:1234"} {
+		if _, err := l.Admit(api, newTestRequest(a, addr), false); err != nil {
+			t.Fatalf("request %d should be admitted in the burst, %s", i, err.Error())
+		}
+	}
+	_, err := l.Admit(api, newTestRequest(a, "172.20.0.2:1234"), false)
+	if le, ok := err.(*LimitError); !ok || le.Reason != "rate" || le.Client != "hello" || le.API != "/limited" {
+		t.Fatalf("the request out of the burst should be limited by rate, got %v", err)
+	}
+
+	// the unknown clients are limited by ip, and each api has its own bucket
+	if _, err := l.Admit(api, newTestRequest(a, "10.0.0.1:1234"), false); err != nil {
+		t.Errorf("the other client should not be limited, %s", err.Error())
+	}
+	if _, err := l.Admit(&fakeAPI{uri: "/other"}, newTestRequest(a, "172.20.0.2:1234"), false); err != nil {
+		t.Errorf("the other api should not be limited, %s", err.Error())
+	}
+	for i := 0; i < 10; i++ {
+		if _, err := l.Admit(&fakeAPI{uri: "/unlimited"}, newTestRequest(a, "172.20.0.2:1234"), false); err != nil {
+			t.Fatalf("the unlimited api should not be limited, %s", err.Error())
+		}
+	}
+
+	// refilled by rate
+	l.lock.Lock()
+	l.clients[limitKey{api: "/limited", client: "hello"}].last = time.Now().Add(-1500 * time.Millisecond)
+	l.lock.Unlock()
+	if _, err := l.Admit(api, newTestRequest(a, "172.20.0.2:1234"), false); err != nil {
+		t.Errorf("the request should be admitted after refilling, %s", err.Error())
+	}
+	for _, u := range l.Usages() {
+		if u.API == "/limited" && u.Client == "hello" && (u.Tokens < 0.4 || u.Tokens > 0.6 || u.Burst != 2 || u.Rate != 1) {
+			t.Errorf("unexpected usage %+v", u)
+		}
+	}
+}
+
+func TestAdmitWatches(t *testing.T) {
+	l := newTestEnv(t, `
+limits:
+  default:
+    watches: 2
+`).Limiter
+	a := &fakeAuth{apps: map[string]string{"172.20.0.2": "hello"}}
+	api := &fakeAPI{uri: "/watched"}
+	r := newTestRequest(a, "172.20.0.2:1234")
+
+	var releases []func()
+	for i := 0; i < 2; i++ {
+		release, err := l.Admit(api, r, true)
+		if err != nil {
+			t.Fatalf("watch %d should be admitted, %s", i, err.Error())
+		}
+		releases = append(releases, release)
+	}
+	if _, err := l.Admit(api, r, true); err == nil || err.(*LimitError).Reason != "watches" {
+		t.Fatalf("the third watch should be limited, got %v", err)
+	}
+	if _, err := l.Admit(api, r, false); err != nil {
+		t.Errorf("the get request should not be limited by the watches, %s", err.Error())
+	}
+
+	// releasing twice only free one watch
+	releases[0]()
+	releases[0]()
+	if _, err := l.Admit(api, r, true); err != nil {
+		t.Fatalf("the watch should be admitted after releasing one, %s", err.Error())
+	}
+	if _, err := l.Admit(api, r, true); err == nil {
+		t.Error("the watch should be limited, a release should not be counted twice")
+	}
+	releases[1]()
+}
+
+func TestAdmitReload(t *testing.T) {
+	env := newTestEnv(t, `
+limits:
+  default:
+    watches: 1
+`)
+	a := &fakeAuth{apps: map[string]string{"172.20.0.2": "hello"}}
+	api := &fakeAPI{uri: "/watched"}
+	r := newTestRequest(a, "172.20.0.2:1234")
+	if _, err := env.Limiter.Admit(api, r, true); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := env.Limiter.Admit(api, r, true); err == nil {
+		t.Fatal("the second watch should be limited")
+	}
+
+	// the limits of the reloaded configuration take effect at once, the watches admitted are still counted
+	cfg := *env.Config()
+	cfg.Limits = conf.LimitsConfig{Default: conf.LimitConfig{Watches: 2}}
+	env.Reload(&cfg)
+	if _, err := env.Limiter.Admit(api, r, true); err != nil {
+		t.Errorf("the second watch should be admitted after reloading, %s", err.Error())
+	}
+	if _, err := env.Limiter.Admit(api, r, true); err == nil {
+		t.Error("the third watch should be limited after reloading")
+	}
+}
+
+func TestAdmitSweep(t *testing.T) {
+	l := newTestEnv(t, `
+limits:
+  default:
+    rate: 100
+    burst: 1
+    watches: 1
+`).Limiter
+	a := &fakeAuth{apps: map[string]string{"172.20.0.2": "hello", "172.20.0.3": "world", "172.20.0.4": "foo"}}
+	api := &fakeAPI{uri: "/swept"}
+
+	release, err := l.Admit(api, newTestRequest(a, "172.20.0.2:1234"), true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := l.Admit(api, newTestRequest(a, "172.20.0.3:1234"), false); err != nil {
+		t.Fatal(err)
+	}
+	l.lock.Lock()
+	for _, cl := range l.clients {
+		cl.last = cl.last.Add(-time.Second) // the buckets are refilled
+	}
+	l.lastSweep = time.Now().Add(-2 * time.Minute)
+	l.lock.Unlock()
+
+	if _, err := l.Admit(api, newTestRequest(a, "172.20.0.4:1234"), false); err != nil {
+		t.Fatal(err)
+	}
+	clients := make(map[string]bool)
+	for _, u := range l.Usages() {
+		clients[u.Client] = true
+	}
+	if !clients["hello"] {
+		t.Error("the client watching should not be swept")
+	}
+	if clients["world"] {
+		t.Error("the idle client should be swept")
+	}
+	if !clients["foo"] {
+		t.Error("the client of the request should be kept")
+	}
+
+	// the watch is still counted after sweeping
+	if _, err := l.Admit(api, newTestRequest(a, "172.20.0.2:1234"), true); err == nil {
+		t.Error("the watch should be limited after sweeping")
+	}
+	release()
+}
+
+func TestRequestCached(t *testing.T) {
+	a := &fakeAuth{apps: map[string]string{"172.20.0.2": "hello"}}
+	r := newTestRequest(a, "172.20.0.2:1234")
+	copied := *r
+	for i := 0; i < 3; i++ {
+		r.Subject()
+		r.AppName()
+		copied.AppName()
+		clientOf(r)
+	}
+	if n := a.Lookups(); n != 2 {
+		t.Errorf("the client should be resolved once for the subject and once for the app name, got %d lookups", n)
+	}
+
+	uncached := &Request{RemoteAddr: "172.20.0.2:1234", Auth: a}
+	uncached.AppName()
+	uncached.AppName()
+	if n := a.Lookups(); n != 4 {
+		t.Errorf("the request not cached should resolve the client every time, got %d lookups", n-2)
+	}
+}
@@ -1,10 +1,12 @@
 package api
 
 import (
-	"net/http"
+	"github.com/golang/protobuf/proto"
+	"github.com/laincloud/lainlet/fieldmask"
 )
 
-// API is a common interface which a api instance must realize
+// API is a common interface which a api instance must realize.
+// A api is defined only once, both the http server and the grpc server serve it by this interface.
 type API interface {
 	// decode the data
 	Decode([]byte) error
@@ -18,10 +20,10 @@ type API interface {
 	// which watcher want to use
 	WatcherName() string
 
-	// the key used to watch,
+	// the key used to watch, the request is also authorized here,
 	// eg. return '/lain/config/vip' for coreinfoWatcher,
 	//     return 'console' as appname for coreifnoWacher and podgroupWatcher
-	Key(r *http.Request) (string, error)
+	Key(r *Request) (string, error)
 
 	// create new api by data
 	// return a new instance of API with real data in it
@@ -33,3 +35,22 @@ type API interface {
 type BanWatcher interface {
 	BanWatch()
 }
+
+// Projector is a interface to support the `fields` argument. you can realize this interface if your api want to return only part of the fields.
+type Projector interface {
+	// return a new instance of API only having the fields in mask, the other fields are zero value,
+	// and the Encode() of the new instance only output the fields in mask.
+	Project(mask fieldmask.Mask) (API, error)
+}
+
+// ProtoAPI is a interface to serve the api by the grpc server.
+type ProtoAPI interface {
+	// the full name of the grpc service, eg. "message.Coreinfo"
+	ServiceName() string
+
+	// return a empty request message of the grpc service, the request is decoded into it
+	ProtoRequest() proto.Message
+
+	// convert the data into the reply message of the grpc service
+	ProtoReply() (proto.Message, error)
+}
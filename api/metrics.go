@@ -0,0 +1,71 @@
+package api
+
+import (
+	"strings"
+	"time"
+
+	"github.com/laincloud/lainlet/metrics"
+)
+
+const (
+	// ProtocolHTTP is the protocol label of the http requests in metrics
+	ProtocolHTTP = "http"
+	// ProtocolGRPC is the protocol label of the grpc requests in metrics
+	ProtocolGRPC = "grpc"
+)
+
+var (
+	requestsCounter = metrics.NewCounter(
+		"lainlet_api_requests_total",
+		"Number of the requests to api, method is get or watch.",
+		"api", "protocol", "method",
+	)
+	requestHistogram = metrics.NewHistogram(
+		"lainlet_api_request_duration_seconds",
+		"Time spent to serve the get requests to api.",
+		nil,
+		"api", "protocol",
+	)
+	activeStreamsGauge = metrics.NewGauge(
+		"lainlet_api_active_streams",
+		"Number of the active watch requests to api, which are sse streams for http.",
+		"api", "protocol",
+	)
+	authDenialsCounter = metrics.NewCounter(
+		"lainlet_auth_denials_total",
+		"Number of the requests to api denied by authorization.",
+		"api", "protocol",
+	)
+	limitedCounter = metrics.NewCounter(
+		"lainlet_api_limited_total",
+		"Number of the requests to api rejected by the limits, reason is rate or watches.",
+		"api", "protocol", "reason",
+	)
+)
+
+// Track record the metrics of a request to api, watch represents if it is a watch request.
+// The returned function must be called with the error of the request when the request finished.
+func Track(api API, protocol string, watch bool) func(err error) {
+	name, method := api.URI(), "get"
+	if watch {
+		method = "watch"
+		activeStreamsGauge.Inc(name, protocol)
+	}
+	requestsCounter.Inc(name, protocol, method)
+	start := time.Now()
+	return func(err error) {
+		if watch {
+			activeStreamsGauge.Dec(name, protocol)
+		} else {
+			requestHistogram.Observe(time.Since(start).Seconds(), name, protocol)
+		}
+		if IsAuthError(err) {
+			authDenialsCounter.Inc(name, protocol)
+		}
+	}
+}
+
+// IsAuthError check if the error is returned by a failed authorization, whose message always starts with "authorize failed".
+func IsAuthError(err error) bool {
+	return err != nil && strings.HasPrefix(err.Error(), "authorize failed")
+}
@@ -0,0 +1,166 @@
+package api
+
+import (
+	"crypto/tls"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+
+	"github.com/laincloud/lainlet/auth"
+	"github.com/laincloud/lainlet/unixsock"
+)
+
+// Request is the protocol independent request of a api, it can be created from a http request or a grpc request.
+type Request struct {
+	// the address of the client, like "ip:port", or the ip forwarded by the trusted proxy
+	RemoteAddr string
+	// the address of the trusted proxy the request come through, empty if it was not forwarded
+	ProxyAddr string
+	// the arguments of the request
+	Args url.Values
+	// the protocol the request come from, ProtocolHTTP or ProtocolGRPC
+	Protocol string
+	// the app verified by the client certificate, empty if the client was not verified
+	Identity string
+	// the peer process of the unix socket, nil if the client did not come from a unix socket
+	Peer *unixsock.Peer
+	// the verified bearer token, nil if there is no token
+	Token *auth.Claims
+	// the error verifying the token, the request is denied by auth if it is not nil
+	TokenErr error
+	// the authorizer identifying the client and deciding whether it can visit the apis
+	Auth auth.Authorizer
+
+	cache *identityCache
+}
+
+// identityCache keep the client resolved by the watchers, shared by the copies of a request
+type identityCache struct {
+	subjectOnce sync.Once
+	subject     auth.Subject
+
+	appOnce sync.Once
+	app     string
+	appErr  error
+}
+
+// Cached make the request resolve the client only once, the later calls of Subject and AppName return the same result.
+// The copies of the request share the result, so the fields identifying the client must not be changed after it.
+func (r *Request) Cached() *Request {
+	r.cache = new(identityCache)
+	return r
+}
+
+// NewRequest create a Request from the http request, a is the authorizer of the server,
+// the unix socket peer and the trusted proxies are found in env.
+func NewRequest(r *http.Request, a auth.Authorizer, env *Env) *Request {
+	r.FormValue("") // make sure the form was parsed
+	ret := &Request{
+		RemoteAddr: r.RemoteAddr,
+		Args:       r.Form,
+		Protocol:   ProtocolHTTP,
+		Identity:   CertIdentity(r.TLS),
+		Peer:       env.Peers.Lookup(r.RemoteAddr),
+		Auth:       a,
+	}
+	if addr := ForwardedAddr(r, env.Config().WebProxy); addr != "" {
+		ret.ProxyAddr, ret.RemoteAddr = r.RemoteAddr, addr
+	}
+	ret.Token, ret.TokenErr = ParseAuthorization(a, r.Header.Get("Authorization"))
+	return ret.Cached()
+}
+
+// ParseAuthorization verify the bearer token in the value of the authorization header, like "Bearer <token>", by the authorizer.
+// It return nil if the value is empty.
+func ParseAuthorization(a auth.Authorizer, value string) (*auth.Claims, error) {
+	if value == "" {
+		return nil, nil
+	}
+	const prefix = "bearer "
+	if len(value) <= len(prefix) || strings.ToLower(value[:len(prefix)]) != prefix {
+		return nil, fmt.Errorf("authorize failed, only bearer token is supported")
+	}
+	return a.VerifyToken(strings.TrimSpace(value[len(prefix):]))
+}
+
+// CertIdentity return the app name in the verified client certificate of the connection, which is the common name of the certificate.
+// It return empty string if there is no verified client certificate.
+func CertIdentity(state *tls.ConnectionState) string {
+	if state == nil || len(state.VerifiedChains) == 0 || len(state.VerifiedChains[0]) == 0 {
+		return ""
+	}
+	return state.VerifiedChains[0][0].Subject.CommonName
+}
+
+// Subject return the client the policy is evaluated for. The client is identified by the bearer token if any,
+// or by the unix socket peer, the verified certificate or the ip in order. The client with a invalid token is anonymous.
+func (r *Request) Subject() auth.Subject {
+	if r.cache == nil {
+		return r.subject()
+	}
+	r.cache.subjectOnce.Do(func() { r.cache.subject = r.subject() })
+	return r.cache.subject
+}
+
+func (r *Request) subject() auth.Subject {
+	switch {
+	case r.TokenErr != nil:
+		return auth.Subject{Addr: r.RemoteAddr, Via: "invalid token"}
+	case r.Token != nil:
+		s := auth.TokenSubject(r.Token)
+		s.Addr = r.RemoteAddr
+		return s
+	case r.Peer != nil:
+		s := auth.Subject{Cred: r.Peer.Cred, Addr: r.RemoteAddr, Via: "unix socket"}
+		if r.Peer.Trusted {
+			s.Roles = []string{auth.RoleSuper}
+		}
+		return s
+	case r.Identity != "":
+		return auth.Subject{App: r.Identity, Addr: r.RemoteAddr, Via: "certificate"}
+	}
+	return r.Auth.IPSubject(r.RemoteAddr)
+}
+
+// Allowed check if the client can visit the key of the api by the policy, api is the uri of the api like "/configwatcher".
+func (r *Request) Allowed(api, key string) bool {
+	return r.Auth.Evaluate(r.Subject(), api, key).Allowed
+}
+
+// AppName return the app name of the client, the client is identified like Subject.
+func (r *Request) AppName() (string, error) {
+	if r.cache == nil {
+		return r.appName()
+	}
+	r.cache.appOnce.Do(func() { r.cache.app, r.cache.appErr = r.appName() })
+	return r.cache.app, r.cache.appErr
+}
+
+func (r *Request) appName() (string, error) {
+	switch {
+	case r.TokenErr != nil:
+		return "", r.TokenErr
+	case r.Token != nil:
+		return r.Token.App, nil
+	case r.Peer != nil:
+		return "", fmt.Errorf("the unix socket peer %s is not a app", r.RemoteAddr)
+	case r.Identity != "":
+		return r.Identity, nil
+	}
+	return r.Auth.AppName(r.RemoteAddr)
+}
+
+// GetString search the string argument by given name, if not exists, return the given value as default.
+func (r *Request) GetString(name string, value string) string {
+	if v := r.Args.Get(name); v != "" {
+		return v
+	}
+	return value
+}
+
+// GetStrings search all the values of the argument by given name.
+func (r *Request) GetStrings(name string) []string {
+	return r.Args[name]
+}
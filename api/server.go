@@ -2,20 +2,33 @@ package api
 
 import (
 	"encoding/json"
+	"net"
 	"net/http"
 	"runtime"
 	"strconv"
 	"strings"
+	"sync"
 	"sync/atomic"
 	"time"
 
 	"github.com/go-martini/martini"
+	"github.com/laincloud/lainlet/auth"
+	"github.com/laincloud/lainlet/fieldmask"
+	"github.com/laincloud/lainlet/jsonpatch"
+	"github.com/laincloud/lainlet/metrics"
 	"github.com/laincloud/lainlet/store"
 	"github.com/laincloud/lainlet/watcher"
 	"github.com/mijia/sweb/log"
 	"golang.org/x/net/context"
 )
 
+const (
+	// PatchRFC6902 is the value of `patch` argument, it let watch request receive json patch instead of the full data
+	PatchRFC6902 = "rfc6902"
+	// PatchEvent is the event name whose data is a json patch against the data of the previous event
+	PatchEvent = "patch"
+)
+
 var (
 	debugConns int32
 )
@@ -28,37 +41,44 @@ func getConnNum() int32 {
 type Server struct {
 	*martini.Martini
 	martini.Router
+
+	watchers map[string]watcher.Watcher
+	apis     map[string]API // the apis by path, used to admit the watch requests
+	auth     auth.Authorizer
+	env      *Env // the configuration, the limits and the watch connections, shared with the grpc server
+
+	sessionsLock sync.Mutex
+	sessions     map[string]*session // the http subscriptions by session id
 }
 
 // New create a http api server; ip is the server ip, it was used by some query;
 // version is the lainlet version, used to return by `/version` api;
-// st is the backend store, it must be valided, should not be a empty interface or a nil value.
+// a is the authorizer of the requests, each handler can get the *Request created with it by injection;
+// env is the state shared with the grpc server, which the handlers can also get by injection, like the admin apis.
 // This function return error when fail to initialize some backend watcher.
-func New(ip, version string, watchers map[string]watcher.Watcher) (*Server, error) {
+func New(ip, version string, watchers map[string]watcher.Watcher, a auth.Authorizer, env *Env) (*Server, error) {
 	r := martini.NewRouter()
 	s := martini.New()
 
-	configWatcher := watchers["configwatcher"]
-	dependsWatcher := watchers["dependswatcher"]
-	containerWatcher := watchers["containerwatcher"]
-	podgroupWatcher := watchers["podgroupwatcher"]
-	nodesWatcher := watchers["nodeswatcher"]
-
 	ctx := context.WithValue(context.Background(), "ip", ip)
-
-	ctx = context.WithValue(ctx, "configwatcher", configWatcher)
-	ctx = context.WithValue(ctx, "dependswatcher", dependsWatcher)
-	ctx = context.WithValue(ctx, "podgroupwatcher", podgroupWatcher)
-	ctx = context.WithValue(ctx, "containerwatcher", containerWatcher)
-	ctx = context.WithValue(ctx, "nodeswatcher", nodesWatcher)
+	srv := &Server{
+		Martini:  s,
+		Router:   r,
+		watchers: watchers,
+		apis:     make(map[string]API),
+		auth:     a,
+		env:      env,
+		sessions: make(map[string]*session),
+	}
 
 	s.Use(martini.Recovery())
 	s.Use(middleWareDebug)
-	s.Use(middleWareWatchEvent)
+	s.Use(srv.middleWareWatchEvent)
 
 	s.MapTo(r, (*martini.Router)(nil)) // router
 	s.Map(log.Logger())                // logger
 	s.Map(ctx)                         // context
+	s.Map(env)                         // configuration, limits and watch connections
 
 	s.Action(r.Handle)
 
@@ -68,21 +88,31 @@ func New(ip, version string, watchers map[string]watcher.Watcher) (*Server, erro
 			"goroutines":  runtime.NumGoroutine(),
 			"connections": getConnNum(),
 			"watchers": map[string]watcher.Status{
-				"config":    configWatcher.Status(),
-				"depends":   dependsWatcher.Status(),
-				"container": containerWatcher.Status(),
-				"podgroup":  podgroupWatcher.Status(),
-				"nodes":     nodesWatcher.Status(),
+				"config":    watchers["configwatcher"].Status(),
+				"depends":   watchers["dependswatcher"].Status(),
+				"container": watchers["containerwatcher"].Status(),
+				"podgroup":  watchers["podgroupwatcher"].Status(),
+				"nodes":     watchers["nodeswatcher"].Status(),
 			},
 		}
 		content, _ := json.Marshal(data)
 		return 200, content
 	})
+	r.Get("/debug/config", func(req *Request) (int, []byte) {
+		if !req.Allowed("/debug/config", "*") {
+			return 400, []byte("authorize failed, no permission")
+		}
+		content, _ := json.Marshal(env.Config())
+		return 200, content
+	})
 	r.Get("/version", func() (int, []byte) {
 		return 200, []byte(version)
 	})
+	r.Get("/metrics", metrics.Handler().ServeHTTP)
+	r.Get(SubscribeURI, srv.handleSubscribe)
+	r.Post(SubscribeURI, srv.handleSubscribeUpdate)
 
-	return &Server{s, r}, nil
+	return srv, nil
 }
 
 // Register a new api. apiserve will auto create a handler for it.
@@ -101,24 +131,33 @@ func (s *Server) Register(api API) {
 		s.Get("/v2"+uri, handler.ServeHTTP)
 		return
 	}
+	wer, ok := s.watchers[api.WatcherName()+"watcher"]
+	if !ok {
+		panic("unknown watcher " + api.WatcherName())
+	}
+	s.apis["/v2"+uri] = api
 	s.Get(
 		"/v2"+uri,
-		func(w http.ResponseWriter, r *http.Request, es *EventSource, ctx context.Context) {
+		func(w http.ResponseWriter, r *http.Request, req *Request, es *EventSource, ctx context.Context) {
 			if GetBool(r, "watch", false) {
-				handleWatch(api, w, r, es, ctx)
+				handleWatch(s.env.Conns, api, wer, req, es, ctx)
 			} else {
-				handleGet(api, w, r, ctx)
+				handleGet(s.env.Limiter, api, wer, req, w)
 			}
 		},
 	)
 }
 
-func handleWatch(api API, w http.ResponseWriter, r *http.Request, es *EventSource, ctx context.Context) {
+// Serve serve http on the listener, like a unix socket listener, it blocks until the listener fail.
+func (s *Server) Serve(l net.Listener) error {
+	log.Infof("listening on %s", l.Addr())
+	return http.Serve(l, s)
+}
 
-	if _, ok := api.(BanWatcher); ok {
-		es.SendEvent(0, store.ERROR.String(), api.URI()+" do not support watch action")
-		return
-	}
+func handleWatch(conns *Registry, api API, wer watcher.Watcher, r *Request, es *EventSource, ctx context.Context) {
+	var err error
+	done := Track(api, ProtocolHTTP, true)
+	defer func() { done(err) }()
 
 	key, err := api.Key(r)
 	if err != nil {
@@ -126,124 +165,67 @@ func handleWatch(api API, w http.ResponseWriter, r *http.Request, es *EventSourc
 		return
 	}
 
-	log.Infof("Request want to watch the key %s", key)
-
-	var (
-		channel <-chan *watcher.Event
-		wer     watcher.Watcher
-	)
-	switch api.WatcherName() {
-	case watcher.CONFIG:
-		wer = ctx.Value("configwatcher").(watcher.Watcher)
-	case watcher.CONTAINER:
-		wer = ctx.Value("containerwatcher").(watcher.Watcher)
-	case watcher.PODGROUP:
-		wer = ctx.Value("podgroupwatcher").(watcher.Watcher)
-	case watcher.DEPENDS:
-		wer = ctx.Value("dependswatcher").(watcher.Watcher)
-	case watcher.NODES:
-		wer = ctx.Value("nodeswatcher").(watcher.Watcher)
-	default:
-		es.SendEvent(0, store.ERROR.String(), "500 unkown watcher")
+	patch := r.GetString("patch", "")
+	if patch != "" && patch != PatchRFC6902 {
+		es.SendEvent(0, store.ERROR.String(), "unsupported patch format "+patch)
 		return
 	}
 
-	var instance API
-	// send the init data
-	data, err := wer.Get(key)
-	if err != nil {
-		log.Errorf("Fail to get %s, %s", key, err.Error())
-		es.SendEvent(0, store.ERROR.String(), err.Error())
-		return
-	}
-	instance, _, err = api.Make(data)
+	mask, err := fieldmask.Parse(r.GetStrings("fields"))
 	if err != nil {
-		log.Errorf("Fail to make data for %s, %s", key, err.Error())
 		es.SendEvent(0, store.ERROR.String(), err.Error())
 		return
 	}
-	content, err := instance.Encode()
-	if err != nil {
-		log.Errorf("Fail to encode data for %s, %s", key, err.Error())
-		es.SendEvent(0, store.ERROR.String(), err.Error())
-		return
-	}
-	es.SendEvent(1, store.INIT.String(), content)
 
-	// start watching
-	channel, err = wer.Watch(key, ctx)
+	log.Infof("Request want to watch the key %s", key)
+
+	var last []byte // the content of the last event, used to create the json patch
+	err = Watch(ctx, conns, wer, api, r, key, mask, func(id uint64, action store.Action, instance API, content []byte) error {
+		if action == store.ERROR {
+			es.SendEvent(id, action.String(), string(content))
+			return nil
+		}
+		if last != nil && patch == PatchRFC6902 && (action == store.UPDATE || action == store.SET) {
+			// send the patch of a update only if it is smaller than the full data, the other events are sent as they are
+			if diff, err := jsonpatch.Diff(last, content); err == nil && len(diff) < len(content) {
+				last = content
+				es.SendEvent(id, PatchEvent, diff)
+				return nil
+			}
+		}
+		last = content
+		es.SendEvent(id, action.String(), content)
+		return nil
+	})
 	if err != nil {
 		log.Errorf("Fail to watch %s, %s", key, err.Error())
 		es.SendEvent(0, store.ERROR.String(), err.Error())
-		return
 	}
-	for {
-		changed := false
-		select {
-		case event, ok := <-channel:
-			if !ok {
-				return
-			}
-			log.Infof("Get a %s event, id=%d action=%s ", api.WatcherName(), event.ID, event.Action.String())
-			if event.Action == store.ERROR {
-				es.SendEvent(event.ID, event.Action.String(), event.Data)
-				continue
-			}
-			instance, changed, err = instance.Make(event.Data)
-			if err != nil {
-				es.SendEvent(0, store.ERROR.String(), err.Error())
-				return
-			}
-			if !changed {
-				continue
-			}
-			content, err := instance.Encode()
-			if err != nil {
-				es.SendEvent(0, store.ERROR.String(), err.Error())
-				return
-			}
-			es.SendEvent(event.ID, event.Action.String(), content)
-		case <-ctx.Done():
-			log.Infof("Get stop signal, a connection stop watching to %s", key)
-			return
-		}
-	}
-	return
 }
 
-func handleGet(api API, w http.ResponseWriter, r *http.Request, ctx context.Context) {
-	var (
-		wer watcher.Watcher
-	)
+func handleGet(limiter *Limiter, api API, wer watcher.Watcher, r *Request, w http.ResponseWriter) {
+	var err error
+	done := Track(api, ProtocolHTTP, false)
+	defer func() { done(err) }()
 
-	key, err := api.Key(r)
-	if err != nil {
-		Return(w, 400, err.Error())
+	if _, err = limiter.Admit(api, r, false); err != nil {
+		Return(w, 429, err.Error())
 		return
 	}
 
-	switch api.WatcherName() {
-	case watcher.CONFIG:
-		wer = ctx.Value("configwatcher").(watcher.Watcher)
-	case watcher.CONTAINER:
-		wer = ctx.Value("containerwatcher").(watcher.Watcher)
-	case watcher.PODGROUP:
-		wer = ctx.Value("podgroupwatcher").(watcher.Watcher)
-	case watcher.DEPENDS:
-		wer = ctx.Value("dependswatcher").(watcher.Watcher)
-	case watcher.NODES:
-		wer = ctx.Value("nodeswatcher").(watcher.Watcher)
-	default:
-		Return(w, 500, "Unkown watcher "+api.WatcherName())
+	key, err := api.Key(r)
+	if err != nil {
+		Return(w, 400, err.Error())
 		return
 	}
 
-	data, err := wer.Get(key)
+	mask, err := fieldmask.Parse(r.GetStrings("fields"))
 	if err != nil {
-		Return(w, 500, err.Error())
+		Return(w, 400, err.Error())
 		return
 	}
-	instance, _, err := api.Make(data)
+
+	instance, err := Get(wer, api, key, mask)
 	if err != nil {
 		Return(w, 500, err.Error())
 		return
@@ -262,24 +244,37 @@ func middleWareDebug(mctx martini.Context) {
 	atomic.AddInt32(&debugConns, -1)
 }
 
-func middleWareWatchEvent(w http.ResponseWriter, r *http.Request, mctx martini.Context, ctx context.Context, router martini.Router) {
+func (s *Server) middleWareWatchEvent(w http.ResponseWriter, r *http.Request, mctx martini.Context, ctx context.Context, router martini.Router) {
 	log.Infof("New Request, %s %s %s", r.RemoteAddr, r.Method, r.URL)
 
 	if len(router.MethodsFor(r.URL.Path)) == 0 {
 		Return(w, 404, "404 not found")
 		return
 	}
+	req := NewRequest(r, s.auth, s.env)
+	mctx.Map(req)
 
 	if !strings.HasPrefix(r.URL.Path, "/v2/") {
 		return
 	}
 
-	if !GetBool(r, "watch", false) {
+	if !isWatch(r) {
 		log.Debugf("Request is not a watch action, create a empty eventsource in case of panic")
 		mctx.Map(&EventSource{}) // add a useless event source
 		return
 	}
 
+	/*********** Check the limits before responding the stream ***********/
+	if api, ok := s.apis[r.URL.Path]; ok {
+		release, err := s.env.Limiter.Admit(api, req, true)
+		if err != nil {
+			log.Warnf("Reject the watch request, %s", err.Error())
+			Return(w, 429, err.Error())
+			return
+		}
+		defer release()
+	}
+
 	/*********** Create a EventSource ***********/
 	es, err := NewEventSource(w)
 	if err != nil {
@@ -322,6 +317,11 @@ func middleWareWatchEvent(w http.ResponseWriter, r *http.Request, mctx martini.C
 	es.Close()
 }
 
+// isWatch check if the request want a event stream, which is a watch request or a subscription
+func isWatch(r *http.Request) bool {
+	return GetBool(r, "watch", false) || (r.Method == "GET" && r.URL.Path == SubscribeURI)
+}
+
 // Return write response to w, code is the http code, data is the content will write into responseBody, can be string or []byte.
 func Return(w http.ResponseWriter, code int, data interface{}) {
 	w.WriteHeader(code)
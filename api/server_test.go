@@ -0,0 +1,141 @@
+package api
+
+import (
+	"bufio"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/laincloud/lainlet/conf"
+	"github.com/laincloud/lainlet/store"
+	"github.com/laincloud/lainlet/watcher"
+	"golang.org/x/net/context"
+)
+
+// mapAPI serve the data in stubWatcher as it is
+type mapAPI struct {
+	Data map[string]interface{}
+}
+
+func (m *mapAPI) Decode(b []byte) error          { return json.Unmarshal(b, &m.Data) }
+func (m *mapAPI) Encode() ([]byte, error)        { return json.Marshal(m.Data) }
+func (m *mapAPI) URI() string                    { return "/map" }
+func (m *mapAPI) WatcherName() string            { return watcher.CONFIG }
+func (m *mapAPI) Key(r *Request) (string, error) { return "*", nil }
+
+func (m *mapAPI) Make(data map[string]interface{}) (API, bool, error) {
+	return &mapAPI{Data: data}, !reflect.DeepEqual(m.Data, data), nil
+}
+
+// stubWatcher return the data set by the test, and send the events of the changes to the watch
+type stubWatcher struct {
+	data map[string]interface{}
+	ch   chan *watcher.Event
+}
+
+func (w *stubWatcher) Get(prefix string) (map[string]interface{}, error) { return w.data, nil }
+func (w *stubWatcher) Status() watcher.Status                            { return watcher.Status{} }
+
+func (w *stubWatcher) Watch(prefix string, ctx context.Context) (<-chan *watcher.Event, error) {
+	return w.ch, nil
+}
+
+// readEvents read the events of the stream, each event is like "patch [...]"
+func readEvents(resp *http.Response) <-chan string {
+	ch := make(chan string, 10)
+	go func() {
+		defer close(ch)
+		br := bufio.NewReader(resp.Body)
+		var name, data string
+		for {
+			line, err := br.ReadString('\n')
+			if err != nil {
+				return
+			}
+			line = strings.TrimRight(line, "\n")
+			switch {
+			case strings.HasPrefix(line, "event: "):
+				name = line[len("event: "):]
+			case strings.HasPrefix(line, "data: "):
+				data = line[len("data: "):]
+			case line == "":
+				ch <- name + " " + data
+				name, data = "", ""
+			}
+		}
+	}()
+	return ch
+}
+
+func expectEvent(t *testing.T, events <-chan string, want string) {
+	select {
+	case ev := <-events:
+		if ev != want {
+			t.Errorf("got the event %q, want %q", ev, want)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatalf("no event received, want %q", want)
+	}
+}
+
+func TestWatchPatch(t *testing.T) {
+	long := strings.Repeat("x", 100)
+	w := &stubWatcher{
+		data: map[string]interface{}{"a": "1", "b": long},
+		ch:   make(chan *watcher.Event, 10),
+	}
+	srv, err := New("127.0.0.1", "test", map[string]watcher.Watcher{watcher.CONFIG + "watcher": w}, &fakeAuth{}, NewEnv(conf.Default()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	srv.Register(&mapAPI{})
+	ts := httptest.NewServer(srv)
+	defer ts.Close()
+
+	resp, err := http.Get(ts.URL + "/v2/map?watch=1&patch=" + PatchRFC6902)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+	events := readEvents(resp)
+	expectEvent(t, events, `init {"a":"1","b":"`+long+`"}`)
+
+	// the update is sent as a patch, the delete is sent as it is even if the patch is smaller,
+	// and so is the update whose patch is not smaller
+	w.ch <- &watcher.Event{ID: 2, Action: store.UPDATE, Data: map[string]interface{}{"a": "2", "b": long}}
+	expectEvent(t, events, `patch [{"op":"replace","path":"/a","value":"2"}]`)
+	w.ch <- &watcher.Event{ID: 3, Action: store.DELETE, Data: map[string]interface{}{"b": long}}
+	expectEvent(t, events, `delete {"b":"`+long+`"}`)
+	w.ch <- &watcher.Event{ID: 4, Action: store.UPDATE, Data: map[string]interface{}{"c": "3"}}
+	expectEvent(t, events, `update {"c":"3"}`)
+}
+
+func TestDebugConfig(t *testing.T) {
+	cases := []struct {
+		apps map[string]string
+		code int
+	}{
+		{nil, 400},
+		{map[string]string{"127.0.0.1": "console"}, 200},
+	}
+	for _, c := range cases {
+		srv, err := New("127.0.0.1", "test", nil, &fakeAuth{apps: c.apps}, NewEnv(conf.Default()))
+		if err != nil {
+			t.Fatal(err)
+		}
+		rec := httptest.NewRecorder()
+		req := httptest.NewRequest("GET", "/debug/config", nil)
+		req.RemoteAddr = "127.0.0.1:1234"
+		srv.ServeHTTP(rec, req)
+		if rec.Code != c.code {
+			t.Errorf("GET /debug/config by %v = %d %s, want %d", c.apps, rec.Code, rec.Body.String(), c.code)
+		}
+		if c.code == 200 && !json.Valid(rec.Body.Bytes()) {
+			t.Errorf("got the invalid config %s", rec.Body.String())
+		}
+	}
+}
@@ -0,0 +1,344 @@
+package api
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+
+	"github.com/laincloud/lainlet/fieldmask"
+	"github.com/laincloud/lainlet/store"
+	"github.com/laincloud/lainlet/watcher"
+	"github.com/mijia/sweb/log"
+	"golang.org/x/net/context"
+)
+
+// SubscribeURI is the uri of the multiplexed subscription of the http server
+const SubscribeURI = "/v2/subscribe"
+
+// TaggedSender is the Sender of a Subscription, tag is the tag of the watch sending the event
+type TaggedSender func(tag string, id uint64, action store.Action, instance API, content []byte) error
+
+// Lookup return the api by name and its watcher, the name is the uri for http and the service name for grpc
+type Lookup func(name string) (API, watcher.Watcher, bool)
+
+// Subscription is a group of watches of a client over one connection, each watch is tagged by the client.
+// The watches can be added and removed at any time, the events of them are sent one by one.
+// It is used by both the http server and the grpc server.
+type Subscription struct {
+	ctx    context.Context
+	env    *Env
+	lookup Lookup
+	send   TaggedSender
+
+	lock sync.Mutex
+	subs map[string]*subscribed // nil after closed
+	wg   sync.WaitGroup
+
+	sendLock sync.Mutex // make the events sent one by one
+}
+
+type subscribed struct {
+	cancel  context.CancelFunc
+	removed bool // protected by sendLock, no event is sent after removed
+}
+
+// NewSubscription create a subscription, all the watches are stopped when ctx is done or Close is called,
+// they are admitted by the limiter of env and listed in its connections like the other watches.
+func NewSubscription(ctx context.Context, env *Env, lookup Lookup, send TaggedSender) *Subscription {
+	return &Subscription{
+		ctx:    ctx,
+		env:    env,
+		lookup: lookup,
+		send:   send,
+		subs:   make(map[string]*subscribed),
+	}
+}
+
+// Add start watching the api by the request whose arguments are in r.Args, the events are tagged by tag.
+// The request is admitted and authorized like a normal watch request, and the error is returned if it failed.
+// A error event is sent if the watch stopped by error, like the data can not be read from store.
+func (s *Subscription) Add(tag, name string, r *Request) error {
+	if tag == "" {
+		return fmt.Errorf("tag required")
+	}
+	a, wer, ok := s.lookup(name)
+	if !ok {
+		return fmt.Errorf("unknown api %s", name)
+	}
+	if _, ok := a.(BanWatcher); ok {
+		return fmt.Errorf("%s do not support watch action", a.URI())
+	}
+
+	s.lock.Lock()
+	err := s.checkTag(tag)
+	s.lock.Unlock()
+	if err != nil {
+		return err
+	}
+
+	// admit and authorize without holding the lock, they may look up the app of the client
+	done := Track(a, r.Protocol, true)
+	release, err := s.env.Limiter.Admit(a, r, true)
+	if err != nil {
+		done(err)
+		return err
+	}
+	key, err := a.Key(r)
+	if err != nil {
+		release()
+		done(err)
+		return err
+	}
+	mask, err := fieldmask.Parse(r.GetStrings("fields"))
+	if err != nil {
+		release()
+		done(err)
+		return err
+	}
+
+	ctx, cancel := context.WithCancel(s.ctx)
+	sub := &subscribed{cancel: cancel}
+	s.lock.Lock()
+	// the subscription may be closed or the tag may be added meanwhile
+	if err := s.checkTag(tag); err != nil {
+		s.lock.Unlock()
+		cancel()
+		release()
+		done(err)
+		return err
+	}
+	s.subs[tag] = sub
+	s.wg.Add(1)
+	s.lock.Unlock()
+	go func() {
+		defer s.wg.Done()
+		defer release()
+		log.Infof("Subscription %s start watching %s, key=%s", tag, a.URI(), key)
+		err := Watch(ctx, s.env.Conns, wer, a, r, key, mask, func(id uint64, action store.Action, instance API, content []byte) error {
+			return s.sendEvent(sub, tag, id, action, instance, content)
+		})
+		done(err)
+		if err == nil && ctx.Err() == nil {
+			err = fmt.Errorf("the watch of %s stopped", a.URI())
+		}
+		if err != nil {
+			log.Errorf("Subscription %s fail to watch %s, %s", tag, key, err.Error())
+			s.sendEvent(sub, tag, 0, store.ERROR, nil, []byte(err.Error()))
+		}
+		s.lock.Lock()
+		if s.subs[tag] == sub {
+			delete(s.subs, tag)
+		}
+		s.lock.Unlock()
+		cancel()
+	}()
+	return nil
+}
+
+// checkTag check if the tag can be added, s.lock should be held
+func (s *Subscription) checkTag(tag string) error {
+	if s.subs == nil {
+		return fmt.Errorf("subscription closed")
+	}
+	if _, ok := s.subs[tag]; ok {
+		return fmt.Errorf("tag %s is in use", tag)
+	}
+	return nil
+}
+
+// Remove stop the watch of tag, no event of it is sent after Remove returned.
+func (s *Subscription) Remove(tag string) error {
+	s.lock.Lock()
+	sub, ok := s.subs[tag]
+	delete(s.subs, tag)
+	s.lock.Unlock()
+	if !ok {
+		return fmt.Errorf("no subscription tagged %s", tag)
+	}
+	s.stop(sub)
+	return nil
+}
+
+// SendError send a error event of tag, like the error of Add and Remove.
+func (s *Subscription) SendError(tag string, err error) error {
+	s.sendLock.Lock()
+	defer s.sendLock.Unlock()
+	return s.send(tag, 0, store.ERROR, nil, []byte(err.Error()))
+}
+
+// Close stop all the watches, and wait for them to finish
+func (s *Subscription) Close() {
+	s.lock.Lock()
+	subs := s.subs
+	s.subs = nil
+	s.lock.Unlock()
+	for _, sub := range subs {
+		s.stop(sub)
+	}
+	s.wg.Wait()
+}
+
+func (s *Subscription) stop(sub *subscribed) {
+	s.sendLock.Lock()
+	sub.removed = true
+	s.sendLock.Unlock()
+	sub.cancel()
+}
+
+func (s *Subscription) sendEvent(sub *subscribed, tag string, id uint64, action store.Action, instance API, content []byte) error {
+	s.sendLock.Lock()
+	defer s.sendLock.Unlock()
+	if sub.removed {
+		return nil
+	}
+	return s.send(tag, id, action, instance, content)
+}
+
+// SubscriptionEvent is the data of the events sent by the http subscription, the event name is the action
+type SubscriptionEvent struct {
+	Tag   string          `json:"tag"`
+	Data  json.RawMessage `json:"data,omitempty"`
+	Error string          `json:"error,omitempty"`
+}
+
+// session is a http subscription, the client changes the subscription by POST with the session id
+type session struct {
+	sub *Subscription
+	r   *Request
+}
+
+// lookupAPI find the api by its uri, with or without the "/v2" prefix
+func (s *Server) lookupAPI(uri string) (API, watcher.Watcher, bool) {
+	if !strings.HasPrefix(uri, "/") {
+		uri = "/" + uri
+	}
+	if !strings.HasPrefix(uri, "/v2/") {
+		uri = "/v2" + uri
+	}
+	a, ok := s.apis[uri]
+	if !ok {
+		return nil, nil, false
+	}
+	wer, ok := s.watchers[a.WatcherName()+"watcher"]
+	return a, wer, ok
+}
+
+// handleSubscribe serve the event stream of the subscription.
+// The first event is "session", whose data is like {"session": "<id>"}, the subscription can be changed by POST with the id.
+// The initial watches are given by the `sub` arguments, see parseSub.
+func (s *Server) handleSubscribe(r *Request, es *EventSource, ctx context.Context) {
+	id, err := newSessionID()
+	if err != nil {
+		es.SendEvent(0, store.ERROR.String(), err.Error())
+		return
+	}
+	sub := NewSubscription(ctx, s.env, s.lookupAPI, func(tag string, eventID uint64, action store.Action, instance API, content []byte) error {
+		ev := SubscriptionEvent{Tag: tag}
+		if action == store.ERROR {
+			ev.Error = string(content)
+		} else {
+			ev.Data = content
+		}
+		data, err := json.Marshal(ev)
+		if err != nil {
+			return err
+		}
+		return es.SendEvent(eventID, action.String(), data)
+	})
+	defer sub.Close()
+
+	s.sessionsLock.Lock()
+	s.sessions[id] = &session{sub: sub, r: r}
+	s.sessionsLock.Unlock()
+	defer func() {
+		s.sessionsLock.Lock()
+		delete(s.sessions, id)
+		s.sessionsLock.Unlock()
+	}()
+
+	data, _ := json.Marshal(map[string]string{"session": id})
+	es.SendEvent(0, "session", data)
+	for _, value := range r.GetStrings("sub") {
+		tag, uri, args, err := parseSub(value)
+		if err == nil {
+			err = sub.Add(tag, uri, withArgs(r, args))
+		}
+		if err != nil {
+			sub.SendError(tag, err)
+		}
+	}
+	<-ctx.Done()
+}
+
+// handleSubscribeUpdate change the subscription of the session, the `add` arguments are the watches to add like the `sub` arguments,
+// and the `remove` arguments are the tags to remove. It must be requested by the same client as the subscription.
+func (s *Server) handleSubscribeUpdate(w http.ResponseWriter, r *Request) {
+	s.sessionsLock.Lock()
+	sess, ok := s.sessions[r.GetString("session", "")]
+	s.sessionsLock.Unlock()
+	if !ok {
+		Return(w, 404, "session not found")
+		return
+	}
+	if clientOf(r) != clientOf(sess.r) {
+		Return(w, 403, "authorize failed, the session belongs to another client")
+		return
+	}
+	for _, tag := range r.GetStrings("remove") {
+		if err := sess.sub.Remove(tag); err != nil {
+			Return(w, 400, err.Error())
+			return
+		}
+	}
+	for _, value := range r.GetStrings("add") {
+		tag, uri, args, err := parseSub(value)
+		if err == nil {
+			err = sess.sub.Add(tag, uri, withArgs(sess.r, args))
+		}
+		if err != nil {
+			code := 400
+			if IsAuthError(err) {
+				code = 403
+			} else if IsLimitError(err) {
+				code = 429
+			}
+			Return(w, code, err.Error())
+			return
+		}
+	}
+	Return(w, 200, "ok")
+}
+
+// parseSub parse the watch like "<tag>:<uri>?<arguments>", eg. "vips:/configwatcher?target=vips"
+func parseSub(value string) (tag, uri string, args url.Values, err error) {
+	index := strings.IndexByte(value, ':')
+	if index <= 0 {
+		return "", "", nil, fmt.Errorf("invalid subscription %q, it should be like <tag>:<uri>?<arguments>", value)
+	}
+	tag = value[:index]
+	u, err := url.Parse(value[index+1:])
+	if err != nil {
+		return tag, "", nil, fmt.Errorf("invalid subscription %q, %s", value, err.Error())
+	}
+	return tag, u.Path, u.Query(), nil
+}
+
+// withArgs return a copy of r with the arguments
+func withArgs(r *Request, args url.Values) *Request {
+	ret := *r
+	ret.Args = args
+	return &ret
+}
+
+func newSessionID() (string, error) {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(b), nil
+}
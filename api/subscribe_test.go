@@ -0,0 +1,365 @@
+package api
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http/httptest"
+	"net/url"
+	"reflect"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/laincloud/lainlet/conf"
+	"github.com/laincloud/lainlet/store"
+	"github.com/laincloud/lainlet/watcher"
+	"golang.org/x/net/context"
+)
+
+// dataAPI serve the data of the key in fakeWatcher, the key is the "key" argument
+type dataAPI struct {
+	Data map[string]interface{}
+}
+
+func (d *dataAPI) Decode(b []byte) error   { return json.Unmarshal(b, &d.Data) }
+func (d *dataAPI) Encode() ([]byte, error) { return json.Marshal(d.Data) }
+func (d *dataAPI) URI() string             { return "/v2/data" }
+func (d *dataAPI) WatcherName() string     { return "fake" }
+
+func (d *dataAPI) Key(r *Request) (string, error) {
+	key := r.GetString("key", "")
+	if !r.Allowed(d.URI(), key) {
+		return "", fmt.Errorf("authorize failed, no permission")
+	}
+	return key, nil
+}
+
+func (d *dataAPI) Make(data map[string]interface{}) (API, bool, error) {
+	return &dataAPI{Data: data}, !reflect.DeepEqual(d.Data, data), nil
+}
+
+// fakeWatcher send the events pushed by push to the watches of the key
+type fakeWatcher struct {
+	lock  sync.Mutex
+	chans map[string]chan *watcher.Event
+}
+
+func (w *fakeWatcher) ch(key string) chan *watcher.Event {
+	w.lock.Lock()
+	defer w.lock.Unlock()
+	if w.chans == nil {
+		w.chans = make(map[string]chan *watcher.Event)
+	}
+	if w.chans[key] == nil {
+		w.chans[key] = make(chan *watcher.Event, 100)
+	}
+	return w.chans[key]
+}
+
+func (w *fakeWatcher) push(key string, value interface{}) {
+	w.ch(key) <- &watcher.Event{Action: store.UPDATE, Data: map[string]interface{}{"value": value}}
+}
+
+// drain drop the events not received by the watches
+func (w *fakeWatcher) drain(key string) {
+	ch := w.ch(key)
+	for len(ch) > 0 {
+		<-ch
+	}
+}
+
+func (w *fakeWatcher) Get(key string) (map[string]interface{}, error) {
+	return map[string]interface{}{"value": "init"}, nil
+}
+
+func (w *fakeWatcher) Watch(key string, ctx context.Context) (<-chan *watcher.Event, error) {
+	return w.ch(key), nil
+}
+
+func (w *fakeWatcher) Status() watcher.Status {
+	return watcher.Status{}
+}
+
+// events record the events sent by the subscription, like "a init init"
+type events struct {
+	lock sync.Mutex
+	list []string
+	ch   chan string
+}
+
+func newEvents() *events {
+	return &events{ch: make(chan string, 1000)}
+}
+
+func (e *events) send(tag string, id uint64, action store.Action, instance API, content []byte) error {
+	var data map[string]interface{}
+	json.Unmarshal(content, &data)
+	ev := fmt.Sprintf("%s %s %v", tag, action.String(), data["value"])
+	if action == store.ERROR {
+		ev = fmt.Sprintf("%s %s %s", tag, action.String(), content)
+	}
+	e.mark(ev)
+	return nil
+}
+
+// mark record the event, or a mark between the events
+func (e *events) mark(ev string) {
+	e.lock.Lock()
+	e.list = append(e.list, ev)
+	e.lock.Unlock()
+	e.ch <- ev
+}
+
+// reset drop the events not expected yet
+func (e *events) reset() {
+	for len(e.ch) > 0 {
+		<-e.ch
+	}
+}
+
+func (e *events) expect(t *testing.T, want string) {
+	select {
+	case ev := <-e.ch:
+		if ev != want {
+			t.Errorf("got the event %q, want %q", ev, want)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatalf("no event received, want %q", want)
+	}
+}
+
+func newTestSubscription(ctx context.Context, w *fakeWatcher, e *events) *Subscription {
+	return NewSubscription(ctx, NewEnv(conf.Default()), func(name string) (API, watcher.Watcher, bool) {
+		if name != "/v2/data" {
+			return nil, nil, false
+		}
+		return &dataAPI{}, w, true
+	}, e.send)
+}
+
+// watchesOf return the number of the watches of the client in the subscription
+func watchesOf(sub *Subscription, addr string) int {
+	n := 0
+	for _, c := range sub.env.Conns.Conns() {
+		if c.RemoteAddr == addr {
+			n++
+		}
+	}
+	return n
+}
+
+func withKey(r *Request, key string) *Request {
+	return withArgs(r, url.Values{"key": {key}})
+}
+
+func TestSubscription(t *testing.T) {
+	a := &fakeAuth{apps: map[string]string{"172.20.1.2": "hello"}}
+	r := newTestRequest(a, "172.20.1.2:1234")
+	w, e := &fakeWatcher{}, newEvents()
+	sub := newTestSubscription(context.Background(), w, e)
+
+	if err := sub.Add("a", "/v2/data", withKey(r, "hello")); err != nil {
+		t.Fatal(err)
+	}
+	e.expect(t, "a init init")
+	if err := sub.Add("b", "/v2/data", withKey(r, "*")); err != nil {
+		t.Fatal(err)
+	}
+	e.expect(t, "b init init")
+	cases := []struct {
+		tag, name, key, reason string
+	}{
+		{"", "/v2/data", "hello", "tag required"},
+		{"c", "/v2/unknown", "hello", "unknown api"},
+		{"c", "/v2/data", "world", "authorize failed"},
+		{"a", "/v2/data", "hello", "tag a is in use"},
+	}
+	for _, c := range cases {
+		if err := sub.Add(c.tag, c.name, withKey(r, c.key)); err == nil || !strings.Contains(err.Error(), c.reason) {
+			t.Errorf("Add(%q, %q) with key %q = %v, want %q", c.tag, c.name, c.key, err, c.reason)
+		}
+	}
+	if n := watchesOf(sub, r.RemoteAddr); n != 2 {
+		t.Errorf("%d watches are running, want 2", n)
+	}
+
+	w.push("hello", 1)
+	e.expect(t, "a update 1")
+	w.push("*", 2)
+	e.expect(t, "b update 2")
+
+	sub.lock.Lock()
+	removed := sub.subs["a"]
+	sub.lock.Unlock()
+	if err := sub.Remove("a"); err != nil {
+		t.Fatal(err)
+	}
+	// the event being sent by the watch when it was removed is dropped
+	if err := sub.sendEvent(removed, "a", 3, store.UPDATE, nil, []byte(`{"value":3}`)); err != nil {
+		t.Error(err)
+	}
+	if err := sub.Remove("a"); err == nil {
+		t.Error("the removed tag should not be removed again")
+	}
+	w.push("hello", 3)
+	w.push("*", 4)
+	e.expect(t, "b update 4")
+	w.drain("hello")
+	// the tag can be used again after removed
+	if err := sub.Add("a", "/v2/data", withKey(r, "hello")); err != nil {
+		t.Fatal(err)
+	}
+	e.expect(t, "a init init")
+
+	sub.Close()
+	// the watches are finished after Close returned
+	if n := watchesOf(sub, r.RemoteAddr); n != 0 {
+		t.Errorf("%d watches are running after closed", n)
+	}
+	w.push("hello", 5)
+	w.push("*", 6)
+	if err := sub.Add("c", "/v2/data", withKey(r, "hello")); err == nil || err.Error() != "subscription closed" {
+		t.Errorf("Add after closed = %v, want the subscription closed", err)
+	}
+	if err := sub.Remove("b"); err == nil {
+		t.Error("Remove after closed should fail")
+	}
+	time.Sleep(50 * time.Millisecond)
+	select {
+	case ev := <-e.ch:
+		t.Errorf("got the event %q after closed", ev)
+	default:
+	}
+}
+
+func TestSubscriptionRemoveRace(t *testing.T) {
+	a := &fakeAuth{apps: map[string]string{"172.20.1.2": "hello"}}
+	r := newTestRequest(a, "172.20.1.2:1234")
+	w, e := &fakeWatcher{}, newEvents()
+	sub := newTestSubscription(context.Background(), w, e)
+	defer sub.Close()
+
+	for i := 0; i < 20; i++ {
+		e.reset()
+		if err := sub.Add("a", "/v2/data", withKey(r, "hello")); err != nil {
+			t.Fatal(err)
+		}
+		e.expect(t, "a init init")
+		stop := make(chan struct{})
+		pushed := make(chan struct{})
+		go func() {
+			defer close(pushed)
+			for n := 0; ; n++ {
+				select {
+				case <-stop:
+					return
+				default:
+					w.push("hello", n)
+					time.Sleep(time.Millisecond)
+				}
+			}
+		}()
+		time.Sleep(5 * time.Millisecond)
+		if err := sub.Remove("a"); err != nil {
+			t.Fatal(err)
+		}
+		e.mark("removed")
+		close(stop)
+		<-pushed
+		w.drain("hello")
+	}
+
+	// no event of the tag is sent after Remove returned
+	e.lock.Lock()
+	defer e.lock.Unlock()
+	removed := false
+	for _, ev := range e.list {
+		switch {
+		case ev == "removed":
+			removed = true
+		case ev == "a init init":
+			removed = false
+		case removed:
+			t.Fatalf("got the event %q after removed", ev)
+		}
+	}
+}
+
+func TestSubscriptionContext(t *testing.T) {
+	a := &fakeAuth{apps: map[string]string{"172.20.1.2": "hello"}}
+	r := newTestRequest(a, "172.20.1.2:1234")
+	w, e := &fakeWatcher{}, newEvents()
+	ctx, cancel := context.WithCancel(context.Background())
+	sub := newTestSubscription(ctx, w, e)
+	if err := sub.Add("a", "/v2/data", withKey(r, "hello")); err != nil {
+		t.Fatal(err)
+	}
+	e.expect(t, "a init init")
+	// the watches stop when the context is done, without error events
+	cancel()
+	sub.Close()
+	if n := watchesOf(sub, r.RemoteAddr); n != 0 {
+		t.Errorf("%d watches are running after the context was done", n)
+	}
+	select {
+	case ev := <-e.ch:
+		t.Errorf("got the event %q after the context was done", ev)
+	default:
+	}
+}
+
+func TestSubscribeUpdate(t *testing.T) {
+	a := &fakeAuth{apps: map[string]string{"172.20.1.2": "hello", "172.20.1.3": "hello", "172.20.1.4": "world"}}
+	owner := newTestRequest(a, "172.20.1.2:1234")
+	w, e := &fakeWatcher{}, newEvents()
+	srv := &Server{
+		watchers: map[string]watcher.Watcher{"fakewatcher": w},
+		apis:     map[string]API{"/v2/data": &dataAPI{}},
+		env:      NewEnv(conf.Default()),
+		sessions: make(map[string]*session),
+	}
+	// the uris in the subscriptions are looked up with or without "/v2"
+	sub := NewSubscription(context.Background(), srv.env, srv.lookupAPI, e.send)
+	defer sub.Close()
+	srv.sessions["s1"] = &session{sub: sub, r: owner}
+
+	cases := []struct {
+		name string
+		addr string
+		args url.Values
+		code int
+		want string
+	}{
+		{"unknown session", "172.20.1.2:1234", url.Values{"session": {"s2"}, "add": {"a:/data?key=hello"}}, 404, ""},
+		{"another app", "172.20.1.4:1234", url.Values{"session": {"s1"}, "add": {"a:/data?key=world"}}, 403, ""},
+		{"unknown ip", "10.0.0.1:1234", url.Values{"session": {"s1"}, "add": {"a:/data?key=hello"}}, 403, ""},
+		{"owner", "172.20.1.2:5678", url.Values{"session": {"s1"}, "add": {"a:data?key=hello"}}, 200, "a init init"},
+		{"another container of the app", "172.20.1.3:1234", url.Values{"session": {"s1"}, "add": {"b:/v2/data?key=*"}}, 200, "b init init"},
+		{"denied key", "172.20.1.2:1234", url.Values{"session": {"s1"}, "add": {"c:/data?key=world"}}, 403, ""},
+		{"invalid subscription", "172.20.1.2:1234", url.Values{"session": {"s1"}, "add": {"/data?key=hello"}}, 400, ""},
+		{"unknown tag", "172.20.1.2:1234", url.Values{"session": {"s1"}, "remove": {"c"}}, 400, ""},
+		{"remove", "172.20.1.2:1234", url.Values{"session": {"s1"}, "remove": {"a"}}, 200, ""},
+	}
+	for _, c := range cases {
+		r := newTestRequest(a, c.addr)
+		r.Args = c.args
+		rec := httptest.NewRecorder()
+		srv.handleSubscribeUpdate(rec, r)
+		if rec.Code != c.code {
+			t.Errorf("%s: got %d %q, want %d", c.name, rec.Code, rec.Body.String(), c.code)
+		}
+		if c.want != "" {
+			e.expect(t, c.want)
+		}
+	}
+	// the watches are added by the request of the owner, not the one updating the session
+	for _, c := range srv.env.Conns.Conns() {
+		if c.Key == "*" && c.RemoteAddr != owner.RemoteAddr {
+			t.Errorf("the watch is added by %s, want the owner %s", c.RemoteAddr, owner.RemoteAddr)
+		}
+	}
+	w.push("hello", 1)
+	w.push("*", 2)
+	e.expect(t, "b update 2")
+}
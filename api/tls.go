@@ -0,0 +1,56 @@
+package api
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"io/ioutil"
+	"net"
+	"net/http"
+
+	"github.com/mijia/sweb/log"
+)
+
+// NewTLSConfig create the tls config of the https server by the certificate and key files.
+// If clientCAFile is not empty, the client certificates are verified by the CAs in it, and the verified ones are used as the identity of the clients,
+// requireClientCert means the clients without a verified certificate are rejected.
+func NewTLSConfig(certFile, keyFile, clientCAFile string, requireClientCert bool) (*tls.Config, error) {
+	cert, err := tls.LoadX509KeyPair(certFile, keyFile)
+	if err != nil {
+		return nil, fmt.Errorf("Fail to load the certificate, %s", err.Error())
+	}
+	cfg := &tls.Config{
+		Certificates: []tls.Certificate{cert},
+	}
+	if clientCAFile == "" {
+		if requireClientCert {
+			return nil, fmt.Errorf("client CA is required to verify the client certificates")
+		}
+		return cfg, nil
+	}
+	content, err := ioutil.ReadFile(clientCAFile)
+	if err != nil {
+		return nil, fmt.Errorf("Fail to read the client CA, %s", err.Error())
+	}
+	pool := x509.NewCertPool()
+	if !pool.AppendCertsFromPEM(content) {
+		return nil, fmt.Errorf("no certificate found in %s", clientCAFile)
+	}
+	cfg.ClientCAs = pool
+	if requireClientCert {
+		cfg.ClientAuth = tls.RequireAndVerifyClientCert
+	} else {
+		cfg.ClientAuth = tls.VerifyClientCertIfGiven
+	}
+	return cfg, nil
+}
+
+// ServeTLS serve https on the listener with the tls config, it blocks until the server fail.
+func (s *Server) ServeTLS(l net.Listener, cfg *tls.Config) error {
+	srv := &http.Server{
+		Handler:   s,
+		TLSConfig: cfg,
+	}
+	log.Infof("listening on %s (https)", l.Addr())
+	return srv.ServeTLS(l, "", "")
+}
@@ -0,0 +1,55 @@
+package v2
+
+import (
+	"encoding/json"
+	"github.com/laincloud/lainlet/api"
+	"strconv"
+)
+
+// ListWatchesAPI return all the active watch connections of both http and grpc
+func ListWatchesAPI(r *api.Request, env *api.Env) (int, string) {
+	if !r.Allowed("/admin/watches", "*") {
+		return 400, "authorize failed, no permission"
+	}
+	content, err := json.Marshal(env.Conns.Conns())
+	if err != nil {
+		return 500, err.Error()
+	}
+	return 200, string(content)
+}
+
+// ListLimitsAPI return the usages of the request limits of each client to each api
+func ListLimitsAPI(r *api.Request, env *api.Env) (int, string) {
+	if !r.Allowed("/admin/limits", "*") {
+		return 400, "authorize failed, no permission"
+	}
+	content, err := json.Marshal(env.Limiter.Usages())
+	if err != nil {
+		return 500, err.Error()
+	}
+	return 200, string(content)
+}
+
+// DisconnectWatchesAPI close the watch connections by the `id` or the `client` argument,
+// client can be a ip or a "ip:port" address.
+func DisconnectWatchesAPI(r *api.Request, env *api.Env) (int, string) {
+	if !r.Allowed("/admin/watches/disconnect", "*") {
+		return 400, "authorize failed, no permission"
+	}
+	var id uint64
+	if s := r.GetString("id", ""); s != "" {
+		var err error
+		if id, err = strconv.ParseUint(s, 10, 64); err != nil || id == 0 {
+			return 400, "invalid id " + s
+		}
+	}
+	client := r.GetString("client", "")
+	if id == 0 && client == "" {
+		return 400, "id or client required"
+	}
+	content, err := json.Marshal(map[string]int{"disconnected": env.Conns.Disconnect(id, client)})
+	if err != nil {
+		return 500, err.Error()
+	}
+	return 200, string(content)
+}
@@ -0,0 +1,177 @@
+package v2_test
+
+import (
+	"bufio"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/laincloud/lainlet/api"
+	"github.com/laincloud/lainlet/api/v2"
+	"github.com/laincloud/lainlet/auth"
+	"github.com/laincloud/lainlet/conf"
+	"golang.org/x/net/context"
+)
+
+// adminAuth allow the super clients to visit everything, and the others to visit the apis except the admin ones
+type adminAuth struct {
+	allowAuth
+	super bool
+}
+
+func (a adminAuth) Evaluate(s auth.Subject, api, key string) auth.Decision {
+	return auth.Decision{Allowed: a.super || !strings.HasPrefix(api, "/admin/"), Rule: -1}
+}
+
+func (a adminAuth) Explain(s auth.Subject, api, key string) *auth.Explanation {
+	return &auth.Explanation{Subject: s, API: api, Key: key, Decision: a.Evaluate(s, api, key)}
+}
+
+func serveAdmin(t *testing.T, super bool) *httptest.Server {
+	srv, err := api.New("127.0.0.1", "test", testWatchers(t), adminAuth{super: super}, api.NewEnv(conf.Default()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	v2.RegisterHTTP(srv, []api.API{new(v2.GeneralConfig)})
+	return httptest.NewServer(srv)
+}
+
+// watchConfig start a watch of the config, it returns after the init event was received, the returned channel is closed when the watch ends
+func watchConfig(t *testing.T, ctx context.Context, url string) <-chan struct{} {
+	req, err := http.NewRequest("GET", url+"/v2/configwatcher?watch=1", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp, err := http.DefaultClient.Do(req.WithContext(ctx))
+	if err != nil {
+		t.Fatal(err)
+	}
+	br := bufio.NewReader(resp.Body)
+	if line, err := br.ReadString('\n'); err != nil || !strings.HasPrefix(line, "id: ") {
+		t.Fatalf("got %q, %v, want the init event", line, err)
+	}
+	ended := make(chan struct{})
+	go func() {
+		defer close(ended)
+		defer resp.Body.Close()
+		ioutil.ReadAll(br)
+	}()
+	return ended
+}
+
+func call(t *testing.T, method, url string) (int, string) {
+	req, err := http.NewRequest(method, url, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+	body, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return resp.StatusCode, string(body)
+}
+
+func listWatches(t *testing.T, url string) []api.Conn {
+	code, body := call(t, "GET", url+"/admin/watches")
+	if code != 200 {
+		t.Fatalf("fail to list the watches, %d %s", code, body)
+	}
+	var conns []api.Conn
+	if err := json.Unmarshal([]byte(body), &conns); err != nil {
+		t.Fatal(err)
+	}
+	return conns
+}
+
+func expectEnded(t *testing.T, ended <-chan struct{}, what string) {
+	select {
+	case <-ended:
+	case <-time.After(5 * time.Second):
+		t.Fatalf("the %s is not disconnected", what)
+	}
+}
+
+func TestAdminWatches(t *testing.T) {
+	ts := serveAdmin(t, true)
+	defer ts.Close()
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	first := watchConfig(t, ctx, ts.URL)
+	second := watchConfig(t, ctx, ts.URL)
+
+	conns := listWatches(t, ts.URL)
+	if len(conns) != 2 || conns[0].ID >= conns[1].ID {
+		t.Fatalf("got the watches %+v, want 2 sorted by id", conns)
+	}
+	for _, c := range conns {
+		if c.API != "/configwatcher" || c.Protocol != api.ProtocolHTTP || c.Key != "*" || !strings.HasPrefix(c.RemoteAddr, "127.0.0.1:") {
+			t.Errorf("got the watch %+v, want the http watch of /configwatcher from 127.0.0.1", c)
+		}
+	}
+
+	cases := []struct {
+		query string
+		code  int
+		body  string
+	}{
+		{"", 400, "id or client required"},
+		{"id=abc", 400, "invalid id abc"},
+		{"id=0", 400, "invalid id 0"},
+		{"client=10.0.0.1", 200, `{"disconnected":0}`},
+	}
+	for _, c := range cases {
+		if code, body := call(t, "POST", ts.URL+"/admin/watches/disconnect?"+c.query); code != c.code || body != c.body {
+			t.Errorf("disconnect by %q = %d %s, want %d %s", c.query, code, body, c.code, c.body)
+		}
+	}
+
+	// by id, only the first watch is disconnected
+	if code, body := call(t, "POST", ts.URL+"/admin/watches/disconnect?id="+strconv.FormatUint(conns[0].ID, 10)); code != 200 || body != `{"disconnected":1}` {
+		t.Errorf("disconnect by id = %d %s", code, body)
+	}
+	expectEnded(t, first, "watch disconnected by id")
+	if left := listWatches(t, ts.URL); len(left) != 1 || left[0].ID != conns[1].ID {
+		t.Errorf("got the watches %+v after disconnecting the first one", left)
+	}
+	// by client, the ip matches all the watches from it
+	if code, body := call(t, "POST", ts.URL+"/admin/watches/disconnect?client=127.0.0.1"); code != 200 || body != `{"disconnected":1}` {
+		t.Errorf("disconnect by client = %d %s", code, body)
+	}
+	expectEnded(t, second, "watch disconnected by client")
+	if conns := listWatches(t, ts.URL); len(conns) != 0 {
+		t.Errorf("got the watches %+v after disconnecting all", conns)
+	}
+}
+
+func TestAdminDenied(t *testing.T) {
+	ts := serveAdmin(t, false)
+	defer ts.Close()
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	ended := watchConfig(t, ctx, ts.URL)
+
+	for _, c := range []struct{ method, uri string }{
+		{"GET", "/admin/watches"},
+		{"GET", "/admin/limits"},
+		{"POST", "/admin/watches/disconnect?client=127.0.0.1"},
+	} {
+		if code, body := call(t, c.method, ts.URL+c.uri); code != 400 || body != "authorize failed, no permission" {
+			t.Errorf("%s %s by the non-super client = %d %s, want denied", c.method, c.uri, code, body)
+		}
+	}
+	select {
+	case <-ended:
+		t.Error("the watch is disconnected by the non-super client")
+	case <-time.After(100 * time.Millisecond):
+	}
+}
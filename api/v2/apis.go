@@ -0,0 +1,39 @@
+package v2
+
+import (
+	"github.com/laincloud/lainlet/api"
+)
+
+// APIs return the apis served by both the http server and the grpc server, localIP is the ip of the node lainlet running on
+func APIs(localIP string) []api.API {
+	return []api.API{
+		new(AppsData),
+		new(GeneralConfig),
+		new(GeneralPodGroup),
+		new(GeneralCoreInfo),
+		new(GeneralNodes),
+		new(GeneralContainers),
+		new(ProxyData),
+		new(Depends),
+		new(WebrouterInfo),
+		new(StreamRouterInfo),
+		new(Ports),
+		new(RebellionAPIProvider),
+		new(CoreInfoForBackupctl),
+		&LocalSpec{
+			LocalIP: localIP,
+		},
+	}
+}
+
+// RegisterHTTP register the apis to the http server, including the ones only served by http
+func RegisterHTTP(srv *api.Server, apis []api.API) {
+	for _, a := range apis {
+		srv.Register(a)
+	}
+	srv.Get("/appname", GetAppNameAPI)
+	srv.Get("/v2/auth/explain", ExplainAuthAPI)
+	srv.Get("/admin/watches", ListWatchesAPI)
+	srv.Post("/admin/watches/disconnect", DisconnectWatchesAPI)
+	srv.Get("/admin/limits", ListLimitsAPI)
+}
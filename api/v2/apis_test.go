@@ -0,0 +1,218 @@
+package v2_test
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"reflect"
+	"testing"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/laincloud/lainlet/api"
+	"github.com/laincloud/lainlet/api/v2"
+	"github.com/laincloud/lainlet/auth"
+	"github.com/laincloud/lainlet/conf"
+	pb "github.com/laincloud/lainlet/message"
+	grpcserver "github.com/laincloud/lainlet/server"
+	"github.com/laincloud/lainlet/spec"
+	"github.com/laincloud/lainlet/watcher"
+	"github.com/laincloud/lainlet/watcher/container"
+	"github.com/laincloud/lainlet/watcher/depends"
+	"github.com/laincloud/lainlet/watcher/nodes"
+	"github.com/laincloud/lainlet/watcher/podgroup"
+	"github.com/mijia/adoc"
+	"golang.org/x/net/context"
+	"google.golang.org/grpc"
+)
+
+// allowAuth allow everything, all the clients are the app "hello"
+type allowAuth struct{}
+
+func (allowAuth) IPSubject(remoteAddr string) auth.Subject { return auth.Subject{Addr: remoteAddr} }
+func (allowAuth) AppName(remoteIP string) (string, error)  { return "hello", nil }
+func (allowAuth) VerifyToken(token string) (*auth.Claims, error) {
+	return nil, fmt.Errorf("no token is accepted")
+}
+func (allowAuth) Evaluate(s auth.Subject, api, key string) auth.Decision {
+	return auth.Decision{Allowed: true, Rule: -1}
+}
+func (a allowAuth) Explain(s auth.Subject, api, key string) *auth.Explanation {
+	return &auth.Explanation{Subject: s, API: api, Key: key, Decision: a.Evaluate(s, api, key)}
+}
+
+// staticWatcher return the same data for any key, and never change
+type staticWatcher struct {
+	data map[string]interface{}
+}
+
+func (w *staticWatcher) Get(prefix string) (map[string]interface{}, error) { return w.data, nil }
+func (w *staticWatcher) Status() watcher.Status                            { return watcher.Status{Synced: true} }
+
+func (w *staticWatcher) Watch(prefix string, ctx context.Context) (<-chan *watcher.Event, error) {
+	return make(chan *watcher.Event), nil
+}
+
+// testWatchers return the watchers having the data of a app "hello", whose web proc has a container running on this host
+func testWatchers(t *testing.T) map[string]watcher.Watcher {
+	hostname, err := os.Hostname()
+	if err != nil {
+		t.Fatal(err)
+	}
+	pod := spec.Pod{
+		InstanceNo: 1,
+		Containers: []spec.Container{{
+			Id:            "1234567890ab",
+			NodeName:      hostname,
+			NodeIp:        "192.168.77.21",
+			ContainerIp:   "172.20.0.2",
+			ContainerPort: 8080,
+			Runtime:       adoc.ContainerDetail{Config: &adoc.ContainerConfig{Env: []string{"LAIN_PROCNAME=web"}}},
+		}},
+	}
+	pg := podgroup.PodGroup{
+		Spec: spec.PodGroupSpec{
+			ImSpec: spec.ImSpec{Name: "hello.web.web", Namespace: "hello"},
+			Pod: spec.PodSpec{
+				Containers: []spec.ContainerSpec{{
+					Image:  "registry.lain.local/hello:release-1",
+					Env:    []string{"LAIN_APP_RELEASE_VERSION=1"},
+					Expose: 8080,
+				}},
+				Dependencies: []spec.Dependency{{PodName: "redis.portal.portal"}},
+				Annotation:   `{"ports":[{"srcport":9000,"dstport":8080}]}`,
+			},
+			NumInstances: 1,
+		},
+	}
+	pg.Pods = []spec.Pod{pod}
+	return map[string]watcher.Watcher{
+		watcher.CONFIG + "watcher":   &staticWatcher{map[string]interface{}{"vips": `["10.0.0.1"]`}},
+		watcher.PODGROUP + "watcher": &staticWatcher{map[string]interface{}{"hello/hello.web.web": pg}},
+		watcher.CONTAINER + "watcher": &staticWatcher{map[string]interface{}{
+			"192.168.77.21/1234567890ab": container.Info{AppName: "hello", ProcName: "web", NodeName: hostname, IP: "172.20.0.2", Port: 8080, InstanceNo: 1},
+		}},
+		watcher.DEPENDS + "watcher": &staticWatcher{map[string]interface{}{
+			"redis.portal.portal": depends.Depends{
+				hostname: {"hello": spec.SharedPodWithSpec{Spec: spec.PodSpec{Annotation: "portal"}, Pod: pod}},
+			},
+		}},
+		watcher.NODES + "watcher": &staticWatcher{map[string]interface{}{
+			"node1:192.168.77.21:22": nodes.NodeInfo{"name": "node1", "ip": "192.168.77.21"},
+		}},
+	}
+}
+
+// serve start the http server and the grpc server of the apis, the returned function stop them
+func serve(t *testing.T, apis []api.API) (string, *grpc.ClientConn, func()) {
+	watchers, env := testWatchers(t), api.NewEnv(conf.Default())
+	httpSrv, err := api.New("127.0.0.1", "test", watchers, allowAuth{}, env)
+	if err != nil {
+		t.Fatal(err)
+	}
+	v2.RegisterHTTP(httpSrv, apis)
+	ts := httptest.NewServer(httpSrv)
+
+	grpcSrv, err := grpcserver.New("", "127.0.0.1", watchers, nil, allowAuth{}, env)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, a := range apis {
+		grpcSrv.Register(a)
+	}
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	go grpcSrv.Serve(lis)
+	conn, err := grpc.Dial(lis.Addr().String(), grpc.WithInsecure())
+	if err != nil {
+		t.Fatal(err)
+	}
+	return ts.URL, conn, func() {
+		conn.Close()
+		grpcSrv.Stop()
+		ts.Close()
+	}
+}
+
+func httpGet(t *testing.T, uri string) []byte {
+	resp, err := http.Get(uri)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+	body, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("GET %s failed, %d %s", uri, resp.StatusCode, body)
+	}
+	return body
+}
+
+// setAppname set the Appname field of the grpc request if it has one, like the `appname` argument of http
+func setAppname(in proto.Message, appname string) {
+	if f := reflect.ValueOf(in).Elem().FieldByName("Appname"); f.IsValid() {
+		f.SetString(appname)
+	}
+}
+
+// TestHTTPAndGRPC check each api return the same data by http and grpc, the data of http is converted to the grpc reply to compare
+func TestHTTPAndGRPC(t *testing.T) {
+	apis := v2.APIs("192.168.77.21")
+	httpURL, conn, stop := serve(t, apis)
+	defer stop()
+
+	for _, a := range apis {
+		pa := a.(api.ProtoAPI)
+		fromHTTP := reflect.New(reflect.TypeOf(a).Elem())
+		fromHTTP.Elem().Set(reflect.ValueOf(a).Elem()) // keep the settings like LocalIP
+		instance := fromHTTP.Interface().(api.API)
+		if err := instance.Decode(httpGet(t, httpURL+"/v2"+a.URI()+"?appname=hello")); err != nil {
+			t.Errorf("%s: fail to decode the http reply, %s", a.URI(), err.Error())
+			continue
+		}
+		want, err := instance.(api.ProtoAPI).ProtoReply()
+		if err != nil {
+			t.Errorf("%s: %s", a.URI(), err.Error())
+			continue
+		}
+
+		in := pa.ProtoRequest()
+		setAppname(in, "hello")
+		got := reflect.New(reflect.TypeOf(want).Elem()).Interface().(proto.Message)
+		if err := grpc.Invoke(context.Background(), "/"+pa.ServiceName()+"/Get", in, got, conn); err != nil {
+			t.Errorf("%s: the grpc call failed, %s", pa.ServiceName(), err.Error())
+			continue
+		}
+		if !proto.Equal(got, want) {
+			t.Errorf("%s: got %v by grpc, want %v by http", pa.ServiceName(), got, want)
+		}
+		if reflect.DeepEqual(got, reflect.New(reflect.TypeOf(want).Elem()).Interface()) {
+			t.Errorf("%s: got no data", pa.ServiceName())
+		}
+	}
+}
+
+func TestAppnameHTTPAndGRPC(t *testing.T) {
+	httpURL, conn, stop := serve(t, nil)
+	defer stop()
+
+	var data map[string]string
+	if err := json.Unmarshal(httpGet(t, httpURL+"/appname?ip="+url.QueryEscape("172.20.0.2")), &data); err != nil {
+		t.Fatal(err)
+	}
+	rpl, err := pb.NewAppnameClient(conn).Get(context.Background(), &pb.AppnameRequest{Ip: "172.20.0.2"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(rpl.Data, data) || data["appname"] != "hello" {
+		t.Errorf("got %v by grpc and %v by http, want the appname hello", rpl.Data, data)
+	}
+}
@@ -3,19 +3,17 @@ package v2
 import (
 	"encoding/json"
 	"github.com/laincloud/lainlet/api"
-	"github.com/laincloud/lainlet/auth"
-	"net/http"
 )
 
-func GetAppNameAPI(rw http.ResponseWriter, req *http.Request) (int, string) {
-	if !auth.IsSuper(req.RemoteAddr) {
-		return 400, "authorize failed, super required"
-	}
-	appname, err := auth.AppName(api.GetString(req, "ip", req.RemoteAddr))
+func GetAppNameAPI(r *api.Request) (int, string) {
+	data, err := api.AppName(r)
 	if err != nil {
+		if api.IsAuthError(err) {
+			return 400, err.Error()
+		}
 		return 500, err.Error()
 	}
-	content, err := json.Marshal(map[string]string{"appname": appname})
+	content, err := json.Marshal(data)
 	if err != nil {
 		return 500, err.Error()
 	}
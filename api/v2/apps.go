@@ -3,11 +3,11 @@ package v2
 import (
 	"encoding/json"
 	"fmt"
+	"github.com/golang/protobuf/proto"
 	"github.com/laincloud/lainlet/api"
-	"github.com/laincloud/lainlet/auth"
+	pb "github.com/laincloud/lainlet/message"
 	"github.com/laincloud/lainlet/watcher"
 	"github.com/laincloud/lainlet/watcher/podgroup"
-	"net/http"
 	"reflect"
 )
 
@@ -50,9 +50,27 @@ func (ad *AppsData) Make(data map[string]interface{}) (api.API, bool, error) {
 	return ret, !reflect.DeepEqual(ad.Data, ret.Data), nil
 }
 
-func (ad *AppsData) Key(r *http.Request) (string, error) {
-	if !auth.IsSuper(r.RemoteAddr) {
-		return "", fmt.Errorf("authorize failed, super required")
+func (ad *AppsData) Key(r *api.Request) (string, error) {
+	if !r.Allowed(ad.URI(), "*") {
+		return "", fmt.Errorf("authorize failed, no permission")
 	}
 	return "*", nil
 }
+
+func (ad *AppsData) ServiceName() string {
+	return "message.Apps"
+}
+
+func (ad *AppsData) ProtoRequest() proto.Message {
+	return new(pb.AppsRequest)
+}
+
+func (ad *AppsData) ProtoReply() (proto.Message, error) {
+	ret := &pb.AppsReply{
+		Data: make(map[string]*pb.AppInfo),
+	}
+	for k, v := range ad.Data {
+		ret.Data[k] = &pb.AppInfo{Appname: v.Appname}
+	}
+	return ret, nil
+}
@@ -0,0 +1,34 @@
+package v2
+
+import (
+	"encoding/json"
+	"github.com/laincloud/lainlet/api"
+	"strings"
+)
+
+// ExplainAuthAPI explain how the policy decide whether the client can visit a api.
+// The arguments are `api`, the uri of the api like "/coreinfowatcher", `ip`, the ip of the client, the caller itself by default,
+// and `app`, the app or the key visited, "*" by default, `key` can be used instead for the apis whose key is not a app, like the config target.
+func ExplainAuthAPI(r *api.Request) (int, string) {
+	if !r.Allowed("/auth/explain", "*") {
+		return 400, "authorize failed, no permission"
+	}
+	uri := strings.TrimPrefix(r.GetString("api", ""), "/v2")
+	if uri == "" {
+		return 400, "api required"
+	}
+	if uri[0] != '/' {
+		uri = "/" + uri
+	}
+	key := r.GetString("key", r.GetString("app", "*"))
+
+	subject := r.Subject()
+	if ip := r.GetString("ip", ""); ip != "" {
+		subject = r.Auth.IPSubject(ip)
+	}
+	content, err := json.Marshal(r.Auth.Explain(subject, uri, key))
+	if err != nil {
+		return 500, err.Error()
+	}
+	return 200, string(content)
+}
@@ -0,0 +1,152 @@
+package v2_test
+
+import (
+	"encoding/json"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/laincloud/lainlet/api"
+	"github.com/laincloud/lainlet/api/v2"
+	"github.com/laincloud/lainlet/auth"
+	"github.com/laincloud/lainlet/conf"
+	"github.com/laincloud/lainlet/store"
+	"golang.org/x/net/context"
+)
+
+// fixtureStore serve the pairs having the key prefix, the watches send nothing
+type fixtureStore struct {
+	store.Store
+	pairs []*store.KVPair
+}
+
+func (s fixtureStore) GetTree(key string) ([]*store.KVPair, error) {
+	var ret []*store.KVPair
+	for _, kv := range s.pairs {
+		if strings.HasPrefix(kv.Key, key+"/") {
+			ret = append(ret, kv)
+		}
+	}
+	if len(ret) == 0 {
+		return nil, store.ErrKeyNotFound
+	}
+	return ret, nil
+}
+
+func (s fixtureStore) Watch(key string, ctx context.Context, recursive bool, index uint64) (<-chan *store.Event, error) {
+	return make(chan *store.Event), nil
+}
+
+// newStoreAuthorizer return the authorizer with the default policy, knowing the app hello at 172.20.0.2
+func newStoreAuthorizer(t *testing.T, ctx context.Context) *auth.StoreAuthorizer {
+	cfg := conf.Default()
+	cfg.IP = "10.0.0.1"
+	a, err := auth.New(fixtureStore{pairs: []*store.KVPair{{
+		Key:   cfg.Keys.PodGroups + "/hello/hello.proc.web",
+		Value: []byte(`{"Spec": {"Name": "hello.proc.web", "Namespace": "hello"}, "Pods": [{"Containers": [{"Id": "c1", "ContainerIp": "172.20.0.2"}]}]}`),
+	}}}, ctx, cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	deadline := time.Now().Add(5 * time.Second)
+	for a.IPSubject("172.20.0.2").App != "hello" {
+		if time.Now().After(deadline) {
+			t.Fatal("the podgroups are not read by the authorizer")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	return a
+}
+
+func TestExplainAuth(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	srv, err := api.New("10.0.0.1", "test", testWatchers(t), newStoreAuthorizer(t, ctx), api.NewEnv(conf.Default()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	v2.RegisterHTTP(srv, nil)
+	explain := func(remoteAddr, query string) (int, string) {
+		req := httptest.NewRequest("GET", "/v2/auth/explain?"+query, nil)
+		req.RemoteAddr = remoteAddr
+		rec := httptest.NewRecorder()
+		srv.ServeHTTP(rec, req)
+		return rec.Code, rec.Body.String()
+	}
+
+	// only the super clients can explain
+	if code, body := explain("172.20.0.2:41234", "api=/procwatcher"); code != 400 || body != "authorize failed, no permission" {
+		t.Errorf("explained by the app hello = %d %s, want denied", code, body)
+	}
+	if code, body := explain("127.0.0.1:41234", "app=hello"); code != 400 || body != "api required" {
+		t.Errorf("explained without api = %d %s", code, body)
+	}
+
+	no := auth.Check{Allow: "role:super", Reason: "hello is not in super_apps and does not have role super"}
+	cases := []struct {
+		query string
+		want  auth.Explanation
+	}{
+		{
+			// the ip is resolved to the app, and the rules not matching the api are skipped
+			"api=/v2/procwatcher&ip=172.20.0.2&app=hello",
+			auth.Explanation{
+				Subject: auth.Subject{App: "hello", Addr: "172.20.0.2", Via: "podgroup"},
+				API:     "/procwatcher",
+				Key:     "hello",
+				Steps: []auth.Step{
+					{Rule: 0},
+					{Rule: 1},
+					{Rule: 2, Matched: true, Checks: []auth.Check{no, {Allow: "self", Passed: true, Reason: "app hello is visiting itself"}}},
+				},
+				Decision: auth.Decision{Allowed: true, Source: auth.PolicySourceDefault, Rule: 2, Allow: "self"},
+			},
+		},
+		{
+			"api=webrouter/webprocs&ip=172.20.0.2&app=world",
+			auth.Explanation{
+				Subject: auth.Subject{App: "hello", Addr: "172.20.0.2", Via: "podgroup"},
+				API:     "/webrouter/webprocs",
+				Key:     "world",
+				Steps: []auth.Step{
+					{Rule: 0},
+					{Rule: 1},
+					{Rule: 2, Matched: true, Checks: []auth.Check{
+						no,
+						{Allow: "self", Reason: "app hello is not world"},
+						{Allow: "depends", Reason: "hello does not depend on world"},
+					}},
+				},
+				Decision: auth.Decision{Source: auth.PolicySourceDefault, Rule: 2},
+			},
+		},
+		{
+			// the caller itself by default, the key can be used instead of app
+			"api=/configwatcher&key=vips",
+			auth.Explanation{
+				Subject: auth.Subject{Roles: []string{auth.RoleSuper}, Addr: "127.0.0.1:41234", Via: "local ip"},
+				API:     "/configwatcher",
+				Key:     "vips",
+				Steps: []auth.Step{
+					{Rule: 0, Matched: true, Checks: []auth.Check{{Allow: "role:super", Passed: true, Reason: "role super has role super by local ip"}}},
+				},
+				Decision: auth.Decision{Allowed: true, Source: auth.PolicySourceDefault, Rule: 0, Allow: "role:super"},
+			},
+		},
+	}
+	for _, c := range cases {
+		code, body := explain("127.0.0.1:41234", c.query)
+		if code != 200 {
+			t.Errorf("explain %s = %d %s", c.query, code, body)
+			continue
+		}
+		want, err := json.Marshal(&c.want)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if body != string(want) {
+			t.Errorf("explain %s got\n%s\nwant\n%s", c.query, body, want)
+		}
+	}
+}
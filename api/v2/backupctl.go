@@ -3,11 +3,12 @@ package v2
 import (
 	"encoding/json"
 	"fmt"
+	"github.com/golang/protobuf/proto"
 	"github.com/laincloud/lainlet/api"
-	"github.com/laincloud/lainlet/auth"
+	"github.com/laincloud/lainlet/fieldmask"
+	pb "github.com/laincloud/lainlet/message"
 	"github.com/laincloud/lainlet/watcher"
 	"github.com/laincloud/lainlet/watcher/podgroup"
-	"net/http"
 	"reflect"
 )
 
@@ -26,6 +27,7 @@ type PodInfoForBackupctl struct {
 
 type CoreInfoForBackupctl struct {
 	Data map[string][]PodInfoForBackupctl
+	mask fieldmask.Mask // the fields kept by Project
 }
 
 func (ci *CoreInfoForBackupctl) Decode(r []byte) error {
@@ -33,7 +35,15 @@ func (ci *CoreInfoForBackupctl) Decode(r []byte) error {
 }
 
 func (ci *CoreInfoForBackupctl) Encode() ([]byte, error) {
-	return json.Marshal(ci.Data)
+	return json.Marshal(fieldmask.Select(ci.Data, ci.mask))
+}
+
+func (ci *CoreInfoForBackupctl) Project(mask fieldmask.Mask) (api.API, error) {
+	ret := &CoreInfoForBackupctl{mask: mask}
+	if err := project(ci.Data, mask, &ret.Data); err != nil {
+		return nil, err
+	}
+	return ret, nil
 }
 
 func (ci *CoreInfoForBackupctl) URI() string {
@@ -71,13 +81,47 @@ func (ci *CoreInfoForBackupctl) Make(data map[string]interface{}) (api.API, bool
 	return ret, !reflect.DeepEqual(ci.Data, ret.Data), nil
 }
 
-func (ci *CoreInfoForBackupctl) Key(r *http.Request) (string, error) {
-	if !auth.IsSuper(r.RemoteAddr) {
-		return "", fmt.Errorf("authorize failed, super required")
+func (ci *CoreInfoForBackupctl) Key(r *api.Request) (string, error) {
+	appName := r.GetString("appname", "*")
+	if !r.Allowed(ci.URI(), appName) {
+		return "", fmt.Errorf("authorize failed, no permission")
 	}
-	appName := api.GetString(r, "appname", "*")
 	if appName != "*" {
 		appName = fixPrefix(appName)
 	}
 	return appName, nil
 }
+
+func (ci *CoreInfoForBackupctl) ServiceName() string {
+	return "message.Backupctl"
+}
+
+func (ci *CoreInfoForBackupctl) ProtoRequest() proto.Message {
+	return new(pb.BackupctlRequest)
+}
+
+func (ci *CoreInfoForBackupctl) ProtoReply() (proto.Message, error) {
+	ret := &pb.BackupctlReply{
+		Data: make(map[string]*pb.BackupctlReply_PodInfoList),
+	}
+	for name, pis := range ci.Data {
+		infos := make([]*pb.PodInfoForBackupctl, len(pis))
+		for i, pi := range pis {
+			infos[i] = &pb.PodInfoForBackupctl{
+				Annotation: pi.Annotation,
+				InstanceNo: int32(pi.InstanceNo),
+				Containers: make([]*pb.ContainerForBackupctl, len(pi.Containers)),
+			}
+			for j, c := range pi.Containers {
+				infos[i].Containers[j] = &pb.ContainerForBackupctl{
+					Id:       c.Id,
+					Ip:       c.Ip,
+					NodeIp:   c.NodeIp,
+					NodeName: c.NodeName,
+				}
+			}
+		}
+		ret.Data[name] = &pb.BackupctlReply_PodInfoList{Pods: infos}
+	}
+	return ret, nil
+}
@@ -3,46 +3,13 @@ package v2
 import (
 	"encoding/json"
 	"fmt"
+	"github.com/golang/protobuf/proto"
 	"github.com/laincloud/lainlet/api"
-	"github.com/laincloud/lainlet/auth"
+	pb "github.com/laincloud/lainlet/message"
 	"github.com/laincloud/lainlet/watcher"
-	"net/http"
 	"reflect"
-	"strings"
 )
 
-var (
-	secretKeys = []string{
-		"*",
-		"swarm_manager_ip",
-		"super_apps",
-		"dnsmasq_servers",
-		"calico_default_rule",
-		"calico_network",
-		"dnsmasq_addresses",
-		"ssl",
-		"vips",
-		"tinydns_fqdns",
-		"bootstrap_node_ip",
-		"dns_port",
-		"vip",
-		"etcd_cluster_token",
-		"system_volumes",
-		"rsyncd_secrets",
-		"dns_ip",
-		"node_network",
-	}
-)
-
-func isSecret(key string) bool {
-	for _, sk := range secretKeys {
-		if strings.HasPrefix(key, sk) {
-			return true
-		}
-	}
-	return false
-}
-
 // Config API
 type GeneralConfig struct {
 	Data map[string]string // data type return by configwatcher
@@ -75,10 +42,22 @@ func (gc *GeneralConfig) Make(conf map[string]interface{}) (api.API, bool, error
 	return ret, !reflect.DeepEqual(gc.Data, ret.Data), nil
 }
 
-func (gc *GeneralConfig) Key(r *http.Request) (string, error) {
-	target := api.GetString(r, "target", "*")
-	if isSecret(target) && !auth.IsSuper(r.RemoteAddr) {
-		return "", fmt.Errorf("authorize failed, super required")
+func (gc *GeneralConfig) Key(r *api.Request) (string, error) {
+	target := r.GetString("target", "*")
+	if !r.Allowed(gc.URI(), target) {
+		return "", fmt.Errorf("authorize failed, no permission")
 	}
 	return target, nil
 }
+
+func (gc *GeneralConfig) ServiceName() string {
+	return "message.Config"
+}
+
+func (gc *GeneralConfig) ProtoRequest() proto.Message {
+	return new(pb.ConfigRequest)
+}
+
+func (gc *GeneralConfig) ProtoReply() (proto.Message, error) {
+	return &pb.ConfigReply{Data: gc.Data}, nil
+}
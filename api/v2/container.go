@@ -3,11 +3,11 @@ package v2
 import (
 	"encoding/json"
 	"fmt"
+	"github.com/golang/protobuf/proto"
 	"github.com/laincloud/lainlet/api"
-	"github.com/laincloud/lainlet/auth"
+	pb "github.com/laincloud/lainlet/message"
 	"github.com/laincloud/lainlet/watcher"
 	"github.com/laincloud/lainlet/watcher/container"
-	"net/http"
 )
 
 // node watcher api, /lain/nodes/nodes
@@ -44,13 +44,40 @@ func (gc *GeneralContainers) Make(data map[string]interface{}) (api.API, bool, e
 	return ret, true, nil
 }
 
-func (gc *GeneralContainers) Key(r *http.Request) (string, error) {
-	if !auth.IsSuper(r.RemoteAddr) {
-		return "", fmt.Errorf("authorize failed, super required")
+func (gc *GeneralContainers) Key(r *api.Request) (string, error) {
+	target := r.GetString("nodename", "*")
+	if !r.Allowed(gc.URI(), target) {
+		return "", fmt.Errorf("authorize failed, no permission")
 	}
-	target := api.GetString(r, "nodename", "*")
 	if target != "*" {
 		target = fixPrefix(target)
 	}
 	return target, nil
 }
+
+func (gc *GeneralContainers) ServiceName() string {
+	return "message.Containers"
+}
+
+func (gc *GeneralContainers) ProtoRequest() proto.Message {
+	return new(pb.ContainersRequest)
+}
+
+func (gc *GeneralContainers) ProtoReply() (proto.Message, error) {
+	ret := &pb.ContainersReply{
+		Data: make(map[string]*pb.Info),
+	}
+	for k, cinfo := range gc.Data {
+		ret.Data[k] = &pb.Info{
+			AppName:    cinfo.AppName,
+			AppVersion: cinfo.AppVersion,
+			ProcName:   cinfo.ProcName,
+			NodeName:   cinfo.NodeName,
+			NodeIP:     cinfo.NodeIP,
+			IP:         cinfo.IP,
+			Port:       int32(cinfo.Port),
+			InstanceNo: int32(cinfo.InstanceNo),
+		}
+	}
+	return ret, nil
+}
@@ -2,12 +2,12 @@ package v2
 
 import (
 	"encoding/json"
-	"fmt"
+	"github.com/golang/protobuf/proto"
 	"github.com/laincloud/lainlet/api"
-	"github.com/laincloud/lainlet/auth"
+	"github.com/laincloud/lainlet/fieldmask"
+	pb "github.com/laincloud/lainlet/message"
 	"github.com/laincloud/lainlet/watcher"
 	"github.com/laincloud/lainlet/watcher/podgroup"
-	"net/http"
 	"reflect"
 )
 
@@ -35,6 +35,8 @@ type PodInfo struct {
 	Containers   []Container `json:"ContainerInfos"`
 	Dependencies []Dependency
 	InstanceNo   int
+	Healthst     int // the health state of the pod, see spec.HealthState
+	State        int // the run state of the pod, see spec.RunState
 }
 
 // Coreinfo type
@@ -45,6 +47,7 @@ type CoreInfo struct {
 // Coreinfo API
 type GeneralCoreInfo struct {
 	Data map[string]CoreInfo
+	mask fieldmask.Mask // the fields kept by Project
 }
 
 func (gci *GeneralCoreInfo) Decode(r []byte) error {
@@ -52,7 +55,15 @@ func (gci *GeneralCoreInfo) Decode(r []byte) error {
 }
 
 func (gci *GeneralCoreInfo) Encode() ([]byte, error) {
-	return json.Marshal(gci.Data)
+	return json.Marshal(fieldmask.Select(gci.Data, gci.mask))
+}
+
+func (gci *GeneralCoreInfo) Project(mask fieldmask.Mask) (api.API, error) {
+	ret := &GeneralCoreInfo{mask: mask}
+	if err := project(gci.Data, mask, &ret.Data); err != nil {
+		return nil, err
+	}
+	return ret, nil
 }
 
 func (gci *GeneralCoreInfo) URI() string {
@@ -76,6 +87,8 @@ func (gci *GeneralCoreInfo) Make(data map[string]interface{}) (api.API, bool, er
 			ci.PodInfos[i] = PodInfo{
 				Annotation:   pg.Spec.Pod.Annotation,
 				InstanceNo:   pod.InstanceNo,
+				Healthst:     int(pod.Healthst),
+				State:        int(pod.State),
 				Containers:   make([]Container, len(pod.Containers)),
 				Dependencies: make([]Dependency, len(pg.Spec.Pod.Dependencies)),
 			}
@@ -106,20 +119,58 @@ func (gci *GeneralCoreInfo) Make(data map[string]interface{}) (api.API, bool, er
 	return ret, !reflect.DeepEqual(ret.Data, gci.Data), nil
 }
 
-func (gci *GeneralCoreInfo) Key(r *http.Request) (string, error) {
-	appName := api.GetString(r, "appname", "*")
-	if !auth.Pass(r.RemoteAddr, appName) {
-		if appName == "*" { // try to set the appname automatically by remoteip
-			appName, err := auth.AppName(r.RemoteAddr)
-			if err != nil {
-				return "", fmt.Errorf("authorize failed, can not confirm the app by request ip")
+func (gci *GeneralCoreInfo) Key(r *api.Request) (string, error) {
+	return appKey(r, gci.URI())
+}
+
+func (gci *GeneralCoreInfo) ServiceName() string {
+	return "message.Coreinfo"
+}
+
+func (gci *GeneralCoreInfo) ProtoRequest() proto.Message {
+	return new(pb.CoreinfoRequest)
+}
+
+func (gci *GeneralCoreInfo) ProtoReply() (proto.Message, error) {
+	ret := &pb.CoreinfoReply{
+		Data: make(map[string]*pb.CoreInfo),
+	}
+	for name, ci := range gci.Data {
+		pci := &pb.CoreInfo{
+			PodInfos: make([]*pb.PodInfo, len(ci.PodInfos)),
+		}
+		for i, pi := range ci.PodInfos {
+			pci.PodInfos[i] = &pb.PodInfo{
+				Annotation:   pi.Annotation,
+				InstanceNo:   int32(pi.InstanceNo),
+				Healthst:     int32(pi.Healthst),
+				State:        int32(pi.State),
+				Containers:   make([]*pb.Container, len(pi.Containers)),
+				Dependencies: make([]*pb.Dependency, len(pi.Dependencies)),
+			}
+			for j, c := range pi.Containers {
+				pci.PodInfos[i].Containers[j] = &pb.Container{
+					Command:  c.Command,
+					Id:       c.Id,
+					Ip:       c.Ip,
+					Cpu:      int32(c.Cpu),
+					Env:      c.Env,
+					Expose:   int32(c.Expose),
+					Image:    c.Image,
+					Memory:   c.Memory,
+					NodeIp:   c.NodeIp,
+					NodeName: c.NodeName,
+					Volumes:  c.Volumes,
+				}
+			}
+			for k, depend := range pi.Dependencies {
+				pci.PodInfos[i].Dependencies[k] = &pb.Dependency{
+					PodName: depend.PodName,
+					Policy:  int32(depend.Policy),
+				}
 			}
-			return fixPrefix(appName), nil
 		}
-		return "", fmt.Errorf("authorize failed, no permission")
-	}
-	if appName != "*" {
-		appName = fixPrefix(appName)
+		ret.Data[name] = pci
 	}
-	return appName, nil
+	return ret, nil
 }
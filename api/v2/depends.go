@@ -3,11 +3,12 @@ package v2
 import (
 	"encoding/json"
 	"fmt"
+	"github.com/golang/protobuf/proto"
 	"github.com/laincloud/lainlet/api"
-	"github.com/laincloud/lainlet/auth"
+	"github.com/laincloud/lainlet/fieldmask"
+	pb "github.com/laincloud/lainlet/message"
 	"github.com/laincloud/lainlet/watcher"
 	"github.com/laincloud/lainlet/watcher/depends"
-	"net/http"
 	"reflect"
 )
 
@@ -27,6 +28,7 @@ type DependsItem struct {
 // Depends API
 type Depends struct {
 	Data map[string]map[string]map[string]DependsItem
+	mask fieldmask.Mask // the fields kept by Project
 }
 
 func (d *Depends) Decode(r []byte) error {
@@ -34,7 +36,15 @@ func (d *Depends) Decode(r []byte) error {
 }
 
 func (d *Depends) Encode() ([]byte, error) {
-	return json.Marshal(d.Data)
+	return json.Marshal(fieldmask.Select(d.Data, d.mask))
+}
+
+func (d *Depends) Project(mask fieldmask.Mask) (api.API, error) {
+	ret := &Depends{mask: mask}
+	if err := project(d.Data, mask, &ret.Data); err != nil {
+		return nil, err
+	}
+	return ret, nil
 }
 
 func (d *Depends) URI() string {
@@ -74,9 +84,51 @@ func (d *Depends) Make(data map[string]interface{}) (api.API, bool, error) {
 	return ret, !reflect.DeepEqual(ret.Data, d.Data), nil
 }
 
-func (d *Depends) Key(r *http.Request) (string, error) {
-	if !auth.IsSuper(r.RemoteAddr) {
-		return "", fmt.Errorf("authorize failed, super required")
+func (d *Depends) Key(r *api.Request) (string, error) {
+	target := r.GetString("target", "*")
+	if !r.Allowed(d.URI(), target) {
+		return "", fmt.Errorf("authorize failed, no permission")
+	}
+	return target, nil
+}
+
+func (d *Depends) ServiceName() string {
+	return "message.Depends"
+}
+
+func (d *Depends) ProtoRequest() proto.Message {
+	return new(pb.DependsRequest)
+}
+
+func (d *Depends) ProtoReply() (proto.Message, error) {
+	ret := &pb.DependsReply{
+		Data: make(map[string]*pb.DependsNodeMap),
+	}
+	for key, nodes := range d.Data {
+		ret.Data[key] = &pb.DependsNodeMap{
+			Nodes: make(map[string]*pb.DependsAppMap),
+		}
+		for nodeName, apps := range nodes {
+			depApp := &pb.DependsAppMap{
+				Apps: make(map[string]*pb.DependsItem),
+			}
+			ret.Data[key].Nodes[nodeName] = depApp
+			for app, item := range apps {
+				containers := make([]*pb.ContainerInfo, len(item.Containers))
+				for i, c := range item.Containers {
+					containers[i] = &pb.ContainerInfo{
+						ContainerID: c.ContainerID,
+						NodeIP:      c.NodeIP,
+						IP:          c.IP,
+						Port:        int32(c.Port),
+					}
+				}
+				depApp.Apps[app] = &pb.DependsItem{
+					Annotation: item.Annotation,
+					Containers: containers,
+				}
+			}
+		}
 	}
-	return api.GetString(r, "target", "*"), nil
+	return ret, nil
 }
@@ -3,11 +3,12 @@ package v2
 import (
 	"encoding/json"
 	"fmt"
+	"github.com/golang/protobuf/proto"
 	"github.com/laincloud/lainlet/api"
-	"github.com/laincloud/lainlet/auth"
+	"github.com/laincloud/lainlet/ipaddr"
+	pb "github.com/laincloud/lainlet/message"
 	"github.com/laincloud/lainlet/watcher"
 	"github.com/laincloud/lainlet/watcher/container"
-	"net/http"
 	"reflect"
 )
 
@@ -36,7 +37,8 @@ func (ls *LocalSpec) WatcherName() string {
 
 func (ls *LocalSpec) Make(data map[string]interface{}) (api.API, bool, error) {
 	ret := &LocalSpec{
-		Data: make([]string, 0),
+		Data:    make([]string, 0),
+		LocalIP: ls.LocalIP,
 	}
 	// merge the repeat item, so using map
 	set := make(map[string]bool)
@@ -50,12 +52,25 @@ func (ls *LocalSpec) Make(data map[string]interface{}) (api.API, bool, error) {
 	return ret, !reflect.DeepEqual(ret.Data, ls.Data), nil
 }
 
-func (ls *LocalSpec) Key(r *http.Request) (string, error) {
-	if !auth.IsSuper(r.RemoteAddr) {
-		return "", fmt.Errorf("authorize failed, super required")
+func (ls *LocalSpec) Key(r *api.Request) (string, error) {
+	nodeIP := ipaddr.Normalize(r.GetString("nodeip", ls.LocalIP))
+	if !r.Allowed(ls.URI(), nodeIP) {
+		return "", fmt.Errorf("authorize failed, no permission")
 	}
-	return fixPrefix(api.GetString(r, "nodeip", ls.LocalIP)), nil
+	return fixPrefix(nodeIP), nil
 }
 
 // to realize BanWatcher interface, abandon watch action
 func (ls *LocalSpec) BanWatch() {}
+
+func (ls *LocalSpec) ServiceName() string {
+	return "message.Localspec"
+}
+
+func (ls *LocalSpec) ProtoRequest() proto.Message {
+	return new(pb.LocalspecRequest)
+}
+
+func (ls *LocalSpec) ProtoReply() (proto.Message, error) {
+	return &pb.LocalspecReply{Data: ls.Data, LocalIP: ls.LocalIP}, nil
+}
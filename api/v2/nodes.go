@@ -3,11 +3,11 @@ package v2
 import (
 	"encoding/json"
 	"fmt"
+	"github.com/golang/protobuf/proto"
 	"github.com/laincloud/lainlet/api"
-	"github.com/laincloud/lainlet/auth"
+	pb "github.com/laincloud/lainlet/message"
 	"github.com/laincloud/lainlet/watcher"
 	"github.com/laincloud/lainlet/watcher/nodes"
-	"net/http"
 	"reflect"
 )
 
@@ -42,13 +42,49 @@ func (gn *GeneralNodes) Make(data map[string]interface{}) (api.API, bool, error)
 	return ret, !reflect.DeepEqual(gn.Data, ret.Data), nil
 }
 
-func (gn *GeneralNodes) Key(r *http.Request) (string, error) {
-	if !auth.IsSuper(r.RemoteAddr) {
-		return "", fmt.Errorf("authorize failed, super required")
+func (gn *GeneralNodes) Key(r *api.Request) (string, error) {
+	target := r.GetString("name", "*")
+	if !r.Allowed(gn.URI(), target) {
+		return "", fmt.Errorf("authorize failed, no permission")
 	}
-	target := api.GetString(r, "name", "*")
 	if target != "*" && target[len(target)-1] != ':' {
 		target = target + ":"
 	}
 	return target, nil
 }
+
+func (gn *GeneralNodes) ServiceName() string {
+	return "message.Nodes"
+}
+
+func (gn *GeneralNodes) ProtoRequest() proto.Message {
+	return new(pb.NodesRequest)
+}
+
+func (gn *GeneralNodes) ProtoReply() (proto.Message, error) {
+	ret := &pb.NodesReply{
+		Data: make(map[string]*pb.NodeInfo),
+	}
+	for k, ni := range gn.Data {
+		pbNi := &pb.NodeInfo{
+			V: make(map[string]*pb.NodeInfo_Value),
+		}
+		for niK, niV := range ni {
+			var pbV *pb.NodeInfo_Value
+			if sval, ok := niV.(string); ok {
+				pbV = &pb.NodeInfo_Value{
+					Vtype: pb.NodeInfo_Value_STRING,
+					Sval:  sval,
+				}
+			} else if mval, ok := niV.(map[string]string); ok {
+				pbV = &pb.NodeInfo_Value{
+					Vtype: pb.NodeInfo_Value_MAP,
+					Mval:  mval,
+				}
+			}
+			pbNi.V[niK] = pbV
+		}
+		ret.Data[k] = pbNi
+	}
+	return ret, nil
+}
@@ -3,11 +3,11 @@ package v2
 import (
 	"encoding/json"
 	"fmt"
+	"github.com/golang/protobuf/proto"
 	"github.com/laincloud/lainlet/api"
-	"github.com/laincloud/lainlet/auth"
+	pb "github.com/laincloud/lainlet/message"
 	"github.com/laincloud/lainlet/watcher"
 	"github.com/laincloud/lainlet/watcher/podgroup"
-	"net/http"
 	"reflect"
 	"strings"
 )
@@ -73,12 +73,12 @@ func (gpg *GeneralPodGroup) Make(data map[string]interface{}) (api.API, bool, er
 	return ret, !reflect.DeepEqual(ret.Data, gpg.Data), nil
 }
 
-func (gpg *GeneralPodGroup) Key(r *http.Request) (string, error) {
-	appName := api.GetString(r, "appname", "")
+func (gpg *GeneralPodGroup) Key(r *api.Request) (string, error) {
+	appName := r.GetString("appname", "")
 	if appName == "" {
 		return "", fmt.Errorf("appname required")
 	}
-	if !auth.Pass(r.RemoteAddr, appName) {
+	if !r.Allowed(gpg.URI(), appName) {
 		return "", fmt.Errorf("authorize failed, no permission")
 	}
 	if appName != "*" {
@@ -86,3 +86,31 @@ func (gpg *GeneralPodGroup) Key(r *http.Request) (string, error) {
 	}
 	return appName, nil
 }
+
+func (gpg *GeneralPodGroup) ServiceName() string {
+	return "message.Podgroup"
+}
+
+func (gpg *GeneralPodGroup) ProtoRequest() proto.Message {
+	return new(pb.PodgroupRequest)
+}
+
+func (gpg *GeneralPodGroup) ProtoReply() (proto.Message, error) {
+	ret := &pb.PodgroupReply{
+		Data: make([]*pb.PodGroup, len(gpg.Data)),
+	}
+	for i, pg := range gpg.Data {
+		ret.Data[i] = &pb.PodGroup{
+			Pods: make([]*pb.Pod, len(pg.Pods)),
+		}
+		for j, pod := range pg.Pods {
+			ret.Data[i].Pods[j] = &pb.Pod{
+				ProcName:   pod.ProcName,
+				InstanceNo: int32(pod.InstanceNo),
+				IP:         pod.IP,
+				Port:       int32(pod.Port),
+			}
+		}
+	}
+	return ret, nil
+}
@@ -2,12 +2,12 @@ package v2
 
 import (
 	"encoding/json"
-	"fmt"
+	"github.com/golang/protobuf/proto"
 	"github.com/laincloud/lainlet/api"
-	"github.com/laincloud/lainlet/auth"
+	"github.com/laincloud/lainlet/fieldmask"
+	pb "github.com/laincloud/lainlet/message"
 	"github.com/laincloud/lainlet/watcher"
 	"github.com/laincloud/lainlet/watcher/podgroup"
-	"net/http"
 	"reflect"
 )
 
@@ -24,6 +24,7 @@ type ProcInfo struct {
 // Proxy API
 type ProxyData struct {
 	Data map[string]ProcInfo
+	mask fieldmask.Mask // the fields kept by Project
 }
 
 func (pd *ProxyData) Decode(r []byte) error {
@@ -31,7 +32,15 @@ func (pd *ProxyData) Decode(r []byte) error {
 }
 
 func (pd *ProxyData) Encode() ([]byte, error) {
-	return json.Marshal(pd.Data)
+	return json.Marshal(fieldmask.Select(pd.Data, pd.mask))
+}
+
+func (pd *ProxyData) Project(mask fieldmask.Mask) (api.API, error) {
+	ret := &ProxyData{mask: mask}
+	if err := project(pd.Data, mask, &ret.Data); err != nil {
+		return nil, err
+	}
+	return ret, nil
 }
 
 func (pd *ProxyData) URI() string {
@@ -65,20 +74,33 @@ func (pd *ProxyData) Make(data map[string]interface{}) (api.API, bool, error) {
 	return ret, !reflect.DeepEqual(pd.Data, ret.Data), nil
 }
 
-func (pd *ProxyData) Key(r *http.Request) (string, error) {
-	appName := api.GetString(r, "appname", "*")
-	if !auth.Pass(r.RemoteAddr, appName) {
-		if appName == "*" { // try to set the appname automatically by remoteip
-			appName, err := auth.AppName(r.RemoteAddr)
-			if err != nil {
-				return "", fmt.Errorf("authorize failed, can not confirm the app by request ip")
+func (pd *ProxyData) Key(r *api.Request) (string, error) {
+	return appKey(r, pd.URI())
+}
+
+func (pd *ProxyData) ServiceName() string {
+	return "message.Proxy"
+}
+
+func (pd *ProxyData) ProtoRequest() proto.Message {
+	return new(pb.ProxyRequest)
+}
+
+func (pd *ProxyData) ProtoReply() (proto.Message, error) {
+	ret := &pb.ProxyReply{
+		Data: make(map[string]*pb.ProcInfo),
+	}
+	for name, pi := range pd.Data {
+		ppi := &pb.ProcInfo{
+			Containers: make([]*pb.ContainerForProxy, len(pi.Containers)),
+		}
+		for i, c := range pi.Containers {
+			ppi.Containers[i] = &pb.ContainerForProxy{
+				ContainerIp:   c.ContainerIp,
+				ContainerPort: int32(c.ContainerPort),
 			}
-			return fixPrefix(appName), nil
 		}
-		return "", fmt.Errorf("authorize failed, no permission")
-	}
-	if appName != "*" {
-		appName = fixPrefix(appName)
+		ret.Data[name] = ppi
 	}
-	return appName, nil
+	return ret, nil
 }
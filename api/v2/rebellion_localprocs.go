@@ -2,13 +2,12 @@ package v2
 
 import (
 	"encoding/json"
-	"fmt"
-	"net/http"
 	"os"
 	"reflect"
 
+	"github.com/golang/protobuf/proto"
 	"github.com/laincloud/lainlet/api"
-	"github.com/laincloud/lainlet/auth"
+	pb "github.com/laincloud/lainlet/message"
 	"github.com/laincloud/lainlet/watcher"
 	"github.com/laincloud/lainlet/watcher/podgroup"
 	"strings"
@@ -95,21 +94,34 @@ func (ap *RebellionAPIProvider) Make(data map[string]interface{}) (api.API, bool
 	return ret, !reflect.DeepEqual(ap.Data, ret.Data), nil
 }
 
-func (ap *RebellionAPIProvider) Key(r *http.Request) (string, error) {
-	appName := api.GetString(r, "appname", "*")
-	var err error
-	if !auth.Pass(r.RemoteAddr, appName) {
-		if appName == "*" { // try to set the appname automatically by remoteip
-			appName, err = auth.AppName(r.RemoteAddr)
-			if err != nil {
-				return "", fmt.Errorf("authorize failed, can not confirm the app by request ip")
+func (ap *RebellionAPIProvider) Key(r *api.Request) (string, error) {
+	return appKey(r, ap.URI())
+}
+
+func (ap *RebellionAPIProvider) ServiceName() string {
+	return "message.RebellionLocalprocs"
+}
+
+func (ap *RebellionAPIProvider) ProtoRequest() proto.Message {
+	return new(pb.RebellionLocalprocsRequest)
+}
+
+func (ap *RebellionAPIProvider) ProtoReply() (proto.Message, error) {
+	ret := &pb.RebellionLocalprocsReply{
+		Data: make(map[string]*pb.CoreInfoForRebellion),
+	}
+	for name, ci := range ap.Data {
+		pci := &pb.CoreInfoForRebellion{
+			PodInfos: make([]*pb.PodInfoForRebellion, len(ci.PodInfos)),
+		}
+		for i, pi := range ci.PodInfos {
+			pci.PodInfos[i] = &pb.PodInfoForRebellion{
+				Annotation: pi.Annotation,
+				InstanceNo: int32(pi.InstanceNo),
+				AppVersion: pi.AppVersion,
 			}
-			return fixPrefix(appName), nil
 		}
-		return "", fmt.Errorf("authorize failed, no permission")
-	}
-	if appName != "*" {
-		appName = fixPrefix(appName)
+		ret.Data[name] = pci
 	}
-	return appName, nil
+	return ret, nil
 }
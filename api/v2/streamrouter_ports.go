@@ -2,12 +2,11 @@ package v2
 
 import (
 	"encoding/json"
-	"fmt"
+	"github.com/golang/protobuf/proto"
 	"github.com/laincloud/lainlet/api"
-	"github.com/laincloud/lainlet/auth"
+	pb "github.com/laincloud/lainlet/message"
 	"github.com/laincloud/lainlet/watcher"
 	"github.com/laincloud/lainlet/watcher/podgroup"
-	"net/http"
 	"reflect"
 	"sort"
 )
@@ -52,20 +51,24 @@ func (si *Ports) Make(data map[string]interface{}) (api.API, bool, error) {
 	return ret, !reflect.DeepEqual(si.Data, ret.Data), nil
 }
 
-func (si *Ports) Key(r *http.Request) (string, error) {
-	appName := api.GetString(r, "appname", "*")
-	if !auth.Pass(r.RemoteAddr, appName) {
-		if appName == "*" { // try to set the appname automatically by remoteip
-			appName, err := auth.AppName(r.RemoteAddr)
-			if err != nil {
-				return "", fmt.Errorf("authorize failed, can not confirm the app by request ip")
-			}
-			return fixPrefix(appName), nil
-		}
-		return "", fmt.Errorf("authorize failed, no permission")
+func (si *Ports) Key(r *api.Request) (string, error) {
+	return appKey(r, si.URI())
+}
+
+func (si *Ports) ServiceName() string {
+	return "message.StreamrouterPorts"
+}
+
+func (si *Ports) ProtoRequest() proto.Message {
+	return new(pb.StreamrouterPortsRequest)
+}
+
+func (si *Ports) ProtoReply() (proto.Message, error) {
+	ret := &pb.StreamrouterPortsReply{
+		Data: make([]int32, len(si.Data)),
 	}
-	if appName != "*" {
-		appName = fixPrefix(appName)
+	for i, port := range si.Data {
+		ret.Data[i] = int32(port)
 	}
-	return appName, nil
+	return ret, nil
 }
@@ -2,12 +2,11 @@ package v2
 
 import (
 	"encoding/json"
-	"fmt"
+	"github.com/golang/protobuf/proto"
 	"github.com/laincloud/lainlet/api"
-	"github.com/laincloud/lainlet/auth"
+	pb "github.com/laincloud/lainlet/message"
 	"github.com/laincloud/lainlet/watcher"
 	"github.com/laincloud/lainlet/watcher/podgroup"
-	"net/http"
 	"reflect"
 	"sort"
 )
@@ -105,26 +104,54 @@ func (si *StreamRouterInfo) Make(data map[string]interface{}) (api.API, bool, er
 			sort.Slice(proc.Services, func(i int, j int) bool { return proc.Services[i].ListenPort < proc.Services[j].ListenPort })
 		}
 	}
-	if containerCount == 0 || aliveCount*2 < containerCount {
-		return ret, false, tooManyDeadContainersError
+	if err := checkAlive(aliveCount, containerCount); err != nil {
+		return ret, false, err
 	}
 	return ret, !reflect.DeepEqual(si.Data, ret.Data), nil
 }
 
-func (si *StreamRouterInfo) Key(r *http.Request) (string, error) {
-	appName := api.GetString(r, "appname", "*")
-	if !auth.Pass(r.RemoteAddr, appName) {
-		if appName == "*" { // try to set the appname automatically by remoteip
-			appName, err := auth.AppName(r.RemoteAddr)
-			if err != nil {
-				return "", fmt.Errorf("authorize failed, can not confirm the app by request ip")
+func (si *StreamRouterInfo) Key(r *api.Request) (string, error) {
+	return appKey(r, si.URI())
+}
+
+func (si *StreamRouterInfo) ServiceName() string {
+	return "message.StreamrouterStreamprocs"
+}
+
+func (si *StreamRouterInfo) ProtoRequest() proto.Message {
+	return new(pb.StreamrouterStreamprocsRequest)
+}
+
+func (si *StreamRouterInfo) ProtoReply() (proto.Message, error) {
+	ret := &pb.StreamrouterStreamprocsReply{
+		Data: make(map[string]*pb.StreamProcList),
+	}
+	for appname, procs := range si.Data {
+		list := &pb.StreamProcList{
+			Procs: make([]*pb.StreamProc, len(procs)),
+		}
+		for i, proc := range procs {
+			list.Procs[i] = &pb.StreamProc{
+				Name:      proc.Name,
+				Upstreams: make([]*pb.StreamUpstream, len(proc.Upstreams)),
+				Services:  make([]*pb.StreamService, len(proc.Services)),
+			}
+			for j, upstream := range proc.Upstreams {
+				list.Procs[i].Upstreams[j] = &pb.StreamUpstream{
+					Host:       upstream.Host,
+					InstanceNo: int32(upstream.InstanceNo),
+				}
+			}
+			for j, service := range proc.Services {
+				list.Procs[i].Services[j] = &pb.StreamService{
+					UpstreamPort: int32(service.UpstreamPort),
+					ListenPort:   int32(service.ListenPort),
+					Send:         service.Send,
+					Expect:       service.Expect,
+				}
 			}
-			return fixPrefix(appName), nil
 		}
-		return "", fmt.Errorf("authorize failed, no permission")
-	}
-	if appName != "*" {
-		appName = fixPrefix(appName)
+		ret.Data[appname] = list
 	}
-	return appName, nil
+	return ret, nil
 }
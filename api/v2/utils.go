@@ -1,5 +1,13 @@
 package v2
 
+import (
+	"fmt"
+	"reflect"
+
+	"github.com/laincloud/lainlet/api"
+	"github.com/laincloud/lainlet/fieldmask"
+)
+
 func fixPrefix(s string) string {
 	l := len(s)
 	if l == 0 {
@@ -10,3 +18,34 @@ func fixPrefix(s string) string {
 	}
 	return s + "/"
 }
+
+// appKey return the key of the apis watching the data of a app by the appname argument, and authorize the request.
+// The clients which can not visit all the apps get their own app by default, confirmed by the request ip,
+// they are checked by Explain first, so only the final decision is logged and audited.
+func appKey(r *api.Request, uri string) (string, error) {
+	appName := r.GetString("appname", "*")
+	if appName == "*" && !r.Auth.Explain(r.Subject(), uri, appName).Decision.Allowed {
+		name, err := r.AppName()
+		if err != nil {
+			r.Allowed(uri, appName) // audit the denial
+			return "", fmt.Errorf("authorize failed, can not confirm the app by request ip")
+		}
+		appName = name
+	}
+	if !r.Allowed(uri, appName) {
+		return "", fmt.Errorf("authorize failed, no permission")
+	}
+	if appName != "*" {
+		appName = fixPrefix(appName)
+	}
+	return appName, nil
+}
+
+// project copy the fields in mask of data into ret, which must be a pointer to the type of data.
+func project(data interface{}, mask fieldmask.Mask, ret interface{}) error {
+	if err := mask.Validate(data); err != nil {
+		return err
+	}
+	reflect.ValueOf(ret).Elem().Set(reflect.ValueOf(fieldmask.Keep(data, mask)))
+	return nil
+}
@@ -0,0 +1,86 @@
+package v2
+
+import (
+	"fmt"
+	"net/url"
+	"reflect"
+	"testing"
+
+	"github.com/laincloud/lainlet/api"
+	"github.com/laincloud/lainlet/auth"
+	"github.com/laincloud/lainlet/ipaddr"
+)
+
+// recordAuth allow the super clients to visit everything and the apps to visit their own data,
+// the keys evaluated by Evaluate, which are logged and audited, are recorded
+type recordAuth struct {
+	apps      map[string]string // ip -> app
+	super     map[string]bool
+	evaluated []string
+}
+
+func (a *recordAuth) IPSubject(remoteAddr string) auth.Subject {
+	if app, ok := a.apps[ipaddr.Host(remoteAddr)]; ok {
+		return auth.Subject{App: app, Addr: remoteAddr, Via: "podgroup"}
+	}
+	return auth.Subject{Addr: remoteAddr, Via: "unknown ip"}
+}
+
+func (a *recordAuth) AppName(remoteIP string) (string, error) {
+	if app, ok := a.apps[ipaddr.Host(remoteIP)]; ok {
+		return app, nil
+	}
+	return "", fmt.Errorf("unkown address %s", remoteIP)
+}
+
+func (a *recordAuth) VerifyToken(token string) (*auth.Claims, error) {
+	return nil, fmt.Errorf("tokens are not accepted")
+}
+
+func (a *recordAuth) decide(s auth.Subject, key string) auth.Decision {
+	return auth.Decision{Allowed: a.super[s.App] || (s.App != "" && key == s.App)}
+}
+
+func (a *recordAuth) Evaluate(s auth.Subject, api, key string) auth.Decision {
+	a.evaluated = append(a.evaluated, key)
+	return a.decide(s, key)
+}
+
+func (a *recordAuth) Explain(s auth.Subject, api, key string) *auth.Explanation {
+	return &auth.Explanation{Subject: s, API: api, Key: key, Decision: a.decide(s, key)}
+}
+
+func TestAppKey(t *testing.T) {
+	cases := []struct {
+		addr, appname string
+		key           string
+		ok            bool
+		evaluated     []string
+	}{
+		{"172.20.0.2:1234", "", "hello/", true, []string{"hello"}},
+		{"172.20.0.2:1234", "hello", "hello/", true, []string{"hello"}},
+		{"172.20.0.2:1234", "world", "", false, []string{"world"}},
+		{"172.20.0.3:1234", "", "*", true, []string{"*"}},
+		{"172.20.0.3:1234", "hello", "hello/", true, []string{"hello"}},
+		{"10.0.0.1:1234", "", "", false, []string{"*"}},
+		{"10.0.0.1:1234", "hello", "", false, []string{"hello"}},
+	}
+	for _, c := range cases {
+		a := &recordAuth{
+			apps:  map[string]string{"172.20.0.2": "hello", "172.20.0.3": "console"},
+			super: map[string]bool{"console": true},
+		}
+		args := url.Values{}
+		if c.appname != "" {
+			args.Set("appname", c.appname)
+		}
+		r := (&api.Request{RemoteAddr: c.addr, Args: args, Auth: a}).Cached()
+		key, err := appKey(r, "/coreinfowatcher")
+		if (err == nil) != c.ok || key != c.key {
+			t.Errorf("appKey(%s, %q) = %q, %v, want %q, ok %v", c.addr, c.appname, key, err, c.key, c.ok)
+		}
+		if !reflect.DeepEqual(a.evaluated, c.evaluated) {
+			t.Errorf("appKey(%s, %q) evaluated %q, want %q", c.addr, c.appname, a.evaluated, c.evaluated)
+		}
+	}
+}
@@ -3,19 +3,26 @@ package v2
 import (
 	"encoding/json"
 	"fmt"
-	"net/http"
 	"reflect"
 	"strings"
 
+	"github.com/golang/protobuf/proto"
 	"github.com/laincloud/lainlet/api"
-	"github.com/laincloud/lainlet/auth"
+	"github.com/laincloud/lainlet/conf"
+	"github.com/laincloud/lainlet/fieldmask"
+	pb "github.com/laincloud/lainlet/message"
 	"github.com/laincloud/lainlet/watcher"
 	"github.com/laincloud/lainlet/watcher/podgroup"
-	"errors"
 )
 
-
-var tooManyDeadContainersError = errors.New("over half of the containers lost their IPs")
+// checkAlive return error if there is no container, or the ratio of the containers having IP is less than the configured ratio
+func checkAlive(aliveCount, containerCount int) error {
+	ratio := conf.Current().WebrouterMinAliveRatio
+	if containerCount == 0 || float64(aliveCount) < ratio*float64(containerCount) {
+		return fmt.Errorf("too many containers lost their IPs, %d of %d alive, %g required", aliveCount, containerCount, ratio)
+	}
+	return nil
+}
 
 type ContainerForWebrouter struct {
 	IP     string `json:"ContainerIp"`
@@ -35,6 +42,7 @@ type CoreInfoForWebrouter struct {
 // Coreinfo API
 type WebrouterInfo struct {
 	Data map[string]CoreInfoForWebrouter
+	mask fieldmask.Mask // the fields kept by Project
 }
 
 func (wi *WebrouterInfo) Decode(r []byte) error {
@@ -42,7 +50,15 @@ func (wi *WebrouterInfo) Decode(r []byte) error {
 }
 
 func (wi *WebrouterInfo) Encode() ([]byte, error) {
-	return json.Marshal(wi.Data)
+	return json.Marshal(fieldmask.Select(wi.Data, wi.mask))
+}
+
+func (wi *WebrouterInfo) Project(mask fieldmask.Mask) (api.API, error) {
+	ret := &WebrouterInfo{mask: mask}
+	if err := project(wi.Data, mask, &ret.Data); err != nil {
+		return nil, err
+	}
+	return ret, nil
 }
 
 func (wi *WebrouterInfo) URI() string {
@@ -86,26 +102,45 @@ func (wi *WebrouterInfo) Make(data map[string]interface{}) (api.API, bool, error
 		}
 		ret.Data[pg.Spec.Name] = ci
 	}
-	if containerCount == 0 || aliveCount * 2 < containerCount {
-		return ret, false, tooManyDeadContainersError
+	if err := checkAlive(aliveCount, containerCount); err != nil {
+		return ret, false, err
 	}
 	return ret, !reflect.DeepEqual(wi.Data, ret.Data), nil
 }
 
-func (wi *WebrouterInfo) Key(r *http.Request) (string, error) {
-	appName := api.GetString(r, "appname", "*")
-	if !auth.Pass(r.RemoteAddr, appName) {
-		if appName == "*" { // try to set the appname automatically by remoteip
-			appName, err := auth.AppName(r.RemoteAddr)
-			if err != nil {
-				return "", fmt.Errorf("authorize failed, can not confirm the app by request ip")
+func (wi *WebrouterInfo) Key(r *api.Request) (string, error) {
+	return appKey(r, wi.URI())
+}
+
+func (wi *WebrouterInfo) ServiceName() string {
+	return "message.WebrouterWebprocs"
+}
+
+func (wi *WebrouterInfo) ProtoRequest() proto.Message {
+	return new(pb.WebrouterWebprocsRequest)
+}
+
+func (wi *WebrouterInfo) ProtoReply() (proto.Message, error) {
+	ret := &pb.WebrouterWebprocsReply{
+		Data: make(map[string]*pb.CoreInfoForWebrouter),
+	}
+	for name, ci := range wi.Data {
+		pci := &pb.CoreInfoForWebrouter{
+			PodInfos: make([]*pb.PodInfoForWebrouter, len(ci.PodInfos)),
+		}
+		for i, pi := range ci.PodInfos {
+			pci.PodInfos[i] = &pb.PodInfoForWebrouter{
+				Annotation: pi.Annotation,
+				Containers: make([]*pb.ContainerForWebrouter, len(pi.Containers)),
+			}
+			for j, c := range pi.Containers {
+				pci.PodInfos[i].Containers[j] = &pb.ContainerForWebrouter{
+					IP:     c.IP,
+					Expose: int32(c.Expose),
+				}
 			}
-			return fixPrefix(appName), nil
 		}
-		return "", fmt.Errorf("authorize failed, no permission")
-	}
-	if appName != "*" {
-		appName = fixPrefix(appName)
+		ret.Data[name] = pci
 	}
-	return appName, nil
+	return ret, nil
 }
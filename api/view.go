@@ -0,0 +1,124 @@
+package api
+
+import (
+	"bytes"
+	"fmt"
+
+	"github.com/laincloud/lainlet/fieldmask"
+	"github.com/laincloud/lainlet/store"
+	"github.com/laincloud/lainlet/watcher"
+	"github.com/mijia/sweb/log"
+	"golang.org/x/net/context"
+)
+
+// Sender is used by Watch to send the data to the client, content is the encoded instance.
+// For a error event, instance is nil and content is the error message.
+type Sender func(id uint64, action store.Action, instance API, content []byte) error
+
+// Get return the current data of api by the key, only the fields in mask are kept.
+func Get(wer watcher.Watcher, api API, key string, mask fieldmask.Mask) (API, error) {
+	data, err := wer.Get(key)
+	if err != nil {
+		return nil, err
+	}
+	instance, _, err := api.Make(data)
+	if err != nil {
+		return nil, err
+	}
+	return project(instance, mask)
+}
+
+// Watch send the current data of api by the key, and then send the new data each time it was changed, until ctx was done.
+// The changes are detected for each call respectively, if mask is not empty, only the changes of the fields in mask are sent.
+// The watch is listed in conns until it returns, it returns ErrDisconnected if it was closed by conns.Disconnect.
+func Watch(ctx context.Context, conns *Registry, wer watcher.Watcher, api API, r *Request, key string, mask fieldmask.Mask, send Sender) error {
+	if _, ok := api.(BanWatcher); ok {
+		return fmt.Errorf("%s do not support watch action", api.URI())
+	}
+	c, ctx := conns.add(ctx, api, r, key)
+	defer conns.remove(c)
+
+	// send the init data
+	data, err := wer.Get(key)
+	if err != nil {
+		return err
+	}
+	full, _, err := api.Make(data)
+	if err != nil {
+		return err
+	}
+	instance, err := project(full, mask)
+	if err != nil {
+		return err
+	}
+	content, err := instance.Encode()
+	if err != nil {
+		return err
+	}
+	if err := send(1, store.INIT, instance, content); err != nil {
+		return err
+	}
+	c.sent()
+
+	// start watching
+	channel, err := wer.Watch(key, ctx)
+	if err != nil {
+		return fmt.Errorf("Fail to watch %s, %s", key, err.Error())
+	}
+	for {
+		select {
+		case event, ok := <-channel:
+			if !ok {
+				return nil
+			}
+			log.Infof("Get a %s event, id=%d action=%s ", api.WatcherName(), event.ID, event.Action.String())
+			if event.Action == store.ERROR {
+				if err := send(event.ID, event.Action, nil, []byte(fmt.Sprint(event.Data))); err != nil {
+					return err
+				}
+				continue
+			}
+			var changed bool
+			full, changed, err = full.Make(event.Data)
+			if err != nil {
+				return err
+			}
+			if !changed {
+				continue
+			}
+			if instance, err = project(full, mask); err != nil {
+				return err
+			}
+			newContent, err := instance.Encode()
+			if err != nil {
+				return err
+			}
+			if len(mask) > 0 && bytes.Equal(content, newContent) {
+				continue // only the changes of the projected fields are sent
+			}
+			content = newContent
+			if err := send(event.ID, event.Action, instance, content); err != nil {
+				return err
+			}
+			c.sent()
+		case <-ctx.Done():
+			log.Infof("Get stop signal, a connection stop watching to %s", key)
+			if c.isKicked() {
+				return ErrDisconnected
+			}
+			return nil
+		}
+	}
+}
+
+// project keep only the fields in mask of the instance
+func project(instance API, mask fieldmask.Mask) (API, error) {
+	if len(mask) == 0 {
+		return instance, nil
+	}
+	p, ok := instance.(Projector)
+	if !ok {
+		return nil, fmt.Errorf("%s do not support fields argument", instance.URI())
+	}
+	return p.Project(mask)
+}
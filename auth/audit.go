@@ -0,0 +1,74 @@
+package auth
+
+import (
+	"encoding/json"
+	"io"
+	"os"
+	"time"
+
+	"github.com/mijia/sweb/log"
+)
+
+// AuditEntry is a line of the audit log, written in json for each decision
+type AuditEntry struct {
+	Time    time.Time `json:"time"`
+	Subject *Subject  `json:"subject"`
+	API     string    `json:"api"`
+	Key     string    `json:"key"`
+	Decision
+}
+
+// SetAuditLog open the audit log file, "-" means the standard output, and the decisions are not audited if it is empty.
+// The previous file is closed, so it can be called again after the file was rotated.
+func (a *StoreAuthorizer) SetAuditLog(filename string) error {
+	var w io.WriteCloser
+	switch filename {
+	case "":
+	case "-":
+		w = nopCloser{os.Stdout}
+	default:
+		f, err := os.OpenFile(filename, os.O_WRONLY|os.O_APPEND|os.O_CREATE, 0640)
+		if err != nil {
+			return err
+		}
+		w = f
+	}
+	a.auditLock.Lock()
+	old := a.auditWriter
+	a.auditWriter = w
+	a.auditLock.Unlock()
+	if old != nil {
+		old.Close()
+	}
+	return nil
+}
+
+func (a *StoreAuthorizer) audit(s *Subject, api, key string, d Decision) {
+	a.auditLock.Lock()
+	defer a.auditLock.Unlock()
+	if a.auditWriter == nil {
+		return
+	}
+	content, err := json.Marshal(&AuditEntry{
+		Time:     time.Now(),
+		Subject:  s,
+		API:      api,
+		Key:      key,
+		Decision: d,
+	})
+	if err != nil {
+		log.Errorf("Fail to encode the audit entry, %s", err.Error())
+		return
+	}
+	if _, err := a.auditWriter.Write(append(content, '\n')); err != nil {
+		log.Errorf("Fail to write the audit log, %s", err.Error())
+	}
+}
+
+type nopCloser struct {
+	io.Writer
+}
+
+func (nopCloser) Close() error {
+	return nil
+}
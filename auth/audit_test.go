@@ -0,0 +1,126 @@
+package auth
+
+import (
+	"bufio"
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/laincloud/lainlet/unixsock"
+)
+
+// readAudit return the entries in the audit log, each line is decoded into a map so the field names are checked
+func readAudit(t *testing.T, filename string) []map[string]interface{} {
+	f, err := os.Open(filename)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	var entries []map[string]interface{}
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		var entry map[string]interface{}
+		if err := json.Unmarshal(scanner.Bytes(), &entry); err != nil {
+			t.Fatalf("invalid audit line %q, %s", scanner.Text(), err.Error())
+		}
+		entries = append(entries, entry)
+	}
+	if err := scanner.Err(); err != nil {
+		t.Fatal(err)
+	}
+	return entries
+}
+
+func TestAuditLog(t *testing.T) {
+	dir, err := ioutil.TempDir("", "lainlet-audit")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	filename := filepath.Join(dir, "audit.log")
+
+	a := newTestAuthorizer(t)
+	defer a.SetAuditLog("")
+	if err := a.SetAuditLog(filename); err != nil {
+		t.Fatal(err)
+	}
+	start := time.Now()
+	a.Evaluate(a.IPSubject("172.20.0.2:41234"), "/procwatcher", "hello")
+	a.Evaluate(Subject{Cred: &unixsock.Cred{PID: 42, UID: 1000, GID: 100}, Addr: "unix:/run/lainlet.sock#1", Via: "unix socket"}, "/configwatcher", "vips")
+	a.Explain(a.IPSubject("172.20.0.2:41234"), "/configwatcher", "vips") // not audited
+
+	entries := readAudit(t, filename)
+	if len(entries) != 2 {
+		t.Fatalf("got %d audit entries, want 2", len(entries))
+	}
+	for i, entry := range entries {
+		ts, ok := entry["time"].(string)
+		if !ok {
+			t.Fatalf("the entry %d has no time, %v", i, entry)
+		}
+		at, err := time.Parse(time.RFC3339Nano, ts)
+		if err != nil || at.Before(start.Add(-time.Second)) || at.After(time.Now().Add(time.Second)) {
+			t.Errorf("the entry %d has the time %s, %v", i, ts, err)
+		}
+		delete(entry, "time")
+	}
+	want := []map[string]interface{}{
+		{
+			"subject": map[string]interface{}{"app": "hello", "addr": "172.20.0.2:41234", "via": "podgroup"},
+			"api":     "/procwatcher",
+			"key":     "hello",
+			"allowed": true,
+			"source":  "default",
+			"rule":    2.0,
+			"allow":   "self",
+		},
+		{
+			"subject": map[string]interface{}{
+				"cred": map[string]interface{}{"pid": 42.0, "uid": 1000.0, "gid": 100.0},
+				"addr": "unix:/run/lainlet.sock#1",
+				"via":  "unix socket",
+			},
+			"api":     "/configwatcher",
+			"key":     "vips",
+			"allowed": false,
+			"source":  "default",
+			"rule":    0.0,
+			"allow":   "",
+		},
+	}
+	if !reflect.DeepEqual(entries, want) {
+		got, _ := json.Marshal(entries)
+		expected, _ := json.Marshal(want)
+		t.Errorf("got the audit entries\n%s\nwant\n%s", got, expected)
+	}
+
+	// the log is reopened after it was rotated, and nothing is audited after it was disabled
+	rotated := filename + ".1"
+	if err := os.Rename(filename, rotated); err != nil {
+		t.Fatal(err)
+	}
+	if err := a.SetAuditLog(filename); err != nil {
+		t.Fatal(err)
+	}
+	a.Evaluate(a.IPSubject("[::1]:41234"), "/nodes", "*")
+	if err := a.SetAuditLog(""); err != nil {
+		t.Fatal(err)
+	}
+	a.Evaluate(a.IPSubject("[::1]:41234"), "/nodes", "*")
+	if entries := readAudit(t, rotated); len(entries) != 2 {
+		t.Errorf("got %d entries in the rotated log, want 2", len(entries))
+	}
+	entries = readAudit(t, filename)
+	if len(entries) != 1 || entries[0]["api"] != "/nodes" || entries[0]["allow"] != "role:super" {
+		t.Errorf("got the entries %v in the reopened log, want the one of /nodes", entries)
+	}
+
+	if err := a.SetAuditLog(filepath.Join(dir, "missing", "audit.log")); err == nil || !strings.Contains(err.Error(), "no such file") {
+		t.Errorf("SetAuditLog in a missing directory = %v, want error", err)
+	}
+}
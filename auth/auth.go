@@ -3,168 +3,144 @@ package auth
 import (
 	"encoding/json"
 	"fmt"
+	"io"
 	"path"
 	"strings"
+	"sync"
+	"sync/atomic"
 
+	"github.com/laincloud/lainlet/conf"
+	"github.com/laincloud/lainlet/ipaddr"
+	"github.com/laincloud/lainlet/spec"
 	"github.com/laincloud/lainlet/store"
 	"github.com/laincloud/lainlet/watcher"
-	"github.com/laincloud/lainlet/spec"
 	"github.com/laincloud/lainlet/watcher/depends"
 	"github.com/mijia/sweb/log"
 	"golang.org/x/net/context"
 )
 
-const (
-	dependsKey        = "/lain/deployd/depends/pods"
-	podgroupKey       = "/lain/deployd/pod_groups"
-	superAppsStoreKey = "/lain/config/super_apps"
-)
+// Authorizer identify the clients and decide whether they can visit the apis, it is used by both the http and the grpc servers.
+type Authorizer interface {
+	// IPSubject return the subject of the client identified by its address
+	IPSubject(remoteAddr string) Subject
+	// AppName return the app name of the ip, the return error is nil only when appname is found
+	AppName(remoteIP string) (string, error)
+	// VerifyToken check the bearer token and return its claims
+	VerifyToken(token string) (*Claims, error)
+	// Evaluate decide whether the subject can visit the key of the api
+	Evaluate(s Subject, api, key string) Decision
+	// Explain evaluate like Evaluate, and return how the decision was made
+	Explain(s Subject, api, key string) *Explanation
+}
+
+// StoreAuthorizer is the Authorizer identifying the clients by the podgroups and depends in store,
+// and evaluating the policy from the policy file, the store or the default one.
+type StoreAuthorizer struct {
+	active  bool // all the requests are allowed when it is not active
+	localIP string
+	cfg     atomic.Value // *conf.Config, set by New and Reload
+
+	// the keys in store
+	dependsKey        string
+	podgroupKey       string
+	superAppsStoreKey string
+	policyStoreKey    string
 
-var (
-	active              = true
-	localIP             = "127.0.0.1"
-	authTable           map[string]containerInfo
 	dependsInvertTable  map[string]string
 	podgroupInvertTable map[string]string
 	dependsWatcher      watcher.Watcher
 	podgroupWatcher     watcher.Watcher
 	superAppsWatcher    watcher.Watcher
-)
+	policyWatcher       watcher.Watcher
+
+	filePolicy      atomic.Value // *Policy, nil if there is no policy file
+	lastStorePolicy *Policy
 
-// Init function initialize the data needed by auth.
-func Init(s store.Store, ctx context.Context, ip string, act bool) error {
-	active = act // set active, auth always return success when unactive
-	localIP = ip
-	authTable = make(map[string]containerInfo)
-	dependsInvertTable = make(map[string]string)
-	podgroupInvertTable = make(map[string]string)
+	tokenKey atomic.Value // []byte
+
+	auditLock   sync.Mutex
+	auditWriter io.WriteCloser // nil if the decisions are not audited
+}
+
+var _ Authorizer = (*StoreAuthorizer)(nil)
+
+// New create a StoreAuthorizer by the configuration, it watches the data needed in store until ctx was canceled.
+func New(s store.Store, ctx context.Context, cfg *conf.Config) (*StoreAuthorizer, error) {
+	a := &StoreAuthorizer{
+		active:              !cfg.NoAuth,
+		localIP:             ipaddr.Normalize(cfg.IP),
+		dependsKey:          cfg.Keys.Depends,
+		podgroupKey:         cfg.Keys.PodGroups,
+		superAppsStoreKey:   cfg.Keys.SuperApps,
+		policyStoreKey:      cfg.Keys.Policy,
+		dependsInvertTable:  make(map[string]string),
+		podgroupInvertTable: make(map[string]string),
+	}
+	a.cfg.Store(cfg)
+	if err := a.Reload(cfg); err != nil {
+		return nil, err
+	}
 	var err error
-	dependsWatcher, err = watcher.New(s, ctx, dependsKey, dependsConvert, dependsInvertKey)
+	a.dependsWatcher, err = watcher.New(s, ctx, "auth_depends", a.dependsKey, a.dependsConvert, a.dependsInvertKey)
 	if err != nil {
-		return err
+		return nil, err
 	}
-	podgroupWatcher, err = watcher.New(s, ctx, podgroupKey, podgroupConvert, podgroupInvertKey)
+	a.podgroupWatcher, err = watcher.New(s, ctx, "auth_podgroup", a.podgroupKey, a.podgroupConvert, a.podgroupInvertKey)
 	if err != nil {
-		return err
+		return nil, err
 	}
-	superAppsWatcher, err = watcher.New(s, ctx, superAppsStoreKey, superAppsConvert, superAppsInvertKey)
+	a.superAppsWatcher, err = watcher.New(s, ctx, "auth_superapps", a.superAppsStoreKey, a.superAppsConvert, a.superAppsInvertKey)
 	if err != nil {
-		return err
+		return nil, err
 	}
-	return nil
-}
-
-type containerInfo struct {
-	AppName     string
-	Proc        string
-	ContainerID string
-}
-
-func isSuperApp(appname string) bool {
-	if !active {
-		log.Debugf("auth is not active, return true")
-		return true
+	if err := a.initPolicy(s, ctx); err != nil {
+		return nil, err
 	}
-	if data, _ := superAppsWatcher.Get(appname); data != nil && len(data) == 1 {
-		return true
-	}
-	log.Warnf("app %s is not a super apps, not found it in super configuration", appname)
-	return false
+	return a, nil
 }
 
-// IsSuper check if the ip belong to the super apps
-func IsSuper(remoteIP string) bool {
-	if !active {
-		log.Debugf("auth is not active, return true")
-		return true
-	}
-	if index := strings.LastIndexByte(remoteIP, ':'); index >= 0 {
-		remoteIP = remoteIP[:index]
-	}
-	log.Debugf("remote ip is %s", remoteIP)
-	if remoteIP == "127.0.0.1" || remoteIP == localIP || remoteIP == "[::1]" {
-		return true
-	}
-	info, err := podgroupWatcher.Get(remoteIP)
-	if err != nil || info == nil {
-		log.Warnf("can not get container info by ip %s, %s", remoteIP, err.Error())
-		return false
-	}
-	if ci, ok := info[remoteIP]; ok {
-		if isSuperApp(ci.(containerInfo).AppName) {
-			return true
+// Reload apply the reloadable configuration, which are the secret keys, the policy file, the token key and the audit log.
+// The audit log is reopened, so it can be rotated.
+func (a *StoreAuthorizer) Reload(cfg *conf.Config) error {
+	a.cfg.Store(cfg)
+	if cfg.TokenKeyFile == "" {
+		a.SetTokenKey(nil)
+	} else {
+		key, err := ReadTokenKey(cfg.TokenKeyFile)
+		if err != nil {
+			return err
 		}
+		a.SetTokenKey(key)
 	}
-	return false
+	return a.SetAuditLog(cfg.AuditLog)
 }
 
-// Pass check if the given ip having limits to visit data for given app
-func Pass(remoteIP string, appname string) bool {
-	if !active {
-		log.Debugf("auth is not active, return true")
-		return true
-	}
-	log.Debugf("verify if %s having limit visiting %s", remoteIP, appname)
-	if index := strings.LastIndexByte(remoteIP, ':'); index >= 0 {
-		remoteIP = remoteIP[:index]
-	}
-	log.Debugf("remote ip is %s", remoteIP)
-	switch remoteIP {
-	case "127.0.0.1", localIP, "[::1]": // ipv6 is [::1]
-		return true
-	default:
-		info, err := podgroupWatcher.Get(remoteIP)
-		if err != nil || info == nil {
-			log.Debugf("verify failed, %s", err.Error())
-			return false
-		}
+func (a *StoreAuthorizer) config() *conf.Config {
+	return a.cfg.Load().(*conf.Config)
+}
 
-		remoteAppName, err := AppName(remoteIP)
-		if err != nil {
-			log.Errorf("Fail to get appname by ip %s, %s", remoteIP, err.Error())
-			return false
-		}
+// Active return whether auth is active, all the requests are allowed when it is not active.
+func (a *StoreAuthorizer) Active() bool {
+	return a.active
+}
 
-		log.Debugf("check if app %s has rights visiting %s", remoteAppName, appname)
-		// visit itself?
-		if remoteAppName == appname {
-			return true
-		}
-		// request from a super app?
-		if isSuperApp(remoteAppName) {
-			return true
-		}
-		// visit it's dependency service?
-		apps, err := dependsWatcher.Get(remoteIP)
-		if err != nil {
-			log.Debugf("verify failed, %s", err.Error())
-			return false
-		}
-		if slice, ok := apps[remoteIP]; ok {
-			for _, item := range slice.([]string) {
-				if item == appname {
-					return true
-				}
-			}
-		}
-	}
-	log.Warnf("verify failed, %s has no permission to visit %s", remoteIP, appname)
-	return false
+type containerInfo struct {
+	AppName     string
+	Proc        string
+	ContainerID string
 }
 
-// AppName return the app name which ip is given ip. the return error is nil only when appname is found
-func AppName(remoteIP string) (string, error) {
-	if index := strings.LastIndexByte(remoteIP, ':'); index >= 0 {
-		remoteIP = remoteIP[:index]
-	}
-	podgroupData, err := podgroupWatcher.Get(remoteIP)
+// AppName return the app name which ip is given ip, with or without port. the return error is nil only when appname is found
+func (a *StoreAuthorizer) AppName(remoteIP string) (string, error) {
+	remoteIP = ipaddr.Host(remoteIP)
+	podgroupData, err := a.podgroupWatcher.Get(remoteIP)
 	if err != nil {
 		return "", err
 	}
 	if appinfo, ok := podgroupData[remoteIP]; ok {
 		return appinfo.(containerInfo).AppName, nil
 	}
-	dependsData, err := dependsWatcher.Get(remoteIP)
+	dependsData, err := a.dependsWatcher.Get(remoteIP)
 	if err != nil {
 		return "", err
 	}
@@ -174,15 +150,15 @@ func AppName(remoteIP string) (string, error) {
 	return "", fmt.Errorf("unkown address %s", remoteIP)
 }
 
-func podgroupInvertKey(key string) string {
-	k, ok := podgroupInvertTable[key]
+func (a *StoreAuthorizer) podgroupInvertKey(key string) string {
+	k, ok := a.podgroupInvertTable[key]
 	if ok {
 		return k
 	}
 	return ""
 }
 
-func podgroupConvert(pairs []*store.KVPair) (map[string]interface{}, error) {
+func (a *StoreAuthorizer) podgroupConvert(pairs []*store.KVPair) (map[string]interface{}, error) {
 	ret := make(map[string]interface{})
 	for _, kv := range pairs {
 		var pg spec.PodGroupWithSpec
@@ -193,27 +169,28 @@ func podgroupConvert(pairs []*store.KVPair) (map[string]interface{}, error) {
 		}
 		for _, pod := range pg.Pods {
 			for _, container := range pod.Containers {
-				ret[container.ContainerIp] = containerInfo{
+				ip := ipaddr.Normalize(container.ContainerIp)
+				ret[ip] = containerInfo{
 					AppName:     pg.Spec.Namespace,
 					Proc:        pg.Spec.Name,
 					ContainerID: container.Id,
 				}
-				podgroupInvertTable[container.ContainerIp] = kv.Key
+				a.podgroupInvertTable[ip] = kv.Key
 			}
 		}
 	}
 	return ret, nil
 }
 
-func dependsInvertKey(key string) string {
-	k, ok := dependsInvertTable[key]
+func (a *StoreAuthorizer) dependsInvertKey(key string) string {
+	k, ok := a.dependsInvertTable[key]
 	if ok {
 		return k
 	}
 	return ""
 }
 
-func dependsConvert(pairs []*store.KVPair) (map[string]interface{}, error) {
+func (a *StoreAuthorizer) dependsConvert(pairs []*store.KVPair) (map[string]interface{}, error) {
 	ret := make(map[string]interface{})
 	for _, kv := range pairs {
 		var dp depends.Depends
@@ -222,20 +199,27 @@ func dependsConvert(pairs []*store.KVPair) (map[string]interface{}, error) {
 			log.Errorf("JSON unmarshal error: %s", err.Error())
 			return nil, fmt.Errorf("a KVPair unmarshal failed")
 		}
-		fields := strings.Split(kv.Key[len(dependsKey)+1:], ".")
+		fields := strings.Split(kv.Key[len(a.dependsKey)+1:], ".")
 		serviceName := fields[0]
 		if len(fields) > 3 {
 			serviceName = strings.Join(fields[:len(fields)-2], ".")
 		}
 		for _, nodeData := range dp {
-			for _, appData := range nodeData {
+			for appName, appData := range nodeData {
+				// the services depended by each app, used by the "depends" subject of the policy
+				if !containsString(ret[appName], serviceName) {
+					services, _ := ret[appName].([]string)
+					ret[appName] = append(services, serviceName)
+					a.dependsInvertTable[appName] = kv.Key
+				}
 				for _, container := range appData.Pod.Containers {
-					if _, ok := ret[container.ContainerIp]; ok {
-						ret[container.ContainerIp] = append(ret[container.ContainerIp].([]string), serviceName)
+					ip := ipaddr.Normalize(container.ContainerIp)
+					if _, ok := ret[ip]; ok {
+						ret[ip] = append(ret[ip].([]string), serviceName)
 					} else {
-						ret[container.ContainerIp] = []string{serviceName}
+						ret[ip] = []string{serviceName}
 					}
-					dependsInvertTable[container.ContainerIp] = kv.Key
+					a.dependsInvertTable[ip] = kv.Key
 				}
 			}
 		}
@@ -243,14 +227,24 @@ func dependsConvert(pairs []*store.KVPair) (map[string]interface{}, error) {
 	return ret, nil
 }
 
-func superAppsInvertKey(key string) string {
-	return path.Join(superAppsStoreKey, key)
+func containsString(slice interface{}, s string) bool {
+	items, _ := slice.([]string)
+	for _, item := range items {
+		if item == s {
+			return true
+		}
+	}
+	return false
+}
+
+func (a *StoreAuthorizer) superAppsInvertKey(key string) string {
+	return path.Join(a.superAppsStoreKey, key)
 }
 
-func superAppsConvert(pairs []*store.KVPair) (map[string]interface{}, error) {
+func (a *StoreAuthorizer) superAppsConvert(pairs []*store.KVPair) (map[string]interface{}, error) {
 	ret := make(map[string]interface{})
 	for _, kv := range pairs {
-		ret[kv.Key[len(superAppsStoreKey)+1:]] = string(kv.Value)
+		ret[kv.Key[len(a.superAppsStoreKey)+1:]] = string(kv.Value)
 	}
 	return ret, nil
 }
@@ -0,0 +1,197 @@
+package auth
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/laincloud/lainlet/conf"
+	"github.com/laincloud/lainlet/store"
+	"github.com/laincloud/lainlet/watcher"
+	"golang.org/x/net/context"
+)
+
+// mapWatcher is a watcher serving the data converted from the fixtures
+type mapWatcher map[string]interface{}
+
+func (w mapWatcher) Get(prefix string) (map[string]interface{}, error) {
+	ret := make(map[string]interface{})
+	for k, v := range w {
+		if strings.HasPrefix(k, prefix) {
+			ret[k] = v
+		}
+	}
+	return ret, nil
+}
+
+func (w mapWatcher) Watch(prefix string, ctx context.Context) (<-chan *watcher.Event, error) {
+	return make(chan *watcher.Event), nil
+}
+
+func (w mapWatcher) Status() watcher.Status {
+	return watcher.Status{TotalKeys: len(w)}
+}
+
+// the podgroups of hello and world, the containers having IPv4, IPv6 and IPv4-mapped IPv6 addresses in different forms
+var podgroupFixtures = []*store.KVPair{
+	{
+		Key: "/lain/deployd/pod_groups/hello/hello.proc.web",
+		Value: []byte(`{
+			"Spec": {"Name": "hello.proc.web", "Namespace": "hello"},
+			"Pods": [
+				{"InstanceNo": 1, "Containers": [{"Id": "c1", "NodeIp": "192.168.77.21", "ContainerIp": "172.20.0.2"}]},
+				{"InstanceNo": 2, "Containers": [{"Id": "c2", "NodeIp": "fd00:77::21", "ContainerIp": "FD00:20:0:0::2"}]}
+			]
+		}`),
+	},
+	{
+		Key: "/lain/deployd/pod_groups/world/world.proc.worker",
+		Value: []byte(`{
+			"Spec": {"Name": "world.proc.worker", "Namespace": "world"},
+			"Pods": [
+				{"InstanceNo": 1, "Containers": [{"Id": "c3", "NodeIp": "192.168.77.22", "ContainerIp": "::ffff:172.20.0.3"}]},
+				{"InstanceNo": 2, "Containers": [{"Id": "c4", "NodeIp": "fd00:77::22", "ContainerIp": "fd00:20::3"}]}
+			]
+		}`),
+	},
+}
+
+// the portal of the service resource used by world
+var dependsFixtures = []*store.KVPair{
+	{
+		Key: "/lain/deployd/depends/pods/resource.proc.portal",
+		Value: []byte(`{
+			"node1": {"world": {"Pod": {"Containers": [{"Id": "p1", "ContainerIp": "172.20.0.9"}]}}},
+			"node2": {"world": {"Pod": {"Containers": [{"Id": "p2", "ContainerIp": "fd00:20:0::9"}]}}}
+		}`),
+	},
+}
+
+func newTestAuthorizer(t *testing.T) *StoreAuthorizer {
+	cfg := conf.Default()
+	cfg.IP = "fd00:77:0::20"
+	a := &StoreAuthorizer{
+		active:              true,
+		localIP:             "fd00:77::20",
+		dependsKey:          cfg.Keys.Depends,
+		podgroupKey:         cfg.Keys.PodGroups,
+		dependsInvertTable:  make(map[string]string),
+		podgroupInvertTable: make(map[string]string),
+		superAppsWatcher:    mapWatcher{},
+	}
+	a.cfg.Store(cfg)
+	a.filePolicy.Store((*Policy)(nil))
+
+	podgroups, err := a.podgroupConvert(podgroupFixtures)
+	if err != nil {
+		t.Fatal(err)
+	}
+	a.podgroupWatcher = mapWatcher(podgroups)
+	depends, err := a.dependsConvert(dependsFixtures)
+	if err != nil {
+		t.Fatal(err)
+	}
+	a.dependsWatcher = mapWatcher(depends)
+	return a
+}
+
+func TestContainerIPIndex(t *testing.T) {
+	a := newTestAuthorizer(t)
+	for _, ip := range []string{"172.20.0.2", "fd00:20::2", "172.20.0.3", "fd00:20::3"} {
+		if _, ok := a.podgroupInvertTable[ip]; !ok {
+			t.Errorf("container ip %s is not indexed in its normalized form", ip)
+		}
+	}
+	for _, ip := range []string{"FD00:20:0:0::2", "::ffff:172.20.0.3"} {
+		if _, ok := a.podgroupInvertTable[ip]; ok {
+			t.Errorf("container ip %s should be indexed in its normalized form", ip)
+		}
+	}
+	if key := a.podgroupInvertKey("fd00:20::2"); key != podgroupFixtures[0].Key {
+		t.Errorf("the invert key of fd00:20::2 is %q, want %q", key, podgroupFixtures[0].Key)
+	}
+	if key := a.dependsInvertKey("fd00:20::9"); key != dependsFixtures[0].Key {
+		t.Errorf("the invert key of fd00:20::9 is %q, want %q", key, dependsFixtures[0].Key)
+	}
+}
+
+func TestAppName(t *testing.T) {
+	a := newTestAuthorizer(t)
+	cases := []struct {
+		addr, app string
+	}{
+		{"172.20.0.2", "hello"},
+		{"172.20.0.2:41234", "hello"},
+		{"::ffff:172.20.0.2", "hello"},
+		{"[::ffff:172.20.0.2]:41234", "hello"},
+		{"fd00:20::2", "hello"},
+		{"[fd00:20::2]:41234", "hello"},
+		{"FD00:20:0::2", "hello"},
+		{"172.20.0.3:80", "world"},
+		{"[fd00:20:0:0:0:0:0:3]:80", "world"},
+		{"172.20.0.9:80", "resource"},
+		{"[fd00:20::9]:80", "resource"},
+	}
+	for _, c := range cases {
+		app, err := a.AppName(c.addr)
+		if err != nil {
+			t.Errorf("AppName(%q) failed, %s", c.addr, err.Error())
+		} else if app != c.app {
+			t.Errorf("AppName(%q) = %q, want %q", c.addr, app, c.app)
+		}
+	}
+	// the prefix of a indexed ip should not match it
+	for _, addr := range []string{"172.20.0.1", "[fd00:20::]:80", "[fd00:20::22]:80", "fd00:20::"} {
+		if app, err := a.AppName(addr); err == nil {
+			t.Errorf("AppName(%q) = %q, want error", addr, app)
+		}
+	}
+}
+
+func TestIPSubject(t *testing.T) {
+	a := newTestAuthorizer(t)
+	cases := []struct {
+		addr, app, via string
+		super          bool
+	}{
+		{"127.0.0.1:41234", "", "local ip", true},
+		{"[::1]:41234", "", "local ip", true},
+		{"::1", "", "local ip", true},
+		{"[fd00:77::20]:41234", "", "local ip", true},
+		{"[fd00:77:0:0::20]:41234", "", "local ip", true},
+		{"[fd00:20::2]:41234", "hello", "podgroup", false},
+		{"172.20.0.3:41234", "world", "podgroup", false},
+		{"[fd00:20::9]:41234", "resource", "depends", false},
+		{"[fd00:20::99]:41234", "", "unknown ip", false},
+	}
+	for _, c := range cases {
+		s := a.IPSubject(c.addr)
+		if s.App != c.app || s.Via != c.via || containsString(s.Roles, RoleSuper) != c.super {
+			t.Errorf("IPSubject(%q) = %+v, want app %q via %q super %v", c.addr, s, c.app, c.via, c.super)
+		}
+		if s.Addr != c.addr {
+			t.Errorf("IPSubject(%q) has address %q", c.addr, s.Addr)
+		}
+	}
+}
+
+func TestEvaluateIPv6(t *testing.T) {
+	a := newTestAuthorizer(t)
+	cases := []struct {
+		addr, api, key string
+		allowed        bool
+	}{
+		{"[fd00:20::2]:41234", "/procwatcher", "hello", true},
+		{"172.20.0.2:41234", "/procwatcher", "hello", true},
+		{"[fd00:20::2]:41234", "/procwatcher", "world", false},
+		{"[fd00:20::3]:41234", "/procwatcher", "resource", true},
+		{"[fd00:20::3]:41234", "/configwatcher", "vips", false},
+		{"[::1]:41234", "/configwatcher", "vips", true},
+		{"[fd00:20::99]:41234", "/procwatcher", "hello", false},
+	}
+	for _, c := range cases {
+		d := a.Evaluate(a.IPSubject(c.addr), c.api, c.key)
+		if d.Allowed != c.allowed {
+			t.Errorf("%s visiting %s of %s is allowed %v, want %v", c.addr, c.key, c.api, d.Allowed, c.allowed)
+		}
+	}
+}
@@ -0,0 +1,462 @@
+package auth
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/laincloud/lainlet/ipaddr"
+	"github.com/laincloud/lainlet/store"
+	"github.com/laincloud/lainlet/unixsock"
+	"github.com/laincloud/lainlet/watcher"
+	"github.com/mijia/sweb/log"
+	"golang.org/x/net/context"
+	"gopkg.in/yaml.v2"
+)
+
+const (
+	// RoleSuper is the role of the super apps, the apps in super_apps of store always have it
+	RoleSuper = "super"
+
+	// the sources of the effective policy
+	PolicySourceFile    = "file"
+	PolicySourceStore   = "store"
+	PolicySourceDefault = "default"
+)
+
+// the interval checking if the policy file was changed
+var policyFileCheckInterval = 5 * time.Second
+
+// appAPIs are the apis whose key is a app name, the app itself and the apps depending on it can visit them by default
+var appAPIs = []string{
+	"/coreinfowatcher",
+	"/procwatcher",
+	"/proxywatcher",
+	"/rebellion/localprocs",
+	"/streamrouter/ports",
+	"/streamrouter/streamprocs",
+	"/webrouter/webprocs",
+}
+
+// Policy is the access control policy of the apis, the first rule matching the api and the key decide whether a request is allowed,
+// the requests matching no rule are denied.
+type Policy struct {
+	// the members of each role, like "app:<appname>", "uid:<uid>" or "gid:<gid>"
+	Roles map[string][]string `yaml:"roles" json:"roles,omitempty"`
+	Rules []Rule              `yaml:"rules" json:"rules"`
+}
+
+// Rule grant the subjects in Allow to visit the keys of the apis
+type Rule struct {
+	APIs []string `yaml:"apis" json:"apis"`           // the uri of the apis like "/configwatcher", "*" means all the apis
+	Keys []string `yaml:"keys" json:"keys,omitempty"` // the prefixes of the keys, like the config target or the appname, empty means all the keys
+	// the subjects allowed, they are
+	//   "*": everyone
+	//   "self": the app visiting itself
+	//   "depends": the app visiting the apps it depends on
+	//   "role:<role>", "app:<appname>", "uid:<uid>", "gid:<gid>"
+	Allow []string `yaml:"allow" json:"allow"`
+}
+
+func (rule *Rule) match(api, key string) bool {
+	if !matchAny(rule.APIs, func(s string) bool { return s == "*" || s == api }) {
+		return false
+	}
+	return len(rule.Keys) == 0 || matchAny(rule.Keys, func(s string) bool { return strings.HasPrefix(key, s) })
+}
+
+// Subject is the client a policy is evaluated for
+type Subject struct {
+	App   string         `json:"app,omitempty"`   // the app of the client, empty if it is unknown
+	Apps  []string       `json:"apps,omitempty"`  // the other apps whose data the client was granted to visit, like by the token scopes
+	Roles []string       `json:"roles,omitempty"` // the roles the client has besides the policy, like RoleSuper of the trusted unix socket peers
+	Cred  *unixsock.Cred `json:"cred,omitempty"`  // the credential of the unix socket peer
+	Addr  string         `json:"addr,omitempty"`  // the address of the client
+	Via   string         `json:"via"`             // how the client was identified, like "podgroup" or "token"
+}
+
+func (s *Subject) String() string {
+	switch {
+	case s.App != "":
+		return "app " + s.App
+	case s.Cred != nil:
+		return fmt.Sprintf("uid %d", s.Cred.UID)
+	case len(s.Roles) > 0:
+		return "role " + strings.Join(s.Roles, ",")
+	}
+	return "anonymous " + s.Addr
+}
+
+// IPSubject return the subject of the client identified by its address, which is the app of the container having the ip in podgroups,
+// or the service the ip depends on in depends. The local addresses have RoleSuper.
+// Both IPv4 and IPv6 addresses are accepted, with or without port.
+func (a *StoreAuthorizer) IPSubject(remoteAddr string) Subject {
+	remoteIP := ipaddr.Host(remoteAddr)
+	if ipaddr.IsLoopback(remoteIP) || remoteIP == a.localIP {
+		return Subject{Roles: []string{RoleSuper}, Addr: remoteAddr, Via: "local ip"}
+	}
+	if data, err := a.podgroupWatcher.Get(remoteIP); err == nil {
+		if info, ok := data[remoteIP]; ok {
+			return Subject{App: info.(containerInfo).AppName, Addr: remoteAddr, Via: "podgroup"}
+		}
+	}
+	if data, err := a.dependsWatcher.Get(remoteIP); err == nil {
+		if services, ok := data[remoteIP].([]string); ok && len(services) > 0 {
+			return Subject{App: services[0], Addr: remoteAddr, Via: "depends"}
+		}
+	}
+	log.Debugf("can not get the app of %s, not found in podgroups or depends", remoteIP)
+	return Subject{Addr: remoteAddr, Via: "unknown ip"}
+}
+
+// Decision is the result of evaluating the policy
+type Decision struct {
+	Allowed bool   `json:"allowed"`
+	Source  string `json:"source"` // where the policy come from, PolicySourceFile, PolicySourceStore or PolicySourceDefault, empty if auth is not active
+	Rule    int    `json:"rule"`   // the index of the rule matched, -1 if no rule matched
+	Allow   string `json:"allow"`  // the allowed subject matched, empty if denied
+}
+
+// Explanation is how a decision was made, returned by Explain
+type Explanation struct {
+	Subject  Subject  `json:"subject"`
+	API      string   `json:"api"`
+	Key      string   `json:"key"`
+	Steps    []Step   `json:"steps"` // the rules evaluated in order
+	Decision Decision `json:"decision"`
+}
+
+// Step is the evaluation of a rule
+type Step struct {
+	Rule    int     `json:"rule"`
+	Matched bool    `json:"matched"` // whether the rule match the api and the key
+	Checks  []Check `json:"checks,omitempty"`
+}
+
+// Check is the check of a allowed subject of a rule
+type Check struct {
+	Allow  string `json:"allow"`
+	Passed bool   `json:"passed"`
+	Reason string `json:"reason"`
+}
+
+// Evaluate decide whether the subject can visit the key of the api by the effective policy, the decision is written to the audit log.
+func (a *StoreAuthorizer) Evaluate(s Subject, api, key string) Decision {
+	d := a.evaluate(&s, api, key, nil)
+	if !d.Allowed {
+		if d.Rule >= 0 {
+			log.Warnf("verify failed, %s has no permission to visit %s of %s by rule %d of the %s policy", s.String(), key, api, d.Rule, d.Source)
+		} else {
+			log.Warnf("verify failed, no rule of the %s policy allow visiting %s of %s", d.Source, key, api)
+		}
+	}
+	a.audit(&s, api, key, d)
+	return d
+}
+
+// Explain evaluate the policy like Evaluate, and return the rules and the checks evaluated. It is not audited.
+func (a *StoreAuthorizer) Explain(s Subject, api, key string) *Explanation {
+	e := &Explanation{Subject: s, API: api, Key: key, Steps: []Step{}}
+	e.Decision = a.evaluate(&s, api, key, e)
+	return e
+}
+
+// evaluate the policy, the steps are recorded in e if it is not nil
+func (a *StoreAuthorizer) evaluate(s *Subject, api, key string, e *Explanation) Decision {
+	if !a.active {
+		return Decision{Allowed: true, Rule: -1}
+	}
+	p, source := a.CurrentPolicy()
+	for i := range p.Rules {
+		step := Step{Rule: i, Matched: p.Rules[i].match(api, key)}
+		if !step.Matched {
+			if e != nil {
+				e.Steps = append(e.Steps, step)
+			}
+			continue
+		}
+		d := Decision{Source: source, Rule: i}
+		for _, allow := range p.Rules[i].Allow {
+			passed, reason := a.allow(p, s, allow, key)
+			step.Checks = append(step.Checks, Check{Allow: allow, Passed: passed, Reason: reason})
+			if passed {
+				d.Allowed, d.Allow = true, allow
+				break
+			}
+		}
+		if e != nil {
+			e.Steps = append(e.Steps, step)
+		}
+		return d
+	}
+	return Decision{Source: source, Rule: -1}
+}
+
+// allow check if the subject is the allowed subject when visiting key, and return the reason
+func (a *StoreAuthorizer) allow(p *Policy, s *Subject, allow, key string) (bool, string) {
+	switch {
+	case allow == "*":
+		return true, "everyone is allowed"
+	case allow == "self":
+		if (s.App != "" && s.App == key) || containsString(s.Apps, key) {
+			return true, s.String() + " is visiting itself"
+		}
+		return false, s.String() + " is not " + key
+	case allow == "depends":
+		if s.App == "" {
+			return false, "the app of the client is unknown"
+		}
+		if a.dependsOn(s.App, key) {
+			return true, s.App + " depends on " + key
+		}
+		return false, s.App + " does not depend on " + key
+	case strings.HasPrefix(allow, "role:"):
+		role := allow[len("role:"):]
+		if containsString(s.Roles, role) {
+			return true, fmt.Sprintf("%s has role %s by %s", s.String(), role, s.Via)
+		}
+		if role == RoleSuper && s.App != "" && a.inSuperApps(s.App) {
+			return true, s.App + " is in super_apps"
+		}
+		for _, member := range p.Roles[role] {
+			if s.is(member) {
+				return true, fmt.Sprintf("%s is a member of role %s", member, role)
+			}
+		}
+		if role == RoleSuper && s.App != "" {
+			return false, fmt.Sprintf("%s is not in super_apps and does not have role %s", s.App, role)
+		}
+		return false, fmt.Sprintf("%s does not have role %s", s.String(), role)
+	}
+	if s.is(allow) {
+		return true, s.String() + " is " + allow
+	}
+	return false, s.String() + " is not " + allow
+}
+
+// is check if the subject is the identity like "app:<appname>", "uid:<uid>" or "gid:<gid>"
+func (s *Subject) is(identity string) bool {
+	fields := strings.SplitN(identity, ":", 2)
+	if len(fields) != 2 {
+		return false
+	}
+	switch fields[0] {
+	case "app":
+		return s.App != "" && s.App == fields[1]
+	case "uid", "gid":
+		if s.Cred == nil {
+			return false
+		}
+		id, err := strconv.ParseUint(fields[1], 10, 32)
+		if err != nil {
+			return false
+		}
+		if fields[0] == "uid" {
+			return s.Cred.UID == uint32(id)
+		}
+		return s.Cred.GID == uint32(id)
+	}
+	return false
+}
+
+// ParsePolicy parse the policy in yaml or json
+func ParsePolicy(content []byte) (*Policy, error) {
+	var p Policy
+	if err := yaml.UnmarshalStrict(content, &p); err != nil {
+		return nil, err
+	}
+	for role, members := range p.Roles {
+		for _, member := range members {
+			if !validIdentity(member) {
+				return nil, fmt.Errorf("invalid member %q of role %s", member, role)
+			}
+		}
+	}
+	for i, rule := range p.Rules {
+		if len(rule.APIs) == 0 || len(rule.Allow) == 0 {
+			return nil, fmt.Errorf("rule %d requires apis and allow", i)
+		}
+		for _, allow := range rule.Allow {
+			switch {
+			case allow == "*", allow == "self", allow == "depends":
+			case strings.HasPrefix(allow, "role:") && len(allow) > len("role:"):
+			case validIdentity(allow):
+			default:
+				return nil, fmt.Errorf("invalid subject %q in rule %d", allow, i)
+			}
+		}
+	}
+	return &p, nil
+}
+
+func validIdentity(identity string) bool {
+	fields := strings.SplitN(identity, ":", 2)
+	if len(fields) != 2 || fields[1] == "" {
+		return false
+	}
+	switch fields[0] {
+	case "app":
+		return true
+	case "uid", "gid":
+		_, err := strconv.ParseUint(fields[1], 10, 32)
+		return err == nil
+	}
+	return false
+}
+
+// DefaultPolicy return the policy used when no policy is configured, secretKeys are the config keys only for the super apps.
+// It's the same as the rules before the policy was introduced:
+// the secret configs and all the other apis are only for the super apps, the apps can visit the data of themselves and their dependencies.
+func DefaultPolicy(secretKeys []string) *Policy {
+	return &Policy{
+		Rules: []Rule{
+			{APIs: []string{"/configwatcher"}, Keys: secretKeys, Allow: []string{"role:" + RoleSuper}},
+			{APIs: []string{"/configwatcher"}, Allow: []string{"*"}},
+			{APIs: appAPIs, Allow: []string{"role:" + RoleSuper, "self", "depends"}},
+			{APIs: []string{"*"}, Allow: []string{"role:" + RoleSuper}},
+		},
+	}
+}
+
+// CurrentPolicy return the effective policy and where it come from.
+// The policy file is preferred to the policy in store, and the default policy is used if neither of them exists.
+func (a *StoreAuthorizer) CurrentPolicy() (*Policy, string) {
+	if p, _ := a.filePolicy.Load().(*Policy); p != nil {
+		return p, PolicySourceFile
+	}
+	if a.policyWatcher != nil {
+		if data, err := a.policyWatcher.Get("policy"); err == nil {
+			if p, ok := data["policy"].(*Policy); ok {
+				return p, PolicySourceStore
+			}
+		}
+	}
+	return DefaultPolicy(a.config().SecretKeys), PolicySourceDefault
+}
+
+// initPolicy load the policy file, and watch it and the policy in store
+func (a *StoreAuthorizer) initPolicy(s store.Store, ctx context.Context) error {
+	a.filePolicy.Store((*Policy)(nil))
+	var (
+		filename = a.config().PolicyFile
+		modTime  time.Time
+	)
+	if filename != "" {
+		fi, err := os.Stat(filename)
+		if err != nil {
+			return err
+		}
+		p, err := readPolicyFile(filename)
+		if err != nil {
+			return err
+		}
+		a.filePolicy.Store(p)
+		modTime = fi.ModTime()
+	}
+	go a.watchPolicyFile(ctx, filename, modTime)
+
+	var err error
+	a.policyWatcher, err = watcher.New(s, ctx, "auth_policy", a.policyStoreKey, a.policyConvert, a.policyInvertKey)
+	return err
+}
+
+func readPolicyFile(filename string) (*Policy, error) {
+	content, err := ioutil.ReadFile(filename)
+	if err != nil {
+		return nil, err
+	}
+	p, err := ParsePolicy(content)
+	if err != nil {
+		return nil, fmt.Errorf("invalid policy file %s, %s", filename, err.Error())
+	}
+	return p, nil
+}
+
+// watchPolicyFile reload the policy file when it was changed, or the policy_file in configuration was changed.
+// The current policy is kept if the new one is invalid.
+func (a *StoreAuthorizer) watchPolicyFile(ctx context.Context, filename string, modTime time.Time) {
+	ticker := time.NewTicker(policyFileCheckInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+		name := a.config().PolicyFile
+		if name == "" {
+			if filename != "" {
+				a.filePolicy.Store((*Policy)(nil))
+				log.Infof("Policy file %s is not used any more", filename)
+				filename = ""
+			}
+			continue
+		}
+		fi, err := os.Stat(name)
+		if err != nil {
+			log.Errorf("Fail to check the policy file, %s", err.Error())
+			continue
+		}
+		if name == filename && fi.ModTime().Equal(modTime) {
+			continue
+		}
+		filename, modTime = name, fi.ModTime()
+		p, err := readPolicyFile(name)
+		if err != nil {
+			log.Errorf("Fail to reload the policy, keep the current one, %s", err.Error())
+			continue
+		}
+		a.filePolicy.Store(p)
+		log.Infof("Policy reloaded from %s", name)
+	}
+}
+
+func (a *StoreAuthorizer) policyInvertKey(key string) string {
+	return a.policyStoreKey
+}
+
+// policyConvert parse the policy in store, the last valid one is kept if it is invalid
+func (a *StoreAuthorizer) policyConvert(pairs []*store.KVPair) (map[string]interface{}, error) {
+	ret := make(map[string]interface{})
+	if len(pairs) == 0 {
+		a.lastStorePolicy = nil
+		return ret, nil
+	}
+	p, err := ParsePolicy(pairs[0].Value)
+	if err != nil {
+		log.Errorf("Invalid policy in %s, %s", a.policyStoreKey, err.Error())
+		p = a.lastStorePolicy
+	} else {
+		log.Infof("Policy reloaded from %s", a.policyStoreKey)
+	}
+	if p != nil {
+		ret["policy"] = p
+		a.lastStorePolicy = p
+	}
+	return ret, nil
+}
+
+func (a *StoreAuthorizer) inSuperApps(appname string) bool {
+	data, _ := a.superAppsWatcher.Get(appname)
+	return data != nil && len(data) == 1
+}
+
+func (a *StoreAuthorizer) dependsOn(appname, service string) bool {
+	apps, err := a.dependsWatcher.Get(appname)
+	if err != nil {
+		return false
+	}
+	return containsString(apps[appname], service)
+}
+
+func matchAny(items []string, fn func(string) bool) bool {
+	for _, item := range items {
+		if fn(item) {
+			return true
+		}
+	}
+	return false
+}
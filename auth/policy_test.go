@@ -0,0 +1,341 @@
+package auth
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/laincloud/lainlet/conf"
+	"github.com/laincloud/lainlet/store"
+	"github.com/laincloud/lainlet/unixsock"
+	"golang.org/x/net/context"
+)
+
+func TestDefaultPolicy(t *testing.T) {
+	a := newTestAuthorizer(t)
+	a.superAppsWatcher = mapWatcher{"console": "{}"}
+	var (
+		super   = Subject{Roles: []string{RoleSuper}, Via: "local ip"}
+		console = Subject{App: "console", Via: "podgroup"}
+		hello   = Subject{App: "hello", Via: "podgroup"}
+		world   = Subject{App: "world", Via: "podgroup"}
+		unknown = Subject{Addr: "172.20.0.99:1234", Via: "unknown ip"}
+	)
+	cases := []struct {
+		s        Subject
+		api, key string
+		rule     int
+		allow    string // empty if denied
+	}{
+		// the secret configs are only for the super apps, the other configs are for everyone
+		{super, "/configwatcher", "vips", 0, "role:super"},
+		{console, "/configwatcher", "ssl/cert", 0, "role:super"},
+		{hello, "/configwatcher", "vips", 0, ""},
+		{hello, "/configwatcher", "*", 0, ""},
+		{unknown, "/configwatcher", "etcd_cluster_token", 0, ""},
+		{hello, "/configwatcher", "domain", 1, "*"},
+		{unknown, "/configwatcher", "domain", 1, "*"},
+		// the apps can visit themselves and the apps they depend on
+		{hello, "/procwatcher", "hello", 2, "self"},
+		{hello, "/webrouter/webprocs", "world", 2, ""},
+		{world, "/coreinfowatcher", "resource", 2, "depends"},
+		{hello, "/proxywatcher", "resource", 2, ""},
+		{console, "/rebellion/localprocs", "hello", 2, "role:super"},
+		{unknown, "/streamrouter/ports", "hello", 2, ""},
+		// all the other apis are only for the super apps
+		{console, "/nodes", "*", 3, "role:super"},
+		{super, "/admin/watches", "*", 3, "role:super"},
+		{hello, "/podgroupwatcher", "hello", 3, ""},
+		{hello, "/depends", "*", 3, ""},
+	}
+	for _, c := range cases {
+		d := a.evaluate(&c.s, c.api, c.key, nil)
+		want := Decision{Allowed: c.allow != "", Source: PolicySourceDefault, Rule: c.rule, Allow: c.allow}
+		if d != want {
+			t.Errorf("%s visiting %s of %s = %+v, want %+v", c.s.String(), c.key, c.api, d, want)
+		}
+	}
+
+	// the secret keys come from the configuration
+	cfg := conf.Default()
+	cfg.SecretKeys = []string{"domain"}
+	a.cfg.Store(cfg)
+	if d := a.evaluate(&hello, "/configwatcher", "domain", nil); d.Allowed || d.Rule != 0 {
+		t.Errorf("hello visiting the secret domain = %+v, want denied by rule 0", d)
+	}
+	if d := a.evaluate(&hello, "/configwatcher", "vips", nil); !d.Allowed || d.Rule != 1 {
+		t.Errorf("hello visiting vips = %+v, want allowed by rule 1", d)
+	}
+
+	// everything is allowed if auth is not active
+	a.active = false
+	if d := a.evaluate(&unknown, "/admin/watches", "*", nil); !d.Allowed || d.Rule != -1 || d.Source != "" {
+		t.Errorf("the inactive auth = %+v, want allowed without any rule", d)
+	}
+}
+
+func TestAllow(t *testing.T) {
+	a := newTestAuthorizer(t)
+	a.superAppsWatcher = mapWatcher{"console": "{}"}
+	p := &Policy{Roles: map[string][]string{
+		"ops":   {"app:deploy", "uid:1000"},
+		"super": {"gid:50"},
+	}}
+	var (
+		hello  = &Subject{App: "hello", Via: "podgroup"}
+		scoped = &Subject{Apps: []string{"hello", "world"}, Via: "token"}
+		world  = &Subject{App: "world", Via: "podgroup"}
+		deploy = &Subject{App: "deploy", Via: "token"}
+		user   = &Subject{Cred: &unixsock.Cred{UID: 1000, GID: 100}, Via: "unix socket"}
+		staff  = &Subject{Cred: &unixsock.Cred{UID: 1001, GID: 50}, Via: "unix socket"}
+		root   = &Subject{Roles: []string{RoleSuper}, Cred: &unixsock.Cred{UID: 0, GID: 0}, Via: "trusted unix socket"}
+		nobody = &Subject{Addr: "172.20.0.99:1234", Via: "unknown ip"}
+	)
+	cases := []struct {
+		s          *Subject
+		allow, key string
+		passed     bool
+		reason     string
+	}{
+		{nobody, "*", "hello", true, "everyone is allowed"},
+
+		{hello, "self", "hello", true, "app hello is visiting itself"},
+		{hello, "self", "hello2", false, "app hello is not hello2"},
+		{scoped, "self", "world", true, "anonymous  is visiting itself"},
+		{nobody, "self", "", false, "anonymous 172.20.0.99:1234 is not "},
+
+		{world, "depends", "resource", true, "world depends on resource"},
+		{hello, "depends", "resource", false, "hello does not depend on resource"},
+		{nobody, "depends", "resource", false, "the app of the client is unknown"},
+
+		{root, "role:super", "", true, "uid 0 has role super by trusted unix socket"},
+		{&Subject{App: "console"}, "role:super", "", true, "console is in super_apps"},
+		{staff, "role:super", "", true, "gid:50 is a member of role super"},
+		{hello, "role:super", "", false, "hello is not in super_apps and does not have role super"},
+		{deploy, "role:ops", "", true, "app:deploy is a member of role ops"},
+		{user, "role:ops", "", true, "uid:1000 is a member of role ops"},
+		{staff, "role:ops", "", false, "uid 1001 does not have role ops"},
+		{&Subject{App: "console"}, "role:ops", "", false, "app console does not have role ops"},
+
+		{hello, "app:hello", "", true, "app hello is app:hello"},
+		{world, "app:hello", "", false, "app world is not app:hello"},
+		{nobody, "app:", "", false, "anonymous 172.20.0.99:1234 is not app:"},
+		{user, "uid:1000", "", true, "uid 1000 is uid:1000"},
+		{staff, "uid:1000", "", false, "uid 1001 is not uid:1000"},
+		{hello, "uid:1000", "", false, "app hello is not uid:1000"},
+		{user, "gid:100", "", true, "uid 1000 is gid:100"},
+		{root, "gid:100", "", false, "uid 0 is not gid:100"},
+		{user, "uid:abc", "", false, "uid 1000 is not uid:abc"},
+	}
+	for _, c := range cases {
+		passed, reason := a.allow(p, c.s, c.allow, c.key)
+		if passed != c.passed || reason != c.reason {
+			t.Errorf("allow %q of %s visiting %q = %v %q, want %v %q", c.allow, c.s.String(), c.key, passed, reason, c.passed, c.reason)
+		}
+	}
+}
+
+func TestEvaluateRules(t *testing.T) {
+	a := newTestAuthorizer(t)
+	p, err := ParsePolicy([]byte(`
+rules:
+  - apis: ["/configwatcher"]
+    keys: ["ssl", "vips"]
+    allow: ["uid:0"]
+  - apis: ["/configwatcher", "/procwatcher"]
+    allow: ["self", "app:world"]
+`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	a.filePolicy.Store(p)
+	hello := Subject{App: "hello", Via: "podgroup"}
+
+	// the first rule matching the api and the key decide, the rules after it are not evaluated
+	e := a.Explain(hello, "/configwatcher", "vips")
+	want := []Step{
+		{Rule: 0, Matched: true, Checks: []Check{{Allow: "uid:0", Passed: false, Reason: "app hello is not uid:0"}}},
+	}
+	if !reflect.DeepEqual(e.Steps, want) || e.Decision != (Decision{Source: PolicySourceFile, Rule: 0}) {
+		t.Errorf("got the steps %+v and %+v, want %+v denied by rule 0", e.Steps, e.Decision, want)
+	}
+	// the checks stop at the first allowed subject
+	e = a.Explain(hello, "/procwatcher", "hello")
+	want = []Step{
+		{Rule: 0, Matched: false},
+		{Rule: 1, Matched: true, Checks: []Check{{Allow: "self", Passed: true, Reason: "app hello is visiting itself"}}},
+	}
+	if !reflect.DeepEqual(e.Steps, want) || e.Decision != (Decision{Allowed: true, Source: PolicySourceFile, Rule: 1, Allow: "self"}) {
+		t.Errorf("got the steps %+v and %+v, want %+v allowed by rule 1", e.Steps, e.Decision, want)
+	}
+	// no rule matched
+	e = a.Explain(hello, "/nodes", "*")
+	if len(e.Steps) != 2 || e.Decision != (Decision{Source: PolicySourceFile, Rule: -1}) {
+		t.Errorf("got the steps %+v and %+v, want denied by no rule", e.Steps, e.Decision)
+	}
+}
+
+func TestParsePolicy(t *testing.T) {
+	p, err := ParsePolicy([]byte(`{"roles": {"ops": ["app:deploy", "gid:50"]}, "rules": [{"apis": ["*"], "allow": ["role:ops"]}]}`))
+	if err != nil {
+		t.Fatalf("fail to parse the policy in json, %s", err.Error())
+	}
+	want := &Policy{
+		Roles: map[string][]string{"ops": {"app:deploy", "gid:50"}},
+		Rules: []Rule{{APIs: []string{"*"}, Allow: []string{"role:ops"}}},
+	}
+	if !reflect.DeepEqual(p, want) {
+		t.Errorf("got the policy %+v, want %+v", p, want)
+	}
+
+	cases := []struct {
+		content, err string
+	}{
+		{"rules:\n  - apis: [\"*\"]\n    allows: [\"*\"]", "field allows not found"},
+		{"rule:\n  - apis: [\"*\"]\n    allow: [\"*\"]", "field rule not found"},
+		{"rules:\n  - apis: [\"*\"]\n    allow: [\"*\"]\n    allow: [\"self\"]", "already set"},
+		{"rules:\n  - allow: [\"*\"]", "rule 0 requires apis and allow"},
+		{"rules:\n  - apis: [\"*\"]", "rule 0 requires apis and allow"},
+		{"rules:\n  - apis: [\"*\"]\n    allow: [\"everyone\"]", `invalid subject "everyone" in rule 0`},
+		{"rules:\n  - apis: [\"*\"]\n    allow: [\"role:\"]", `invalid subject "role:" in rule 0`},
+		{"rules:\n  - apis: [\"*\"]\n    allow: [\"uid:-1\"]", `invalid subject "uid:-1" in rule 0`},
+		{"roles:\n  ops: [\"user:1000\"]", `invalid member "user:1000" of role ops`},
+		{"roles:\n  ops: [\"role:super\"]", `invalid member "role:super" of role ops`},
+	}
+	for _, c := range cases {
+		if _, err := ParsePolicy([]byte(c.content)); err == nil || !strings.Contains(err.Error(), c.err) {
+			t.Errorf("ParsePolicy(%q) = %v, want the error %q", c.content, err, c.err)
+		}
+	}
+}
+
+// policyStore serve the policy in store, its changes are sent to the watches by events
+type policyStore struct {
+	store.Store
+	pairs  []*store.KVPair
+	events chan *store.Event
+}
+
+func (s *policyStore) GetTree(key string) ([]*store.KVPair, error) {
+	if len(s.pairs) == 0 {
+		return nil, store.ErrKeyNotFound
+	}
+	return s.pairs, nil
+}
+
+func (s *policyStore) Watch(key string, ctx context.Context, recursive bool, index uint64) (<-chan *store.Event, error) {
+	return s.events, nil
+}
+
+// eventually wait the condition to become true, the policy is reloaded asynchronously
+func eventually(t *testing.T, what string, cond func() bool) {
+	deadline := time.Now().Add(5 * time.Second)
+	for !cond() {
+		if time.Now().After(deadline) {
+			t.Fatalf("timeout waiting %s", what)
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+func TestPolicyReload(t *testing.T) {
+	interval := policyFileCheckInterval
+	policyFileCheckInterval = 10 * time.Millisecond
+	defer func() { policyFileCheckInterval = interval }()
+
+	dir, err := ioutil.TempDir("", "lainlet-policy")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	filename := filepath.Join(dir, "policy.yaml")
+	// the modification time is changed explicitly, it may not change between the writes in a short time
+	modTime := time.Now().Add(-time.Hour)
+	writeFile := func(content string) {
+		if err := ioutil.WriteFile(filename, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+		modTime = modTime.Add(time.Second)
+		if err := os.Chtimes(filename, modTime, modTime); err != nil {
+			t.Fatal(err)
+		}
+	}
+	policyOf := func(app string) string {
+		return "rules:\n  - apis: [\"*\"]\n    allow: [\"app:" + app + "\"]\n"
+	}
+
+	a := newTestAuthorizer(t)
+	cfg := conf.Default()
+	cfg.PolicyFile = filename
+	a.cfg.Store(cfg)
+	a.policyStoreKey = cfg.Keys.Policy
+	writeFile(policyOf("file1"))
+	s := &policyStore{
+		pairs:  []*store.KVPair{{Key: cfg.Keys.Policy, Value: []byte(policyOf("store1"))}},
+		events: make(chan *store.Event),
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	if err := a.initPolicy(s, ctx); err != nil {
+		t.Fatal(err)
+	}
+
+	// current return the source of the effective policy and the subject allowed by it
+	current := func() string {
+		p, source := a.CurrentPolicy()
+		if source == PolicySourceDefault {
+			return source
+		}
+		return source + " " + p.Rules[0].Allow[0]
+	}
+	expect := func(want string) {
+		eventually(t, "the policy "+want, func() bool { return current() == want })
+	}
+	// send the event of the policy in store, the event is handled after the next one was received
+	send := func(action store.Action, content string) {
+		event := &store.Event{Action: action, Key: cfg.Keys.Policy}
+		if content != "" {
+			event.Data = []*store.KVPair{{Key: cfg.Keys.Policy, Value: []byte(content)}}
+		}
+		select {
+		case s.events <- event:
+		case <-time.After(5 * time.Second):
+			t.Fatal("the policy in store is not watched")
+		}
+	}
+
+	// the policy file is preferred, it is reloaded when it was changed, and kept if the new one is invalid
+	expect("file app:file1")
+	writeFile(policyOf("file2"))
+	expect("file app:file2")
+	writeFile("rules: [")
+	time.Sleep(10 * policyFileCheckInterval)
+	if got := current(); got != "file app:file2" {
+		t.Errorf("got the policy %s after the policy file became invalid, want the last valid one", got)
+	}
+
+	// the policy in store is used when the policy file is not configured any more
+	cfg = conf.Default()
+	a.cfg.Store(cfg)
+	expect("store app:store1")
+	send(store.UPDATE, policyOf("store2"))
+	expect("store app:store2")
+	send(store.UPDATE, "rules:\n  - apis: [\"*\"]\n    allow: [\"nobody\"]\n")
+	send(store.UPDATE, "rules: [")
+	if got := current(); got != "store app:store2" {
+		t.Errorf("got the policy %s after the policy in store became invalid, want the last valid one", got)
+	}
+	send(store.DELETE, "")
+	expect(PolicySourceDefault)
+
+	// the policy file is loaded again when it is configured
+	writeFile(policyOf("file3"))
+	cfg = conf.Default()
+	cfg.PolicyFile = filename
+	a.cfg.Store(cfg)
+	expect("file app:file3")
+}
@@ -0,0 +1,149 @@
+package auth
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"strings"
+	"time"
+)
+
+const (
+	// ScopeSuper is the scope which grants the token the super access
+	ScopeSuper = "super"
+	// ScopeAppPrefix is the prefix of the scopes granting the token to visit the data of a app, like "app:webrouter"
+	ScopeAppPrefix = "app:"
+
+	// tokenHeader is the header of all the tokens, which are JWT signed by HS256
+	tokenHeader = `{"alg":"HS256","typ":"JWT"}`
+	// minTokenKeyLen is the min length of the key signing the tokens
+	minTokenKeyLen = 32
+)
+
+// Claims is the content of a token
+type Claims struct {
+	App       string   `json:"sub"`              // the app the token holder acting as
+	Scopes    []string `json:"scopes,omitempty"` // the additional grants, ScopeSuper or ScopeAppPrefix + appname
+	IssuedAt  int64    `json:"iat"`
+	ExpiresAt int64    `json:"exp,omitempty"` // unix time, the token never expires if it is 0
+	NotBefore int64    `json:"nbf,omitempty"` // unix time, the token is not valid before it
+}
+
+// HasScope check if the claims having the scope
+func (c *Claims) HasScope(scope string) bool {
+	for _, s := range c.Scopes {
+		if s == scope {
+			return true
+		}
+	}
+	return false
+}
+
+// ReadTokenKey read the key of the tokens from the file, the spaces around the key are ignored, it should be at least 32 bytes.
+func ReadTokenKey(filename string) ([]byte, error) {
+	content, err := ioutil.ReadFile(filename)
+	if err != nil {
+		return nil, err
+	}
+	key := bytes.TrimSpace(content)
+	if len(key) < minTokenKeyLen {
+		return nil, fmt.Errorf("the token key in %s is too short, at least %d bytes", filename, minTokenKeyLen)
+	}
+	return key, nil
+}
+
+// SetTokenKey set the key to verify the tokens, the tokens are not accepted if the key is empty.
+func (a *StoreAuthorizer) SetTokenKey(key []byte) {
+	a.tokenKey.Store(key)
+}
+
+// SignToken create a token of the claims signed by the key
+func SignToken(key []byte, claims *Claims) (string, error) {
+	if claims.App == "" {
+		return "", fmt.Errorf("app is required")
+	}
+	if len(key) < minTokenKeyLen {
+		return "", fmt.Errorf("the token key is too short, at least %d bytes", minTokenKeyLen)
+	}
+	payload, err := json.Marshal(claims)
+	if err != nil {
+		return "", err
+	}
+	unsigned := encodeSegment([]byte(tokenHeader)) + "." + encodeSegment(payload)
+	return unsigned + "." + encodeSegment(sign(key, unsigned)), nil
+}
+
+// VerifyToken check the signature and the expiration of the token by the key set by SetTokenKey, and return its claims.
+func (a *StoreAuthorizer) VerifyToken(token string) (*Claims, error) {
+	key, _ := a.tokenKey.Load().([]byte)
+	return VerifyToken(key, token)
+}
+
+// VerifyToken check the signature and the valid time of the token by the key, and return its claims.
+// The keys shorter than 32 bytes are not accepted.
+func VerifyToken(key []byte, token string) (*Claims, error) {
+	if len(key) < minTokenKeyLen {
+		return nil, fmt.Errorf("authorize failed, token is not accepted")
+	}
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		return nil, fmt.Errorf("authorize failed, malformed token")
+	}
+	var header struct {
+		Alg string `json:"alg"`
+	}
+	if content, err := decodeSegment(parts[0]); err != nil || json.Unmarshal(content, &header) != nil || header.Alg != "HS256" {
+		return nil, fmt.Errorf("authorize failed, unsupported token")
+	}
+	signature, err := decodeSegment(parts[2])
+	if err != nil || !hmac.Equal(signature, sign(key, parts[0]+"."+parts[1])) {
+		return nil, fmt.Errorf("authorize failed, invalid token signature")
+	}
+	payload, err := decodeSegment(parts[1])
+	if err != nil {
+		return nil, fmt.Errorf("authorize failed, malformed token")
+	}
+	var claims Claims
+	if err := json.Unmarshal(payload, &claims); err != nil || claims.App == "" {
+		return nil, fmt.Errorf("authorize failed, malformed token")
+	}
+	now := time.Now().Unix()
+	if claims.ExpiresAt != 0 && now >= claims.ExpiresAt {
+		return nil, fmt.Errorf("authorize failed, token expired")
+	}
+	if claims.NotBefore != 0 && now < claims.NotBefore {
+		return nil, fmt.Errorf("authorize failed, token is not valid yet")
+	}
+	return &claims, nil
+}
+
+// TokenSubject return the subject of the token holder, the scopes grant it RoleSuper or the data of other apps.
+func TokenSubject(claims *Claims) Subject {
+	s := Subject{App: claims.App, Via: "token"}
+	for _, scope := range claims.Scopes {
+		if scope == ScopeSuper {
+			s.Roles = append(s.Roles, RoleSuper)
+		} else if strings.HasPrefix(scope, ScopeAppPrefix) {
+			s.Apps = append(s.Apps, scope[len(ScopeAppPrefix):])
+		}
+	}
+	return s
+}
+
+func sign(key []byte, s string) []byte {
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(s))
+	return mac.Sum(nil)
+}
+
+func encodeSegment(b []byte) string {
+	return base64.RawURLEncoding.EncodeToString(b)
+}
+
+func decodeSegment(s string) ([]byte, error) {
+	return base64.RawURLEncoding.DecodeString(s)
+}
@@ -0,0 +1,147 @@
+package auth
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+var testTokenKey = []byte("0123456789abcdef0123456789abcdef")
+
+// forge a token with the header and the claims signed by the key, the signature is empty if key is nil
+func forge(t *testing.T, header string, claims interface{}, key []byte) string {
+	payload, err := json.Marshal(claims)
+	if err != nil {
+		t.Fatal(err)
+	}
+	unsigned := encodeSegment([]byte(header)) + "." + encodeSegment(payload)
+	if key == nil {
+		return unsigned + "."
+	}
+	return unsigned + "." + encodeSegment(sign(key, unsigned))
+}
+
+func TestVerifyToken(t *testing.T) {
+	now := time.Now()
+	claims := &Claims{App: "ci", Scopes: []string{"app:hello"}, IssuedAt: now.Unix(), ExpiresAt: now.Add(time.Hour).Unix()}
+	token, err := SignToken(testTokenKey, claims)
+	if err != nil {
+		t.Fatal(err)
+	}
+	got, err := VerifyToken(testTokenKey, token)
+	if err != nil {
+		t.Fatalf("the valid token is rejected, %s", err.Error())
+	}
+	if !reflect.DeepEqual(got, claims) {
+		t.Errorf("VerifyToken return %+v, want %+v", got, claims)
+	}
+
+	parts := strings.Split(token, ".")
+	otherKey := []byte("fedcba9876543210fedcba9876543210")
+	cases := []struct {
+		name, token, reason string
+	}{
+		{"bad signature", parts[0] + "." + parts[1] + "." + encodeSegment([]byte("bad")), "invalid token signature"},
+		{"signed by another key", forge(t, tokenHeader, claims, otherKey), "invalid token signature"},
+		{"changed claims", parts[0] + "." + encodeSegment([]byte(`{"sub":"hello","scopes":["super"]}`)) + "." + parts[2], "invalid token signature"},
+		{"expired", forge(t, tokenHeader, &Claims{App: "ci", ExpiresAt: now.Add(-time.Second).Unix()}, testTokenKey), "token expired"},
+		{"not valid yet", forge(t, tokenHeader, &Claims{App: "ci", NotBefore: now.Add(time.Hour).Unix()}, testTokenKey), "not valid yet"},
+		{"alg none", forge(t, `{"alg":"none","typ":"JWT"}`, claims, nil), "unsupported token"},
+		{"alg none signed", forge(t, `{"alg":"none","typ":"JWT"}`, claims, testTokenKey), "unsupported token"},
+		{"alg HS512", forge(t, `{"alg":"HS512","typ":"JWT"}`, claims, testTokenKey), "unsupported token"},
+		{"alg RS256", forge(t, `{"alg":"RS256","typ":"JWT"}`, claims, testTokenKey), "unsupported token"},
+		{"no alg", forge(t, `{"typ":"JWT"}`, claims, testTokenKey), "unsupported token"},
+		{"no app", forge(t, tokenHeader, &Claims{Scopes: []string{"super"}}, testTokenKey), "malformed token"},
+		{"two parts", parts[0] + "." + parts[1], "malformed token"},
+		{"not base64", parts[0] + "." + parts[1] + ".!", "invalid token signature"},
+		{"empty", "", "malformed token"},
+	}
+	for _, c := range cases {
+		_, err := VerifyToken(testTokenKey, c.token)
+		if err == nil {
+			t.Errorf("%s: the token should be rejected", c.name)
+		} else if !strings.Contains(err.Error(), c.reason) {
+			t.Errorf("%s: rejected by %q, want %q", c.name, err.Error(), c.reason)
+		}
+	}
+
+	if _, err := VerifyToken(testTokenKey, forge(t, tokenHeader, &Claims{App: "ci", NotBefore: now.Add(-time.Second).Unix()}, testTokenKey)); err != nil {
+		t.Errorf("the token after its nbf should be accepted, %s", err.Error())
+	}
+}
+
+func TestTokenKeyLength(t *testing.T) {
+	shortKey := testTokenKey[:31]
+	if _, err := SignToken(shortKey, &Claims{App: "ci"}); err == nil {
+		t.Error("signing by a short key should fail")
+	}
+	token := forge(t, tokenHeader, &Claims{App: "ci"}, shortKey)
+	if _, err := VerifyToken(shortKey, token); err == nil {
+		t.Error("the token signed by a short key should be rejected")
+	}
+	if _, err := VerifyToken(nil, token); err == nil {
+		t.Error("the tokens should be rejected without a key")
+	}
+
+	f, err := ioutil.TempFile("", "lainlet-token-key")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.Remove(f.Name())
+	f.Write(append(append([]byte("  "), shortKey...), '\n'))
+	f.Close()
+	if _, err := ReadTokenKey(f.Name()); err == nil {
+		t.Error("the short key in file should be rejected")
+	}
+	if err := ioutil.WriteFile(f.Name(), append(append([]byte("\n"), testTokenKey...), ' ', '\n'), 0600); err != nil {
+		t.Fatal(err)
+	}
+	if key, err := ReadTokenKey(f.Name()); err != nil || string(key) != string(testTokenKey) {
+		t.Errorf("ReadTokenKey = %q, %v, want %q", key, err, testTokenKey)
+	}
+}
+
+func TestTokenKeyReload(t *testing.T) {
+	a := newTestAuthorizer(t)
+	token, err := SignToken(testTokenKey, &Claims{App: "ci"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := a.VerifyToken(token); err == nil {
+		t.Error("the tokens should be rejected before the key was set")
+	}
+	a.SetTokenKey(testTokenKey)
+	if _, err := a.VerifyToken(token); err != nil {
+		t.Errorf("the token is rejected, %s", err.Error())
+	}
+
+	newKey := []byte("fedcba9876543210fedcba9876543210")
+	a.SetTokenKey(newKey)
+	if _, err := a.VerifyToken(token); err == nil {
+		t.Error("the token signed by the old key should be rejected after reloading")
+	}
+	newToken, err := SignToken(newKey, &Claims{App: "ci"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := a.VerifyToken(newToken); err != nil {
+		t.Errorf("the token signed by the new key is rejected, %s", err.Error())
+	}
+
+	a.SetTokenKey(nil)
+	if _, err := a.VerifyToken(newToken); err == nil {
+		t.Error("the tokens should be rejected after the key was removed")
+	}
+}
+
+func TestTokenSubject(t *testing.T) {
+	s := TokenSubject(&Claims{App: "ci", Scopes: []string{"super", "app:hello", "app:world", "unknown"}})
+	want := Subject{App: "ci", Apps: []string{"hello", "world"}, Roles: []string{RoleSuper}, Via: "token"}
+	if !reflect.DeepEqual(s, want) {
+		t.Errorf("TokenSubject = %+v, want %+v", s, want)
+	}
+}
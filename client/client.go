@@ -1,17 +1,20 @@
 package client
 
 import (
-	"bufio"
 	"bytes"
+	"crypto/tls"
+	"fmt"
 	"golang.org/x/net/context"
 	"io"
 	"io/ioutil"
 	"github.com/laincloud/lainlet/api/v2"
+	"github.com/laincloud/lainlet/jsonpatch"
 	"github.com/laincloud/lainlet/watcher/container"
 	"github.com/laincloud/lainlet/watcher/nodes"
 	"net/http"
 	"net/url"
-	"strconv"
+	"strings"
+	"sync"
 	"time"
 )
 
@@ -22,6 +25,7 @@ const (
 	DELETE           = "delete"
 	INIT             = "init"
 	HEARTBEAT        = "heartbeat"
+	PATCH            = "patch" // never returned by Watch(), the patch will be applied and returned as a UPDATE event
 )
 
 // The Data type return by /v2/configwatcher
@@ -70,19 +74,70 @@ type Response struct {
 
 	// the returned data return by watch request, the Data is a json-format.
 	// you can import `github.com/laincloud/api/v2`, and using the coresponding type to Decode.
-	Data     []byte
-	finished bool // check if one response was completed, because it's field was set by the data read from response body line by line
+	Data []byte
+}
+
+// StatusError is returned by Do if lainlet responded a status code other than 2xx, like 403 if the request was denied
+type StatusError struct {
+	Code    int
+	Message string // the response body
+}
+
+func (e *StatusError) Error() string {
+	return fmt.Sprintf("lainlet responded %d %s, %s", e.Code, http.StatusText(e.Code), e.Message)
+}
+
+// Temporary check if the request may succeed later, like the server was overloaded or the client was rate limited
+func (e *StatusError) Temporary() bool {
+	return e.Code >= 500 || e.Code == http.StatusTooManyRequests || e.Code == http.StatusRequestTimeout
 }
 
 type Client struct {
-	addr string
+	eps   *endpoints
+	token string
+
+	lock         sync.Mutex
+	tlsTransport *http.Transport // the transport using the TLS config, nil if SetTLSConfig was not called
 }
 
-// create a new client, addr is lainlet address such as "127.0.0.1:9001"
+// create a new client, addr is lainlet address such as "127.0.0.1:9001" or "https://127.0.0.1:9001", see NewWithEndpoints for multiple lainlets
 func New(addr string) *Client {
-	return &Client{
-		addr: addr,
+	return NewWithEndpoints([]string{addr})
+}
+
+// SetToken set the bearer token minted by tools/token, which is sent with every request
+func (c *Client) SetToken(token string) {
+	c.token = token
+}
+
+// SetTLSConfig set the TLS config of https, like the CA verifying lainlet and the client certificate,
+// the endpoints without a scheme use https after it was called
+func (c *Client) SetTLSConfig(cfg *tls.Config) {
+	c.lock.Lock()
+	c.tlsTransport = newTransport(cfg)
+	c.lock.Unlock()
+}
+
+func (c *Client) transport() *http.Transport {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	if c.tlsTransport != nil {
+		return c.tlsTransport
+	}
+	return defaultTransport
+}
+
+// split return the scheme and the host of the endpoint
+func (c *Client) split(addr string) (string, string) {
+	for _, scheme := range []string{"https", "http"} {
+		if strings.HasPrefix(addr, scheme+"://") {
+			return scheme, strings.TrimSuffix(strings.TrimPrefix(addr, scheme+"://"), "/")
+		}
+	}
+	if c.transport() != defaultTransport {
+		return "https", addr
 	}
+	return "http", addr
 }
 
 // get data from the given uri, return the []byte read from http response body.
@@ -103,7 +158,7 @@ func (c *Client) Get(uri string, timeout time.Duration) ([]byte, error) {
 // The return channel will be closed when the context was canceled or the http connection was closed.
 // it return error when fail to send request to lainlet.
 func (c *Client) Watch(uri string, ctx context.Context) (<-chan *Response, error) {
-	reader, err := c.Do(uri, 0, true)
+	reader, err := c.Do(withPatch(uri), 0, true)
 	if err != nil {
 		return nil, err
 	}
@@ -119,63 +174,71 @@ func (c *Client) Watch(uri string, ctx context.Context) (<-chan *Response, error
 	go func() {
 		defer close(stop)
 		defer close(respCh)
-		var (
-			newone bool = true // if need to create a new response
-			resp   *Response
-		)
-		buf := bufio.NewReader(reader)
+		var last []byte // the latest full data, used to apply the json patch
+		events := newEventReader(reader)
 		for {
-			if newone {
-				resp = new(Response)
-				resp.finished = false
-				newone = false
-			}
-			line, err := buf.ReadBytes('\n')
+			resp, err := events.next()
 			if err != nil {
-				if err != io.EOF || len(line) == 0 {
-					// got a unexpected(not io.EOF) error, or read none data with io.EOF
-					return
-				}
+				return
 			}
-			if pureLine := bytes.TrimSpace(line); len(pureLine) == 0 {
-				continue // empty line
-			} else {
-				fields := bytes.SplitN(pureLine, []byte{':'}, 2)
-				if len(fields) < 2 {
-					continue
-				}
-				switch key := string(bytes.TrimSpace(fields[0])); key {
-				case "id":
-					if id, err := strconv.ParseInt(string(bytes.TrimSpace(fields[1])), 10, 64); err == nil {
-						resp.Id = id
-					}
-				case "event":
-					resp.Event = string(bytes.TrimSpace(fields[1]))
-					if resp.Event == "heartbeat" {
-						resp.finished = true
-					}
-				case "data":
-					resp.Data = bytes.TrimSpace(fields[1])
-					resp.finished = true
-				}
+			if resp, err = applyPatch(resp, last); err != nil {
+				// can not keep the data consistent any more, stop watching
+				respCh <- &Response{Event: ERROR, Data: []byte(err.Error())}
+				return
 			}
-			if resp.finished {
-				respCh <- resp
-				newone = true
+			if isDataEvent(resp.Event) {
+				last = resp.Data
 			}
+			respCh <- resp
 		}
 	}()
 	return (<-chan *Response)(respCh), nil
 }
 
-// send a http request
+// withPatch ask lainlet to send the changes as json patches, Watch and WatchReconnect apply them transparently
+func withPatch(uri string) string {
+	u, err := url.Parse(uri)
+	if err != nil {
+		return uri
+	}
+	q := u.Query()
+	if q.Get("patch") == "" {
+		q.Set("patch", "rfc6902")
+		u.RawQuery = q.Encode()
+	}
+	return u.String()
+}
+
+// applyPatch return the full data of the PATCH event as a UPDATE event, last is the data of the last event
+func applyPatch(resp *Response, last []byte) (*Response, error) {
+	if resp.Event != PATCH {
+		return resp, nil
+	}
+	data, err := jsonpatch.Apply(last, resp.Data)
+	if err != nil {
+		return nil, fmt.Errorf("fail to apply patch, %s", err.Error())
+	}
+	resp.Event, resp.Data = UPDATE, data
+	return resp, nil
+}
+
+func isDataEvent(event string) bool {
+	return event == INIT || event == UPDATE || event == DELETE
+}
+
+// send a http request, a *StatusError is returned if the status code is not 2xx.
+// The events of the watch request are returned as they are, the PATCH events are sent only if the uri has the patch argument.
 func (c *Client) Do(uri string, timeout time.Duration, watch bool) (io.ReadCloser, error) {
+	reader, _, err := c.do(context.Background(), uri, timeout, watch, nil)
+	return reader, err
+}
+
+// do send the request to the endpoints in order until one of them responded, and return the endpoint responded
+func (c *Client) do(ctx context.Context, uri string, timeout time.Duration, watch bool, header http.Header) (io.ReadCloser, string, error) {
 	u, err := url.Parse(uri)
 	if err != nil {
-		return nil, err
+		return nil, "", err
 	}
-	u.Scheme = "http"
-	u.Host = c.addr
 
 	q := u.Query()
 	if watch {
@@ -185,9 +248,43 @@ func (c *Client) Do(uri string, timeout time.Duration, watch bool) (io.ReadClose
 	}
 	u.RawQuery = q.Encode()
 
-	resp, err := (&http.Client{Timeout: timeout}).Get(u.String())
+	// the last error is returned if all the endpoints failed
+	var reader io.ReadCloser
+	for _, addr := range c.eps.order() {
+		u.Scheme, u.Host = c.split(addr)
+		reader, err = c.send(ctx, u.String(), timeout, header)
+		if err != nil && ctx.Err() == nil && shouldFailover(err) {
+			c.eps.fail(addr)
+			continue
+		}
+		if ctx.Err() == nil {
+			c.eps.succeed(addr)
+		}
+		return reader, addr, err
+	}
+	return nil, "", err
+}
+
+func (c *Client) send(ctx context.Context, rawurl string, timeout time.Duration, header http.Header) (io.ReadCloser, error) {
+	req, err := http.NewRequest("GET", rawurl, nil)
 	if err != nil {
 		return nil, err
 	}
+	req = req.WithContext(ctx)
+	for key, values := range header {
+		req.Header[key] = values
+	}
+	if c.token != "" {
+		req.Header.Set("Authorization", "Bearer "+c.token)
+	}
+	resp, err := (&http.Client{Transport: c.transport(), Timeout: timeout}).Do(req)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		defer resp.Body.Close()
+		body, _ := ioutil.ReadAll(io.LimitReader(resp.Body, 4096))
+		return nil, &StatusError{Code: resp.StatusCode, Message: string(bytes.TrimSpace(body))}
+	}
 	return resp.Body, nil
 }
@@ -0,0 +1,172 @@
+package client
+
+import (
+	"crypto/tls"
+	"golang.org/x/net/context"
+	"net"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// DefaultRetryInterval is how long a failed endpoint is skipped, the preferred endpoints are probed in this interval while watching another one
+const DefaultRetryInterval = 5 * time.Second
+
+// the transport of the requests without a TLS config
+var defaultTransport = newTransport(nil)
+
+// newTransport return a transport with a short dial timeout to fail over quickly
+func newTransport(tlsConfig *tls.Config) *http.Transport {
+	return &http.Transport{
+		Proxy: http.ProxyFromEnvironment,
+		DialContext: (&net.Dialer{
+			Timeout:   3 * time.Second,
+			KeepAlive: 30 * time.Second,
+		}).DialContext,
+		TLSClientConfig:       tlsConfig,
+		MaxIdleConns:          100,
+		IdleConnTimeout:       90 * time.Second,
+		TLSHandshakeTimeout:   10 * time.Second,
+		ExpectContinueTimeout: time.Second,
+	}
+}
+
+// endpoints is the ordered lainlet endpoints of a client, the requests go to the first one not failed recently
+type endpoints struct {
+	addrs []string
+
+	lock     sync.Mutex
+	failed   map[string]time.Time // the endpoints failed and when
+	active   string
+	onChange func(addr string)
+}
+
+func newEndpoints(addrs []string) *endpoints {
+	return &endpoints{
+		addrs:  addrs,
+		failed: make(map[string]time.Time),
+	}
+}
+
+// order return the endpoints to try, the ones failed in DefaultRetryInterval are at the end
+func (eps *endpoints) order() []string {
+	eps.lock.Lock()
+	defer eps.lock.Unlock()
+	now := time.Now()
+	ret, failed := make([]string, 0, len(eps.addrs)), make([]string, 0)
+	for _, addr := range eps.addrs {
+		if t, ok := eps.failed[addr]; ok && now.Sub(t) < DefaultRetryInterval {
+			failed = append(failed, addr)
+		} else {
+			ret = append(ret, addr)
+		}
+	}
+	return append(ret, failed...)
+}
+
+// preferred return the endpoints before addr
+func (eps *endpoints) preferred(addr string) []string {
+	for i, a := range eps.addrs {
+		if a == addr {
+			return eps.addrs[:i]
+		}
+	}
+	return nil
+}
+
+func (eps *endpoints) fail(addr string) {
+	eps.lock.Lock()
+	eps.failed[addr] = time.Now()
+	eps.lock.Unlock()
+}
+
+// recover forget the failure of the endpoint
+func (eps *endpoints) recover(addr string) {
+	eps.lock.Lock()
+	delete(eps.failed, addr)
+	eps.lock.Unlock()
+}
+
+// succeed mark the endpoint as the active one
+func (eps *endpoints) succeed(addr string) {
+	eps.lock.Lock()
+	delete(eps.failed, addr)
+	changed := addr != eps.active
+	eps.active = addr
+	onChange := eps.onChange
+	eps.lock.Unlock()
+	if changed && onChange != nil {
+		onChange(addr)
+	}
+}
+
+// shouldFailover check if the request may succeed on another endpoint
+func shouldFailover(err error) bool {
+	if se, ok := err.(*StatusError); ok {
+		return se.Code >= 500
+	}
+	return true
+}
+
+// NewWithEndpoints create a client with the ordered lainlet endpoints, like the local lainlet and then the others.
+// An endpoint can be prefixed by "https://" or "http://", the endpoints without it use https only if SetTLSConfig was called.
+// The requests go to the first endpoint which did not fail in DefaultRetryInterval, and fail over to the next one
+// if the endpoint can not be connected or responded 5xx. WatchReconnect watch again on the preferred endpoint after it recovered.
+func NewWithEndpoints(addrs []string) *Client {
+	return &Client{
+		eps: newEndpoints(addrs),
+	}
+}
+
+// ActiveEndpoint return the endpoint of the last successful request, empty if there is none
+func (c *Client) ActiveEndpoint() string {
+	c.eps.lock.Lock()
+	defer c.eps.lock.Unlock()
+	return c.eps.active
+}
+
+// SetEndpointChangeHandler set the function called with the new endpoint when the requests switched to another endpoint
+func (c *Client) SetEndpointChangeHandler(fn func(addr string)) {
+	c.eps.lock.Lock()
+	c.eps.onChange = fn
+	c.eps.lock.Unlock()
+}
+
+// probePreferred cancel the watch on addr by calling switchOver, when any of the endpoints preferred to addr responds again
+func (c *Client) probePreferred(ctx context.Context, addr string, switchOver func()) {
+	preferred := c.eps.preferred(addr)
+	if len(preferred) == 0 {
+		return
+	}
+	ticker := time.NewTicker(DefaultRetryInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+		case <-ctx.Done():
+			return
+		}
+		for _, p := range preferred {
+			if c.probe(ctx, p) {
+				c.eps.recover(p)
+				switchOver()
+				return
+			}
+		}
+	}
+}
+
+// probe check if the endpoint is alive by /version
+func (c *Client) probe(ctx context.Context, addr string) bool {
+	scheme, host := c.split(addr)
+	req, err := http.NewRequest("GET", scheme+"://"+host+"/version", nil)
+	if err != nil {
+		return false
+	}
+	resp, err := (&http.Client{Transport: c.transport(), Timeout: 3 * time.Second}).Do(req.WithContext(ctx))
+	if err != nil {
+		return false
+	}
+	resp.Body.Close()
+	return resp.StatusCode == http.StatusOK
+}
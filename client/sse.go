@@ -0,0 +1,74 @@
+package client
+
+import (
+	"bufio"
+	"bytes"
+	"io"
+	"strconv"
+)
+
+// eventReader parse the event stream, see https://html.spec.whatwg.org/multipage/server-sent-events.html#event-stream-interpretation
+type eventReader struct {
+	buf    *bufio.Reader
+	lastID string // the last id received, it is kept across the events like the browsers do
+}
+
+func newEventReader(r io.Reader) *eventReader {
+	return &eventReader{buf: bufio.NewReader(r)}
+}
+
+// next read the next event, the data lines of the event are joined by "\n".
+// It return io.EOF if the stream ended, the incomplete event at the end is dropped.
+func (er *eventReader) next() (*Response, error) {
+	var (
+		resp    = new(Response)
+		data    bytes.Buffer
+		hasData bool
+		hasAny  bool
+	)
+	for {
+		line, err := er.buf.ReadBytes('\n')
+		if err != nil {
+			if err == io.EOF && len(line) > 0 {
+				err = io.ErrUnexpectedEOF
+			}
+			return nil, err
+		}
+		line = bytes.TrimRight(line, "\r\n")
+		if len(line) == 0 {
+			if !hasAny {
+				continue
+			}
+			if hasData {
+				resp.Data = data.Bytes()
+			}
+			return resp, nil
+		}
+		if line[0] == ':' {
+			continue // comment
+		}
+		field, value := line, []byte(nil)
+		if index := bytes.IndexByte(line, ':'); index >= 0 {
+			field, value = line[:index], line[index+1:]
+			if len(value) > 0 && value[0] == ' ' {
+				value = value[1:]
+			}
+		}
+		hasAny = true
+		switch string(field) {
+		case "id":
+			er.lastID = string(value)
+			if id, err := strconv.ParseInt(string(value), 10, 64); err == nil {
+				resp.Id = id
+			}
+		case "event":
+			resp.Event = string(value)
+		case "data":
+			if hasData {
+				data.WriteByte('\n')
+			}
+			data.Write(value)
+			hasData = true
+		}
+	}
+}
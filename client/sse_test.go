@@ -0,0 +1,53 @@
+package client
+
+import (
+	"io"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestEventReader(t *testing.T) {
+	cases := []struct {
+		name   string
+		stream string
+		events []Response
+		lastID string
+		err    error
+	}{
+		{"one event", "id: 1\nevent: init\ndata: {}\n\n", []Response{{1, "init", []byte("{}")}}, "1", io.EOF},
+		{"multi-line data", "id: 2\nevent: update\ndata: {\ndata:  \"a\": 1\ndata: }\n\n", []Response{{2, "update", []byte("{\n \"a\": 1\n}")}}, "2", io.EOF},
+		{"empty data lines", "event: update\ndata\ndata:\n\n", []Response{{0, "update", []byte("\n")}}, "", io.EOF},
+		{"crlf", "id: 3\r\nevent: init\r\ndata: {}\r\n\r\n", []Response{{3, "init", []byte("{}")}}, "3", io.EOF},
+		{"no space after colon", "id:4\nevent:init\ndata:{}\n\n", []Response{{4, "init", []byte("{}")}}, "4", io.EOF},
+		{"comments", ": hello\n\n: heartbeat\nid: 1\n: between the fields\nevent: init\ndata: {}\n\n", []Response{{1, "init", []byte("{}")}}, "1", io.EOF},
+		{"no data", "id: 0\nevent: heartbeat\n\n", []Response{{0, "heartbeat", nil}}, "0", io.EOF},
+		{"retry and unknown fields ignored", "retry: 1000\nid: 5\nfoo: bar\nevent: init\ndata: {}\n\n", []Response{{5, "init", []byte("{}")}}, "5", io.EOF},
+		{"id kept across events", "id: 6\nevent: init\ndata: 1\n\nevent: update\ndata: 2\n\n", []Response{{6, "init", []byte("1")}, {0, "update", []byte("2")}}, "6", io.EOF},
+		{"id not a number", "id: abc\nevent: init\ndata: {}\n\n", []Response{{0, "init", []byte("{}")}}, "abc", io.EOF},
+		{"blank lines between events", "\n\nevent: init\ndata: 1\n\n\n\nevent: update\ndata: 2\n\n", []Response{{0, "init", []byte("1")}, {0, "update", []byte("2")}}, "", io.EOF},
+		{"incomplete event dropped", "event: init\ndata: 1\n\nevent: update\ndata: 2\n", []Response{{0, "init", []byte("1")}}, "", io.EOF},
+		{"truncated line", "event: init\ndata: 1\n\nevent: upd", []Response{{0, "init", []byte("1")}}, "", io.ErrUnexpectedEOF},
+	}
+	for _, c := range cases {
+		er := newEventReader(strings.NewReader(c.stream))
+		var events []Response
+		var err error
+		for {
+			var resp *Response
+			if resp, err = er.next(); err != nil {
+				break
+			}
+			events = append(events, *resp)
+		}
+		if !reflect.DeepEqual(events, c.events) {
+			t.Errorf("%s: got the events %+v, want %+v", c.name, events, c.events)
+		}
+		if er.lastID != c.lastID {
+			t.Errorf("%s: the last id is %q, want %q", c.name, er.lastID, c.lastID)
+		}
+		if err != c.err {
+			t.Errorf("%s: the stream ended by %v, want %v", c.name, err, c.err)
+		}
+	}
+}
@@ -0,0 +1,106 @@
+package client_test
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"math/big"
+	"net"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/laincloud/lainlet/client"
+	"github.com/laincloud/lainlet/lainlettest"
+)
+
+// issue a certificate signed by parent, it is self-signed if parent is nil
+func issue(t *testing.T, cn string, parent *tls.Certificate, isCA bool) tls.Certificate {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tmpl := &x509.Certificate{
+		SerialNumber: big.NewInt(time.Now().UnixNano()),
+		Subject:      pkix.Name{CommonName: cn},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		KeyUsage:     x509.KeyUsageDigitalSignature | x509.KeyUsageKeyEncipherment,
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth, x509.ExtKeyUsageClientAuth},
+		IPAddresses:  []net.IP{net.ParseIP("127.0.0.1")},
+	}
+	if isCA {
+		tmpl.IsCA, tmpl.BasicConstraintsValid = true, true
+		tmpl.KeyUsage |= x509.KeyUsageCertSign
+	}
+	parentCert, parentKey := tmpl, interface{}(key)
+	if parent != nil {
+		parentCert, parentKey = parent.Leaf, parent.PrivateKey
+	}
+	der, err := x509.CreateCertificate(rand.Reader, tmpl, parentCert, &key.PublicKey, parentKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	leaf, err := x509.ParseCertificate(der)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return tls.Certificate{Certificate: [][]byte{der}, PrivateKey: key, Leaf: leaf}
+}
+
+func TestTLS(t *testing.T) {
+	ca := issue(t, "lain ca", nil, true)
+	pool := x509.NewCertPool()
+	pool.AddCert(ca.Leaf)
+	serverCert := issue(t, "lainlet", &ca, false)
+	clientCert := issue(t, "hello", &ca, false)
+
+	srv, err := lainlettest.NewServer(&lainlettest.Options{
+		Auth: true,
+		TLS: &tls.Config{
+			Certificates: []tls.Certificate{serverCert},
+			ClientCAs:    pool,
+			ClientAuth:   tls.RequireAndVerifyClientCert,
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer srv.Close()
+	if err := srv.AddApp("hello", lainlettest.Proc{Type: "web"}); err != nil {
+		t.Fatal(err)
+	}
+	if err := srv.AddApp("world", lainlettest.Proc{Type: "web"}); err != nil {
+		t.Fatal(err)
+	}
+
+	c := client.New(srv.HTTPAddr)
+	c.SetTLSConfig(&tls.Config{RootCAs: pool, Certificates: []tls.Certificate{clientCert}})
+	data, err := c.Get("/v2/coreinfowatcher/?appname=hello", 5*time.Second)
+	if err != nil {
+		t.Fatalf("fail to get the coreinfo by https, %s", err.Error())
+	}
+	if !strings.Contains(string(data), "hello.web.web") {
+		t.Errorf("unexpected coreinfo %s", data)
+	}
+	// the app is identified by the certificate
+	if _, err := c.Get("/v2/coreinfowatcher/?appname=world", 5*time.Second); err == nil {
+		t.Error("hello should not read the coreinfo of world")
+	}
+
+	// the scheme of the endpoint is preferred
+	c = client.New("http://" + srv.HTTPAddr)
+	c.SetTLSConfig(&tls.Config{RootCAs: pool, Certificates: []tls.Certificate{clientCert}})
+	if _, err := c.Get("/v2/coreinfowatcher/?appname=hello", 5*time.Second); err == nil {
+		t.Error("the request by http should fail")
+	}
+
+	c = srv.Client()
+	c.SetTLSConfig(&tls.Config{RootCAs: pool})
+	if _, err := c.Get("/v2/coreinfowatcher/?appname=hello", 5*time.Second); err == nil {
+		t.Error("the request without a client certificate should fail")
+	}
+}
@@ -0,0 +1,335 @@
+package client
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"golang.org/x/net/context"
+	"io"
+	"net/http"
+	"net/url"
+	"reflect"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// the defaults of WatchOptions
+const (
+	DefaultHeartbeat  = 10 * time.Second
+	DefaultMinBackoff = time.Second
+	DefaultMaxBackoff = 30 * time.Second
+)
+
+// WatchOptions is the options of WatchReconnect, the zero values mean the defaults
+type WatchOptions struct {
+	// the heartbeat interval requested, it is ignored if the uri has the heartbeat argument
+	Heartbeat time.Duration
+	// reconnect if nothing was received for HeartbeatTimeout, 3 heartbeats by default
+	HeartbeatTimeout time.Duration
+	// the backoff of reconnecting grows from MinBackoff to MaxBackoff exponentially
+	MinBackoff time.Duration
+	MaxBackoff time.Duration
+}
+
+func (opts *WatchOptions) withDefaults() WatchOptions {
+	var ret WatchOptions
+	if opts != nil {
+		ret = *opts
+	}
+	if ret.Heartbeat <= 0 {
+		ret.Heartbeat = DefaultHeartbeat
+	}
+	if ret.MinBackoff <= 0 {
+		ret.MinBackoff = DefaultMinBackoff
+	}
+	if ret.MaxBackoff < ret.MinBackoff {
+		ret.MaxBackoff = DefaultMaxBackoff
+		if ret.MaxBackoff < ret.MinBackoff {
+			ret.MaxBackoff = ret.MinBackoff
+		}
+	}
+	return ret
+}
+
+// WatchReconnect watch the uri like Watch, but reconnect with backoff if the connection failed,
+// or if nothing was received for the heartbeat timeout, sending the Last-Event-ID header.
+// With multiple endpoints, it reconnects to the next endpoint, and switches back to the preferred one after it recovered.
+// lainlet always send the full data in the init event of a new connection, it is skipped if the data did not change,
+// otherwise it is sent as a UPDATE event, so the events look like one connection.
+// The ERROR events are sent as they are, a ERROR event is also sent before reconnecting.
+// The channel is closed when ctx is done, or lainlet responded a status error which is not temporary, like 403, after sending it as a ERROR event.
+func (c *Client) WatchReconnect(ctx context.Context, uri string, opts *WatchOptions) <-chan *Response {
+	o := opts.withDefaults()
+	uri = withPatch(uri)
+	if u, err := url.Parse(uri); err == nil {
+		q := u.Query()
+		if q.Get("heartbeat") == "" {
+			q.Set("heartbeat", strconv.Itoa(int((o.Heartbeat+time.Second-1)/time.Second)))
+			u.RawQuery = q.Encode()
+			uri = u.String()
+		}
+		if seconds, err := strconv.Atoi(q.Get("heartbeat")); err == nil && seconds > 0 && o.HeartbeatTimeout <= 0 {
+			o.HeartbeatTimeout = 3 * time.Duration(seconds) * time.Second
+		}
+	}
+
+	respCh := make(chan *Response)
+	go func() {
+		defer close(respCh)
+		send := func(resp *Response) bool {
+			select {
+			case respCh <- resp:
+				return true
+			case <-ctx.Done():
+				return false
+			}
+		}
+		var (
+			last    []byte // the latest full data, nil before the first init event
+			lastID  string
+			backoff = o.MinBackoff
+		)
+		for {
+			received, err := c.watchOnce(ctx, uri, lastID, o.HeartbeatTimeout, func(resp *Response, id string) error {
+				resp, err := applyPatch(resp, last)
+				if err != nil {
+					return err
+				}
+				if isDataEvent(resp.Event) {
+					lastID = id // the heartbeats and errors are always sent with id 0
+					if resp.Event == INIT && last != nil {
+						if sameData(resp.Data, last) {
+							last = resp.Data
+							return nil
+						}
+						resp.Event = UPDATE
+					}
+					last = resp.Data
+				}
+				if !send(resp) {
+					return ctx.Err()
+				}
+				return nil
+			})
+			if ctx.Err() != nil {
+				return
+			}
+			if err == errSwitched {
+				continue
+			}
+			if se, ok := err.(*StatusError); ok && !se.Temporary() {
+				send(&Response{Event: ERROR, Data: []byte(err.Error())})
+				return
+			}
+			if err == nil {
+				err = io.EOF
+			}
+			if !send(&Response{Event: ERROR, Data: []byte("watch interrupted, reconnecting, " + err.Error())}) {
+				return
+			}
+			if received {
+				backoff = o.MinBackoff
+			}
+			select {
+			case <-time.After(backoff):
+			case <-ctx.Done():
+				return
+			}
+			if backoff *= 2; backoff > o.MaxBackoff {
+				backoff = o.MaxBackoff
+			}
+		}
+	}()
+	return respCh
+}
+
+// sameData check if the json data decode to the same value, so the different encoding of the same data, like the order of the keys, is ignored.
+// The data can not be decoded is compared by bytes.
+func sameData(a, b []byte) bool {
+	var va, vb interface{}
+	if json.Unmarshal(a, &va) != nil || json.Unmarshal(b, &vb) != nil {
+		return bytes.Equal(a, b)
+	}
+	return reflect.DeepEqual(va, vb)
+}
+
+// watchOnce watch the uri by one connection until it failed, or ctx is done, or fn returned error.
+// received tells if any event was received, the connection is closed if nothing was received for timeout,
+// or if a endpoint preferred to the one connected recovered, then errSwitched is returned.
+func (c *Client) watchOnce(ctx context.Context, uri, lastID string, timeout time.Duration, fn func(resp *Response, id string) error) (received bool, err error) {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	var header http.Header
+	if lastID != "" {
+		header = http.Header{"Last-Event-ID": []string{lastID}}
+	}
+	reader, addr, err := c.do(ctx, uri, 0, true, header)
+	if err != nil {
+		return false, err
+	}
+	defer reader.Close()
+
+	var (
+		lock   sync.Mutex
+		reason error // why the connection was closed by the client
+	)
+	stop := func(err error) {
+		lock.Lock()
+		if reason == nil {
+			reason = err
+		}
+		lock.Unlock()
+		cancel()
+	}
+	if timeout > 0 {
+		timer := time.AfterFunc(timeout, func() {
+			c.eps.fail(addr)
+			stop(errHeartbeatTimeout)
+		})
+		defer timer.Stop()
+		reader = &deadlineReader{ReadCloser: reader, timer: timer, timeout: timeout}
+	}
+	go c.probePreferred(ctx, addr, func() { stop(errSwitched) })
+
+	events := newEventReader(reader)
+	for {
+		resp, err := events.next()
+		if err != nil {
+			lock.Lock()
+			defer lock.Unlock()
+			if reason != nil {
+				err = reason
+			}
+			return received, err
+		}
+		received = true
+		if err := fn(resp, events.lastID); err != nil {
+			return received, err
+		}
+	}
+}
+
+var (
+	errHeartbeatTimeout = errors.New("no heartbeat received in time")
+	errSwitched         = errors.New("switched to the preferred endpoint")
+)
+
+// deadlineReader reset the timer every time some data was read
+type deadlineReader struct {
+	io.ReadCloser
+	timer   *time.Timer
+	timeout time.Duration
+}
+
+func (r *deadlineReader) Read(p []byte) (int, error) {
+	n, err := r.ReadCloser.Read(p)
+	if n > 0 {
+		r.timer.Reset(r.timeout)
+	}
+	return n, err
+}
+
+// watchDecoded watch the uri by WatchReconnect in background, and send the value decoded from the data of each INIT, UPDATE and DELETE event to ch,
+// which is a channel of the type returned by decode. The data can not be decoded is skipped, and ch is closed when the watch stopped.
+func (c *Client) watchDecoded(ctx context.Context, uri string, ch interface{}, decode func(data []byte) (interface{}, error)) {
+	chv := reflect.ValueOf(ch)
+	go func() {
+		defer chv.Close()
+		for resp := range c.WatchReconnect(ctx, uri, nil) {
+			if !isDataEvent(resp.Event) {
+				continue
+			}
+			v, err := decode(resp.Data)
+			if err != nil {
+				continue
+			}
+			chosen, _, _ := reflect.Select([]reflect.SelectCase{
+				{Dir: reflect.SelectSend, Chan: chv, Send: reflect.ValueOf(v)},
+				{Dir: reflect.SelectRecv, Chan: reflect.ValueOf(ctx.Done())},
+			})
+			if chosen != 0 {
+				return
+			}
+		}
+	}()
+}
+
+// WatchConfig watch /v2/configwatcher by WatchReconnect, the channel receive the full data every time it changed,
+// and is closed when ctx is done or the watch failed permanently, the data can not be decoded is skipped.
+// The other WatchXxx helpers work the same way.
+func (c *Client) WatchConfig(ctx context.Context, target string) <-chan Config {
+	ch := make(chan Config)
+	c.watchDecoded(ctx, "/v2/configwatcher?target="+url.QueryEscape(target), ch, func(data []byte) (interface{}, error) {
+		var v Config
+		err := json.Unmarshal(data, &v)
+		return v, err
+	})
+	return ch
+}
+
+// WatchCoreInfo watch /v2/coreinfowatcher, all the apps if appname is empty
+func (c *Client) WatchCoreInfo(ctx context.Context, appname string) <-chan CoreInfos {
+	ch := make(chan CoreInfos)
+	c.watchDecoded(ctx, "/v2/coreinfowatcher?appname="+url.QueryEscape(appname), ch, func(data []byte) (interface{}, error) {
+		var v CoreInfos
+		err := json.Unmarshal(data, &v)
+		return v, err
+	})
+	return ch
+}
+
+// WatchPodGroups watch /v2/procwatcher
+func (c *Client) WatchPodGroups(ctx context.Context, appname string) <-chan PodGroups {
+	ch := make(chan PodGroups)
+	c.watchDecoded(ctx, "/v2/procwatcher?appname="+url.QueryEscape(appname), ch, func(data []byte) (interface{}, error) {
+		var v PodGroups
+		err := json.Unmarshal(data, &v)
+		return v, err
+	})
+	return ch
+}
+
+// WatchProxy watch /v2/proxywatcher, all the apps if appname is empty
+func (c *Client) WatchProxy(ctx context.Context, appname string) <-chan Proxy {
+	ch := make(chan Proxy)
+	c.watchDecoded(ctx, "/v2/proxywatcher?appname="+url.QueryEscape(appname), ch, func(data []byte) (interface{}, error) {
+		var v Proxy
+		err := json.Unmarshal(data, &v)
+		return v, err
+	})
+	return ch
+}
+
+// WatchDepends watch /v2/depends, all the depends if target is empty
+func (c *Client) WatchDepends(ctx context.Context, target string) <-chan Depends {
+	ch := make(chan Depends)
+	c.watchDecoded(ctx, "/v2/depends?target="+url.QueryEscape(target), ch, func(data []byte) (interface{}, error) {
+		var v Depends
+		err := json.Unmarshal(data, &v)
+		return v, err
+	})
+	return ch
+}
+
+// WatchNodes watch /v2/nodes, all the nodes if name is empty
+func (c *Client) WatchNodes(ctx context.Context, name string) <-chan NodesInfo {
+	ch := make(chan NodesInfo)
+	c.watchDecoded(ctx, "/v2/nodes?name="+url.QueryEscape(name), ch, func(data []byte) (interface{}, error) {
+		var v NodesInfo
+		err := json.Unmarshal(data, &v)
+		return v, err
+	})
+	return ch
+}
+
+// WatchContainers watch /v2/containers, the containers on all the nodes if nodename is empty
+func (c *Client) WatchContainers(ctx context.Context, nodename string) <-chan Containers {
+	ch := make(chan Containers)
+	c.watchDecoded(ctx, "/v2/containers?nodename="+url.QueryEscape(nodename), ch, func(data []byte) (interface{}, error) {
+		var v Containers
+		err := json.Unmarshal(data, &v)
+		return v, err
+	})
+	return ch
+}
@@ -0,0 +1,221 @@
+package client
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"golang.org/x/net/context"
+)
+
+// scriptServer serve each watch request by the next handler, and record the requests
+type scriptServer struct {
+	*httptest.Server
+	handlers []http.HandlerFunc
+
+	lock     sync.Mutex
+	requests []*http.Request
+	times    []time.Time
+}
+
+func newScriptServer(handlers ...http.HandlerFunc) *scriptServer {
+	s := &scriptServer{handlers: handlers}
+	s.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		s.lock.Lock()
+		n := len(s.requests)
+		s.requests = append(s.requests, r)
+		s.times = append(s.times, time.Now())
+		s.lock.Unlock()
+		if n >= len(s.handlers) {
+			<-r.Context().Done()
+			return
+		}
+		s.handlers[n](w, r)
+	}))
+	return s
+}
+
+func (s *scriptServer) requested() ([]*http.Request, []time.Time) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	return append([]*http.Request(nil), s.requests...), append([]time.Time(nil), s.times...)
+}
+
+// stream write the events, and keep the connection open if hang is true
+func stream(hang bool, events ...string) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/event-stream")
+		for _, e := range events {
+			fmt.Fprint(w, e)
+		}
+		w.(http.Flusher).Flush()
+		if hang {
+			<-r.Context().Done()
+		}
+	}
+}
+
+func status(code int, message string) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, message, code)
+	}
+}
+
+func event(id int, name, data string) string {
+	return fmt.Sprintf("id: %d\nevent: %s\ndata: %s\n\n", id, name, data)
+}
+
+// expectResponses receive the responses, the data of ERROR events are only checked by prefix
+func expectResponses(t *testing.T, ch <-chan *Response, want ...string) {
+	for _, w := range want {
+		select {
+		case resp, ok := <-ch:
+			if !ok {
+				t.Fatalf("the channel is closed, want %q", w)
+			}
+			got := resp.Event + " " + string(resp.Data)
+			if resp.Event == ERROR && strings.HasPrefix(got, w) {
+				continue
+			}
+			if got != w {
+				t.Errorf("got %q, want %q", got, w)
+			}
+		case <-time.After(5 * time.Second):
+			t.Fatalf("no response received, want %q", w)
+		}
+	}
+}
+
+func TestWatchReconnect(t *testing.T) {
+	srv := newScriptServer(
+		stream(false, event(1, INIT, `{"a":1,"b":[1,2]}`)),
+		// the same data encoded in another way is skipped
+		stream(false, event(1, INIT, `{"b": [1, 2], "a": 1.0}`), event(2, UPDATE, `{"a":2}`)),
+		status(http.StatusServiceUnavailable, "overloaded"),
+		// the changed data in the new init event is sent as a update
+		stream(false, event(3, INIT, `{"a":3}`), "id: 0\nevent: heartbeat\n\n"),
+		status(http.StatusForbidden, "no permission"),
+	)
+	defer srv.Close()
+
+	c := New(srv.URL)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	ch := c.WatchReconnect(ctx, "/v2/configwatcher?target=vips", &WatchOptions{MinBackoff: 40 * time.Millisecond, MaxBackoff: 160 * time.Millisecond})
+	expectResponses(t, ch,
+		INIT+` {"a":1,"b":[1,2]}`,
+		ERROR+" watch interrupted, reconnecting",
+		UPDATE+` {"a":2}`,
+		ERROR+" watch interrupted, reconnecting",
+		ERROR+" watch interrupted, reconnecting, lainlet responded 503",
+		UPDATE+` {"a":3}`,
+		"heartbeat ",
+		ERROR+" watch interrupted, reconnecting",
+		ERROR+" lainlet responded 403",
+	)
+	select {
+	case resp, ok := <-ch:
+		if ok {
+			t.Errorf("got %+v after the permanent error, want the channel closed", resp)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("the channel is not closed after the permanent error")
+	}
+
+	requests, times := srv.requested()
+	if len(requests) != 5 {
+		t.Fatalf("%d requests received, want 5", len(requests))
+	}
+	lastIDs := []string{"", "1", "2", "2", "3"}
+	for i, r := range requests {
+		if id := r.Header.Get("Last-Event-ID"); id != lastIDs[i] {
+			t.Errorf("request %d has Last-Event-ID %q, want %q", i, id, lastIDs[i])
+		}
+		if q := r.URL.Query(); q.Get("watch") != "1" || q.Get("heartbeat") != "10" || q.Get("patch") != "rfc6902" || q.Get("target") != "vips" {
+			t.Errorf("request %d has the arguments %s", i, r.URL.RawQuery)
+		}
+	}
+	// the backoff is reset after the events were received, and doubled after the failures
+	delays := []time.Duration{40, 40, 80, 40}
+	for i, d := range delays {
+		if delay := times[i+1].Sub(times[i]); delay < d*time.Millisecond || delay > (2*d+100)*time.Millisecond {
+			t.Errorf("the delay before request %d is %s, want about %dms", i+1, delay, d)
+		}
+	}
+}
+
+func TestWatchReconnectHeartbeatTimeout(t *testing.T) {
+	srv := newScriptServer(
+		stream(true, event(1, INIT, `{"a":1}`)),
+		stream(true, event(1, INIT, `{"a":1}`), event(2, UPDATE, `{"a":2}`)),
+	)
+	defer srv.Close()
+
+	c := New(srv.URL)
+	ctx, cancel := context.WithCancel(context.Background())
+	ch := c.WatchReconnect(ctx, "/v2/configwatcher?target=vips", &WatchOptions{HeartbeatTimeout: 100 * time.Millisecond, MinBackoff: 10 * time.Millisecond})
+	expectResponses(t, ch,
+		INIT+` {"a":1}`,
+		ERROR+" watch interrupted, reconnecting, "+errHeartbeatTimeout.Error(),
+		UPDATE+` {"a":2}`,
+	)
+	cancel()
+	for range ch {
+	}
+}
+
+func TestWatchConfig(t *testing.T) {
+	srv := newScriptServer(
+		stream(true, event(1, INIT, `{"vips":"a"}`), event(2, UPDATE, `not json`), "id: 0\nevent: heartbeat\n\n", event(3, UPDATE, `{"vips":"b"}`)),
+	)
+	defer srv.Close()
+
+	c := New(srv.URL)
+	ctx, cancel := context.WithCancel(context.Background())
+	ch := c.WatchConfig(ctx, "vips")
+	// the data can not be decoded and the events without data are skipped
+	for _, want := range []string{"a", "b"} {
+		select {
+		case config := <-ch:
+			if config["vips"] != want {
+				t.Errorf("got the config %v, want the vips %q", config, want)
+			}
+		case <-time.After(5 * time.Second):
+			t.Fatalf("no config received, want the vips %q", want)
+		}
+	}
+	cancel()
+	select {
+	case _, ok := <-ch:
+		if ok {
+			t.Error("the channel should be closed after the context was done")
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("the channel is not closed after the context was done")
+	}
+}
+
+func TestSameData(t *testing.T) {
+	cases := []struct {
+		a, b string
+		same bool
+	}{
+		{`{"a":1,"b":2}`, `{"b":2,"a":1}`, true},
+		{`{"a":[1,2]}`, `{ "a" : [ 1, 2 ] }`, true},
+		{`{"a":1}`, `{"a":1.0}`, true},
+		{`{"a":[1,2]}`, `{"a":[2,1]}`, false},
+		{`{"a":1}`, `{"a":"1"}`, false},
+		{`{"a":null}`, `{}`, false},
+		{`not json`, `not json`, true},
+		{`not json`, `{}`, false},
+	}
+	for _, c := range cases {
+		if same := sameData([]byte(c.a), []byte(c.b)); same != c.same {
+			t.Errorf("sameData(%s, %s) = %v, want %v", c.a, c.b, same, c.same)
+		}
+	}
+}
@@ -0,0 +1,421 @@
+// Package conf is the configuration of lainlet.
+// The configuration is read from a yaml file, and each item can be overridden by a environment variable,
+// named by "LAINLET_" and the upper case yaml path joined by "_", like `LAINLET_GRPC_ADDR`.
+// The list items are separated by comma in the environment variables.
+package conf
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"reflect"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/laincloud/lainlet/ipaddr"
+	"github.com/mijia/sweb/log"
+	"gopkg.in/yaml.v2"
+)
+
+// EnvPrefix is the prefix of the environment variables overriding the configuration
+const EnvPrefix = "LAINLET_"
+
+// Config is the configuration of lainlet
+type Config struct {
+	Web    string       `yaml:"web" json:"web"`       // the address of the http server
+	Etcd   string       `yaml:"etcd" json:"etcd"`     // the etcd cluster entry points, separated by comma
+	IP     string       `yaml:"ip" json:"ip"`         // the ip of the node lainlet running on
+	Debug  bool         `yaml:"debug" json:"debug"`   // open the debug log
+	NoAuth bool         `yaml:"noauth" json:"noauth"` // close auth
+	WebTLS WebTLSConfig `yaml:"web_tls" json:"web_tls"`
+	// the reverse proxies or load balancers in front of the http server
+	WebProxy WebProxyConfig `yaml:"web_proxy" json:"web_proxy"`
+	GRPC     GRPCConfig     `yaml:"grpc" json:"grpc"`
+	Unix     UnixConfig     `yaml:"unix" json:"unix"`
+
+	// the keys in store
+	Keys KeysConfig `yaml:"keys" json:"keys"`
+
+	// the config keys only super apps can read in the default policy, matched by prefix, reloadable
+	SecretKeys []string `yaml:"secret_keys" json:"secret_keys"`
+	// the file of the access control policy, which is preferred to the policy in store, reloadable
+	PolicyFile string `yaml:"policy_file" json:"policy_file"`
+	// the file recording every decision of the policy in json lines, "-" means the standard output, reloadable
+	AuditLog string `yaml:"audit_log" json:"audit_log"`
+	// the time to wait before retrying when the store fail, reloadable
+	RetryInterval Duration `yaml:"retry_interval" json:"retry_interval"`
+	// webrouter api return error if the ratio of the alive containers is less than it, reloadable
+	WebrouterMinAliveRatio float64 `yaml:"webrouter_min_alive_ratio" json:"webrouter_min_alive_ratio"`
+	// the file of the key verifying the bearer tokens, the tokens are not accepted if it is empty, reloadable
+	TokenKeyFile string `yaml:"token_key_file" json:"token_key_file"`
+	// the limits of the requests of each client, reloadable
+	Limits LimitsConfig `yaml:"limits" json:"limits"`
+}
+
+// WebTLSConfig is the configuration of the https server, https is used if Cert is not empty
+type WebTLSConfig struct {
+	Cert string `yaml:"cert" json:"cert"`
+	Key  string `yaml:"key" json:"key"`
+	// the CA verifying the client certificates, whose common name is used as the app name of the client
+	ClientCA string `yaml:"client_ca" json:"client_ca"`
+	// reject the clients without a verified certificate
+	RequireClientCert bool `yaml:"require_client_cert" json:"require_client_cert"`
+}
+
+// WebProxyConfig is the configuration of the reverse proxies or load balancers in front of the http server
+type WebProxyConfig struct {
+	// the networks of the trusted proxies like "10.0.0.0/8" or "fd00::/8", or a single ip, reloadable.
+	// The address of the client is read from X-Forwarded-For or X-Real-IP of the requests from them.
+	Trusted []string `yaml:"trusted" json:"trusted"`
+	// accept the PROXY protocol header from the trusted proxies on the tcp address of the http server
+	ProxyProtocol bool `yaml:"proxy_protocol" json:"proxy_protocol"`
+}
+
+// Trusts check if the ip of the address is in the networks of the trusted proxies
+func (p WebProxyConfig) Trusts(addr string) bool {
+	for _, s := range p.Trusted {
+		if n, err := ipaddr.ParseNet(s); err == nil && ipaddr.Contains(n, addr) { // validated when loading
+			return true
+		}
+	}
+	return false
+}
+
+// GRPCConfig is the configuration of the grpc server
+type GRPCConfig struct {
+	Addr string `yaml:"addr" json:"addr"`
+	TLS  bool   `yaml:"tls" json:"tls"`
+	Key  string `yaml:"key" json:"key"`
+	Cert string `yaml:"cert" json:"cert"`
+	// ping the client after the connection is idle for KeepaliveTime, and close it if the ping is not acked in KeepaliveTimeout,
+	// so the dead clients are found in KeepaliveTime+KeepaliveTimeout even if there are only idle watch streams
+	KeepaliveTime    Duration `yaml:"keepalive_time" json:"keepalive_time"`
+	KeepaliveTimeout Duration `yaml:"keepalive_timeout" json:"keepalive_timeout"`
+	// the clients pinging more often than it are disconnected
+	KeepaliveMinTime Duration `yaml:"keepalive_min_time" json:"keepalive_min_time"`
+	// close the connection gracefully after it is alive for MaxConnectionAge, the streams are closed after MaxConnectionAgeGrace then,
+	// 0 means infinity. It makes the long lived clients reconnect, and spread over the lainlets behind a load balancer.
+	MaxConnectionAge      Duration `yaml:"max_connection_age" json:"max_connection_age"`
+	MaxConnectionAgeGrace Duration `yaml:"max_connection_age_grace" json:"max_connection_age_grace"`
+	// the max size of the messages in bytes, 0 means the default of grpc
+	MaxRecvMsgSize int `yaml:"max_recv_msg_size" json:"max_recv_msg_size"`
+	MaxSendMsgSize int `yaml:"max_send_msg_size" json:"max_send_msg_size"`
+}
+
+// UnixConfig is the configuration of the unix sockets, the servers listen on them besides the tcp addresses
+type UnixConfig struct {
+	Web  string `yaml:"web" json:"web"`   // the socket of the http server, not listen if empty
+	GRPC string `yaml:"grpc" json:"grpc"` // the socket of the grpc server, not listen if empty
+	Mode string `yaml:"mode" json:"mode"` // the file mode of the sockets in octal, like "0660"
+	// the peers whose uid or gid is in the lists are trusted as super apps
+	TrustedUIDs []uint32 `yaml:"trusted_uids" json:"trusted_uids"`
+	TrustedGIDs []uint32 `yaml:"trusted_gids" json:"trusted_gids"`
+}
+
+// FileMode return the file mode of the sockets
+func (u UnixConfig) FileMode() os.FileMode {
+	mode, _ := strconv.ParseUint(u.Mode, 8, 32) // validated when loading
+	return os.FileMode(mode)
+}
+
+// LimitConfig is the limit of the requests of each client to a api, the zero values mean unlimited
+type LimitConfig struct {
+	Rate    float64 `yaml:"rate" json:"rate"`       // the requests per second, both get and watch requests are counted
+	Burst   int     `yaml:"burst" json:"burst"`     // the max requests in a burst, at least 1 if Rate is set
+	Watches int     `yaml:"watches" json:"watches"` // the max concurrent watches
+}
+
+// LimitsConfig is the limits of the requests of each client
+type LimitsConfig struct {
+	Default LimitConfig            `yaml:"default" json:"default"`
+	APIs    map[string]LimitConfig `yaml:"apis" json:"apis"` // the limits of the apis by uri, like "/coreinfowatcher", instead of Default
+}
+
+// For return the limit of the api by its uri
+func (l LimitsConfig) For(uri string) LimitConfig {
+	if limit, ok := l.APIs[uri]; ok {
+		return limit
+	}
+	return l.Default
+}
+
+// KeysConfig is the keys watched in store
+type KeysConfig struct {
+	Config    string `yaml:"config" json:"config"`
+	Depends   string `yaml:"depends" json:"depends"`
+	PodGroups string `yaml:"pod_groups" json:"pod_groups"`
+	Nodes     string `yaml:"nodes" json:"nodes"`
+	SuperApps string `yaml:"super_apps" json:"super_apps"`
+	Policy    string `yaml:"policy" json:"policy"`
+}
+
+// Duration is a time.Duration written like "3s" in the configuration
+type Duration time.Duration
+
+// D return d as a time.Duration
+func (d Duration) D() time.Duration {
+	return time.Duration(d)
+}
+
+func (d Duration) String() string {
+	return time.Duration(d).String()
+}
+
+func (d *Duration) parse(s string) error {
+	v, err := time.ParseDuration(s)
+	if err != nil {
+		return err
+	}
+	*d = Duration(v)
+	return nil
+}
+
+// UnmarshalYAML implements yaml.Unmarshaler
+func (d *Duration) UnmarshalYAML(unmarshal func(interface{}) error) error {
+	var s string
+	if err := unmarshal(&s); err != nil {
+		return err
+	}
+	return d.parse(s)
+}
+
+// MarshalYAML implements yaml.Marshaler
+func (d Duration) MarshalYAML() (interface{}, error) {
+	return d.String(), nil
+}
+
+// MarshalJSON implements json.Marshaler
+func (d Duration) MarshalJSON() ([]byte, error) {
+	return json.Marshal(d.String())
+}
+
+// Default return the default configuration
+func Default() *Config {
+	return &Config{
+		Keys: KeysConfig{
+			Config:    "/lain/config",
+			Depends:   "/lain/deployd/depends/pods",
+			PodGroups: "/lain/deployd/pod_groups",
+			Nodes:     "/lain/nodes/nodes",
+			SuperApps: "/lain/config/super_apps",
+			Policy:    "/lain/config/lainlet_policy",
+		},
+		SecretKeys: []string{
+			"*",
+			"swarm_manager_ip",
+			"super_apps",
+			"dnsmasq_servers",
+			"calico_default_rule",
+			"calico_network",
+			"dnsmasq_addresses",
+			"ssl",
+			"vips",
+			"tinydns_fqdns",
+			"bootstrap_node_ip",
+			"dns_port",
+			"vip",
+			"etcd_cluster_token",
+			"system_volumes",
+			"rsyncd_secrets",
+			"dns_ip",
+			"node_network",
+		},
+		GRPC: GRPCConfig{
+			KeepaliveTime:    Duration(30 * time.Second),
+			KeepaliveTimeout: Duration(10 * time.Second),
+			KeepaliveMinTime: Duration(10 * time.Second),
+			MaxRecvMsgSize:   4 << 20,
+			MaxSendMsgSize:   64 << 20,
+		},
+		Unix: UnixConfig{
+			Mode:        "0660",
+			TrustedUIDs: []uint32{0},
+		},
+		RetryInterval:          Duration(3 * time.Second),
+		WebrouterMinAliveRatio: 0.5,
+	}
+}
+
+var (
+	current atomic.Value // *Config
+
+	mu       sync.Mutex
+	file     string
+	override func(*Config)
+)
+
+func init() {
+	current.Store(Default())
+}
+
+// Current return the effective configuration, it must not be modified.
+func Current() *Config {
+	return current.Load().(*Config)
+}
+
+// Load read the configuration from the file, empty file means only the defaults and the environment variables are used.
+// override is called after the environment variables are applied, usually used to apply the command line flags, it can be nil.
+// The file and override are remembered for Reload.
+func Load(filename string, fn func(*Config)) error {
+	mu.Lock()
+	defer mu.Unlock()
+	cfg, err := read(filename, fn)
+	if err != nil {
+		return err
+	}
+	file, override = filename, fn
+	current.Store(cfg)
+	return nil
+}
+
+// Reload read the configuration again, only the reloadable items take effect, the others need restarting lainlet.
+func Reload() error {
+	mu.Lock()
+	defer mu.Unlock()
+	cfg, err := read(file, override)
+	if err != nil {
+		return err
+	}
+	old := Current()
+	reloaded := *old
+	reloaded.SecretKeys = cfg.SecretKeys
+	reloaded.PolicyFile = cfg.PolicyFile
+	reloaded.AuditLog = cfg.AuditLog
+	reloaded.RetryInterval = cfg.RetryInterval
+	reloaded.WebrouterMinAliveRatio = cfg.WebrouterMinAliveRatio
+	reloaded.Limits = cfg.Limits
+	reloaded.TokenKeyFile = cfg.TokenKeyFile
+	reloaded.WebProxy.Trusted = cfg.WebProxy.Trusted
+	if !reflect.DeepEqual(&reloaded, cfg) {
+		log.Warnf("Some changes of the configuration only take effect after restarting lainlet")
+	}
+	current.Store(&reloaded)
+	log.Infof("Configuration reloaded from %q", file)
+	return nil
+}
+
+func read(filename string, fn func(*Config)) (*Config, error) {
+	cfg := Default()
+	if filename != "" {
+		content, err := ioutil.ReadFile(filename)
+		if err != nil {
+			return nil, err
+		}
+		if err := yaml.UnmarshalStrict(content, cfg); err != nil {
+			return nil, fmt.Errorf("invalid configuration file %s, %s", filename, err.Error())
+		}
+	}
+	if err := applyEnv(reflect.ValueOf(cfg).Elem(), strings.TrimSuffix(EnvPrefix, "_")); err != nil {
+		return nil, err
+	}
+	if fn != nil {
+		fn(cfg)
+	}
+	if _, err := strconv.ParseUint(cfg.Unix.Mode, 8, 32); err != nil {
+		return nil, fmt.Errorf("invalid unix.mode %q", cfg.Unix.Mode)
+	}
+	if cfg.RetryInterval <= 0 {
+		return nil, fmt.Errorf("retry_interval must be positive")
+	}
+	g := cfg.GRPC
+	if g.KeepaliveTime <= 0 || g.KeepaliveTimeout <= 0 || g.KeepaliveMinTime < 0 || g.MaxConnectionAge < 0 || g.MaxConnectionAgeGrace < 0 {
+		return nil, fmt.Errorf("invalid keepalive or connection age of grpc, keepalive_time and keepalive_timeout must be positive, the others must not be negative")
+	}
+	if g.MaxRecvMsgSize < 0 || g.MaxSendMsgSize < 0 {
+		return nil, fmt.Errorf("invalid message size of grpc, negative value")
+	}
+	if cfg.WebrouterMinAliveRatio < 0 || cfg.WebrouterMinAliveRatio > 1 {
+		return nil, fmt.Errorf("webrouter_min_alive_ratio must be in [0, 1]")
+	}
+	for _, s := range cfg.WebProxy.Trusted {
+		if _, err := ipaddr.ParseNet(s); err != nil {
+			return nil, fmt.Errorf("invalid trusted proxy %q in web_proxy, %s", s, err.Error())
+		}
+	}
+	limits := map[string]LimitConfig{"default": cfg.Limits.Default}
+	for uri, limit := range cfg.Limits.APIs {
+		limits[uri] = limit
+	}
+	for name, limit := range limits {
+		if limit.Rate < 0 || limit.Burst < 0 || limit.Watches < 0 {
+			return nil, fmt.Errorf("invalid limit of %s, negative value", name)
+		}
+	}
+	return cfg, nil
+}
+
+// applyEnv set the fields of v by the environment variables, prefix is the name of v
+func applyEnv(v reflect.Value, prefix string) error {
+	for i := 0; i < v.NumField(); i++ {
+		tag := strings.Split(v.Type().Field(i).Tag.Get("yaml"), ",")[0]
+		name := prefix + "_" + strings.ToUpper(tag)
+		field := v.Field(i)
+		if field.Kind() == reflect.Struct {
+			if err := applyEnv(field, name); err != nil {
+				return err
+			}
+			continue
+		}
+		value, ok := os.LookupEnv(name)
+		if !ok {
+			continue
+		}
+		if err := setValue(field, value); err != nil {
+			return fmt.Errorf("invalid environment variable %s, %s", name, err.Error())
+		}
+	}
+	return nil
+}
+
+func setValue(field reflect.Value, value string) error {
+	switch p := field.Addr().Interface().(type) {
+	case *Duration:
+		return p.parse(value)
+	case *string:
+		*p = value
+	case *bool:
+		b, err := strconv.ParseBool(value)
+		if err != nil {
+			return err
+		}
+		*p = b
+	case *int:
+		i, err := strconv.Atoi(value)
+		if err != nil {
+			return err
+		}
+		*p = i
+	case *float64:
+		f, err := strconv.ParseFloat(value, 64)
+		if err != nil {
+			return err
+		}
+		*p = f
+	case *[]string:
+		*p = nil
+		for _, item := range strings.Split(value, ",") {
+			if item = strings.TrimSpace(item); item != "" {
+				*p = append(*p, item)
+			}
+		}
+	case *[]uint32:
+		*p = nil
+		for _, item := range strings.Split(value, ",") {
+			if item = strings.TrimSpace(item); item == "" {
+				continue
+			}
+			i, err := strconv.ParseUint(item, 10, 32)
+			if err != nil {
+				return err
+			}
+			*p = append(*p, uint32(i))
+		}
+	default:
+		return fmt.Errorf("unsupported type %s", field.Type())
+	}
+	return nil
+}
@@ -0,0 +1,210 @@
+package conf
+
+import (
+	"io/ioutil"
+	"os"
+	"reflect"
+	"testing"
+	"time"
+)
+
+// writeFile write the configuration into a temporary file, the returned func removes it
+func writeFile(t *testing.T, content string) (string, func()) {
+	f, err := ioutil.TempFile("", "lainlet-conf")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	if _, err := f.WriteString(content); err != nil {
+		t.Fatal(err)
+	}
+	return f.Name(), func() { os.Remove(f.Name()) }
+}
+
+// setEnv set the environment variables, the returned func restores them
+func setEnv(env map[string]string) func() {
+	old := make(map[string]*string)
+	for k, v := range env {
+		if value, ok := os.LookupEnv(k); ok {
+			old[k] = &value
+		} else {
+			old[k] = nil
+		}
+		os.Setenv(k, v)
+	}
+	return func() {
+		for k, v := range old {
+			if v == nil {
+				os.Unsetenv(k)
+			} else {
+				os.Setenv(k, *v)
+			}
+		}
+	}
+}
+
+// reset restore the configuration after the test loading a file
+func reset() {
+	mu.Lock()
+	defer mu.Unlock()
+	file, override = "", nil
+	current.Store(Default())
+}
+
+func TestRead(t *testing.T) {
+	filename, remove := writeFile(t, `
+web: ":9001"
+ip: "10.0.0.1"
+debug: true
+grpc:
+  addr: ":9002"
+  keepalive_time: 1m
+unix:
+  trusted_uids: [0, 1000]
+secret_keys: ["vips"]
+limits:
+  default:
+    rate: 10
+  apis:
+    /coreinfowatcher:
+      watches: 2
+`)
+	defer remove()
+	defer setEnv(map[string]string{
+		"LAINLET_IP":                  "10.0.0.2",
+		"LAINLET_GRPC_ADDR":           ":9003",
+		"LAINLET_GRPC_KEEPALIVE_TIME": "2m",
+		"LAINLET_UNIX_TRUSTED_UIDS":   "0, 1001,",
+		"LAINLET_SECRET_KEYS":         "ssl,vips",
+		"LAINLET_DEBUG":               "false",
+	})()
+
+	cfg, err := read(filename, func(cfg *Config) {
+		cfg.GRPC.Addr = ":9004"
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	cases := []struct {
+		name      string
+		got, want interface{}
+	}{
+		{"web from the file", cfg.Web, ":9001"},
+		{"ip from the env", cfg.IP, "10.0.0.2"},
+		{"debug from the env", cfg.Debug, false},
+		{"grpc.addr from the flags", cfg.GRPC.Addr, ":9004"},
+		{"grpc.keepalive_time from the env", cfg.GRPC.KeepaliveTime, Duration(2 * time.Minute)},
+		{"grpc.keepalive_timeout by default", cfg.GRPC.KeepaliveTimeout, Duration(10 * time.Second)},
+		{"unix.trusted_uids from the env", cfg.Unix.TrustedUIDs, []uint32{0, 1001}},
+		{"unix.mode by default", cfg.Unix.Mode, "0660"},
+		{"secret_keys from the env", cfg.SecretKeys, []string{"ssl", "vips"}},
+		{"keys by default", cfg.Keys, Default().Keys},
+		{"the default limit from the file", cfg.Limits.For("/nodes"), LimitConfig{Rate: 10}},
+		{"the api limit from the file", cfg.Limits.For("/coreinfowatcher"), LimitConfig{Watches: 2}},
+	}
+	for _, c := range cases {
+		if !reflect.DeepEqual(c.got, c.want) {
+			t.Errorf("%s: got %v, want %v", c.name, c.got, c.want)
+		}
+	}
+}
+
+func TestReadInvalid(t *testing.T) {
+	files := []string{
+		"unknown: 1",
+		"web: [1]",
+		"retry_interval: 1",
+		"retry_interval: -1s",
+		"grpc:\n  keepalive_time: 0s",
+		"grpc:\n  max_connection_age: -1s",
+		"grpc:\n  max_recv_msg_size: -1",
+		"unix:\n  mode: \"0999\"",
+		"webrouter_min_alive_ratio: 1.5",
+		"web_proxy:\n  trusted: [\"10.0.0.0/33\"]",
+		"limits:\n  default:\n    rate: -1",
+		"limits:\n  apis:\n    /nodes:\n      watches: -1",
+	}
+	for _, content := range files {
+		filename, remove := writeFile(t, content)
+		if _, err := read(filename, nil); err == nil {
+			t.Errorf("the configuration %q should be invalid", content)
+		}
+		remove()
+	}
+
+	envs := []map[string]string{
+		{"LAINLET_DEBUG": "yes"},
+		{"LAINLET_GRPC_KEEPALIVE_TIME": "1"},
+		{"LAINLET_GRPC_MAX_RECV_MSG_SIZE": "1M"},
+		{"LAINLET_WEBROUTER_MIN_ALIVE_RATIO": "half"},
+		{"LAINLET_UNIX_TRUSTED_UIDS": "root"},
+	}
+	for _, env := range envs {
+		restore := setEnv(env)
+		if _, err := read("", nil); err == nil {
+			t.Errorf("the environment variables %v should be invalid", env)
+		}
+		restore()
+	}
+
+	if _, err := read("/nonexistent/lainlet.yaml", nil); err == nil {
+		t.Error("reading a nonexistent file should fail")
+	}
+	if _, err := read("", func(cfg *Config) { cfg.Unix.Mode = "rw" }); err == nil {
+		t.Error("the invalid value set by the flags should be rejected")
+	}
+}
+
+func TestReload(t *testing.T) {
+	defer reset()
+	filename, remove := writeFile(t, `
+web: ":9001"
+grpc:
+  addr: ":9002"
+secret_keys: ["vips"]
+retry_interval: 1s
+`)
+	defer remove()
+	if err := Load(filename, func(cfg *Config) { cfg.IP = "10.0.0.1" }); err != nil {
+		t.Fatal(err)
+	}
+	old := Current()
+
+	if err := ioutil.WriteFile(filename, []byte(`
+web: ":9011"
+grpc:
+  addr: ":9012"
+secret_keys: ["ssl"]
+retry_interval: 2s
+web_proxy:
+  trusted: ["10.0.0.0/8"]
+`), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := Reload(); err != nil {
+		t.Fatal(err)
+	}
+	cfg := Current()
+	if cfg.Web != ":9001" || cfg.GRPC.Addr != ":9002" {
+		t.Errorf("web and grpc.addr should not be reloaded, got %q and %q", cfg.Web, cfg.GRPC.Addr)
+	}
+	if cfg.IP != "10.0.0.1" {
+		t.Errorf("the flags should be applied again, got ip %q", cfg.IP)
+	}
+	if !reflect.DeepEqual(cfg.SecretKeys, []string{"ssl"}) || cfg.RetryInterval != Duration(2*time.Second) || !cfg.WebProxy.Trusts("10.1.2.3:1234") {
+		t.Errorf("the reloadable items are not reloaded, got %+v", cfg)
+	}
+	if old.RetryInterval != Duration(time.Second) || !reflect.DeepEqual(old.SecretKeys, []string{"vips"}) {
+		t.Error("the old configuration should not be changed")
+	}
+
+	if err := ioutil.WriteFile(filename, []byte("retry_interval: 0s"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := Reload(); err == nil {
+		t.Error("reloading a invalid configuration should fail")
+	}
+	if Current() != cfg {
+		t.Error("the configuration should be kept if reloading failed")
+	}
+}
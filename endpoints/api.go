@@ -0,0 +1,177 @@
+package endpoints
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"reflect"
+	"strings"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/laincloud/lainlet/api"
+	"github.com/laincloud/lainlet/fieldmask"
+	"github.com/laincloud/lainlet/store"
+	"github.com/laincloud/lainlet/watcher"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// APIEndpoint serve a api by grpc, the grpc service is described by the api itself.
+// It is the same api served by the http server, only the request and the reply are different.
+// The client is identified by the interceptors of the server before the api is called, see Identify.
+type APIEndpoint struct {
+	api   api.API
+	proto api.ProtoAPI
+	wch   watcher.Watcher
+	env   *api.Env
+}
+
+// NewAPIEndpoint create the endpoint of a, the requests are admitted by the limiter of env and the watches are listed in its connections
+func NewAPIEndpoint(a api.API, wch watcher.Watcher, env *api.Env) (*APIEndpoint, error) {
+	pa, ok := a.(api.ProtoAPI)
+	if !ok {
+		return nil, fmt.Errorf("%s do not support grpc", a.URI())
+	}
+	ed := &APIEndpoint{
+		api:   a,
+		proto: pa,
+		wch:   wch,
+		env:   env,
+	}
+	return ed, nil
+}
+
+// ServiceDesc return the description of the grpc service, which having a `Get` method,
+// and a `Watch` stream if the api support watch.
+func (ed *APIEndpoint) ServiceDesc() *grpc.ServiceDesc {
+	desc := &grpc.ServiceDesc{
+		ServiceName: ed.proto.ServiceName(),
+		HandlerType: (*interface{})(nil),
+		Methods: []grpc.MethodDesc{
+			{
+				MethodName: "Get",
+				Handler:    ed.getHandler,
+			},
+		},
+		Metadata: "message.proto",
+	}
+	if _, ok := ed.api.(api.BanWatcher); !ok {
+		desc.Streams = []grpc.StreamDesc{
+			{
+				StreamName:    "Watch",
+				Handler:       ed.watchHandler,
+				ServerStreams: true,
+			},
+		}
+	}
+	return desc
+}
+
+func (ed *APIEndpoint) Get(ctx context.Context, in proto.Message) (proto.Message, error) {
+	r, err := newRequest(ctx, in)
+	if err != nil {
+		return nil, err
+	}
+	if _, err = ed.env.Limiter.Admit(ed.api, r, false); err != nil {
+		return nil, status.Error(codes.ResourceExhausted, err.Error())
+	}
+	key, err := ed.api.Key(r)
+	if err != nil {
+		return nil, err
+	}
+	mask, err := fieldmask.Parse(r.GetStrings("fields"))
+	if err != nil {
+		return nil, err
+	}
+	instance, err := api.Get(ed.wch, ed.api, key, mask)
+	if err != nil {
+		return nil, err
+	}
+	return instance.(api.ProtoAPI).ProtoReply()
+}
+
+func (ed *APIEndpoint) Watch(in proto.Message, stream grpc.ServerStream) error {
+	ctx := stream.Context()
+	r, err := newRequest(ctx, in)
+	if err != nil {
+		return err
+	}
+	release, err := ed.env.Limiter.Admit(ed.api, r, true)
+	if err != nil {
+		return status.Error(codes.ResourceExhausted, err.Error())
+	}
+	defer release()
+	key, err := ed.api.Key(r)
+	if err != nil {
+		return err
+	}
+	mask, err := fieldmask.Parse(r.GetStrings("fields"))
+	if err != nil {
+		return err
+	}
+	return api.Watch(ctx, ed.env.Conns, ed.wch, ed.api, r, key, mask, func(id uint64, action store.Action, instance api.API, content []byte) error {
+		if action == store.ERROR {
+			return fmt.Errorf("got an error from store, ID: %v, Data: %s", id, content)
+		}
+		reply, err := instance.(api.ProtoAPI).ProtoReply()
+		if err != nil {
+			return err
+		}
+		return stream.SendMsg(reply)
+	})
+}
+
+func (ed *APIEndpoint) getHandler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := ed.proto.ProtoRequest()
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return ed.Get(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/" + ed.proto.ServiceName() + "/Get",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return ed.Get(ctx, req.(proto.Message))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func (ed *APIEndpoint) watchHandler(srv interface{}, stream grpc.ServerStream) error {
+	in := ed.proto.ProtoRequest()
+	if err := stream.RecvMsg(in); err != nil {
+		return err
+	}
+	return ed.Watch(in, stream)
+}
+
+// newRequest create a api request from the grpc request message for the client identified in ctx,
+// each string field of the message is a argument, named by the lower case field name.
+func newRequest(ctx context.Context, in proto.Message) (*api.Request, error) {
+	client, ok := RequestFromContext(ctx)
+	if !ok {
+		return nil, status.Error(codes.Unauthenticated, "the client is not identified")
+	}
+	r := *client
+	r.Args = make(url.Values)
+	v := reflect.Indirect(reflect.ValueOf(in))
+	for i := 0; i < v.NumField(); i++ {
+		f := v.Type().Field(i)
+		if f.PkgPath != "" {
+			continue
+		}
+		name := strings.ToLower(f.Name)
+		switch val := v.Field(i).Interface().(type) {
+		case string:
+			if val != "" {
+				r.Args.Set(name, val)
+			}
+		case []string:
+			r.Args[name] = val
+		}
+	}
+	return &r, nil
+}
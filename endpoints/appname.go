@@ -2,42 +2,31 @@ package endpoints
 
 import (
 	"context"
-	"fmt"
 
-	"github.com/laincloud/lainlet/auth"
+	"github.com/laincloud/lainlet/api"
 	pb "github.com/laincloud/lainlet/message"
 )
 
+// AppnameEndpoint serve the /appname api by grpc, see api.AppName
 type AppnameEndpoint struct {
 	name string
 }
 
 func NewAppnameEndpoint() *AppnameEndpoint {
 	wh := &AppnameEndpoint{
-		name:  "Appname",
+		name: "Appname",
 	}
 	return wh
 }
 
 func (ed *AppnameEndpoint) Get(ctx context.Context, in *pb.AppnameRequest) (*pb.AppnameReply, error) {
-	remoteAddr, err := getRemoteAddr(ctx)
+	r, err := newRequest(ctx, in)
 	if err != nil {
 		return nil, err
 	}
-	if !auth.IsSuper(remoteAddr) {
-		return nil, fmt.Errorf("authorize failed, super required")
-	}
-	ip := remoteAddr
-	if in.Ip != "" {
-		ip = in.Ip
-	}
-	appname, err := auth.AppName(ip)
+	data, err := api.AppName(r)
 	if err != nil {
 		return nil, err
 	}
-	ret := &pb.AppnameReply{
-		Data:make(map[string]string),
-	}
-	ret.Data["appname"] = appname
-	return ret, nil
+	return &pb.AppnameReply{Data: data}, nil
 }
@@ -0,0 +1,102 @@
+package endpoints
+
+import (
+	"context"
+	"fmt"
+	"io"
+
+	"github.com/golang/protobuf/ptypes"
+	"github.com/laincloud/lainlet/api"
+	pb "github.com/laincloud/lainlet/message"
+	"github.com/laincloud/lainlet/store"
+	"github.com/laincloud/lainlet/watcher"
+)
+
+// SubscriptionEndpoint serve the multiplexed subscription of the apis served by the endpoints.
+// The client ADD the watches of the apis by their services and requests, and REMOVE them by tags at any time.
+type SubscriptionEndpoint struct {
+	endpoints map[string]*APIEndpoint // by service name
+	env       *api.Env
+}
+
+func NewSubscriptionEndpoint(eds []*APIEndpoint, env *api.Env) *SubscriptionEndpoint {
+	ed := &SubscriptionEndpoint{
+		endpoints: make(map[string]*APIEndpoint, len(eds)),
+		env:       env,
+	}
+	for _, e := range eds {
+		ed.endpoints[e.proto.ServiceName()] = e
+	}
+	return ed
+}
+
+func (ed *SubscriptionEndpoint) lookup(name string) (api.API, watcher.Watcher, bool) {
+	e, ok := ed.endpoints[name]
+	if !ok {
+		return nil, nil, false
+	}
+	return e.api, e.wch, true
+}
+
+func (ed *SubscriptionEndpoint) Subscribe(stream pb.Subscription_SubscribeServer) error {
+	ctx := stream.Context()
+	sub := api.NewSubscription(ctx, ed.env, ed.lookup, func(tag string, id uint64, action store.Action, instance api.API, content []byte) error {
+		ev := &pb.SubscribeEvent{Tag: tag, ID: id, Action: action.String()}
+		if action == store.ERROR {
+			ev.Error = string(content)
+		} else {
+			reply, err := instance.(api.ProtoAPI).ProtoReply()
+			if err != nil {
+				return err
+			}
+			if ev.Reply, err = ptypes.MarshalAny(reply); err != nil {
+				return err
+			}
+		}
+		return stream.Send(ev)
+	})
+	defer sub.Close()
+
+	for {
+		in, err := stream.Recv()
+		if err == io.EOF {
+			<-ctx.Done() // the client will not change the subscription, keep sending the events
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		switch in.Op {
+		case pb.SubscribeRequest_ADD:
+			err = ed.add(ctx, sub, in)
+		case pb.SubscribeRequest_REMOVE:
+			err = sub.Remove(in.Tag)
+		default:
+			err = fmt.Errorf("unknown operation %s", in.Op)
+		}
+		if err != nil {
+			if err := sub.SendError(in.Tag, err); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+// add start watching the api by the request in the message, for the client identified in ctx
+func (ed *SubscriptionEndpoint) add(ctx context.Context, sub *api.Subscription, in *pb.SubscribeRequest) error {
+	e, ok := ed.endpoints[in.Service]
+	if !ok {
+		return fmt.Errorf("unknown service %s", in.Service)
+	}
+	req := e.proto.ProtoRequest()
+	if in.Request != nil {
+		if err := ptypes.UnmarshalAny(in.Request, req); err != nil {
+			return fmt.Errorf("invalid request of %s, %s", in.Service, err.Error())
+		}
+	}
+	r, err := newRequest(ctx, req)
+	if err != nil {
+		return err
+	}
+	return sub.Add(in.Tag, in.Service, r)
+}
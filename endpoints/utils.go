@@ -5,18 +5,55 @@ import (
 	"fmt"
 	"net"
 
+	"github.com/laincloud/lainlet/api"
+	"github.com/laincloud/lainlet/auth"
+	"github.com/laincloud/lainlet/unixsock"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/metadata"
 	"google.golang.org/grpc/peer"
 )
 
-func fixPrefix(s string) string {
-	l := len(s)
-	if l == 0 {
-		return s
+type requestKey struct{}
+
+// Identify resolve the client of the grpc call in ctx by the peer address, the verified certificate and the bearer token in metadata,
+// a is the authorizer of the server, and the unix socket peers are looked up in peers.
+// The returned request has no arguments, which are filled by each endpoint from its request message.
+func Identify(ctx context.Context, a auth.Authorizer, peers *unixsock.Peers) (*api.Request, error) {
+	remoteAddr, err := getRemoteAddr(ctx)
+	if err != nil {
+		return nil, err
+	}
+	r := &api.Request{
+		RemoteAddr: remoteAddr,
+		Protocol:   api.ProtocolGRPC,
+		Identity:   getIdentity(ctx),
+		Peer:       peers.Lookup(remoteAddr),
+		Auth:       a,
 	}
-	if s[l-1] == '/' {
-		return s
+	if md, ok := metadata.FromIncomingContext(ctx); ok && len(md["authorization"]) > 0 {
+		r.Token, r.TokenErr = api.ParseAuthorization(a, md["authorization"][0])
+	}
+	return r.Cached(), nil
+}
+
+// NewContext return a context carrying the request of the identified client, the endpoints serve the client in it.
+func NewContext(ctx context.Context, r *api.Request) context.Context {
+	return context.WithValue(ctx, requestKey{}, r)
+}
+
+// RequestFromContext return the request of the client identified by Identify, it must not be modified.
+func RequestFromContext(ctx context.Context) (*api.Request, bool) {
+	r, ok := ctx.Value(requestKey{}).(*api.Request)
+	return r, ok
+}
+
+// RemoteAddr return the address of the peer of the grpc call
+func RemoteAddr(ctx context.Context) string {
+	addr, err := getRemoteAddr(ctx)
+	if err != nil {
+		return "unknown"
 	}
-	return s + "/"
+	return addr
 }
 
 func getRemoteAddr(ctx context.Context) (string, error) {
@@ -29,3 +66,16 @@ func getRemoteAddr(ctx context.Context) (string, error) {
 	}
 	return pr.Addr.String(), nil
 }
+
+// getIdentity return the app name in the verified client certificate, empty if there is none
+func getIdentity(ctx context.Context) string {
+	pr, ok := peer.FromContext(ctx)
+	if !ok {
+		return ""
+	}
+	info, ok := pr.AuthInfo.(credentials.TLSInfo)
+	if !ok {
+		return ""
+	}
+	return api.CertIdentity(&info.State)
+}
@@ -0,0 +1,362 @@
+// Package fieldmask implements the field projection used by the `fields` argument of apis.
+// A mask is a list of dot separated paths, like `PodInfos.ContainerInfos.ContainerIp`,
+// each name in a path matches the struct field name or the json name of the field, case insensitively.
+// The fields of the embedded structs are promoted like encoding/json does, so they are selected by their own names.
+// Maps and slices are transparent, the mask is applied to each of their items.
+package fieldmask
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+)
+
+// Mask is a parsed field mask, a nil Mask selects everything.
+type Mask map[string]Mask
+
+// Parse parse the given paths into a Mask, paths can also be comma separated.
+func Parse(paths []string) (Mask, error) {
+	var mask Mask
+	for _, item := range paths {
+		for _, path := range strings.Split(item, ",") {
+			path = strings.TrimSpace(path)
+			if path == "" {
+				continue
+			}
+			if mask == nil {
+				mask = make(Mask)
+			}
+			node := mask
+			for _, name := range strings.Split(path, ".") {
+				if name == "" {
+					return nil, fmt.Errorf("invalid field path %q", path)
+				}
+				name = strings.ToLower(name)
+				child, ok := node[name]
+				if !ok {
+					child = make(Mask)
+					node[name] = child
+				}
+				node = child
+			}
+		}
+	}
+	return mask, nil
+}
+
+// String return the paths of the mask, sorted.
+func (m Mask) String() string {
+	paths := make([]string, 0)
+	m.walk("", func(path string) { paths = append(paths, path) })
+	sort.Strings(paths)
+	return strings.Join(paths, ",")
+}
+
+func (m Mask) walk(prefix string, f func(string)) {
+	for name, child := range m {
+		if len(child) == 0 {
+			f(prefix + name)
+		} else {
+			child.walk(prefix+name+".", f)
+		}
+	}
+}
+
+// Validate check if all the paths in mask can be found in the type of v.
+func (m Mask) Validate(v interface{}) error {
+	if len(m) == 0 {
+		return nil
+	}
+	return validate(reflect.TypeOf(v), m, "")
+}
+
+func validate(t reflect.Type, m Mask, prefix string) error {
+	for t.Kind() == reflect.Ptr || t.Kind() == reflect.Map || t.Kind() == reflect.Slice || t.Kind() == reflect.Array {
+		t = t.Elem()
+	}
+	if t.Kind() != reflect.Struct {
+		return fmt.Errorf("field %q has no sub fields", strings.TrimSuffix(prefix, "."))
+	}
+	fs := fields(t)
+	for name, child := range m {
+		f, ok := lookup(fs, name)
+		if !ok {
+			return fmt.Errorf("unknown field %q", prefix+name)
+		}
+		if len(child) > 0 {
+			if err := validate(f.typ, child, prefix+name+"."); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// Select return a json-encodable copy of v, which only having the fields in mask.
+// v itself will not be changed.
+func Select(v interface{}, m Mask) interface{} {
+	if len(m) == 0 {
+		return v
+	}
+	return selectValue(reflect.ValueOf(v), m)
+}
+
+func selectValue(v reflect.Value, m Mask) interface{} {
+	switch v.Kind() {
+	case reflect.Ptr, reflect.Interface:
+		if v.IsNil() {
+			return nil
+		}
+		return selectValue(v.Elem(), m)
+	case reflect.Map:
+		if v.IsNil() {
+			return nil
+		}
+		ret := make(map[string]interface{}, v.Len())
+		for _, k := range v.MapKeys() {
+			ret[fmt.Sprint(k.Interface())] = selectValue(v.MapIndex(k), m)
+		}
+		return ret
+	case reflect.Slice, reflect.Array:
+		if v.Kind() == reflect.Slice && v.IsNil() {
+			return nil
+		}
+		ret := make([]interface{}, v.Len())
+		for i := 0; i < v.Len(); i++ {
+			ret[i] = selectValue(v.Index(i), m)
+		}
+		return ret
+	case reflect.Struct:
+		ret := make(map[string]interface{}, len(m))
+		for _, f := range fields(v.Type()) {
+			child, ok := f.match(m)
+			if !ok {
+				continue
+			}
+			fv, ok := fieldByIndex(v, f.index)
+			if !ok {
+				continue // in a nil embedded struct
+			}
+			if len(child) == 0 {
+				ret[f.name] = fv.Interface()
+			} else {
+				ret[f.name] = selectValue(fv, child)
+			}
+		}
+		return ret
+	}
+	return v.Interface()
+}
+
+// Keep return a copy of v in the same type, the fields not in mask are zero values.
+// The maps, slices and pointers on the paths of the mask are copied, so v itself will not be changed.
+func Keep(v interface{}, m Mask) interface{} {
+	if len(m) == 0 || v == nil {
+		return v
+	}
+	return keepValue(reflect.ValueOf(v), m).Interface()
+}
+
+func keepValue(v reflect.Value, m Mask) reflect.Value {
+	switch v.Kind() {
+	case reflect.Ptr:
+		if v.IsNil() {
+			return v
+		}
+		ret := reflect.New(v.Type().Elem())
+		ret.Elem().Set(keepValue(v.Elem(), m))
+		return ret
+	case reflect.Interface:
+		if v.IsNil() {
+			return v
+		}
+		ret := reflect.New(v.Type()).Elem()
+		ret.Set(keepValue(v.Elem(), m))
+		return ret
+	case reflect.Map:
+		if v.IsNil() {
+			return v
+		}
+		ret := reflect.MakeMapWithSize(v.Type(), v.Len())
+		for _, k := range v.MapKeys() {
+			ret.SetMapIndex(k, keepValue(v.MapIndex(k), m))
+		}
+		return ret
+	case reflect.Slice:
+		if v.IsNil() {
+			return v
+		}
+		ret := reflect.MakeSlice(v.Type(), v.Len(), v.Len())
+		for i := 0; i < v.Len(); i++ {
+			ret.Index(i).Set(keepValue(v.Index(i), m))
+		}
+		return ret
+	case reflect.Array:
+		ret := reflect.New(v.Type()).Elem()
+		for i := 0; i < v.Len(); i++ {
+			ret.Index(i).Set(keepValue(v.Index(i), m))
+		}
+		return ret
+	case reflect.Struct:
+		ret := reflect.New(v.Type()).Elem()
+		for _, f := range fields(v.Type()) {
+			child, ok := f.match(m)
+			if !ok {
+				continue
+			}
+			fv, ok := fieldByIndex(v, f.index)
+			if !ok {
+				continue
+			}
+			if len(child) > 0 {
+				fv = keepValue(fv, child)
+			}
+			setFieldByIndex(ret, f.index, fv)
+		}
+		return ret
+	}
+	return v
+}
+
+// field is a field of a struct seen by encoding/json, the fields of the embedded structs are promoted
+type field struct {
+	name   string // the json name
+	goName string // the name in the struct
+	index  []int
+	typ    reflect.Type
+}
+
+func (f *field) match(m Mask) (Mask, bool) {
+	if child, ok := m[strings.ToLower(f.goName)]; ok {
+		return child, true
+	}
+	child, ok := m[strings.ToLower(f.name)]
+	return child, ok
+}
+
+// fields return the fields of the struct type t like encoding/json, the fields of the embedded structs having no json name
+// are promoted, a field hides the promoted ones having the same name in the deeper structs, and the ones in the same depth hide each other.
+func fields(t reflect.Type) []field {
+	var ret []field
+	seen := map[reflect.Type]bool{t: true}
+	current := []field{{typ: t}}
+	for len(current) > 0 {
+		var next []field
+		depth := make(map[string][]field) // the fields in this depth by json name
+		var names []string
+		for _, embedded := range current {
+			st := embedded.typ
+			for i := 0; i < st.NumField(); i++ {
+				sf := st.Field(i)
+				name := jsonName(sf)
+				if name == "-" {
+					continue
+				}
+				index := append(append([]int(nil), embedded.index...), i)
+				ft := sf.Type
+				if ft.Kind() == reflect.Ptr {
+					ft = ft.Elem()
+				}
+				if sf.Anonymous && !hasJSONName(sf) && ft.Kind() == reflect.Struct {
+					// the fields of a unexported embedded struct can not be read by reflect, they are ignored
+					if sf.PkgPath == "" && !seen[ft] {
+						seen[ft] = true
+						next = append(next, field{index: index, typ: ft})
+					}
+					continue
+				}
+				if sf.PkgPath != "" {
+					continue
+				}
+				if _, ok := depth[name]; !ok {
+					names = append(names, name)
+				}
+				depth[name] = append(depth[name], field{name: name, goName: sf.Name, index: index, typ: sf.Type})
+			}
+		}
+		for _, name := range names {
+			if fs := depth[name]; len(fs) == 1 && !hidden(ret, name) {
+				ret = append(ret, fs[0])
+			}
+		}
+		for _, name := range names {
+			if len(depth[name]) > 1 {
+				ret = append(ret, field{name: name}) // hide the deeper ones, it is removed below
+			}
+		}
+		current = next
+	}
+	visible := ret[:0]
+	for _, f := range ret {
+		if f.typ != nil {
+			visible = append(visible, f)
+		}
+	}
+	return visible
+}
+
+func hidden(fs []field, name string) bool {
+	for _, f := range fs {
+		if f.name == name {
+			return true
+		}
+	}
+	return false
+}
+
+// fieldByIndex return the field of v by the index, it return false if the field is in a nil embedded struct
+func fieldByIndex(v reflect.Value, index []int) (reflect.Value, bool) {
+	for i, x := range index {
+		if i > 0 && v.Kind() == reflect.Ptr {
+			if v.IsNil() {
+				return reflect.Value{}, false
+			}
+			v = v.Elem()
+		}
+		v = v.Field(x)
+	}
+	return v, true
+}
+
+// setFieldByIndex set the field of v by the index, the nil embedded structs on the way are allocated
+func setFieldByIndex(v reflect.Value, index []int, value reflect.Value) {
+	for i, x := range index {
+		if i > 0 && v.Kind() == reflect.Ptr {
+			if v.IsNil() {
+				v.Set(reflect.New(v.Type().Elem()))
+			}
+			v = v.Elem()
+		}
+		v = v.Field(x)
+	}
+	v.Set(value)
+}
+
+func jsonName(f reflect.StructField) string {
+	tag := f.Tag.Get("json")
+	if idx := strings.IndexByte(tag, ','); idx >= 0 {
+		tag = tag[:idx]
+	}
+	if tag == "" {
+		return f.Name
+	}
+	return tag
+}
+
+func hasJSONName(f reflect.StructField) bool {
+	tag := f.Tag.Get("json")
+	if idx := strings.IndexByte(tag, ','); idx >= 0 {
+		tag = tag[:idx]
+	}
+	return tag != ""
+}
+
+func lookup(fs []field, name string) (field, bool) {
+	for _, f := range fs {
+		if strings.ToLower(f.goName) == name || strings.ToLower(f.name) == name {
+			return f, true
+		}
+	}
+	return field{}, false
+}
@@ -0,0 +1,248 @@
+package fieldmask
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+)
+
+type container struct {
+	ContainerIp string `json:"container_ip"`
+	Port        int
+}
+
+type pod struct {
+	InstanceNo int         `json:"instance_no"`
+	Containers []container `json:"containers,omitempty"`
+	Env        map[string]string
+	Secret     string `json:"-"`
+	hidden     string
+}
+
+type podGroup struct {
+	Name string
+	Pods []*pod `json:"pods"`
+}
+
+func TestParse(t *testing.T) {
+	cases := []struct {
+		paths []string
+		mask  string
+	}{
+		{nil, ""},
+		{[]string{""}, ""},
+		{[]string{"Name"}, "name"},
+		{[]string{"pods.Containers.ContainerIp"}, "pods.containers.containerip"},
+		{[]string{"Name,pods.instance_no", " pods.Env "}, "name,pods.env,pods.instance_no"},
+		{[]string{"pods.env", "pods"}, "pods.env"},
+		{[]string{"a,,b"}, "a,b"},
+	}
+	for _, c := range cases {
+		mask, err := Parse(c.paths)
+		if err != nil {
+			t.Errorf("Parse(%q) failed, %s", c.paths, err.Error())
+			continue
+		}
+		if mask.String() != c.mask {
+			t.Errorf("Parse(%q) = %q, want %q", c.paths, mask.String(), c.mask)
+		}
+	}
+	if mask, _ := Parse(nil); mask != nil {
+		t.Errorf("Parse(nil) = %v, want nil", mask)
+	}
+	for _, paths := range [][]string{{"pods..env"}, {".name"}, {"name."}} {
+		if _, err := Parse(paths); err == nil {
+			t.Errorf("Parse(%q) should fail", paths)
+		}
+	}
+}
+
+func TestValidate(t *testing.T) {
+	cases := []struct {
+		path string
+		ok   bool
+	}{
+		{"", true},
+		{"Name", true},
+		{"name", true},
+		{"pods", true},
+		{"Pods.InstanceNo", true},
+		{"pods.instance_no", true},
+		{"pods.containers.container_ip", true},
+		{"pods.Containers.containerip", true},
+		{"pods.env", true},
+		{"unknown", false},
+		{"pods.unknown", false},
+		{"pods.containers.unknown", false},
+		{"name.sub", false},
+		{"pods.env.key", false},
+		{"pods.secret", false},
+		{"pods.hidden", false},
+	}
+	for _, c := range cases {
+		mask, err := Parse([]string{c.path})
+		if err != nil {
+			t.Fatalf("Parse(%q) failed, %s", c.path, err.Error())
+		}
+		if err := mask.Validate(map[string]podGroup{}); (err == nil) != c.ok {
+			t.Errorf("Validate(%q) = %v, want ok %v", c.path, err, c.ok)
+		}
+	}
+}
+
+func TestSelect(t *testing.T) {
+	data := map[string]*podGroup{
+		"hello.web.web": {
+			Name: "hello.web.web",
+			Pods: []*pod{
+				{
+					InstanceNo: 1,
+					Containers: []container{{ContainerIp: "172.20.0.2", Port: 8080}},
+					Env:        map[string]string{"A": "1"},
+					Secret:     "secret",
+					hidden:     "hidden",
+				},
+				nil,
+			},
+		},
+	}
+	cases := []struct {
+		path, want string
+	}{
+		{"", `{"hello.web.web":{"Name":"hello.web.web","pods":[{"instance_no":1,"containers":[{"container_ip":"172.20.0.2","Port":8080}],"Env":{"A":"1"}},null]}}`},
+		{"name", `{"hello.web.web":{"Name":"hello.web.web"}}`},
+		{"pods.instanceno", `{"hello.web.web":{"pods":[{"instance_no":1},null]}}`},
+		{"Pods.Containers.container_ip", `{"hello.web.web":{"pods":[{"containers":[{"container_ip":"172.20.0.2"}]},null]}}`},
+		{"pods.containers,name", `{"hello.web.web":{"Name":"hello.web.web","pods":[{"containers":[{"container_ip":"172.20.0.2","Port":8080}]},null]}}`},
+		{"pods.env", `{"hello.web.web":{"pods":[{"Env":{"A":"1"}},null]}}`},
+		{"pods.secret,pods.hidden", `{"hello.web.web":{"pods":[{},null]}}`},
+		{"unknown", `{"hello.web.web":{}}`},
+	}
+	for _, c := range cases {
+		mask, err := Parse([]string{c.path})
+		if err != nil {
+			t.Fatalf("Parse(%q) failed, %s", c.path, err.Error())
+		}
+		content, err := json.Marshal(Select(data, mask))
+		if err != nil {
+			t.Errorf("fail to encode the selection of %q, %s", c.path, err.Error())
+			continue
+		}
+		if string(content) != c.want {
+			t.Errorf("Select(%q) = %s, want %s", c.path, content, c.want)
+		}
+	}
+	if data["hello.web.web"].Pods[0].Secret != "secret" || len(data["hello.web.web"].Pods[0].Containers) != 1 {
+		t.Error("Select should not change the data")
+	}
+}
+
+type Meta struct {
+	Owner string `json:"owner"`
+	Name  string
+}
+
+type Runtime struct {
+	State string `json:"state"`
+}
+
+type Labels struct {
+	Team string
+}
+
+type spec struct {
+	Image string
+}
+
+// app embed the structs, the fields of Meta and Runtime are promoted, Labels is not since it has a json name
+type app struct {
+	Meta
+	*Runtime
+	spec
+	Labels `json:"labels"`
+	Name   string // hide Meta.Name
+}
+
+func TestEmbedded(t *testing.T) {
+	a := app{Meta: Meta{Owner: "alice", Name: "meta"}, Labels: Labels{Team: "lain"}, spec: spec{Image: "hello"}, Name: "hello"}
+	cases := []struct {
+		path, want string
+	}{
+		{"owner", `{"owner":"alice"}`},
+		{"Owner,name", `{"Name":"hello","owner":"alice"}`},
+		{"state", `{}`}, // Runtime is nil
+		{"labels.team", `{"labels":{"Team":"lain"}}`},
+		{"team", `{}`},
+		{"meta", `{}`},
+		{"image", `{}`},
+	}
+	for _, c := range cases {
+		mask, _ := Parse([]string{c.path})
+		content, err := json.Marshal(Select(a, mask))
+		if err != nil {
+			t.Errorf("fail to encode the selection of %q, %s", c.path, err.Error())
+			continue
+		}
+		if string(content) != c.want {
+			t.Errorf("Select(%q) = %s, want %s", c.path, content, c.want)
+		}
+	}
+	for path, ok := range map[string]bool{"owner": true, "state": true, "name": true, "labels.team": true, "team": false, "meta": false, "image": false} {
+		mask, _ := Parse([]string{path})
+		if err := mask.Validate(a); (err == nil) != ok {
+			t.Errorf("Validate(%q) = %v, want ok %v", path, err, ok)
+		}
+	}
+
+	// the promoted field in the nil embedded struct is allocated when it is kept
+	a.Runtime = &Runtime{State: "running"}
+	mask, _ := Parse([]string{"owner,state"})
+	kept := Keep(a, mask).(app)
+	want := app{Meta: Meta{Owner: "alice"}, Runtime: &Runtime{State: "running"}}
+	if !reflect.DeepEqual(kept, want) {
+		t.Errorf("Keep(owner,state) = %+v, want %+v", kept, want)
+	}
+	if kept.Runtime == a.Runtime {
+		t.Error("Keep should copy the embedded pointer")
+	}
+}
+
+func TestKeep(t *testing.T) {
+	data := map[string]*podGroup{
+		"hello.web.web": {
+			Name: "hello.web.web",
+			Pods: []*pod{
+				{
+					InstanceNo: 1,
+					Containers: []container{{ContainerIp: "172.20.0.2", Port: 8080}},
+					Env:        map[string]string{"A": "1"},
+				},
+				nil,
+			},
+		},
+	}
+	mask, _ := Parse([]string{"pods.containers.container_ip,pods.env"})
+	kept := Keep(data, mask).(map[string]*podGroup)
+	want := map[string]*podGroup{
+		"hello.web.web": {
+			Pods: []*pod{
+				{
+					Containers: []container{{ContainerIp: "172.20.0.2"}},
+					Env:        map[string]string{"A": "1"},
+				},
+				nil,
+			},
+		},
+	}
+	if !reflect.DeepEqual(kept, want) {
+		content, _ := json.Marshal(kept)
+		t.Errorf("Keep = %s", content)
+	}
+	kept["hello.web.web"].Pods[0].Containers[0].ContainerIp = "changed"
+	if data["hello.web.web"].Pods[0].Containers[0].ContainerIp != "172.20.0.2" || data["hello.web.web"].Pods[0].Containers[0].Port != 8080 {
+		t.Error("Keep should not change the data")
+	}
+	if Keep(data, nil).(map[string]*podGroup)["hello.web.web"] != data["hello.web.web"] {
+		t.Error("Keep with a empty mask should return the data itself")
+	}
+}
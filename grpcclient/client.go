@@ -3,32 +3,81 @@ package grpcclient
 import (
 	"context"
 	"fmt"
+	"os"
 	"time"
 
 	"sync"
 
+	"github.com/golang/protobuf/proto"
 	pb "github.com/laincloud/lainlet/message"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/keepalive"
 )
 
 const (
+	// RPC_TIMEOUT is the default timeout in seconds, it can be overridden by the LAINLET_RPC_TIMEOUT environment variable, like "10s"
 	RPC_TIMEOUT = 10
+
+	// DefaultKeepaliveTime and DefaultKeepaliveTimeout are the defaults of Config.KeepaliveTime and Config.KeepaliveTimeout
+	DefaultKeepaliveTime    = 30 * time.Second
+	DefaultKeepaliveTimeout = 10 * time.Second
+	// DefaultMaxRecvMsgSize is the default of Config.MaxRecvMsgSize, larger than the 4MB of grpc for the large replies like CoreinfoReply
+	DefaultMaxRecvMsgSize = 64 << 20
 )
 
+// defaultTimeout return the timeout used when Config.Timeout is not set
+func defaultTimeout() time.Duration {
+	if d, err := time.ParseDuration(os.Getenv("LAINLET_RPC_TIMEOUT")); err == nil && d > 0 {
+		return d
+	}
+	return RPC_TIMEOUT * time.Second
+}
+
 type Config struct {
 	Addr               string
 	CertFile           string
 	ServerNameOverride string
 	Timeout            int
+	Token              string // the bearer token minted by tools/token, sent with every call
+	// ping lainlet after the connection is idle for KeepaliveTime, and close it if the ping is not acked in KeepaliveTimeout,
+	// then the watches fail in KeepaliveTime+KeepaliveTimeout when lainlet is dead.
+	// KeepaliveTime should not be less than the keepalive_min_time of lainlet, which is 10s by default.
+	KeepaliveTime    time.Duration
+	KeepaliveTimeout time.Duration
+	// the max size of the messages in bytes, the default of grpc is used for MaxSendMsgSize if it is 0
+	MaxRecvMsgSize int
+	MaxSendMsgSize int
+	// the ordered lainlet endpoints, like the local lainlet and then the others, Addr is ignored if it is set.
+	// The calls go to the first endpoint connected, and go back to the preferred one after it recovered,
+	// the reconnecting watchers watch again on the new endpoint when the endpoint changed.
+	// The endpoints should use the same certificate with tls, since the first one is the authority of all.
+	Addrs []string
+	// called with the new endpoint when the calls switched to another endpoint, it should not block
+	OnEndpointChange func(addr string)
+}
+
+// tokenCreds send the bearer token in the "authorization" metadata
+type tokenCreds string
+
+func (t tokenCreds) GetRequestMetadata(ctx context.Context, uri ...string) (map[string]string, error) {
+	return map[string]string{"authorization": "Bearer " + string(t)}, nil
+}
+
+// RequireTransportSecurity is false, because lainlet may serve grpc without tls inside the cluster
+func (t tokenCreds) RequireTransportSecurity() bool {
+	return false
 }
 
 type Client struct {
 	addr    string
 	cfg     *Config
 	timeout time.Duration
+	eps     *endpoints
+	conn    *grpc.ClientConn
 
-	lainletClient pb.LainletClient
+	lainletClient      pb.LainletClient
+	subscriptionClient pb.SubscriptionClient
 
 	appnameClient    pb.AppnameClient
 	appsClient       pb.AppsClient
@@ -60,16 +109,36 @@ func New(cfg *Config) (*Client, error) {
 	} else {
 		opts = append(opts, grpc.WithInsecure())
 	}
-	conn, err := grpc.Dial(cfg.Addr, opts...)
+	if cfg.Token != "" {
+		opts = append(opts, grpc.WithPerRPCCredentials(tokenCreds(cfg.Token)))
+	}
+	opts = append(opts, connOptions(cfg)...)
+	addrs := cfg.Addrs
+	if len(addrs) == 0 {
+		addrs = []string{cfg.Addr}
+	}
+	eps := newEndpoints(addrs, cfg.OnEndpointChange)
+	target := addrs[0]
+	if len(addrs) > 1 {
+		target = eps.target()
+		opts = append(opts, grpc.WithBalancerName(priorityBalancer))
+	} else {
+		eps.active = target
+	}
+	conn, err := grpc.Dial(target, opts...)
 	if err != nil {
+		eps.release()
 		return nil, err
 	}
 
 	cli := &Client{
-		addr:          cfg.Addr,
-		cfg:           cfg,
-		lainletClient: pb.NewLainletClient(conn),
-		appnameClient: pb.NewAppnameClient(conn),
+		addr:               addrs[0],
+		cfg:                cfg,
+		eps:                eps,
+		conn:               conn,
+		lainletClient:      pb.NewLainletClient(conn),
+		subscriptionClient: pb.NewSubscriptionClient(conn),
+		appnameClient:      pb.NewAppnameClient(conn),
 
 		appsClient:                    pb.NewAppsClient(conn),
 		backupctlClient:               pb.NewBackupctlClient(conn),
@@ -88,7 +157,7 @@ func New(cfg *Config) (*Client, error) {
 	}
 
 	if cfg.Timeout <= 0 {
-		cli.timeout = RPC_TIMEOUT * time.Second
+		cli.timeout = defaultTimeout()
 	} else {
 		cli.timeout = time.Duration(cfg.Timeout) * time.Second
 	}
@@ -96,36 +165,91 @@ func New(cfg *Config) (*Client, error) {
 	return cli, nil
 }
 
+// Close close the connection to lainlet, the calls and the watches of the client fail after it was closed
+func (cli *Client) Close() error {
+	cli.eps.release()
+	return cli.conn.Close()
+}
+
+// ActiveEndpoint return the endpoint the calls go to, it is the last one connected if none is connected now
+func (cli *Client) ActiveEndpoint() string {
+	addr, _ := cli.eps.get()
+	return addr
+}
+
+// connOptions return the options of the keepalive and message size in cfg
+func connOptions(cfg *Config) []grpc.DialOption {
+	ka := keepalive.ClientParameters{
+		Time:                cfg.KeepaliveTime,
+		Timeout:             cfg.KeepaliveTimeout,
+		PermitWithoutStream: true,
+	}
+	if ka.Time <= 0 {
+		ka.Time = DefaultKeepaliveTime
+	}
+	if ka.Timeout <= 0 {
+		ka.Timeout = DefaultKeepaliveTimeout
+	}
+	callOpts := []grpc.CallOption{grpc.MaxCallRecvMsgSize(DefaultMaxRecvMsgSize)}
+	if cfg.MaxRecvMsgSize > 0 {
+		callOpts[0] = grpc.MaxCallRecvMsgSize(cfg.MaxRecvMsgSize)
+	}
+	if cfg.MaxSendMsgSize > 0 {
+		callOpts = append(callOpts, grpc.MaxCallSendMsgSize(cfg.MaxSendMsgSize))
+	}
+	return []grpc.DialOption{grpc.WithKeepaliveParams(ka), grpc.WithDefaultCallOptions(callOpts...)}
+}
+
 func (cli *Client) Version() (*pb.VersionReply, error) {
-	req := &pb.EmptyRequest{}
 	ctx, cancel := context.WithTimeout(context.Background(), cli.timeout)
 	defer cancel()
+	return cli.VersionContext(ctx)
+}
+
+// VersionContext is Version with a context, the timeout of the client is not applied.
+func (cli *Client) VersionContext(ctx context.Context) (*pb.VersionReply, error) {
+	req := &pb.EmptyRequest{}
 	rpl, err := cli.lainletClient.Version(ctx, req)
 	return rpl, err
 }
 
 func (cli *Client) Status() (*pb.StatusReply, error) {
-	req := &pb.EmptyRequest{}
 	ctx, cancel := context.WithTimeout(context.Background(), cli.timeout)
 	defer cancel()
+	return cli.StatusContext(ctx)
+}
+
+// StatusContext is Status with a context, the timeout of the client is not applied.
+func (cli *Client) StatusContext(ctx context.Context) (*pb.StatusReply, error) {
+	req := &pb.EmptyRequest{}
 	rpl, err := cli.lainletClient.Status(ctx, req)
 	return rpl, err
 }
 
 // Appname only has Get rpc
 func (cli *Client) AppnameGet(ip string) (*pb.AppnameReply, error) {
-	req := &pb.AppnameRequest{Ip: ip}
 	ctx, cancel := context.WithTimeout(context.Background(), cli.timeout)
 	defer cancel()
+	return cli.AppnameGetContext(ctx, ip)
+}
+
+// AppnameGetContext is AppnameGet with a context, the timeout of the client is not applied.
+func (cli *Client) AppnameGetContext(ctx context.Context, ip string) (*pb.AppnameReply, error) {
+	req := &pb.AppnameRequest{Ip: ip}
 	rpl, err := cli.appnameClient.Get(ctx, req)
 	return rpl, err
 }
 
 // Localspec only has Get rpc
 func (cli *Client) LocalspecGet(nodeip string) (*pb.LocalspecReply, error) {
-	req := &pb.LocalspecRequest{Nodeip: nodeip}
 	ctx, cancel := context.WithTimeout(context.Background(), cli.timeout)
 	defer cancel()
+	return cli.LocalspecGetContext(ctx, nodeip)
+}
+
+// LocalspecGetContext is LocalspecGet with a context, the timeout of the client is not applied.
+func (cli *Client) LocalspecGetContext(ctx context.Context, nodeip string) (*pb.LocalspecReply, error) {
+	req := &pb.LocalspecRequest{Nodeip: nodeip}
 	rpl, err := cli.localspecClient.Get(ctx, req)
 	return rpl, err
 }
@@ -146,9 +270,14 @@ func (cli *Client) LocalspecGet(nodeip string) (*pb.LocalspecReply, error) {
 
 //CODE GENERATION Apps START
 func (cli *Client) AppsGet() (*pb.AppsReply, error) {
-	req := &pb.AppsRequest{}
 	ctx, cancel := context.WithTimeout(context.Background(), cli.timeout)
 	defer cancel()
+	return cli.AppsGetContext(ctx)
+}
+
+// AppsGetContext is AppsGet with a context, the timeout of the client is not applied.
+func (cli *Client) AppsGetContext(ctx context.Context) (*pb.AppsReply, error) {
+	req := &pb.AppsRequest{}
 	rpl, err := cli.appsClient.Get(ctx, req)
 	return rpl, err
 }
@@ -167,8 +296,13 @@ func (wch *AppsWatcher) Next() (*pb.AppsReply, error) {
 	return rpl, err
 }
 func (cli *Client) AppsWatch() (*AppsWatcher, error) {
+	return cli.AppsWatchContext(context.Background())
+}
+
+// AppsWatchContext is AppsWatch with a context, the stream is closed when ctx is done.
+func (cli *Client) AppsWatchContext(ctx context.Context) (*AppsWatcher, error) {
 	req := &pb.AppsRequest{}
-	stream, err := cli.appsClient.Watch(context.Background(), req)
+	stream, err := cli.appsClient.Watch(ctx, req)
 	if err != nil {
 		return nil, err
 	}
@@ -190,12 +324,50 @@ func (cli *Client) AppsWatch() (*AppsWatcher, error) {
 	}()
 	return wch, nil
 }
+
+// AppsReconnectWatcher is the watcher reconnecting when the stream was broken, see ReconnectWatcher.
+type AppsReconnectWatcher struct {
+	*ReconnectWatcher
+}
+
+// Next return the next reply, the error is not nil only when the watcher stopped.
+func (wch *AppsReconnectWatcher) Next() (*pb.AppsReply, error) {
+	rpl, err := wch.next()
+	if err != nil {
+		return nil, err
+	}
+	return rpl.(*pb.AppsReply), nil
+}
+
+// AppsWatchReconnect watch like AppsWatch, and reconnect with backoff until ctx is done, opts can be nil.
+func (cli *Client) AppsWatchReconnect(ctx context.Context, opts *ReconnectOptions) *AppsReconnectWatcher {
+	req := &pb.AppsRequest{}
+	return &AppsReconnectWatcher{cli.newReconnectWatcher(ctx, opts, func(ctx context.Context) (func() (proto.Message, error), error) {
+		stream, err := cli.appsClient.Watch(ctx, req)
+		if err != nil {
+			return nil, err
+		}
+		return func() (proto.Message, error) { return stream.Recv() }, nil
+	})}
+}
+
+// AppsInformer create the informer caching the Data of the AppsReply, all the items are resynced every resync period if it is positive.
+func (cli *Client) AppsInformer(resync time.Duration) *Informer {
+	return newInformer(resync, func(ctx context.Context) *ReconnectWatcher {
+		return cli.AppsWatchReconnect(ctx, nil).ReconnectWatcher
+	})
+}
 //CODE GENERATION Apps END
 //CODE GENERATION Backupctl START
 func (cli *Client) BackupctlGet(appname string) (*pb.BackupctlReply, error) {
-	req := &pb.BackupctlRequest{Appname: appname}
 	ctx, cancel := context.WithTimeout(context.Background(), cli.timeout)
 	defer cancel()
+	return cli.BackupctlGetContext(ctx, appname)
+}
+
+// BackupctlGetContext is BackupctlGet with a context, the timeout of the client is not applied.
+func (cli *Client) BackupctlGetContext(ctx context.Context, appname string) (*pb.BackupctlReply, error) {
+	req := &pb.BackupctlRequest{Appname: appname}
 	rpl, err := cli.backupctlClient.Get(ctx, req)
 	return rpl, err
 }
@@ -214,8 +386,13 @@ func (wch *BackupctlWatcher) Next() (*pb.BackupctlReply, error) {
 	return rpl, err
 }
 func (cli *Client) BackupctlWatch(appname string) (*BackupctlWatcher, error) {
+	return cli.BackupctlWatchContext(context.Background(), appname)
+}
+
+// BackupctlWatchContext is BackupctlWatch with a context, the stream is closed when ctx is done.
+func (cli *Client) BackupctlWatchContext(ctx context.Context, appname string) (*BackupctlWatcher, error) {
 	req := &pb.BackupctlRequest{Appname: appname}
-	stream, err := cli.backupctlClient.Watch(context.Background(), req)
+	stream, err := cli.backupctlClient.Watch(ctx, req)
 	if err != nil {
 		return nil, err
 	}
@@ -237,12 +414,50 @@ func (cli *Client) BackupctlWatch(appname string) (*BackupctlWatcher, error) {
 	}()
 	return wch, nil
 }
+
+// BackupctlReconnectWatcher is the watcher reconnecting when the stream was broken, see ReconnectWatcher.
+type BackupctlReconnectWatcher struct {
+	*ReconnectWatcher
+}
+
+// Next return the next reply, the error is not nil only when the watcher stopped.
+func (wch *BackupctlReconnectWatcher) Next() (*pb.BackupctlReply, error) {
+	rpl, err := wch.next()
+	if err != nil {
+		return nil, err
+	}
+	return rpl.(*pb.BackupctlReply), nil
+}
+
+// BackupctlWatchReconnect watch like BackupctlWatch, and reconnect with backoff until ctx is done, opts can be nil.
+func (cli *Client) BackupctlWatchReconnect(ctx context.Context, appname string, opts *ReconnectOptions) *BackupctlReconnectWatcher {
+	req := &pb.BackupctlRequest{Appname: appname}
+	return &BackupctlReconnectWatcher{cli.newReconnectWatcher(ctx, opts, func(ctx context.Context) (func() (proto.Message, error), error) {
+		stream, err := cli.backupctlClient.Watch(ctx, req)
+		if err != nil {
+			return nil, err
+		}
+		return func() (proto.Message, error) { return stream.Recv() }, nil
+	})}
+}
+
+// BackupctlInformer create the informer caching the Data of the BackupctlReply, all the items are resynced every resync period if it is positive.
+func (cli *Client) BackupctlInformer(appname string, resync time.Duration) *Informer {
+	return newInformer(resync, func(ctx context.Context) *ReconnectWatcher {
+		return cli.BackupctlWatchReconnect(ctx, appname, nil).ReconnectWatcher
+	})
+}
 //CODE GENERATION Backupctl END
 //CODE GENERATION Config START
 func (cli *Client) ConfigGet(target string) (*pb.ConfigReply, error) {
-	req := &pb.ConfigRequest{Target: target}
 	ctx, cancel := context.WithTimeout(context.Background(), cli.timeout)
 	defer cancel()
+	return cli.ConfigGetContext(ctx, target)
+}
+
+// ConfigGetContext is ConfigGet with a context, the timeout of the client is not applied.
+func (cli *Client) ConfigGetContext(ctx context.Context, target string) (*pb.ConfigReply, error) {
+	req := &pb.ConfigRequest{Target: target}
 	rpl, err := cli.configClient.Get(ctx, req)
 	return rpl, err
 }
@@ -261,8 +476,13 @@ func (wch *ConfigWatcher) Next() (*pb.ConfigReply, error) {
 	return rpl, err
 }
 func (cli *Client) ConfigWatch(target string) (*ConfigWatcher, error) {
+	return cli.ConfigWatchContext(context.Background(), target)
+}
+
+// ConfigWatchContext is ConfigWatch with a context, the stream is closed when ctx is done.
+func (cli *Client) ConfigWatchContext(ctx context.Context, target string) (*ConfigWatcher, error) {
 	req := &pb.ConfigRequest{Target: target}
-	stream, err := cli.configClient.Watch(context.Background(), req)
+	stream, err := cli.configClient.Watch(ctx, req)
 	if err != nil {
 		return nil, err
 	}
@@ -284,12 +504,50 @@ func (cli *Client) ConfigWatch(target string) (*ConfigWatcher, error) {
 	}()
 	return wch, nil
 }
+
+// ConfigReconnectWatcher is the watcher reconnecting when the stream was broken, see ReconnectWatcher.
+type ConfigReconnectWatcher struct {
+	*ReconnectWatcher
+}
+
+// Next return the next reply, the error is not nil only when the watcher stopped.
+func (wch *ConfigReconnectWatcher) Next() (*pb.ConfigReply, error) {
+	rpl, err := wch.next()
+	if err != nil {
+		return nil, err
+	}
+	return rpl.(*pb.ConfigReply), nil
+}
+
+// ConfigWatchReconnect watch like ConfigWatch, and reconnect with backoff until ctx is done, opts can be nil.
+func (cli *Client) ConfigWatchReconnect(ctx context.Context, target string, opts *ReconnectOptions) *ConfigReconnectWatcher {
+	req := &pb.ConfigRequest{Target: target}
+	return &ConfigReconnectWatcher{cli.newReconnectWatcher(ctx, opts, func(ctx context.Context) (func() (proto.Message, error), error) {
+		stream, err := cli.configClient.Watch(ctx, req)
+		if err != nil {
+			return nil, err
+		}
+		return func() (proto.Message, error) { return stream.Recv() }, nil
+	})}
+}
+
+// ConfigInformer create the informer caching the Data of the ConfigReply, all the items are resynced every resync period if it is positive.
+func (cli *Client) ConfigInformer(target string, resync time.Duration) *Informer {
+	return newInformer(resync, func(ctx context.Context) *ReconnectWatcher {
+		return cli.ConfigWatchReconnect(ctx, target, nil).ReconnectWatcher
+	})
+}
 //CODE GENERATION Config END
 //CODE GENERATION Containers START
 func (cli *Client) ContainersGet(nodename string) (*pb.ContainersReply, error) {
-	req := &pb.ContainersRequest{Nodename: nodename}
 	ctx, cancel := context.WithTimeout(context.Background(), cli.timeout)
 	defer cancel()
+	return cli.ContainersGetContext(ctx, nodename)
+}
+
+// ContainersGetContext is ContainersGet with a context, the timeout of the client is not applied.
+func (cli *Client) ContainersGetContext(ctx context.Context, nodename string) (*pb.ContainersReply, error) {
+	req := &pb.ContainersRequest{Nodename: nodename}
 	rpl, err := cli.containersClient.Get(ctx, req)
 	return rpl, err
 }
@@ -308,8 +566,13 @@ func (wch *ContainersWatcher) Next() (*pb.ContainersReply, error) {
 	return rpl, err
 }
 func (cli *Client) ContainersWatch(nodename string) (*ContainersWatcher, error) {
+	return cli.ContainersWatchContext(context.Background(), nodename)
+}
+
+// ContainersWatchContext is ContainersWatch with a context, the stream is closed when ctx is done.
+func (cli *Client) ContainersWatchContext(ctx context.Context, nodename string) (*ContainersWatcher, error) {
 	req := &pb.ContainersRequest{Nodename: nodename}
-	stream, err := cli.containersClient.Watch(context.Background(), req)
+	stream, err := cli.containersClient.Watch(ctx, req)
 	if err != nil {
 		return nil, err
 	}
@@ -331,12 +594,50 @@ func (cli *Client) ContainersWatch(nodename string) (*ContainersWatcher, error)
 	}()
 	return wch, nil
 }
+
+// ContainersReconnectWatcher is the watcher reconnecting when the stream was broken, see ReconnectWatcher.
+type ContainersReconnectWatcher struct {
+	*ReconnectWatcher
+}
+
+// Next return the next reply, the error is not nil only when the watcher stopped.
+func (wch *ContainersReconnectWatcher) Next() (*pb.ContainersReply, error) {
+	rpl, err := wch.next()
+	if err != nil {
+		return nil, err
+	}
+	return rpl.(*pb.ContainersReply), nil
+}
+
+// ContainersWatchReconnect watch like ContainersWatch, and reconnect with backoff until ctx is done, opts can be nil.
+func (cli *Client) ContainersWatchReconnect(ctx context.Context, nodename string, opts *ReconnectOptions) *ContainersReconnectWatcher {
+	req := &pb.ContainersRequest{Nodename: nodename}
+	return &ContainersReconnectWatcher{cli.newReconnectWatcher(ctx, opts, func(ctx context.Context) (func() (proto.Message, error), error) {
+		stream, err := cli.containersClient.Watch(ctx, req)
+		if err != nil {
+			return nil, err
+		}
+		return func() (proto.Message, error) { return stream.Recv() }, nil
+	})}
+}
+
+// ContainersInformer create the informer caching the Data of the ContainersReply, all the items are resynced every resync period if it is positive.
+func (cli *Client) ContainersInformer(nodename string, resync time.Duration) *Informer {
+	return newInformer(resync, func(ctx context.Context) *ReconnectWatcher {
+		return cli.ContainersWatchReconnect(ctx, nodename, nil).ReconnectWatcher
+	})
+}
 //CODE GENERATION Containers END
 //CODE GENERATION Coreinfo START
 func (cli *Client) CoreinfoGet(appname string) (*pb.CoreinfoReply, error) {
-	req := &pb.CoreinfoRequest{Appname: appname}
 	ctx, cancel := context.WithTimeout(context.Background(), cli.timeout)
 	defer cancel()
+	return cli.CoreinfoGetContext(ctx, appname)
+}
+
+// CoreinfoGetContext is CoreinfoGet with a context, the timeout of the client is not applied.
+func (cli *Client) CoreinfoGetContext(ctx context.Context, appname string) (*pb.CoreinfoReply, error) {
+	req := &pb.CoreinfoRequest{Appname: appname}
 	rpl, err := cli.coreinfoClient.Get(ctx, req)
 	return rpl, err
 }
@@ -355,8 +656,13 @@ func (wch *CoreinfoWatcher) Next() (*pb.CoreinfoReply, error) {
 	return rpl, err
 }
 func (cli *Client) CoreinfoWatch(appname string) (*CoreinfoWatcher, error) {
+	return cli.CoreinfoWatchContext(context.Background(), appname)
+}
+
+// CoreinfoWatchContext is CoreinfoWatch with a context, the stream is closed when ctx is done.
+func (cli *Client) CoreinfoWatchContext(ctx context.Context, appname string) (*CoreinfoWatcher, error) {
 	req := &pb.CoreinfoRequest{Appname: appname}
-	stream, err := cli.coreinfoClient.Watch(context.Background(), req)
+	stream, err := cli.coreinfoClient.Watch(ctx, req)
 	if err != nil {
 		return nil, err
 	}
@@ -378,12 +684,50 @@ func (cli *Client) CoreinfoWatch(appname string) (*CoreinfoWatcher, error) {
 	}()
 	return wch, nil
 }
+
+// CoreinfoReconnectWatcher is the watcher reconnecting when the stream was broken, see ReconnectWatcher.
+type CoreinfoReconnectWatcher struct {
+	*ReconnectWatcher
+}
+
+// Next return the next reply, the error is not nil only when the watcher stopped.
+func (wch *CoreinfoReconnectWatcher) Next() (*pb.CoreinfoReply, error) {
+	rpl, err := wch.next()
+	if err != nil {
+		return nil, err
+	}
+	return rpl.(*pb.CoreinfoReply), nil
+}
+
+// CoreinfoWatchReconnect watch like CoreinfoWatch, and reconnect with backoff until ctx is done, opts can be nil.
+func (cli *Client) CoreinfoWatchReconnect(ctx context.Context, appname string, opts *ReconnectOptions) *CoreinfoReconnectWatcher {
+	req := &pb.CoreinfoRequest{Appname: appname}
+	return &CoreinfoReconnectWatcher{cli.newReconnectWatcher(ctx, opts, func(ctx context.Context) (func() (proto.Message, error), error) {
+		stream, err := cli.coreinfoClient.Watch(ctx, req)
+		if err != nil {
+			return nil, err
+		}
+		return func() (proto.Message, error) { return stream.Recv() }, nil
+	})}
+}
+
+// CoreinfoInformer create the informer caching the Data of the CoreinfoReply, all the items are resynced every resync period if it is positive.
+func (cli *Client) CoreinfoInformer(appname string, resync time.Duration) *Informer {
+	return newInformer(resync, func(ctx context.Context) *ReconnectWatcher {
+		return cli.CoreinfoWatchReconnect(ctx, appname, nil).ReconnectWatcher
+	})
+}
 //CODE GENERATION Coreinfo END
 //CODE GENERATION Nodes START
 func (cli *Client) NodesGet(name string) (*pb.NodesReply, error) {
-	req := &pb.NodesRequest{Name: name}
 	ctx, cancel := context.WithTimeout(context.Background(), cli.timeout)
 	defer cancel()
+	return cli.NodesGetContext(ctx, name)
+}
+
+// NodesGetContext is NodesGet with a context, the timeout of the client is not applied.
+func (cli *Client) NodesGetContext(ctx context.Context, name string) (*pb.NodesReply, error) {
+	req := &pb.NodesRequest{Name: name}
 	rpl, err := cli.nodesClient.Get(ctx, req)
 	return rpl, err
 }
@@ -402,8 +746,13 @@ func (wch *NodesWatcher) Next() (*pb.NodesReply, error) {
 	return rpl, err
 }
 func (cli *Client) NodesWatch(name string) (*NodesWatcher, error) {
+	return cli.NodesWatchContext(context.Background(), name)
+}
+
+// NodesWatchContext is NodesWatch with a context, the stream is closed when ctx is done.
+func (cli *Client) NodesWatchContext(ctx context.Context, name string) (*NodesWatcher, error) {
 	req := &pb.NodesRequest{Name: name}
-	stream, err := cli.nodesClient.Watch(context.Background(), req)
+	stream, err := cli.nodesClient.Watch(ctx, req)
 	if err != nil {
 		return nil, err
 	}
@@ -425,12 +774,50 @@ func (cli *Client) NodesWatch(name string) (*NodesWatcher, error) {
 	}()
 	return wch, nil
 }
+
+// NodesReconnectWatcher is the watcher reconnecting when the stream was broken, see ReconnectWatcher.
+type NodesReconnectWatcher struct {
+	*ReconnectWatcher
+}
+
+// Next return the next reply, the error is not nil only when the watcher stopped.
+func (wch *NodesReconnectWatcher) Next() (*pb.NodesReply, error) {
+	rpl, err := wch.next()
+	if err != nil {
+		return nil, err
+	}
+	return rpl.(*pb.NodesReply), nil
+}
+
+// NodesWatchReconnect watch like NodesWatch, and reconnect with backoff until ctx is done, opts can be nil.
+func (cli *Client) NodesWatchReconnect(ctx context.Context, name string, opts *ReconnectOptions) *NodesReconnectWatcher {
+	req := &pb.NodesRequest{Name: name}
+	return &NodesReconnectWatcher{cli.newReconnectWatcher(ctx, opts, func(ctx context.Context) (func() (proto.Message, error), error) {
+		stream, err := cli.nodesClient.Watch(ctx, req)
+		if err != nil {
+			return nil, err
+		}
+		return func() (proto.Message, error) { return stream.Recv() }, nil
+	})}
+}
+
+// NodesInformer create the informer caching the Data of the NodesReply, all the items are resynced every resync period if it is positive.
+func (cli *Client) NodesInformer(name string, resync time.Duration) *Informer {
+	return newInformer(resync, func(ctx context.Context) *ReconnectWatcher {
+		return cli.NodesWatchReconnect(ctx, name, nil).ReconnectWatcher
+	})
+}
 //CODE GENERATION Nodes END
 //CODE GENERATION Podgroup START
 func (cli *Client) PodgroupGet(appname string) (*pb.PodgroupReply, error) {
-	req := &pb.PodgroupRequest{Appname: appname}
 	ctx, cancel := context.WithTimeout(context.Background(), cli.timeout)
 	defer cancel()
+	return cli.PodgroupGetContext(ctx, appname)
+}
+
+// PodgroupGetContext is PodgroupGet with a context, the timeout of the client is not applied.
+func (cli *Client) PodgroupGetContext(ctx context.Context, appname string) (*pb.PodgroupReply, error) {
+	req := &pb.PodgroupRequest{Appname: appname}
 	rpl, err := cli.podgroupClient.Get(ctx, req)
 	return rpl, err
 }
@@ -449,8 +836,13 @@ func (wch *PodgroupWatcher) Next() (*pb.PodgroupReply, error) {
 	return rpl, err
 }
 func (cli *Client) PodgroupWatch(appname string) (*PodgroupWatcher, error) {
+	return cli.PodgroupWatchContext(context.Background(), appname)
+}
+
+// PodgroupWatchContext is PodgroupWatch with a context, the stream is closed when ctx is done.
+func (cli *Client) PodgroupWatchContext(ctx context.Context, appname string) (*PodgroupWatcher, error) {
 	req := &pb.PodgroupRequest{Appname: appname}
-	stream, err := cli.podgroupClient.Watch(context.Background(), req)
+	stream, err := cli.podgroupClient.Watch(ctx, req)
 	if err != nil {
 		return nil, err
 	}
@@ -472,12 +864,50 @@ func (cli *Client) PodgroupWatch(appname string) (*PodgroupWatcher, error) {
 	}()
 	return wch, nil
 }
+
+// PodgroupReconnectWatcher is the watcher reconnecting when the stream was broken, see ReconnectWatcher.
+type PodgroupReconnectWatcher struct {
+	*ReconnectWatcher
+}
+
+// Next return the next reply, the error is not nil only when the watcher stopped.
+func (wch *PodgroupReconnectWatcher) Next() (*pb.PodgroupReply, error) {
+	rpl, err := wch.next()
+	if err != nil {
+		return nil, err
+	}
+	return rpl.(*pb.PodgroupReply), nil
+}
+
+// PodgroupWatchReconnect watch like PodgroupWatch, and reconnect with backoff until ctx is done, opts can be nil.
+func (cli *Client) PodgroupWatchReconnect(ctx context.Context, appname string, opts *ReconnectOptions) *PodgroupReconnectWatcher {
+	req := &pb.PodgroupRequest{Appname: appname}
+	return &PodgroupReconnectWatcher{cli.newReconnectWatcher(ctx, opts, func(ctx context.Context) (func() (proto.Message, error), error) {
+		stream, err := cli.podgroupClient.Watch(ctx, req)
+		if err != nil {
+			return nil, err
+		}
+		return func() (proto.Message, error) { return stream.Recv() }, nil
+	})}
+}
+
+// PodgroupInformer create the informer caching the Data of the PodgroupReply, all the items are resynced every resync period if it is positive.
+func (cli *Client) PodgroupInformer(appname string, resync time.Duration) *Informer {
+	return newInformer(resync, func(ctx context.Context) *ReconnectWatcher {
+		return cli.PodgroupWatchReconnect(ctx, appname, nil).ReconnectWatcher
+	})
+}
 //CODE GENERATION Podgroup END
 //CODE GENERATION Proxy START
 func (cli *Client) ProxyGet(appname string) (*pb.ProxyReply, error) {
-	req := &pb.ProxyRequest{Appname: appname}
 	ctx, cancel := context.WithTimeout(context.Background(), cli.timeout)
 	defer cancel()
+	return cli.ProxyGetContext(ctx, appname)
+}
+
+// ProxyGetContext is ProxyGet with a context, the timeout of the client is not applied.
+func (cli *Client) ProxyGetContext(ctx context.Context, appname string) (*pb.ProxyReply, error) {
+	req := &pb.ProxyRequest{Appname: appname}
 	rpl, err := cli.proxyClient.Get(ctx, req)
 	return rpl, err
 }
@@ -496,8 +926,13 @@ func (wch *ProxyWatcher) Next() (*pb.ProxyReply, error) {
 	return rpl, err
 }
 func (cli *Client) ProxyWatch(appname string) (*ProxyWatcher, error) {
+	return cli.ProxyWatchContext(context.Background(), appname)
+}
+
+// ProxyWatchContext is ProxyWatch with a context, the stream is closed when ctx is done.
+func (cli *Client) ProxyWatchContext(ctx context.Context, appname string) (*ProxyWatcher, error) {
 	req := &pb.ProxyRequest{Appname: appname}
-	stream, err := cli.proxyClient.Watch(context.Background(), req)
+	stream, err := cli.proxyClient.Watch(ctx, req)
 	if err != nil {
 		return nil, err
 	}
@@ -519,12 +954,50 @@ func (cli *Client) ProxyWatch(appname string) (*ProxyWatcher, error) {
 	}()
 	return wch, nil
 }
+
+// ProxyReconnectWatcher is the watcher reconnecting when the stream was broken, see ReconnectWatcher.
+type ProxyReconnectWatcher struct {
+	*ReconnectWatcher
+}
+
+// Next return the next reply, the error is not nil only when the watcher stopped.
+func (wch *ProxyReconnectWatcher) Next() (*pb.ProxyReply, error) {
+	rpl, err := wch.next()
+	if err != nil {
+		return nil, err
+	}
+	return rpl.(*pb.ProxyReply), nil
+}
+
+// ProxyWatchReconnect watch like ProxyWatch, and reconnect with backoff until ctx is done, opts can be nil.
+func (cli *Client) ProxyWatchReconnect(ctx context.Context, appname string, opts *ReconnectOptions) *ProxyReconnectWatcher {
+	req := &pb.ProxyRequest{Appname: appname}
+	return &ProxyReconnectWatcher{cli.newReconnectWatcher(ctx, opts, func(ctx context.Context) (func() (proto.Message, error), error) {
+		stream, err := cli.proxyClient.Watch(ctx, req)
+		if err != nil {
+			return nil, err
+		}
+		return func() (proto.Message, error) { return stream.Recv() }, nil
+	})}
+}
+
+// ProxyInformer create the informer caching the Data of the ProxyReply, all the items are resynced every resync period if it is positive.
+func (cli *Client) ProxyInformer(appname string, resync time.Duration) *Informer {
+	return newInformer(resync, func(ctx context.Context) *ReconnectWatcher {
+		return cli.ProxyWatchReconnect(ctx, appname, nil).ReconnectWatcher
+	})
+}
 //CODE GENERATION Proxy END
 //CODE GENERATION RebellionLocalprocs START
 func (cli *Client) RebellionLocalprocsGet(appname string) (*pb.RebellionLocalprocsReply, error) {
-	req := &pb.RebellionLocalprocsRequest{Appname: appname}
 	ctx, cancel := context.WithTimeout(context.Background(), cli.timeout)
 	defer cancel()
+	return cli.RebellionLocalprocsGetContext(ctx, appname)
+}
+
+// RebellionLocalprocsGetContext is RebellionLocalprocsGet with a context, the timeout of the client is not applied.
+func (cli *Client) RebellionLocalprocsGetContext(ctx context.Context, appname string) (*pb.RebellionLocalprocsReply, error) {
+	req := &pb.RebellionLocalprocsRequest{Appname: appname}
 	rpl, err := cli.rebellionLocalprocsClient.Get(ctx, req)
 	return rpl, err
 }
@@ -543,8 +1016,13 @@ func (wch *RebellionLocalprocsWatcher) Next() (*pb.RebellionLocalprocsReply, err
 	return rpl, err
 }
 func (cli *Client) RebellionLocalprocsWatch(appname string) (*RebellionLocalprocsWatcher, error) {
+	return cli.RebellionLocalprocsWatchContext(context.Background(), appname)
+}
+
+// RebellionLocalprocsWatchContext is RebellionLocalprocsWatch with a context, the stream is closed when ctx is done.
+func (cli *Client) RebellionLocalprocsWatchContext(ctx context.Context, appname string) (*RebellionLocalprocsWatcher, error) {
 	req := &pb.RebellionLocalprocsRequest{Appname: appname}
-	stream, err := cli.rebellionLocalprocsClient.Watch(context.Background(), req)
+	stream, err := cli.rebellionLocalprocsClient.Watch(ctx, req)
 	if err != nil {
 		return nil, err
 	}
@@ -566,12 +1044,50 @@ func (cli *Client) RebellionLocalprocsWatch(appname string) (*RebellionLocalproc
 	}()
 	return wch, nil
 }
+
+// RebellionLocalprocsReconnectWatcher is the watcher reconnecting when the stream was broken, see ReconnectWatcher.
+type RebellionLocalprocsReconnectWatcher struct {
+	*ReconnectWatcher
+}
+
+// Next return the next reply, the error is not nil only when the watcher stopped.
+func (wch *RebellionLocalprocsReconnectWatcher) Next() (*pb.RebellionLocalprocsReply, error) {
+	rpl, err := wch.next()
+	if err != nil {
+		return nil, err
+	}
+	return rpl.(*pb.RebellionLocalprocsReply), nil
+}
+
+// RebellionLocalprocsWatchReconnect watch like RebellionLocalprocsWatch, and reconnect with backoff until ctx is done, opts can be nil.
+func (cli *Client) RebellionLocalprocsWatchReconnect(ctx context.Context, appname string, opts *ReconnectOptions) *RebellionLocalprocsReconnectWatcher {
+	req := &pb.RebellionLocalprocsRequest{Appname: appname}
+	return &RebellionLocalprocsReconnectWatcher{cli.newReconnectWatcher(ctx, opts, func(ctx context.Context) (func() (proto.Message, error), error) {
+		stream, err := cli.rebellionLocalprocsClient.Watch(ctx, req)
+		if err != nil {
+			return nil, err
+		}
+		return func() (proto.Message, error) { return stream.Recv() }, nil
+	})}
+}
+
+// RebellionLocalprocsInformer create the informer caching the Data of the RebellionLocalprocsReply, all the items are resynced every resync period if it is positive.
+func (cli *Client) RebellionLocalprocsInformer(appname string, resync time.Duration) *Informer {
+	return newInformer(resync, func(ctx context.Context) *ReconnectWatcher {
+		return cli.RebellionLocalprocsWatchReconnect(ctx, appname, nil).ReconnectWatcher
+	})
+}
 //CODE GENERATION RebellionLocalprocs END
 //CODE GENERATION StreamrouterPorts START
 func (cli *Client) StreamrouterPortsGet(appname string) (*pb.StreamrouterPortsReply, error) {
-	req := &pb.StreamrouterPortsRequest{Appname: appname}
 	ctx, cancel := context.WithTimeout(context.Background(), cli.timeout)
 	defer cancel()
+	return cli.StreamrouterPortsGetContext(ctx, appname)
+}
+
+// StreamrouterPortsGetContext is StreamrouterPortsGet with a context, the timeout of the client is not applied.
+func (cli *Client) StreamrouterPortsGetContext(ctx context.Context, appname string) (*pb.StreamrouterPortsReply, error) {
+	req := &pb.StreamrouterPortsRequest{Appname: appname}
 	rpl, err := cli.streamrouterPortsClient.Get(ctx, req)
 	return rpl, err
 }
@@ -590,8 +1106,13 @@ func (wch *StreamrouterPortsWatcher) Next() (*pb.StreamrouterPortsReply, error)
 	return rpl, err
 }
 func (cli *Client) StreamrouterPortsWatch(appname string) (*StreamrouterPortsWatcher, error) {
+	return cli.StreamrouterPortsWatchContext(context.Background(), appname)
+}
+
+// StreamrouterPortsWatchContext is StreamrouterPortsWatch with a context, the stream is closed when ctx is done.
+func (cli *Client) StreamrouterPortsWatchContext(ctx context.Context, appname string) (*StreamrouterPortsWatcher, error) {
 	req := &pb.StreamrouterPortsRequest{Appname: appname}
-	stream, err := cli.streamrouterPortsClient.Watch(context.Background(), req)
+	stream, err := cli.streamrouterPortsClient.Watch(ctx, req)
 	if err != nil {
 		return nil, err
 	}
@@ -613,12 +1134,50 @@ func (cli *Client) StreamrouterPortsWatch(appname string) (*StreamrouterPortsWat
 	}()
 	return wch, nil
 }
+
+// StreamrouterPortsReconnectWatcher is the watcher reconnecting when the stream was broken, see ReconnectWatcher.
+type StreamrouterPortsReconnectWatcher struct {
+	*ReconnectWatcher
+}
+
+// Next return the next reply, the error is not nil only when the watcher stopped.
+func (wch *StreamrouterPortsReconnectWatcher) Next() (*pb.StreamrouterPortsReply, error) {
+	rpl, err := wch.next()
+	if err != nil {
+		return nil, err
+	}
+	return rpl.(*pb.StreamrouterPortsReply), nil
+}
+
+// StreamrouterPortsWatchReconnect watch like StreamrouterPortsWatch, and reconnect with backoff until ctx is done, opts can be nil.
+func (cli *Client) StreamrouterPortsWatchReconnect(ctx context.Context, appname string, opts *ReconnectOptions) *StreamrouterPortsReconnectWatcher {
+	req := &pb.StreamrouterPortsRequest{Appname: appname}
+	return &StreamrouterPortsReconnectWatcher{cli.newReconnectWatcher(ctx, opts, func(ctx context.Context) (func() (proto.Message, error), error) {
+		stream, err := cli.streamrouterPortsClient.Watch(ctx, req)
+		if err != nil {
+			return nil, err
+		}
+		return func() (proto.Message, error) { return stream.Recv() }, nil
+	})}
+}
+
+// StreamrouterPortsInformer create the informer caching the Data of the StreamrouterPortsReply, all the items are resynced every resync period if it is positive.
+func (cli *Client) StreamrouterPortsInformer(appname string, resync time.Duration) *Informer {
+	return newInformer(resync, func(ctx context.Context) *ReconnectWatcher {
+		return cli.StreamrouterPortsWatchReconnect(ctx, appname, nil).ReconnectWatcher
+	})
+}
 //CODE GENERATION StreamrouterPorts END
 //CODE GENERATION StreamrouterStreamprocs START
 func (cli *Client) StreamrouterStreamprocsGet(appname string) (*pb.StreamrouterStreamprocsReply, error) {
-	req := &pb.StreamrouterStreamprocsRequest{Appname: appname}
 	ctx, cancel := context.WithTimeout(context.Background(), cli.timeout)
 	defer cancel()
+	return cli.StreamrouterStreamprocsGetContext(ctx, appname)
+}
+
+// StreamrouterStreamprocsGetContext is StreamrouterStreamprocsGet with a context, the timeout of the client is not applied.
+func (cli *Client) StreamrouterStreamprocsGetContext(ctx context.Context, appname string) (*pb.StreamrouterStreamprocsReply, error) {
+	req := &pb.StreamrouterStreamprocsRequest{Appname: appname}
 	rpl, err := cli.streamrouterStreamprocsClient.Get(ctx, req)
 	return rpl, err
 }
@@ -637,8 +1196,13 @@ func (wch *StreamrouterStreamprocsWatcher) Next() (*pb.StreamrouterStreamprocsRe
 	return rpl, err
 }
 func (cli *Client) StreamrouterStreamprocsWatch(appname string) (*StreamrouterStreamprocsWatcher, error) {
+	return cli.StreamrouterStreamprocsWatchContext(context.Background(), appname)
+}
+
+// StreamrouterStreamprocsWatchContext is StreamrouterStreamprocsWatch with a context, the stream is closed when ctx is done.
+func (cli *Client) StreamrouterStreamprocsWatchContext(ctx context.Context, appname string) (*StreamrouterStreamprocsWatcher, error) {
 	req := &pb.StreamrouterStreamprocsRequest{Appname: appname}
-	stream, err := cli.streamrouterStreamprocsClient.Watch(context.Background(), req)
+	stream, err := cli.streamrouterStreamprocsClient.Watch(ctx, req)
 	if err != nil {
 		return nil, err
 	}
@@ -660,12 +1224,50 @@ func (cli *Client) StreamrouterStreamprocsWatch(appname string) (*StreamrouterSt
 	}()
 	return wch, nil
 }
+
+// StreamrouterStreamprocsReconnectWatcher is the watcher reconnecting when the stream was broken, see ReconnectWatcher.
+type StreamrouterStreamprocsReconnectWatcher struct {
+	*ReconnectWatcher
+}
+
+// Next return the next reply, the error is not nil only when the watcher stopped.
+func (wch *StreamrouterStreamprocsReconnectWatcher) Next() (*pb.StreamrouterStreamprocsReply, error) {
+	rpl, err := wch.next()
+	if err != nil {
+		return nil, err
+	}
+	return rpl.(*pb.StreamrouterStreamprocsReply), nil
+}
+
+// StreamrouterStreamprocsWatchReconnect watch like StreamrouterStreamprocsWatch, and reconnect with backoff until ctx is done, opts can be nil.
+func (cli *Client) StreamrouterStreamprocsWatchReconnect(ctx context.Context, appname string, opts *ReconnectOptions) *StreamrouterStreamprocsReconnectWatcher {
+	req := &pb.StreamrouterStreamprocsRequest{Appname: appname}
+	return &StreamrouterStreamprocsReconnectWatcher{cli.newReconnectWatcher(ctx, opts, func(ctx context.Context) (func() (proto.Message, error), error) {
+		stream, err := cli.streamrouterStreamprocsClient.Watch(ctx, req)
+		if err != nil {
+			return nil, err
+		}
+		return func() (proto.Message, error) { return stream.Recv() }, nil
+	})}
+}
+
+// StreamrouterStreamprocsInformer create the informer caching the Data of the StreamrouterStreamprocsReply, all the items are resynced every resync period if it is positive.
+func (cli *Client) StreamrouterStreamprocsInformer(appname string, resync time.Duration) *Informer {
+	return newInformer(resync, func(ctx context.Context) *ReconnectWatcher {
+		return cli.StreamrouterStreamprocsWatchReconnect(ctx, appname, nil).ReconnectWatcher
+	})
+}
 //CODE GENERATION StreamrouterStreamprocs END
 //CODE GENERATION WebrouterWebprocs START
 func (cli *Client) WebrouterWebprocsGet(appname string) (*pb.WebrouterWebprocsReply, error) {
-	req := &pb.WebrouterWebprocsRequest{Appname: appname}
 	ctx, cancel := context.WithTimeout(context.Background(), cli.timeout)
 	defer cancel()
+	return cli.WebrouterWebprocsGetContext(ctx, appname)
+}
+
+// WebrouterWebprocsGetContext is WebrouterWebprocsGet with a context, the timeout of the client is not applied.
+func (cli *Client) WebrouterWebprocsGetContext(ctx context.Context, appname string) (*pb.WebrouterWebprocsReply, error) {
+	req := &pb.WebrouterWebprocsRequest{Appname: appname}
 	rpl, err := cli.webrouterWebprocsClient.Get(ctx, req)
 	return rpl, err
 }
@@ -684,8 +1286,13 @@ func (wch *WebrouterWebprocsWatcher) Next() (*pb.WebrouterWebprocsReply, error)
 	return rpl, err
 }
 func (cli *Client) WebrouterWebprocsWatch(appname string) (*WebrouterWebprocsWatcher, error) {
+	return cli.WebrouterWebprocsWatchContext(context.Background(), appname)
+}
+
+// WebrouterWebprocsWatchContext is WebrouterWebprocsWatch with a context, the stream is closed when ctx is done.
+func (cli *Client) WebrouterWebprocsWatchContext(ctx context.Context, appname string) (*WebrouterWebprocsWatcher, error) {
 	req := &pb.WebrouterWebprocsRequest{Appname: appname}
-	stream, err := cli.webrouterWebprocsClient.Watch(context.Background(), req)
+	stream, err := cli.webrouterWebprocsClient.Watch(ctx, req)
 	if err != nil {
 		return nil, err
 	}
@@ -707,13 +1314,51 @@ func (cli *Client) WebrouterWebprocsWatch(appname string) (*WebrouterWebprocsWat
 	}()
 	return wch, nil
 }
+
+// WebrouterWebprocsReconnectWatcher is the watcher reconnecting when the stream was broken, see ReconnectWatcher.
+type WebrouterWebprocsReconnectWatcher struct {
+	*ReconnectWatcher
+}
+
+// Next return the next reply, the error is not nil only when the watcher stopped.
+func (wch *WebrouterWebprocsReconnectWatcher) Next() (*pb.WebrouterWebprocsReply, error) {
+	rpl, err := wch.next()
+	if err != nil {
+		return nil, err
+	}
+	return rpl.(*pb.WebrouterWebprocsReply), nil
+}
+
+// WebrouterWebprocsWatchReconnect watch like WebrouterWebprocsWatch, and reconnect with backoff until ctx is done, opts can be nil.
+func (cli *Client) WebrouterWebprocsWatchReconnect(ctx context.Context, appname string, opts *ReconnectOptions) *WebrouterWebprocsReconnectWatcher {
+	req := &pb.WebrouterWebprocsRequest{Appname: appname}
+	return &WebrouterWebprocsReconnectWatcher{cli.newReconnectWatcher(ctx, opts, func(ctx context.Context) (func() (proto.Message, error), error) {
+		stream, err := cli.webrouterWebprocsClient.Watch(ctx, req)
+		if err != nil {
+			return nil, err
+		}
+		return func() (proto.Message, error) { return stream.Recv() }, nil
+	})}
+}
+
+// WebrouterWebprocsInformer create the informer caching the Data of the WebrouterWebprocsReply, all the items are resynced every resync period if it is positive.
+func (cli *Client) WebrouterWebprocsInformer(appname string, resync time.Duration) *Informer {
+	return newInformer(resync, func(ctx context.Context) *ReconnectWatcher {
+		return cli.WebrouterWebprocsWatchReconnect(ctx, appname, nil).ReconnectWatcher
+	})
+}
 //CODE GENERATION WebrouterWebprocs END
 
 //CODE GENERATION Depends START
 func (cli *Client) DependsGet(target string) (*pb.DependsReply, error) {
-	req := &pb.DependsRequest{Target: target}
 	ctx, cancel := context.WithTimeout(context.Background(), cli.timeout)
 	defer cancel()
+	return cli.DependsGetContext(ctx, target)
+}
+
+// DependsGetContext is DependsGet with a context, the timeout of the client is not applied.
+func (cli *Client) DependsGetContext(ctx context.Context, target string) (*pb.DependsReply, error) {
+	req := &pb.DependsRequest{Target: target}
 	rpl, err := cli.dependsClient.Get(ctx, req)
 	return rpl, err
 }
@@ -732,8 +1377,13 @@ func (wch *DependsWatcher) Next() (*pb.DependsReply, error) {
 	return rpl, err
 }
 func (cli *Client) DependsWatch(target string) (*DependsWatcher, error) {
+	return cli.DependsWatchContext(context.Background(), target)
+}
+
+// DependsWatchContext is DependsWatch with a context, the stream is closed when ctx is done.
+func (cli *Client) DependsWatchContext(ctx context.Context, target string) (*DependsWatcher, error) {
 	req := &pb.DependsRequest{Target: target}
-	stream, err := cli.dependsClient.Watch(context.Background(), req)
+	stream, err := cli.dependsClient.Watch(ctx, req)
 	if err != nil {
 		return nil, err
 	}
@@ -755,4 +1405,37 @@ func (cli *Client) DependsWatch(target string) (*DependsWatcher, error) {
 	}()
 	return wch, nil
 }
+
+// DependsReconnectWatcher is the watcher reconnecting when the stream was broken, see ReconnectWatcher.
+type DependsReconnectWatcher struct {
+	*ReconnectWatcher
+}
+
+// Next return the next reply, the error is not nil only when the watcher stopped.
+func (wch *DependsReconnectWatcher) Next() (*pb.DependsReply, error) {
+	rpl, err := wch.next()
+	if err != nil {
+		return nil, err
+	}
+	return rpl.(*pb.DependsReply), nil
+}
+
+// DependsWatchReconnect watch like DependsWatch, and reconnect with backoff until ctx is done, opts can be nil.
+func (cli *Client) DependsWatchReconnect(ctx context.Context, target string, opts *ReconnectOptions) *DependsReconnectWatcher {
+	req := &pb.DependsRequest{Target: target}
+	return &DependsReconnectWatcher{cli.newReconnectWatcher(ctx, opts, func(ctx context.Context) (func() (proto.Message, error), error) {
+		stream, err := cli.dependsClient.Watch(ctx, req)
+		if err != nil {
+			return nil, err
+		}
+		return func() (proto.Message, error) { return stream.Recv() }, nil
+	})}
+}
+
+// DependsInformer create the informer caching the Data of the DependsReply, all the items are resynced every resync period if it is positive.
+func (cli *Client) DependsInformer(target string, resync time.Duration) *Informer {
+	return newInformer(resync, func(ctx context.Context) *ReconnectWatcher {
+		return cli.DependsWatchReconnect(ctx, target, nil).ReconnectWatcher
+	})
+}
 //CODE GENERATION Depends END
@@ -0,0 +1,157 @@
+package grpcclient
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"sync"
+
+	"google.golang.org/grpc/balancer"
+	"google.golang.org/grpc/balancer/base"
+	"google.golang.org/grpc/resolver"
+)
+
+// the scheme and balancer dialing the ordered lainlet endpoints of Config.Addrs
+const (
+	endpointsScheme  = "lainlet-endpoints"
+	priorityBalancer = "lainlet_priority"
+)
+
+func init() {
+	resolver.Register(endpointsResolverBuilder{})
+	balancer.Register(base.NewBalancerBuilder(priorityBalancer, priorityPickerBuilder{}))
+}
+
+// endpoints is the lainlet endpoints of a client, the calls go to the first one connected in order.
+// grpc keeps reconnecting the failed ones in background, so the calls go back to the preferred one after it recovered.
+type endpoints struct {
+	id       string
+	addrs    []string
+	onChange func(addr string)
+
+	lock    sync.Mutex
+	active  string
+	changed chan struct{} // closed when the active endpoint changed
+}
+
+var (
+	endpointsLock sync.Mutex
+	endpointsByID = make(map[string]*endpoints)
+	endpointsSeq  int
+)
+
+func newEndpoints(addrs []string, onChange func(addr string)) *endpoints {
+	endpointsLock.Lock()
+	defer endpointsLock.Unlock()
+	endpointsSeq++
+	eps := &endpoints{
+		id:       strconv.Itoa(endpointsSeq),
+		addrs:    addrs,
+		onChange: onChange,
+		changed:  make(chan struct{}),
+	}
+	endpointsByID[eps.id] = eps
+	return eps
+}
+
+// release unregister the endpoints, which can not be dialed after released
+func (eps *endpoints) release() {
+	endpointsLock.Lock()
+	delete(endpointsByID, eps.id)
+	endpointsLock.Unlock()
+}
+
+// target return the target dialing the endpoints, the endpoint part is the first address, which is used as the authority of tls
+func (eps *endpoints) target() string {
+	return endpointsScheme + "://" + eps.id + "/" + eps.addrs[0]
+}
+
+// get return the active endpoint, and the channel closed when it changed
+func (eps *endpoints) get() (string, <-chan struct{}) {
+	eps.lock.Lock()
+	defer eps.lock.Unlock()
+	return eps.active, eps.changed
+}
+
+func (eps *endpoints) setActive(addr string) {
+	eps.lock.Lock()
+	if addr == eps.active {
+		eps.lock.Unlock()
+		return
+	}
+	eps.active = addr
+	close(eps.changed)
+	eps.changed = make(chan struct{})
+	eps.lock.Unlock()
+	if eps.onChange != nil {
+		eps.onChange(addr)
+	}
+}
+
+// endpointMeta is the metadata of the resolved addresses, index is the order in Config.Addrs
+type endpointMeta struct {
+	eps   *endpoints
+	index int
+}
+
+type endpointsResolverBuilder struct{}
+
+func (endpointsResolverBuilder) Scheme() string {
+	return endpointsScheme
+}
+
+func (endpointsResolverBuilder) Build(target resolver.Target, cc resolver.ClientConn, opts resolver.BuildOption) (resolver.Resolver, error) {
+	endpointsLock.Lock()
+	eps, ok := endpointsByID[target.Authority]
+	endpointsLock.Unlock()
+	if !ok {
+		return nil, fmt.Errorf("unknown lainlet endpoints %s", target.Authority)
+	}
+	addrs := make([]resolver.Address, len(eps.addrs))
+	for i, addr := range eps.addrs {
+		addrs[i] = resolver.Address{Addr: addr, Metadata: &endpointMeta{eps: eps, index: i}}
+	}
+	cc.NewAddress(addrs)
+	return endpointsResolver{}, nil
+}
+
+// endpointsResolver does nothing, the endpoints never change
+type endpointsResolver struct{}
+
+func (endpointsResolver) ResolveNow(opts resolver.ResolveNowOption) {}
+
+func (endpointsResolver) Close() {}
+
+// priorityPickerBuilder build the picker picking the first ready endpoint in order
+type priorityPickerBuilder struct{}
+
+func (priorityPickerBuilder) Build(readySCs map[resolver.Address]balancer.SubConn) balancer.Picker {
+	var (
+		best *endpointMeta
+		addr string
+		sc   balancer.SubConn
+	)
+	for a, s := range readySCs {
+		meta, ok := a.Metadata.(*endpointMeta)
+		if ok && (best == nil || meta.index < best.index) {
+			best, addr, sc = meta, a.Addr, s
+		}
+	}
+	if best == nil {
+		return priorityPicker{}
+	}
+	best.eps.setActive(addr)
+	return priorityPicker{sc: sc}
+}
+
+// priorityPicker pick the SubConn, and wait for the next picker if there is none
+type priorityPicker struct {
+	sc balancer.SubConn
+}
+
+func (p priorityPicker) Pick(ctx context.Context, opts balancer.PickOptions) (balancer.SubConn, func(balancer.DoneInfo), error) {
+	if p.sc == nil {
+		return nil, nil, balancer.ErrNoSubConnAvailable
+	}
+	return p.sc, nil, nil
+}
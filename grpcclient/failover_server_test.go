@@ -0,0 +1,114 @@
+package grpcclient_test
+
+import (
+	"context"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/laincloud/lainlet/grpcclient"
+	"github.com/laincloud/lainlet/lainlettest"
+)
+
+// newVIPsServer start a lainlet serving the vips config
+func newVIPsServer(t *testing.T, vips string) *lainlettest.Server {
+	srv, err := lainlettest.NewServer(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := srv.SetConfig("vips", vips); err != nil {
+		srv.Close()
+		t.Fatal(err)
+	}
+	return srv
+}
+
+// deadAddr return a address no one listening on
+func deadAddr(t *testing.T) string {
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer lis.Close()
+	return lis.Addr().String()
+}
+
+// waitFor wait until cond is true
+func waitFor(t *testing.T, what string, cond func() bool) {
+	deadline := time.Now().Add(5 * time.Second)
+	for !cond() {
+		if time.Now().After(deadline) {
+			t.Fatalf("timeout waiting for %s", what)
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+func TestFailover(t *testing.T) {
+	a, b := newVIPsServer(t, "a"), newVIPsServer(t, "b")
+	defer b.Close()
+	closed := false
+	defer func() {
+		if !closed {
+			a.Close()
+		}
+	}()
+
+	changes := make(chan string, 10)
+	dead := deadAddr(t)
+	cli, err := grpcclient.New(&grpcclient.Config{
+		Addrs:            []string{dead, a.GRPCAddr, b.GRPCAddr},
+		OnEndpointChange: func(addr string) { changes <- addr },
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer cli.Close()
+
+	// the first endpoint connected in order is used, the calls may go to b before a is connected
+	waitFor(t, "the endpoint "+a.GRPCAddr, func() bool { return cli.ActiveEndpoint() == a.GRPCAddr })
+	rpl, err := cli.ConfigGet("vips")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if rpl.Data["vips"] != "a" {
+		t.Errorf("the call goes to the lainlet serving %q, want a", rpl.Data["vips"])
+	}
+	for len(changes) > 0 {
+		if addr := <-changes; addr == dead {
+			t.Errorf("switched to the dead endpoint")
+		}
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	wch := cli.ConfigWatchReconnect(ctx, "vips", &grpcclient.ReconnectOptions{MinBackoff: 10 * time.Millisecond})
+	if vips := nextVIPs(t, wch); vips != "a" {
+		t.Errorf("the watch goes to the lainlet serving %q, want a", vips)
+	}
+
+	// the next endpoint is used after a was down, and the watch switches to it
+	a.Close()
+	closed = true
+	select {
+	case addr := <-changes:
+		if addr != b.GRPCAddr {
+			t.Errorf("switched to %s, want %s", addr, b.GRPCAddr)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("OnEndpointChange is not called after the endpoint was down")
+	}
+	if addr := cli.ActiveEndpoint(); addr != b.GRPCAddr {
+		t.Errorf("the active endpoint is %s, want %s", addr, b.GRPCAddr)
+	}
+	if vips := nextVIPs(t, wch); vips != "b" {
+		t.Errorf("the watch goes to the lainlet serving %q after switched, want b", vips)
+	}
+	rpl, err = cli.ConfigGet("vips")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if rpl.Data["vips"] != "b" {
+		t.Errorf("the call goes to the lainlet serving %q after switched, want b", rpl.Data["vips"])
+	}
+}
@@ -0,0 +1,72 @@
+package grpcclient
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	pb "github.com/laincloud/lainlet/message"
+)
+
+func registered(eps *endpoints) bool {
+	endpointsLock.Lock()
+	defer endpointsLock.Unlock()
+	_, ok := endpointsByID[eps.id]
+	return ok
+}
+
+func TestClientClose(t *testing.T) {
+	for _, cfg := range []*Config{
+		{Addr: "127.0.0.1:1"},
+		{Addrs: []string{"127.0.0.1:1", "127.0.0.1:2"}},
+	} {
+		cli, err := New(cfg)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !registered(cli.eps) {
+			t.Errorf("the endpoints of %v is not registered", cfg)
+		}
+		if err := cli.Close(); err != nil {
+			t.Errorf("Close return %v", err)
+		}
+		if registered(cli.eps) {
+			t.Errorf("the endpoints of %v is not released after closed", cfg)
+		}
+		ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+		if _, err := cli.configClient.Get(ctx, &pb.ConfigRequest{Target: "vips"}); err == nil {
+			t.Error("the calls should fail after closed")
+		}
+		cancel()
+	}
+}
+
+func TestEndpointsActive(t *testing.T) {
+	var changes []string
+	eps := newEndpoints([]string{"a:1", "b:1"}, func(addr string) { changes = append(changes, addr) })
+	defer eps.release()
+	if eps.target() != endpointsScheme+"://"+eps.id+"/a:1" {
+		t.Errorf("the target is %s", eps.target())
+	}
+	_, changed := eps.get()
+	eps.setActive("a:1")
+	eps.setActive("a:1")
+	select {
+	case <-changed:
+	default:
+		t.Error("the channel is not closed after the active endpoint changed")
+	}
+	_, changed = eps.get()
+	eps.setActive("b:1")
+	if addr, _ := eps.get(); addr != "b:1" {
+		t.Errorf("the active endpoint is %s, want b:1", addr)
+	}
+	select {
+	case <-changed:
+	default:
+		t.Error("the channel is not closed after the active endpoint changed")
+	}
+	if len(changes) != 2 || changes[0] != "a:1" || changes[1] != "b:1" {
+		t.Errorf("OnEndpointChange is called with %q, want a:1 and b:1", changes)
+	}
+}
@@ -0,0 +1,196 @@
+package grpcclient
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/golang/protobuf/proto"
+)
+
+// EventHandler is the callbacks of a Informer, the nil callbacks are ignored.
+// They are called one by one in the goroutine running the informer, so they should not block for long,
+// the store can be read in them, but AddEventHandler can not be called in them.
+type EventHandler struct {
+	OnAdd    func(key string, obj interface{})
+	OnUpdate func(key string, oldObj, newObj interface{}) // oldObj is the same as newObj when resyncing
+	OnDelete func(key string, obj interface{})
+}
+
+// Informer keep the latest data of a watch in a local store, and call the handlers with the changes of each item.
+// The items are the entries of the Data of the replies, like the *pb.CoreInfo of each proc in CoreinfoReply,
+// the items of a list, like the *pb.PodGroup in PodgroupReply, are keyed by their index.
+// It is created by the XxxInformer methods of Client, like CoreinfoInformer, and sits on the reconnecting watchers.
+type Informer struct {
+	watch  func(ctx context.Context) *ReconnectWatcher
+	resync time.Duration
+
+	lock   sync.RWMutex // protect items and synced
+	items  map[string]interface{}
+	synced bool
+
+	handlerLock sync.Mutex // protect handlers, and make the handlers called one by one
+	handlers    []EventHandler
+}
+
+func newInformer(resync time.Duration, watch func(ctx context.Context) *ReconnectWatcher) *Informer {
+	return &Informer{
+		watch:  watch,
+		resync: resync,
+		items:  make(map[string]interface{}),
+	}
+}
+
+// AddEventHandler add the handler, the items already in the store are sent to it by OnAdd at first.
+func (inf *Informer) AddEventHandler(h EventHandler) {
+	inf.handlerLock.Lock()
+	defer inf.handlerLock.Unlock()
+	inf.handlers = append(inf.handlers, h)
+	if h.OnAdd != nil {
+		for key, obj := range inf.List() {
+			h.OnAdd(key, obj)
+		}
+	}
+}
+
+// HasSynced check if the first reply was received, the store is empty before it.
+func (inf *Informer) HasSynced() bool {
+	inf.lock.RLock()
+	defer inf.lock.RUnlock()
+	return inf.synced
+}
+
+// Get return the item of the key in the store
+func (inf *Informer) Get(key string) (interface{}, bool) {
+	inf.lock.RLock()
+	defer inf.lock.RUnlock()
+	obj, ok := inf.items[key]
+	return obj, ok
+}
+
+// List return a copy of all the items in the store, by their keys
+func (inf *Informer) List() map[string]interface{} {
+	inf.lock.RLock()
+	defer inf.lock.RUnlock()
+	ret := make(map[string]interface{}, len(inf.items))
+	for key, obj := range inf.items {
+		ret[key] = obj
+	}
+	return ret
+}
+
+// Run watch and update the store until ctx is done or the watch failed permanently, and return the error.
+// All the items are sent to OnUpdate every resync period if it is positive.
+func (inf *Informer) Run(ctx context.Context) error {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	wch := inf.watch(ctx)
+
+	replies := make(chan proto.Message)
+	go func() {
+		defer close(replies)
+		for {
+			rpl, err := wch.next()
+			if err != nil {
+				return
+			}
+			replies <- rpl
+		}
+	}()
+
+	var tick <-chan time.Time
+	if inf.resync > 0 {
+		ticker := time.NewTicker(inf.resync)
+		defer ticker.Stop()
+		tick = ticker.C
+	}
+	for {
+		select {
+		case rpl, ok := <-replies:
+			if !ok {
+				return wch.Err()
+			}
+			inf.update(replyItems(rpl))
+		case <-tick:
+			inf.resyncAll()
+		}
+	}
+}
+
+// update replace the store by the items, and call the handlers with the differences
+func (inf *Informer) update(items map[string]interface{}) {
+	inf.handlerLock.Lock()
+	defer inf.handlerLock.Unlock()
+	inf.lock.Lock()
+	old := inf.items
+	inf.items, inf.synced = items, true
+	inf.lock.Unlock()
+	for key, obj := range items {
+		oldObj, ok := old[key]
+		switch {
+		case !ok:
+			for _, h := range inf.handlers {
+				if h.OnAdd != nil {
+					h.OnAdd(key, obj)
+				}
+			}
+		case !itemEqual(oldObj, obj):
+			for _, h := range inf.handlers {
+				if h.OnUpdate != nil {
+					h.OnUpdate(key, oldObj, obj)
+				}
+			}
+		}
+	}
+	for key, oldObj := range old {
+		if _, ok := items[key]; ok {
+			continue
+		}
+		for _, h := range inf.handlers {
+			if h.OnDelete != nil {
+				h.OnDelete(key, oldObj)
+			}
+		}
+	}
+}
+
+func (inf *Informer) resyncAll() {
+	inf.handlerLock.Lock()
+	defer inf.handlerLock.Unlock()
+	for key, obj := range inf.List() {
+		for _, h := range inf.handlers {
+			if h.OnUpdate != nil {
+				h.OnUpdate(key, obj, obj)
+			}
+		}
+	}
+}
+
+// replyItems return the items in the Data field of the reply, the items of a list are keyed by index
+func replyItems(rpl proto.Message) map[string]interface{} {
+	ret := make(map[string]interface{})
+	data := reflect.Indirect(reflect.ValueOf(rpl)).FieldByName("Data")
+	switch data.Kind() {
+	case reflect.Map:
+		for _, key := range data.MapKeys() {
+			ret[fmt.Sprint(key.Interface())] = data.MapIndex(key).Interface()
+		}
+	case reflect.Slice:
+		for i := 0; i < data.Len(); i++ {
+			ret[strconv.Itoa(i)] = data.Index(i).Interface()
+		}
+	}
+	return ret
+}
+
+func itemEqual(a, b interface{}) bool {
+	if ma, ok := a.(proto.Message); ok {
+		if mb, ok := b.(proto.Message); ok {
+			return proto.Equal(ma, mb)
+		}
+	}
+	return reflect.DeepEqual(a, b)
+}
@@ -0,0 +1,203 @@
+package grpcclient
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"sort"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/golang/protobuf/proto"
+	pb "github.com/laincloud/lainlet/message"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// chanStream is the openFunc of the streams replying the messages sent by send, the stream is broken by err by breakStream
+type chanStream struct {
+	err error
+
+	lock    sync.Mutex
+	replies chan proto.Message
+}
+
+func newChanStream(err error) *chanStream {
+	return &chanStream{err: err, replies: make(chan proto.Message)}
+}
+
+func (s *chanStream) send(rpl proto.Message) {
+	s.lock.Lock()
+	replies := s.replies
+	s.lock.Unlock()
+	replies <- rpl
+}
+
+// breakStream break the current stream, the next stream opened receive the replies sent after
+func (s *chanStream) breakStream() {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	close(s.replies)
+	s.replies = make(chan proto.Message)
+}
+
+func (s *chanStream) open(ctx context.Context) (func() (proto.Message, error), error) {
+	s.lock.Lock()
+	replies := s.replies
+	s.lock.Unlock()
+	return func() (proto.Message, error) {
+		select {
+		case rpl, ok := <-replies:
+			if !ok {
+				return nil, s.err
+			}
+			return rpl, nil
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+	}, nil
+}
+
+func (s *chanStream) informer(resync time.Duration) *Informer {
+	return newInformer(resync, func(ctx context.Context) *ReconnectWatcher {
+		return newTestClient().newReconnectWatcher(ctx, &ReconnectOptions{MinBackoff: time.Millisecond}, s.open)
+	})
+}
+
+// recordEvents return the handler sending the events to the channel, like "add 0 a" or "update 0 a b"
+func recordEvents(events chan<- string) EventHandler {
+	return EventHandler{
+		OnAdd: func(key string, obj interface{}) { events <- fmt.Sprintf("add %s %s", key, itemName(obj)) },
+		OnUpdate: func(key string, oldObj, newObj interface{}) {
+			events <- fmt.Sprintf("update %s %s %s", key, itemName(oldObj), itemName(newObj))
+		},
+		OnDelete: func(key string, obj interface{}) { events <- fmt.Sprintf("delete %s %s", key, itemName(obj)) },
+	}
+}
+
+func itemName(obj interface{}) string {
+	if pg, ok := obj.(*pb.PodGroup); ok {
+		return pg.Pods[0].ProcName
+	}
+	return fmt.Sprint(obj)
+}
+
+// expectEvents wait for the events, the events of a reply are sorted because the order of them is random
+func expectEvents(t *testing.T, events <-chan string, want ...string) {
+	var got []string
+	for len(got) < len(want) {
+		select {
+		case e := <-events:
+			got = append(got, e)
+		case <-time.After(5 * time.Second):
+			t.Fatalf("got the events %q, want %q", got, want)
+		}
+	}
+	sort.Strings(got)
+	sort.Strings(want)
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("got the events %q, want %q", got, want)
+	}
+}
+
+func podgroups(procs ...string) *pb.PodgroupReply {
+	rpl := &pb.PodgroupReply{}
+	for _, proc := range procs {
+		rpl.Data = append(rpl.Data, &pb.PodGroup{Pods: []*pb.Pod{{InstanceNo: 1, ProcName: proc}}})
+	}
+	return rpl
+}
+
+func TestInformer(t *testing.T) {
+	stream := newChanStream(status.Error(codes.Unavailable, "unavailable"))
+	inf := stream.informer(0)
+	events := make(chan string, 10)
+	inf.AddEventHandler(recordEvents(events))
+	if inf.HasSynced() {
+		t.Error("the informer should not be synced before the first reply")
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	errc := make(chan error, 1)
+	go func() { errc <- inf.Run(ctx) }()
+
+	stream.send(podgroups("hello.web.web", "hello.worker.w"))
+	expectEvents(t, events, "add 0 hello.web.web", "add 1 hello.worker.w")
+	if !inf.HasSynced() {
+		t.Error("the informer should be synced after the first reply")
+	}
+	if obj, ok := inf.Get("1"); !ok || itemName(obj) != "hello.worker.w" {
+		t.Errorf("Get(%q) = %v, %v, want the second item", "1", obj, ok)
+	}
+
+	// the items of a list are keyed by index, so a removed item is a update of the following ones
+	stream.send(podgroups("hello.web.web", "hello.worker.x"))
+	expectEvents(t, events, "update 1 hello.worker.w hello.worker.x")
+	stream.send(podgroups("hello.worker.x"))
+	expectEvents(t, events, "update 0 hello.web.web hello.worker.x", "delete 1 hello.worker.x")
+	// the same data makes no events, the equal items are compared by value
+	stream.send(podgroups("hello.worker.x"))
+	stream.send(podgroups("hello.worker.x", "hello.web.web"))
+	expectEvents(t, events, "add 1 hello.web.web")
+
+	// the new handler receive the items in the store at first
+	added := make(chan string, 10)
+	inf.AddEventHandler(recordEvents(added))
+	expectEvents(t, added, "add 0 hello.worker.x", "add 1 hello.web.web")
+	if list := inf.List(); len(list) != 2 {
+		t.Errorf("List return %d items, want 2", len(list))
+	}
+
+	// a new stream is opened after the stream was broken, and its initial state is diffed with the store
+	stream.breakStream()
+	stream.send(podgroups("hello.worker.x"))
+	expectEvents(t, events, "delete 1 hello.web.web")
+	expectEvents(t, added, "delete 1 hello.web.web")
+
+	cancel()
+	select {
+	case err := <-errc:
+		if err != context.Canceled {
+			t.Errorf("Run return %v, want context.Canceled", err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("Run did not return after the context was done")
+	}
+}
+
+func TestInformerResync(t *testing.T) {
+	stream := newChanStream(nil)
+	inf := stream.informer(30 * time.Millisecond)
+	events := make(chan string, 10)
+	inf.AddEventHandler(recordEvents(events))
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go inf.Run(ctx)
+
+	stream.send(&pb.ConfigReply{Data: map[string]string{"vips": "a"}})
+	expectEvents(t, events, "add vips a")
+	// the items are sent to OnUpdate periodically without changes
+	expectEvents(t, events, "update vips a a")
+	expectEvents(t, events, "update vips a a")
+}
+
+func TestInformerStop(t *testing.T) {
+	stream := newChanStream(status.Error(codes.PermissionDenied, "no permission"))
+	inf := stream.informer(0)
+	errc := make(chan error, 1)
+	go func() { errc <- inf.Run(context.Background()) }()
+	stream.send(&pb.ConfigReply{})
+	stream.breakStream()
+	select {
+	case err := <-errc:
+		if s, ok := status.FromError(err); !ok || s.Code() != codes.PermissionDenied {
+			t.Errorf("Run return %v, want the error stopping the watch", err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("Run did not return after the watch failed permanently")
+	}
+	if !inf.HasSynced() || len(inf.List()) != 0 {
+		t.Errorf("the informer should be synced by the empty reply, synced %v, items %v", inf.HasSynced(), inf.List())
+	}
+}
@@ -0,0 +1,183 @@
+package grpcclient
+
+import (
+	"context"
+	"errors"
+	"math/rand"
+	"sync"
+	"time"
+
+	"github.com/golang/protobuf/proto"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+const (
+	// DefaultMinBackoff is the default delay before the first reconnecting
+	DefaultMinBackoff = time.Second
+	// DefaultMaxBackoff is the default max delay between reconnecting
+	DefaultMaxBackoff = 30 * time.Second
+)
+
+// ReconnectOptions is the options of the reconnecting watchers
+type ReconnectOptions struct {
+	// the delay before reconnecting is doubled after each failure from MinBackoff to MaxBackoff,
+	// and reset after a reply was received. DefaultMinBackoff and DefaultMaxBackoff are used if they are not set.
+	MinBackoff time.Duration
+	MaxBackoff time.Duration
+	// skip the initial state of the new stream if it is the same as the last reply delivered
+	Dedup bool
+	// called with the error breaking the stream before reconnecting, it can be nil
+	OnError func(err error)
+}
+
+// ReconnectWatcher is the common part of the reconnecting watchers, like ConfigReconnectWatcher.
+// It watches again when the stream was broken, and the new stream deliver the fresh initial state at first.
+// It also watches again at once when the client switched to another endpoint, see Config.Addrs.
+// It stops when the context is done or the error can not be fixed by reconnecting, like PermissionDenied.
+type ReconnectWatcher struct {
+	ch chan proto.Message
+
+	lock sync.Mutex
+	err  error
+}
+
+// openFunc open a stream, and return the function receiving the replies from it
+type openFunc func(ctx context.Context) (func() (proto.Message, error), error)
+
+// errSwitched break the stream when the client switched to another endpoint
+var errSwitched = errors.New("switched to another lainlet endpoint")
+
+func (cli *Client) newReconnectWatcher(ctx context.Context, opts *ReconnectOptions, open openFunc) *ReconnectWatcher {
+	o := ReconnectOptions{}
+	if opts != nil {
+		o = *opts
+	}
+	if o.MinBackoff <= 0 {
+		o.MinBackoff = DefaultMinBackoff
+	}
+	if o.MaxBackoff < o.MinBackoff {
+		o.MaxBackoff = DefaultMaxBackoff
+		if o.MaxBackoff < o.MinBackoff {
+			o.MaxBackoff = o.MinBackoff
+		}
+	}
+	wch := &ReconnectWatcher{
+		ch: make(chan proto.Message),
+	}
+	go wch.run(ctx, o, cli.eps, open)
+	return wch
+}
+
+// Err return the error stopping the watcher, nil if it is running
+func (wch *ReconnectWatcher) Err() error {
+	wch.lock.Lock()
+	defer wch.lock.Unlock()
+	return wch.err
+}
+
+func (wch *ReconnectWatcher) next() (proto.Message, error) {
+	if rpl, ok := <-wch.ch; ok {
+		return rpl, nil
+	}
+	return nil, wch.Err()
+}
+
+func (wch *ReconnectWatcher) run(ctx context.Context, opts ReconnectOptions, eps *endpoints, open openFunc) {
+	var (
+		last    proto.Message // the last reply delivered
+		backoff = opts.MinBackoff
+	)
+	defer close(wch.ch)
+	for {
+		err := wch.receive(ctx, opts, eps, open, &last, func() { backoff = opts.MinBackoff })
+		if ctx.Err() != nil {
+			wch.stop(ctx.Err())
+			return
+		}
+		if err == errSwitched {
+			continue
+		}
+		if !retriable(err) {
+			wch.stop(err)
+			return
+		}
+		if opts.OnError != nil {
+			opts.OnError(err)
+		}
+		// wait for a random time in [backoff/2, backoff), so the clients do not reconnect at the same time
+		select {
+		case <-time.After(backoff/2 + time.Duration(rand.Int63n(int64(backoff/2)+1))):
+		case <-ctx.Done():
+			wch.stop(ctx.Err())
+			return
+		}
+		if backoff *= 2; backoff > opts.MaxBackoff {
+			backoff = opts.MaxBackoff
+		}
+	}
+}
+
+// receive open a stream and deliver its replies until it was broken, received is called for each reply received.
+// The stream is broken by errSwitched if the active endpoint of eps changed after the stream was opened.
+func (wch *ReconnectWatcher) receive(ctx context.Context, opts ReconnectOptions, eps *endpoints, open openFunc, last *proto.Message, received func()) error {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	_, changed := eps.get()
+	recv, err := open(ctx)
+	if err != nil {
+		return err
+	}
+	switched := make(chan struct{})
+	go func() {
+		select {
+		case <-changed:
+			close(switched)
+			cancel()
+		case <-ctx.Done():
+		}
+	}()
+	broken := func(err error) error {
+		select {
+		case <-switched:
+			return errSwitched
+		default:
+			return err
+		}
+	}
+	for first := true; ; first = false {
+		rpl, err := recv()
+		if err != nil {
+			return broken(err)
+		}
+		received()
+		if first && opts.Dedup && *last != nil && proto.Equal(rpl, *last) {
+			continue
+		}
+		select {
+		case wch.ch <- rpl:
+			*last = rpl
+		case <-ctx.Done():
+			return broken(ctx.Err())
+		}
+	}
+}
+
+func (wch *ReconnectWatcher) stop(err error) {
+	wch.lock.Lock()
+	wch.err = err
+	wch.lock.Unlock()
+}
+
+// retriable check if the error breaking the stream may be fixed by reconnecting
+func retriable(err error) bool {
+	s, ok := status.FromError(err)
+	if !ok {
+		return true
+	}
+	switch s.Code() {
+	case codes.PermissionDenied, codes.Unauthenticated, codes.InvalidArgument, codes.Unimplemented:
+		return false
+	}
+	return true
+}
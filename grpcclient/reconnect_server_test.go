@@ -0,0 +1,118 @@
+package grpcclient_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/laincloud/lainlet/grpcclient"
+	"github.com/laincloud/lainlet/lainlettest"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// nextVIPs return the "vips" in the next config reply, or fail if no reply is received in time
+func nextVIPs(t *testing.T, wch *grpcclient.ConfigReconnectWatcher) string {
+	type result struct {
+		vips string
+		err  error
+	}
+	ch := make(chan result, 1)
+	go func() {
+		rpl, err := wch.Next()
+		if err != nil {
+			ch <- result{err: err}
+			return
+		}
+		ch <- result{vips: rpl.Data["vips"]}
+	}()
+	select {
+	case r := <-ch:
+		if r.err != nil {
+			t.Fatalf("the watcher stopped, %s", r.err.Error())
+		}
+		return r.vips
+	case <-time.After(5 * time.Second):
+		t.Fatal("no reply received")
+	}
+	return ""
+}
+
+func TestConfigWatchReconnect(t *testing.T) {
+	srv, err := lainlettest.NewServer(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer srv.Close()
+	if err := srv.SetConfig("vips", "a"); err != nil {
+		t.Fatal(err)
+	}
+	cli, err := srv.GRPCClient()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	broken := make(chan error, 10)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	wch := cli.ConfigWatchReconnect(ctx, "vips", &grpcclient.ReconnectOptions{
+		MinBackoff: 10 * time.Millisecond,
+		MaxBackoff: 20 * time.Millisecond,
+		Dedup:      true,
+		OnError:    func(err error) { broken <- err },
+	})
+	if vips := nextVIPs(t, wch); vips != "a" {
+		t.Fatalf("the initial vips is %q, want %q", vips, "a")
+	}
+
+	// the same initial state of the new stream is skipped, so the next reply is the change
+	if n := srv.Disconnect(0, "127.0.0.1"); n == 0 {
+		t.Fatal("no watch is disconnected")
+	}
+	select {
+	case err := <-broken:
+		if err == nil {
+			t.Error("OnError is called without the error")
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("OnError is not called after the stream was broken")
+	}
+	// wait for the new stream, the change before it is received as its initial state
+	time.Sleep(100 * time.Millisecond)
+	if err := srv.SetConfig("vips", "b"); err != nil {
+		t.Fatal(err)
+	}
+	if vips := nextVIPs(t, wch); vips != "b" {
+		t.Errorf("the vips after reconnecting is %q, want %q", vips, "b")
+	}
+
+	cancel()
+	if _, err := wch.Next(); err != context.Canceled {
+		t.Errorf("the watcher should stop by the context, got %v", err)
+	}
+}
+
+func TestConfigWatchReconnectUnauthenticated(t *testing.T) {
+	srv, err := lainlettest.NewServer(&lainlettest.Options{Auth: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer srv.Close()
+	cli, err := grpcclient.New(&grpcclient.Config{Addr: srv.GRPCAddr, Token: "invalid"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	called := false
+	wch := cli.ConfigWatchReconnect(context.Background(), "vips", &grpcclient.ReconnectOptions{
+		MinBackoff: 10 * time.Millisecond,
+		OnError:    func(error) { called = true },
+	})
+	_, err = wch.Next()
+	if s, ok := status.FromError(err); !ok || s.Code() != codes.Unauthenticated {
+		t.Errorf("the watcher should stop by Unauthenticated, got %v", err)
+	}
+	if called {
+		t.Error("the watcher should not reconnect with a invalid token")
+	}
+}
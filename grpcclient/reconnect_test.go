@@ -0,0 +1,197 @@
+package grpcclient
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/golang/protobuf/proto"
+	pb "github.com/laincloud/lainlet/message"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestRetriable(t *testing.T) {
+	cases := []struct {
+		err error
+		ok  bool
+	}{
+		{errors.New("connection reset"), true},
+		{status.Error(codes.Unavailable, "unavailable"), true},
+		{status.Error(codes.Unknown, "disconnected by administrator"), true},
+		{status.Error(codes.ResourceExhausted, "too many watches"), true},
+		{status.Error(codes.Internal, "internal"), true},
+		{status.Error(codes.PermissionDenied, "no permission"), false},
+		{status.Error(codes.Unauthenticated, "invalid token"), false},
+		{status.Error(codes.InvalidArgument, "invalid field"), false},
+		{status.Error(codes.Unimplemented, "unknown service"), false},
+	}
+	for _, c := range cases {
+		if retriable(c.err) != c.ok {
+			t.Errorf("retriable(%v) != %v", c.err, c.ok)
+		}
+	}
+}
+
+// fakeStreams is the openFunc of the streams replying the messages, each stream is broken by err after its replies
+type fakeStreams struct {
+	replies [][]proto.Message
+	err     error
+
+	lock   sync.Mutex
+	opened []time.Time
+}
+
+func (f *fakeStreams) open(ctx context.Context) (func() (proto.Message, error), error) {
+	f.lock.Lock()
+	n := len(f.opened)
+	f.opened = append(f.opened, time.Now())
+	f.lock.Unlock()
+	var replies []proto.Message
+	if n < len(f.replies) {
+		replies = f.replies[n]
+	}
+	return func() (proto.Message, error) {
+		if len(replies) == 0 {
+			return nil, f.err
+		}
+		rpl := replies[0]
+		replies = replies[1:]
+		return rpl, nil
+	}, nil
+}
+
+func (f *fakeStreams) times() []time.Time {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+	return append([]time.Time(nil), f.opened...)
+}
+
+func newTestClient() *Client {
+	eps := newEndpoints([]string{"127.0.0.1:9002"}, nil)
+	eps.active = "127.0.0.1:9002"
+	return &Client{eps: eps}
+}
+
+// errCode return the code of the grpc error, codes.Unknown if it is not a status error
+func errCode(err error) codes.Code {
+	if s, ok := status.FromError(err); ok {
+		return s.Code()
+	}
+	return codes.Unknown
+}
+
+func config(data map[string]string) *pb.ConfigReply {
+	return &pb.ConfigReply{Data: data}
+}
+
+func TestReconnectBackoff(t *testing.T) {
+	min, max := 40*time.Millisecond, 160*time.Millisecond
+	streams := &fakeStreams{err: status.Error(codes.Unavailable, "unavailable")}
+	var errs int
+	ctx, cancel := context.WithCancel(context.Background())
+	wch := newTestClient().newReconnectWatcher(ctx, &ReconnectOptions{MinBackoff: min, MaxBackoff: max, OnError: func(error) { errs++ }}, streams.open)
+	for len(streams.times()) < 6 {
+		time.Sleep(10 * time.Millisecond)
+	}
+	cancel()
+	if _, err := wch.next(); err != context.Canceled {
+		t.Errorf("the watcher should stop by the context, got %v", err)
+	}
+
+	times := streams.times()
+	backoff := min
+	for i := 1; i < 6; i++ {
+		delay := times[i].Sub(times[i-1])
+		// the delay is random in [backoff/2, backoff), with some time for scheduling
+		if delay < backoff/2 || delay > backoff+50*time.Millisecond {
+			t.Errorf("the delay before reconnecting %d is %s, want in [%s, %s)", i, delay, backoff/2, backoff)
+		}
+		if backoff *= 2; backoff > max {
+			backoff = max
+		}
+	}
+	if errs < 5 {
+		t.Errorf("OnError is called %d times, want at least 5", errs)
+	}
+}
+
+func TestReconnectBackoffReset(t *testing.T) {
+	min := 100 * time.Millisecond
+	streams := &fakeStreams{
+		// the third stream receive a reply, so the backoff is reset
+		replies: [][]proto.Message{nil, nil, {config(map[string]string{"a": "1"})}},
+		err:     status.Error(codes.Unavailable, "unavailable"),
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	wch := newTestClient().newReconnectWatcher(ctx, &ReconnectOptions{MinBackoff: min, MaxBackoff: time.Second}, streams.open)
+	if _, err := wch.next(); err != nil {
+		t.Fatal(err)
+	}
+	for len(streams.times()) < 4 {
+		time.Sleep(10 * time.Millisecond)
+	}
+	times := streams.times()
+	if delay := times[2].Sub(times[1]); delay < min {
+		t.Errorf("the backoff should be doubled after failures, the delay is %s", delay)
+	}
+	if delay := times[3].Sub(times[2]); delay >= 2*min {
+		t.Errorf("the backoff should be reset after a reply, the delay is %s", delay)
+	}
+}
+
+func TestReconnectNotRetriable(t *testing.T) {
+	for _, code := range []codes.Code{codes.PermissionDenied, codes.Unauthenticated, codes.InvalidArgument, codes.Unimplemented} {
+		streams := &fakeStreams{
+			replies: [][]proto.Message{{config(map[string]string{"a": "1"})}},
+			err:     status.Error(code, "denied"),
+		}
+		called := false
+		wch := newTestClient().newReconnectWatcher(context.Background(), &ReconnectOptions{MinBackoff: time.Millisecond, OnError: func(error) { called = true }}, streams.open)
+		if _, err := wch.next(); err != nil {
+			t.Fatalf("%s: the reply before the error should be delivered, %s", code, err.Error())
+		}
+		_, err := wch.next()
+		if errCode(err) != code || errCode(wch.Err()) != code {
+			t.Errorf("%s: the watcher should stop by the error, got %v", code, err)
+		}
+		if n := len(streams.times()); n != 1 || called {
+			t.Errorf("%s: the watcher should not reconnect, opened %d streams, OnError called %v", code, n, called)
+		}
+	}
+}
+
+func TestReconnectDedup(t *testing.T) {
+	a, b := config(map[string]string{"a": "1"}), config(map[string]string{"a": "2"})
+	for _, dedup := range []bool{true, false} {
+		streams := &fakeStreams{
+			// the initial state of each new stream, and the changes
+			replies: [][]proto.Message{{a}, {config(map[string]string{"a": "1"}), b}, {config(map[string]string{"a": "2"})}, {a}},
+			err:     status.Error(codes.Unavailable, "unavailable"),
+		}
+		ctx, cancel := context.WithCancel(context.Background())
+		wch := newTestClient().newReconnectWatcher(ctx, &ReconnectOptions{MinBackoff: time.Millisecond, MaxBackoff: time.Millisecond, Dedup: dedup}, streams.open)
+		var got []string
+		for len(got) < 3 {
+			rpl, err := wch.next()
+			if err != nil {
+				t.Fatal(err)
+			}
+			got = append(got, rpl.(*pb.ConfigReply).Data["a"])
+		}
+		cancel()
+		want := []string{"1", "2", "1"}
+		if !dedup {
+			want = []string{"1", "1", "2"}
+		}
+		for i := range want {
+			if got[i] != want[i] {
+				t.Errorf("dedup %v: got %q, want %q", dedup, got, want)
+				break
+			}
+		}
+	}
+}
@@ -0,0 +1,215 @@
+package grpcclient
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/laincloud/lainlet/ipaddr"
+	pb "github.com/laincloud/lainlet/message"
+	"google.golang.org/grpc/grpclog"
+	"google.golang.org/grpc/resolver"
+)
+
+// Scheme is the scheme of the targets resolved by lainlet, like "lainlet:///hello.web.web"
+const Scheme = "lainlet"
+
+// the states of the pods in CoreinfoReply, the same as spec.HealthState and spec.RunState
+const (
+	healthStateStarting  = 1
+	healthStateUnHealthy = 3
+	runStateSuccess      = 2
+)
+
+// ResolverOptions is the options of the resolver
+type ResolverOptions struct {
+	// only resolve the running pods which are healthy or have no health check
+	HealthyOnly bool
+	// only resolve the containers on the node, which is a node name or ip, if there are any of them
+	PreferNode string
+	// prefer the node lainlet running on, it is ignored if PreferNode is set.
+	// The node is read by the localspec api, which only the super apps can call without the nodeip,
+	// the other clients fall back to the ip of the lainlet endpoint, so set PreferNode if it is not the node ip.
+	PreferLocal bool
+	// the options of the coreinfo watcher, it can be nil
+	Reconnect *ReconnectOptions
+}
+
+// NewResolverBuilder create the resolver builder for the "lainlet" scheme, opts can be nil.
+// The target is "lainlet:///<procname>" or "lainlet:///<procname>:<port>", like "lainlet:///hello.web.web",
+// the addresses are the containers of the proc in the Coreinfo watch, on the port exposed by the proc if port is not given.
+func (cli *Client) NewResolverBuilder(opts *ResolverOptions) resolver.Builder {
+	b := &resolverBuilder{cli: cli}
+	if opts != nil {
+		b.opts = *opts
+	}
+	return b
+}
+
+// RegisterResolver register the resolver builder created by NewResolverBuilder, then grpc.Dial("lainlet:///hello.web.web") works.
+// Since the resolver return a list of addresses, use a balancer like round_robin by grpc.WithBalancerName to spread the calls.
+func (cli *Client) RegisterResolver(opts *ResolverOptions) {
+	resolver.Register(cli.NewResolverBuilder(opts))
+}
+
+type resolverBuilder struct {
+	cli  *Client
+	opts ResolverOptions
+}
+
+func (b *resolverBuilder) Scheme() string {
+	return Scheme
+}
+
+func (b *resolverBuilder) Build(target resolver.Target, cc resolver.ClientConn, opts resolver.BuildOption) (resolver.Resolver, error) {
+	proc, port, err := parseProcTarget(target.Endpoint)
+	if err != nil {
+		return nil, err
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	r := &lainletResolver{
+		cli:    b.cli,
+		opts:   b.opts,
+		cc:     cc,
+		proc:   proc,
+		port:   port,
+		cancel: cancel,
+	}
+	go r.run(ctx)
+	return r, nil
+}
+
+// parseProcTarget parse "<procname>[:<port>]", the procname is like "<appname>.<proctype>.<name>"
+func parseProcTarget(endpoint string) (string, int, error) {
+	proc, port := endpoint, 0
+	if index := strings.LastIndexByte(endpoint, ':'); index >= 0 {
+		p, err := strconv.Atoi(endpoint[index+1:])
+		if err != nil || p <= 0 || p > 65535 {
+			return "", 0, fmt.Errorf("invalid port in target %q", endpoint)
+		}
+		proc, port = endpoint[:index], p
+	}
+	if strings.Count(proc, ".") < 2 {
+		return "", 0, fmt.Errorf("invalid procname %q in target, it should be like appname.web.web", proc)
+	}
+	return proc, port, nil
+}
+
+// appnameOf return the appname of the proc, the appname may contain dots, like "resource.redis.hello"
+func appnameOf(proc string) string {
+	for i := 0; i < 2; i++ {
+		proc = proc[:strings.LastIndexByte(proc, '.')]
+	}
+	return proc
+}
+
+type lainletResolver struct {
+	cli    *Client
+	opts   ResolverOptions
+	cc     resolver.ClientConn
+	proc   string
+	port   int
+	cancel context.CancelFunc
+}
+
+func (r *lainletResolver) run(ctx context.Context) {
+	node := r.opts.PreferNode
+	if node == "" && r.opts.PreferLocal {
+		node = r.localNode(ctx)
+	}
+	wch := r.cli.CoreinfoWatchReconnect(ctx, appnameOf(r.proc), r.opts.Reconnect)
+	var last []string
+	for {
+		rpl, err := wch.Next()
+		if err != nil {
+			if ctx.Err() == nil {
+				grpclog.Errorf("lainlet resolver: stop watching %s, %v", r.proc, err)
+			}
+			return
+		}
+		addrs := r.addresses(rpl.Data[r.proc], node)
+		if last != nil && equalStrings(addrs, last) {
+			continue
+		}
+		last = addrs
+		ret := make([]resolver.Address, len(addrs))
+		for i, addr := range addrs {
+			ret[i] = resolver.Address{Addr: addr}
+		}
+		r.cc.NewAddress(ret)
+	}
+}
+
+// localNode return the ip of the node lainlet running on, or the ip of the lainlet endpoint if the localspec api is denied
+func (r *lainletResolver) localNode(ctx context.Context) string {
+	rpl, err := r.cli.LocalspecGetContext(ctx, "")
+	if err == nil {
+		return rpl.LocalIP
+	}
+	addr, _ := r.cli.eps.get()
+	if addr == "" {
+		addr = r.cli.eps.addrs[0]
+	}
+	host, _, splitErr := net.SplitHostPort(addr)
+	if ip := net.ParseIP(host); splitErr != nil || ip == nil || ip.IsLoopback() {
+		grpclog.Warningf("lainlet resolver: fail to get the local node, the containers on it are not preferred, %v", err)
+		return ""
+	}
+	grpclog.Infof("lainlet resolver: fail to get the local node, prefer the node of the lainlet endpoint %s, %v", host, err)
+	return host
+}
+
+// addresses return the sorted addresses of the containers of ci, the ones on node are preferred if node is not empty
+func (r *lainletResolver) addresses(ci *pb.CoreInfo, node string) []string {
+	all, local := make([]string, 0), make([]string, 0)
+	for _, pod := range ci.GetPodInfos() {
+		if r.opts.HealthyOnly && !healthy(pod) {
+			continue
+		}
+		for _, c := range pod.Containers {
+			port := r.port
+			if port == 0 {
+				port = int(c.Expose)
+			}
+			if c.Ip == "" || port <= 0 {
+				continue
+			}
+			addr := net.JoinHostPort(ipaddr.Normalize(c.Ip), strconv.Itoa(port))
+			all = append(all, addr)
+			if node != "" && (c.NodeName == node || ipaddr.Equal(c.NodeIp, node)) {
+				local = append(local, addr)
+			}
+		}
+	}
+	if len(local) > 0 {
+		all = local
+	}
+	sort.Strings(all)
+	return all
+}
+
+func healthy(pod *pb.PodInfo) bool {
+	return pod.State == runStateSuccess && pod.Healthst != healthStateStarting && pod.Healthst != healthStateUnHealthy
+}
+
+func equalStrings(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// ResolveNow does nothing, the addresses are updated by watching
+func (r *lainletResolver) ResolveNow(opts resolver.ResolveNowOption) {}
+
+func (r *lainletResolver) Close() {
+	r.cancel()
+}
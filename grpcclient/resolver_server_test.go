@@ -0,0 +1,129 @@
+package grpcclient_test
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/laincloud/lainlet/grpcclient"
+	"github.com/laincloud/lainlet/lainlettest"
+	"github.com/laincloud/lainlet/spec"
+	"google.golang.org/grpc/resolver"
+)
+
+// fakeClientConn send the addresses from the resolver to the channel
+type fakeClientConn struct {
+	resolver.ClientConn
+	addrs chan []string
+}
+
+func (cc *fakeClientConn) NewAddress(addrs []resolver.Address) {
+	ret := make([]string, len(addrs))
+	for i, addr := range addrs {
+		ret[i] = addr.Addr
+	}
+	cc.addrs <- ret
+}
+
+// build the resolver of the target "lainlet:///<endpoint>"
+func build(cli *grpcclient.Client, opts *grpcclient.ResolverOptions, endpoint string, cc resolver.ClientConn) (resolver.Resolver, error) {
+	return cli.NewResolverBuilder(opts).Build(resolver.Target{Scheme: grpcclient.Scheme, Endpoint: endpoint}, cc, resolver.BuildOption{})
+}
+
+// resolve build the resolver of the endpoint of the target, and return the channel receiving the addresses
+func resolve(t *testing.T, srv *lainlettest.Server, opts *grpcclient.ResolverOptions, endpoint string) (<-chan []string, func()) {
+	cli, err := srv.GRPCClient()
+	if err != nil {
+		t.Fatal(err)
+	}
+	cc := &fakeClientConn{addrs: make(chan []string, 10)}
+	r, err := build(cli, opts, endpoint, cc)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return cc.addrs, r.Close
+}
+
+func expectAddrs(t *testing.T, addrs <-chan []string, want ...string) {
+	select {
+	case got := <-addrs:
+		if !reflect.DeepEqual(got, want) {
+			t.Errorf("got the addresses %q, want %q", got, want)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatalf("no addresses received, want %q", want)
+	}
+}
+
+func newResolverServer(t *testing.T, ip string) *lainlettest.Server {
+	srv, err := lainlettest.NewServer(&lainlettest.Options{IP: ip})
+	if err != nil {
+		t.Fatal(err)
+	}
+	srv.AddNode("node1", "10.0.0.1")
+	srv.AddNode("node2", "10.0.0.2")
+	// the containers are 172.20.0.2 on node1 and 172.20.0.3 on node2
+	if err := srv.AddApp("hello", lainlettest.Proc{Type: "web", Instances: 2, Expose: 8080}); err != nil {
+		srv.Close()
+		t.Fatal(err)
+	}
+	return srv
+}
+
+func TestResolver(t *testing.T) {
+	srv := newResolverServer(t, "")
+	defer srv.Close()
+
+	cli, err := srv.GRPCClient()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := build(cli, nil, "hello.web", &fakeClientConn{}); err == nil {
+		t.Error("the invalid target should be rejected")
+	}
+
+	addrs, stop := resolve(t, srv, nil, "hello.web.web")
+	defer stop()
+	expectAddrs(t, addrs, "172.20.0.2:8080", "172.20.0.3:8080")
+	if err := srv.ScaleProc("hello.web.web", 3); err != nil {
+		t.Fatal(err)
+	}
+	expectAddrs(t, addrs, "172.20.0.2:8080", "172.20.0.3:8080", "172.20.0.4:8080")
+	if err := srv.SetContainerIP("hello.web.web", 1, "172.20.1.2"); err != nil {
+		t.Fatal(err)
+	}
+	expectAddrs(t, addrs, "172.20.0.3:8080", "172.20.0.4:8080", "172.20.1.2:8080")
+	// the unchanged addresses are not sent again
+	if err := srv.SetPodState("hello.web.web", 3, spec.RunStateExit); err != nil {
+		t.Fatal(err)
+	}
+	if err := srv.ScaleProc("hello.web.web", 2); err != nil {
+		t.Fatal(err)
+	}
+	expectAddrs(t, addrs, "172.20.0.3:8080", "172.20.1.2:8080")
+}
+
+func TestResolverHealthyOnly(t *testing.T) {
+	srv := newResolverServer(t, "")
+	defer srv.Close()
+	addrs, stop := resolve(t, srv, &grpcclient.ResolverOptions{HealthyOnly: true, PreferNode: "node2"}, "hello.web.web:9000")
+	defer stop()
+	expectAddrs(t, addrs, "172.20.0.3:9000")
+	// the other nodes are used when no healthy container on the node
+	if err := srv.SetPodState("hello.web.web", 2, spec.RunStateExit); err != nil {
+		t.Fatal(err)
+	}
+	expectAddrs(t, addrs, "172.20.0.2:9000")
+	if err := srv.SetPodState("hello.web.web", 2, spec.RunStateSuccess); err != nil {
+		t.Fatal(err)
+	}
+	expectAddrs(t, addrs, "172.20.0.3:9000")
+}
+
+func TestResolverPreferLocal(t *testing.T) {
+	srv := newResolverServer(t, "10.0.0.1")
+	defer srv.Close()
+	addrs, stop := resolve(t, srv, &grpcclient.ResolverOptions{PreferLocal: true}, "hello.web.web")
+	defer stop()
+	expectAddrs(t, addrs, "172.20.0.2:8080")
+}
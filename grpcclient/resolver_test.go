@@ -0,0 +1,90 @@
+package grpcclient
+
+import (
+	"reflect"
+	"testing"
+
+	pb "github.com/laincloud/lainlet/message"
+)
+
+func TestParseProcTarget(t *testing.T) {
+	cases := []struct {
+		endpoint string
+		proc     string
+		port     int
+		ok       bool
+	}{
+		{"hello.web.web", "hello.web.web", 0, true},
+		{"hello.web.web:8080", "hello.web.web", 8080, true},
+		{"resource.redis.hello.worker.redis:6379", "resource.redis.hello.worker.redis", 6379, true},
+		{"hello.web.web:0", "", 0, false},
+		{"hello.web.web:65536", "", 0, false},
+		{"hello.web.web:http", "", 0, false},
+		{"hello.web.web:", "", 0, false},
+		{"hello.web", "", 0, false},
+		{"hello:8080", "", 0, false},
+		{"", "", 0, false},
+	}
+	for _, c := range cases {
+		proc, port, err := parseProcTarget(c.endpoint)
+		if (err == nil) != c.ok || proc != c.proc || port != c.port {
+			t.Errorf("parseProcTarget(%q) = %q, %d, %v, want %q, %d, ok %v", c.endpoint, proc, port, err, c.proc, c.port, c.ok)
+		}
+	}
+}
+
+func TestAppnameOf(t *testing.T) {
+	cases := []struct {
+		proc, appname string
+	}{
+		{"hello.web.web", "hello"},
+		{"hello.worker.w", "hello"},
+		{"resource.redis.hello.worker.redis", "resource.redis.hello"},
+	}
+	for _, c := range cases {
+		if appname := appnameOf(c.proc); appname != c.appname {
+			t.Errorf("appnameOf(%q) = %q, want %q", c.proc, appname, c.appname)
+		}
+	}
+}
+
+func TestResolverAddresses(t *testing.T) {
+	pod := func(state, healthst int32, ip, node, nodeIP string) *pb.PodInfo {
+		return &pb.PodInfo{State: state, Healthst: healthst, Containers: []*pb.Container{{Ip: ip, Expose: 8080, NodeName: node, NodeIp: nodeIP}}}
+	}
+	ci := &pb.CoreInfo{PodInfos: []*pb.PodInfo{
+		pod(runStateSuccess, 2, "172.20.0.4", "node2", "10.0.0.2"), // healthy
+		pod(runStateSuccess, 0, "172.20.0.3", "node1", "10.0.0.1"), // no health check
+		pod(runStateSuccess, healthStateStarting, "172.20.0.5", "node1", "10.0.0.1"),
+		pod(runStateSuccess, healthStateUnHealthy, "172.20.0.6", "node2", "10.0.0.2"),
+		pod(1, 0, "172.20.0.7", "node1", "10.0.0.1"), // pending
+		pod(runStateSuccess, 0, "", "node2", "10.0.0.2"),
+		pod(runStateSuccess, 0, "fd00:20::2", "node3", "fd00:1::3"),
+	}}
+	cases := []struct {
+		name string
+		opts ResolverOptions
+		port int
+		node string
+		want []string
+	}{
+		{"all", ResolverOptions{}, 0, "", []string{"172.20.0.3:8080", "172.20.0.4:8080", "172.20.0.5:8080", "172.20.0.6:8080", "172.20.0.7:8080", "[fd00:20::2]:8080"}},
+		{"healthy only", ResolverOptions{HealthyOnly: true}, 0, "", []string{"172.20.0.3:8080", "172.20.0.4:8080", "[fd00:20::2]:8080"}},
+		{"port", ResolverOptions{HealthyOnly: true}, 9000, "", []string{"172.20.0.3:9000", "172.20.0.4:9000", "[fd00:20::2]:9000"}},
+		{"node name", ResolverOptions{HealthyOnly: true}, 0, "node1", []string{"172.20.0.3:8080"}},
+		{"node ip", ResolverOptions{HealthyOnly: true}, 0, "10.0.0.2", []string{"172.20.0.4:8080"}},
+		{"node ipv6", ResolverOptions{}, 0, "fd00:1:0::3", []string{"[fd00:20::2]:8080"}},
+		{"no container on node", ResolverOptions{HealthyOnly: true}, 0, "node4", []string{"172.20.0.3:8080", "172.20.0.4:8080", "[fd00:20::2]:8080"}},
+		{"unhealthy containers on node", ResolverOptions{HealthyOnly: true}, 0, "10.0.0.1", []string{"172.20.0.3:8080"}},
+	}
+	for _, c := range cases {
+		r := &lainletResolver{opts: c.opts, port: c.port}
+		if addrs := r.addresses(ci, c.node); !reflect.DeepEqual(addrs, c.want) {
+			t.Errorf("%s: addresses = %q, want %q", c.name, addrs, c.want)
+		}
+	}
+	r := &lainletResolver{}
+	if addrs := r.addresses(nil, ""); addrs == nil || len(addrs) != 0 {
+		t.Errorf("addresses of the missing proc = %#v, want a empty list", addrs)
+	}
+}
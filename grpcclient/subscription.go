@@ -0,0 +1,57 @@
+package grpcclient
+
+import (
+	"context"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/golang/protobuf/ptypes"
+	pb "github.com/laincloud/lainlet/message"
+)
+
+// Subscription watch several apis over one stream, the watches are added and removed by tags at any time,
+// and the events of them are received by Next, tagged by the tags.
+type Subscription struct {
+	stream pb.Subscription_SubscribeClient
+}
+
+// Subscribe open a subscription, it is closed when ctx is done.
+func (cli *Client) Subscribe(ctx context.Context) (*Subscription, error) {
+	stream, err := cli.subscriptionClient.Subscribe(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return &Subscription{stream: stream}, nil
+}
+
+// Add watch the service by the request, like Add("vips", "message.Config", &pb.ConfigRequest{Target: "vips"}),
+// the events are tagged by tag. If it failed, like the request was denied, a error event of tag is received.
+func (s *Subscription) Add(tag, service string, req proto.Message) error {
+	any, err := ptypes.MarshalAny(req)
+	if err != nil {
+		return err
+	}
+	return s.stream.Send(&pb.SubscribeRequest{Op: pb.SubscribeRequest_ADD, Tag: tag, Service: service, Request: any})
+}
+
+// Remove stop the watch of tag
+func (s *Subscription) Remove(tag string) error {
+	return s.stream.Send(&pb.SubscribeRequest{Op: pb.SubscribeRequest_REMOVE, Tag: tag})
+}
+
+// Next return the next event, the reply can be unmarshaled by ptypes.UnmarshalAny, or by Reply.
+// The error is not nil only when the stream is broken.
+func (s *Subscription) Next() (*pb.SubscribeEvent, error) {
+	return s.stream.Recv()
+}
+
+// Reply return the reply in the event, like *pb.ConfigReply, it is nil for the error events
+func Reply(ev *pb.SubscribeEvent) (proto.Message, error) {
+	if ev.Reply == nil {
+		return nil, nil
+	}
+	var dyn ptypes.DynamicAny
+	if err := ptypes.UnmarshalAny(ev.Reply, &dyn); err != nil {
+		return nil, err
+	}
+	return dyn.Message, nil
+}
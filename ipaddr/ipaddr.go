@@ -0,0 +1,67 @@
+// Package ipaddr parse and normalize the ip addresses of the clients and the containers, both IPv4 and IPv6 are supported.
+// The normalized form of a ip is the dotted form for IPv4, including the IPv4-mapped IPv6 addresses,
+// and the compressed lower case form without zone for IPv6, like "fd00::1".
+package ipaddr
+
+import (
+	"fmt"
+	"net"
+	"strings"
+)
+
+// Normalize return the normalized form of the ip, it is returned unchanged if it is not a ip.
+func Normalize(ip string) string {
+	s := strings.TrimSuffix(strings.TrimPrefix(ip, "["), "]")
+	if index := strings.IndexByte(s, '%'); index >= 0 { // the zone of a link local address
+		s = s[:index]
+	}
+	parsed := net.ParseIP(s)
+	if parsed == nil {
+		return ip
+	}
+	if v4 := parsed.To4(); v4 != nil {
+		return v4.String()
+	}
+	return parsed.String()
+}
+
+// Host return the normalized ip of the address, which can be "ip:port", "[ipv6]:port", or a ip without port like "fd00::1".
+func Host(addr string) string {
+	if host, _, err := net.SplitHostPort(addr); err == nil {
+		return Normalize(host)
+	}
+	return Normalize(addr)
+}
+
+// IsLoopback check if the ip of the address is a loopback address, like 127.0.0.1 or ::1.
+func IsLoopback(addr string) bool {
+	ip := net.ParseIP(Host(addr))
+	return ip != nil && ip.IsLoopback()
+}
+
+// Equal check if the ips of the two addresses are the same
+func Equal(a, b string) bool {
+	return Host(a) == Host(b)
+}
+
+// ParseNet parse the network in CIDR notation like "10.0.0.0/8" or "fd00::/8", a single ip is parsed as the network only containing itself.
+func ParseNet(s string) (*net.IPNet, error) {
+	if strings.IndexByte(s, '/') < 0 {
+		ip := net.ParseIP(Normalize(s))
+		if ip == nil {
+			return nil, fmt.Errorf("invalid ip %s", s)
+		}
+		if v4 := ip.To4(); v4 != nil {
+			return &net.IPNet{IP: v4, Mask: net.CIDRMask(32, 32)}, nil
+		}
+		return &net.IPNet{IP: ip, Mask: net.CIDRMask(128, 128)}, nil
+	}
+	_, n, err := net.ParseCIDR(s)
+	return n, err
+}
+
+// Contains check if the ip of the address is in the network, the IPv4-mapped IPv6 addresses are in the IPv4 networks.
+func Contains(n *net.IPNet, addr string) bool {
+	ip := net.ParseIP(Host(addr))
+	return ip != nil && n.Contains(ip)
+}
@@ -0,0 +1,84 @@
+package ipaddr
+
+import "testing"
+
+func TestHost(t *testing.T) {
+	cases := []struct {
+		addr, host string
+	}{
+		{"10.0.0.1", "10.0.0.1"},
+		{"10.0.0.1:9001", "10.0.0.1"},
+		{"::ffff:10.0.0.1", "10.0.0.1"},
+		{"[::ffff:10.0.0.1]:9001", "10.0.0.1"},
+		{"fd00::1", "fd00::1"},
+		{"FD00:0:0:0::1", "fd00::1"},
+		{"[fd00::1]", "fd00::1"},
+		{"[fd00::1]:9001", "fd00::1"},
+		{"[fe80::1%eth0]:9001", "fe80::1"},
+		{"::1", "::1"},
+		{"[::1]:9001", "::1"},
+		{"localhost:9001", "localhost"},
+	}
+	for _, c := range cases {
+		if host := Host(c.addr); host != c.host {
+			t.Errorf("Host(%q) = %q, want %q", c.addr, host, c.host)
+		}
+	}
+}
+
+func TestIsLoopback(t *testing.T) {
+	for addr, want := range map[string]bool{
+		"127.0.0.1:9001": true,
+		"127.0.1.1":      true,
+		"[::1]:9001":     true,
+		"::1":            true,
+		"10.0.0.1:9001":  false,
+		"[fd00::1]:9001": false,
+		"localhost":      false,
+	} {
+		if IsLoopback(addr) != want {
+			t.Errorf("IsLoopback(%q) != %v", addr, want)
+		}
+	}
+}
+
+func TestEqual(t *testing.T) {
+	if !Equal("[FD00::1]:1234", "fd00:0::1") {
+		t.Error("the same IPv6 address in different forms should be equal")
+	}
+	if Equal("10.0.0.1:1234", "10.0.0.10") {
+		t.Error("different addresses should not be equal")
+	}
+}
+
+func TestContains(t *testing.T) {
+	cases := []struct {
+		network, addr string
+		contains      bool
+	}{
+		{"10.0.0.0/8", "10.1.2.3:9001", true},
+		{"10.0.0.0/8", "[::ffff:10.1.2.3]:9001", true},
+		{"10.0.0.0/8", "11.0.0.1", false},
+		{"fd00::/16", "[fd00:20::2]:9001", true},
+		{"fd00::/16", "fe80::1", false},
+		{"10.0.0.1", "10.0.0.1:9001", true},
+		{"10.0.0.1", "10.0.0.2", false},
+		{"FD00::1", "[fd00:0::1]:9001", true},
+		{"10.0.0.0/8", "localhost", false},
+	}
+	for _, c := range cases {
+		n, err := ParseNet(c.network)
+		if err != nil {
+			t.Errorf("ParseNet(%q) failed, %s", c.network, err.Error())
+			continue
+		}
+		if Contains(n, c.addr) != c.contains {
+			t.Errorf("Contains(%q, %q) != %v", c.network, c.addr, c.contains)
+		}
+	}
+	for _, s := range []string{"", "10.0.0.0/33", "localhost", "10.0.0"} {
+		if _, err := ParseNet(s); err == nil {
+			t.Errorf("ParseNet(%q) should fail", s)
+		}
+	}
+}
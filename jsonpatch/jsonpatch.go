@@ -0,0 +1,332 @@
+// Package jsonpatch implements the subset of RFC 6902 (JSON Patch) lainlet needs:
+// computing a patch between two encoded json documents, and applying a patch to a document.
+package jsonpatch
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Operation represents a single json patch operation
+type Operation struct {
+	Op    string      `json:"op"`
+	Path  string      `json:"path"`
+	From  string      `json:"from,omitempty"`
+	Value interface{} `json:"value,omitempty"`
+}
+
+// MarshalJSON always write the value for add, replace and test operations, even if it is null.
+func (op Operation) MarshalJSON() ([]byte, error) {
+	type operation Operation // avoid recursion
+	switch op.Op {
+	case "add", "replace", "test":
+		return json.Marshal(struct {
+			operation
+			Value interface{} `json:"value"`
+		}{operation(op), op.Value})
+	}
+	return json.Marshal(operation(op))
+}
+
+// Patch is a list of operations, applied in order
+type Patch []Operation
+
+// Diff compute the patch which transform the json document `from` into `to`.
+func Diff(from, to []byte) ([]byte, error) {
+	a, err := decode(from)
+	if err != nil {
+		return nil, err
+	}
+	b, err := decode(to)
+	if err != nil {
+		return nil, err
+	}
+	patch := make(Patch, 0)
+	patch = diff(patch, "", a, b)
+	return json.Marshal(patch)
+}
+
+// Apply the json patch to the json document, return the patched document.
+func Apply(doc, patch []byte) ([]byte, error) {
+	var ops Patch
+	dec := json.NewDecoder(bytes.NewReader(patch))
+	dec.UseNumber()
+	if err := dec.Decode(&ops); err != nil {
+		return nil, err
+	}
+	root, err := decode(doc)
+	if err != nil {
+		return nil, err
+	}
+	for _, op := range ops {
+		if root, err = apply(root, op); err != nil {
+			return nil, err
+		}
+	}
+	return json.Marshal(root)
+}
+
+func decode(data []byte) (interface{}, error) {
+	var v interface{}
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber() // keep the numbers as what they are
+	if err := dec.Decode(&v); err != nil {
+		return nil, err
+	}
+	return v, nil
+}
+
+func diff(patch Patch, path string, a, b interface{}) Patch {
+	switch av := a.(type) {
+	case map[string]interface{}:
+		if bv, ok := b.(map[string]interface{}); ok {
+			return diffObject(patch, path, av, bv)
+		}
+	case []interface{}:
+		if bv, ok := b.([]interface{}); ok {
+			return diffArray(patch, path, av, bv)
+		}
+	}
+	if !reflect.DeepEqual(a, b) {
+		patch = append(patch, Operation{Op: "replace", Path: path, Value: b})
+	}
+	return patch
+}
+
+func diffObject(patch Patch, path string, a, b map[string]interface{}) Patch {
+	keys := make([]string, 0, len(a)+len(b))
+	for k := range a {
+		keys = append(keys, k)
+	}
+	for k := range b {
+		if _, ok := a[k]; !ok {
+			keys = append(keys, k)
+		}
+	}
+	sort.Strings(keys) // make the patch stable
+	for _, k := range keys {
+		p := path + "/" + escape(k)
+		av, inA := a[k]
+		bv, inB := b[k]
+		switch {
+		case inA && !inB:
+			patch = append(patch, Operation{Op: "remove", Path: p})
+		case !inA && inB:
+			patch = append(patch, Operation{Op: "add", Path: p, Value: bv})
+		default:
+			patch = diff(patch, p, av, bv)
+		}
+	}
+	return patch
+}
+
+func diffArray(patch Patch, path string, a, b []interface{}) Patch {
+	n := len(a)
+	if len(b) < n {
+		n = len(b)
+	}
+	for i := 0; i < n; i++ {
+		patch = diff(patch, path+"/"+strconv.Itoa(i), a[i], b[i])
+	}
+	for i := n; i < len(b); i++ {
+		patch = append(patch, Operation{Op: "add", Path: path + "/-", Value: b[i]})
+	}
+	// remove from the tail, so the indexes in front keep valid
+	for i := len(a) - 1; i >= n; i-- {
+		patch = append(patch, Operation{Op: "remove", Path: path + "/" + strconv.Itoa(i)})
+	}
+	return patch
+}
+
+func escape(token string) string {
+	return strings.Replace(strings.Replace(token, "~", "~0", -1), "/", "~1", -1)
+}
+
+func unescape(token string) string {
+	return strings.Replace(strings.Replace(token, "~1", "/", -1), "~0", "~", -1)
+}
+
+func splitPath(path string) ([]string, error) {
+	if path == "" {
+		return []string{}, nil
+	}
+	if path[0] != '/' {
+		return nil, fmt.Errorf("invalid json pointer %q", path)
+	}
+	tokens := strings.Split(path[1:], "/")
+	for i := range tokens {
+		tokens[i] = unescape(tokens[i])
+	}
+	return tokens, nil
+}
+
+func apply(root interface{}, op Operation) (interface{}, error) {
+	switch op.Op {
+	case "add":
+		return add(root, op.Path, op.Value)
+	case "remove":
+		root, _, err := remove(root, op.Path)
+		return root, err
+	case "replace":
+		root, _, err := remove(root, op.Path)
+		if err != nil {
+			return nil, err
+		}
+		return add(root, op.Path, op.Value)
+	case "move":
+		root, value, err := remove(root, op.From)
+		if err != nil {
+			return nil, err
+		}
+		return add(root, op.Path, value)
+	case "copy":
+		value, err := get(root, op.From)
+		if err != nil {
+			return nil, err
+		}
+		return add(root, op.Path, value)
+	case "test":
+		value, err := get(root, op.Path)
+		if err != nil {
+			return nil, err
+		}
+		if !equal(value, op.Value) {
+			return nil, fmt.Errorf("test operation failed at %q", op.Path)
+		}
+		return root, nil
+	}
+	return nil, fmt.Errorf("unknown operation %q", op.Op)
+}
+
+func equal(a, b interface{}) bool {
+	ac, _ := json.Marshal(a)
+	bc, _ := json.Marshal(b)
+	return bytes.Equal(ac, bc)
+}
+
+func get(root interface{}, path string) (interface{}, error) {
+	tokens, err := splitPath(path)
+	if err != nil {
+		return nil, err
+	}
+	node := root
+	for _, token := range tokens {
+		switch v := node.(type) {
+		case map[string]interface{}:
+			child, ok := v[token]
+			if !ok {
+				return nil, fmt.Errorf("path %q not found", path)
+			}
+			node = child
+		case []interface{}:
+			i, err := strconv.Atoi(token)
+			if err != nil || i < 0 || i >= len(v) {
+				return nil, fmt.Errorf("invalid array index in path %q", path)
+			}
+			node = v[i]
+		default:
+			return nil, fmt.Errorf("path %q not found", path)
+		}
+	}
+	return node, nil
+}
+
+// update walk to the parent of the path, and replace the child by calling f.
+// f receive the parent node and the last token, return the new parent node.
+func update(root interface{}, tokens []string, f func(parent interface{}, token string) (interface{}, error)) (interface{}, error) {
+	if len(tokens) == 1 {
+		return f(root, tokens[0])
+	}
+	switch v := root.(type) {
+	case map[string]interface{}:
+		child, ok := v[tokens[0]]
+		if !ok {
+			return nil, fmt.Errorf("path token %q not found", tokens[0])
+		}
+		child, err := update(child, tokens[1:], f)
+		if err != nil {
+			return nil, err
+		}
+		v[tokens[0]] = child
+		return v, nil
+	case []interface{}:
+		i, err := strconv.Atoi(tokens[0])
+		if err != nil || i < 0 || i >= len(v) {
+			return nil, fmt.Errorf("invalid array index %q", tokens[0])
+		}
+		child, err := update(v[i], tokens[1:], f)
+		if err != nil {
+			return nil, err
+		}
+		v[i] = child
+		return v, nil
+	}
+	return nil, fmt.Errorf("path token %q not found", tokens[0])
+}
+
+func add(root interface{}, path string, value interface{}) (interface{}, error) {
+	tokens, err := splitPath(path)
+	if err != nil {
+		return nil, err
+	}
+	if len(tokens) == 0 {
+		return value, nil
+	}
+	return update(root, tokens, func(parent interface{}, token string) (interface{}, error) {
+		switch v := parent.(type) {
+		case map[string]interface{}:
+			v[token] = value
+			return v, nil
+		case []interface{}:
+			if token == "-" {
+				return append(v, value), nil
+			}
+			i, err := strconv.Atoi(token)
+			if err != nil || i < 0 || i > len(v) {
+				return nil, fmt.Errorf("invalid array index in path %q", path)
+			}
+			v = append(v, nil)
+			copy(v[i+1:], v[i:])
+			v[i] = value
+			return v, nil
+		}
+		return nil, fmt.Errorf("can not add value at %q", path)
+	})
+}
+
+func remove(root interface{}, path string) (interface{}, interface{}, error) {
+	tokens, err := splitPath(path)
+	if err != nil {
+		return nil, nil, err
+	}
+	if len(tokens) == 0 {
+		return nil, root, nil
+	}
+	var removed interface{}
+	root, err = update(root, tokens, func(parent interface{}, token string) (interface{}, error) {
+		switch v := parent.(type) {
+		case map[string]interface{}:
+			value, ok := v[token]
+			if !ok {
+				return nil, fmt.Errorf("path %q not found", path)
+			}
+			removed = value
+			delete(v, token)
+			return v, nil
+		case []interface{}:
+			i, err := strconv.Atoi(token)
+			if err != nil || i < 0 || i >= len(v) {
+				return nil, fmt.Errorf("invalid array index in path %q", path)
+			}
+			removed = v[i]
+			return append(v[:i], v[i+1:]...), nil
+		}
+		return nil, fmt.Errorf("path %q not found", path)
+	})
+	return root, removed, err
+}
@@ -0,0 +1,140 @@
+package jsonpatch
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+)
+
+func sameJSON(t *testing.T, a, b []byte) bool {
+	var av, bv interface{}
+	if err := json.Unmarshal(a, &av); err != nil {
+		t.Fatalf("invalid json %s, %s", a, err.Error())
+	}
+	if err := json.Unmarshal(b, &bv); err != nil {
+		t.Fatalf("invalid json %s, %s", b, err.Error())
+	}
+	return reflect.DeepEqual(av, bv)
+}
+
+func TestDiffApply(t *testing.T) {
+	cases := []struct {
+		from, to string
+	}{
+		{`{}`, `{}`},
+		{`{"a":1}`, `{"a":2}`},
+		{`{"a":1,"b":2}`, `{"b":2,"c":3}`},
+		{`{"a":{"b":{"c":1}}}`, `{"a":{"b":{"c":2,"d":[1,2]}}}`},
+		{`{"a":[1,2,3]}`, `{"a":[1,5]}`},
+		{`{"a":[1]}`, `{"a":[1,2,3]}`},
+		{`{"a":[{"x":1},{"x":2}]}`, `{"a":[{"x":1},{"x":3},{"y":4}]}`},
+		{`{"a":{"b":1}}`, `{"a":[1]}`},
+		{`{"a":null}`, `{"a":{"b":null}}`},
+		{`{"a/b":1,"c~d":2}`, `{"a/b":2,"e~/f":3}`},
+		{`{"n":12345678901234567890}`, `{"n":0.1}`},
+		{`[1,2]`, `{"a":1}`},
+		{`"a"`, `null`},
+	}
+	for _, c := range cases {
+		patch, err := Diff([]byte(c.from), []byte(c.to))
+		if err != nil {
+			t.Errorf("Diff(%s, %s) failed, %s", c.from, c.to, err.Error())
+			continue
+		}
+		doc, err := Apply([]byte(c.from), patch)
+		if err != nil {
+			t.Errorf("Apply(%s, %s) failed, %s", c.from, patch, err.Error())
+			continue
+		}
+		if !sameJSON(t, doc, []byte(c.to)) {
+			t.Errorf("Apply(%s, Diff(%s, %s)) = %s, want %s", c.from, c.from, c.to, doc, c.to)
+		}
+	}
+}
+
+func TestDiff(t *testing.T) {
+	cases := []struct {
+		from, to, patch string
+	}{
+		{`{"a":1}`, `{"a":1}`, `[]`},
+		{`{"a":1,"b":2}`, `{"b":3,"c":null}`, `[{"op":"remove","path":"/a"},{"op":"replace","path":"/b","value":3},{"op":"add","path":"/c","value":null}]`},
+		{`{"a/b":1,"c~d":1}`, `{"a/b":2,"c~d":2}`, `[{"op":"replace","path":"/a~1b","value":2},{"op":"replace","path":"/c~0d","value":2}]`},
+		{`[1,2,3]`, `[1]`, `[{"op":"remove","path":"/2"},{"op":"remove","path":"/1"}]`},
+		{`[1]`, `[1,2,3]`, `[{"op":"add","path":"/-","value":2},{"op":"add","path":"/-","value":3}]`},
+		{`{"a":1}`, `[1]`, `[{"op":"replace","path":"","value":[1]}]`},
+	}
+	for _, c := range cases {
+		patch, err := Diff([]byte(c.from), []byte(c.to))
+		if err != nil {
+			t.Errorf("Diff(%s, %s) failed, %s", c.from, c.to, err.Error())
+			continue
+		}
+		if string(patch) != c.patch {
+			t.Errorf("Diff(%s, %s) = %s, want %s", c.from, c.to, patch, c.patch)
+		}
+	}
+}
+
+func TestApply(t *testing.T) {
+	cases := []struct {
+		doc, patch, want string
+	}{
+		// escaping
+		{`{"a/b":1}`, `[{"op":"replace","path":"/a~1b","value":2}]`, `{"a/b":2}`},
+		{`{"a~b":1}`, `[{"op":"remove","path":"/a~0b"}]`, `{}`},
+		{`{}`, `[{"op":"add","path":"/~01","value":1}]`, `{"~1":1}`},
+		// arrays
+		{`[1,2]`, `[{"op":"add","path":"/-","value":3}]`, `[1,2,3]`},
+		{`[1,2]`, `[{"op":"add","path":"/0","value":0}]`, `[0,1,2]`},
+		{`[1,2]`, `[{"op":"add","path":"/2","value":3}]`, `[1,2,3]`},
+		{`[1,2,3]`, `[{"op":"remove","path":"/1"}]`, `[1,3]`},
+		{`{"a":[[1],[2]]}`, `[{"op":"replace","path":"/a/1/0","value":3}]`, `{"a":[[1],[3]]}`},
+		// the whole document
+		{`{"a":1}`, `[{"op":"replace","path":"","value":[1]}]`, `[1]`},
+		// test, move and copy
+		{`{"a":{"b":1}}`, `[{"op":"test","path":"/a","value":{"b":1}}]`, `{"a":{"b":1}}`},
+		{`{"a":1,"b":2}`, `[{"op":"move","from":"/a","path":"/c"}]`, `{"b":2,"c":1}`},
+		{`{"a":[1,2]}`, `[{"op":"move","from":"/a/0","path":"/a/-"}]`, `{"a":[2,1]}`},
+		{`{"a":{"b":1}}`, `[{"op":"copy","from":"/a","path":"/c"}]`, `{"a":{"b":1},"c":{"b":1}}`},
+		// applied in order
+		{`{}`, `[{"op":"add","path":"/a","value":[]},{"op":"add","path":"/a/-","value":1},{"op":"test","path":"/a/0","value":1}]`, `{"a":[1]}`},
+	}
+	for _, c := range cases {
+		doc, err := Apply([]byte(c.doc), []byte(c.patch))
+		if err != nil {
+			t.Errorf("Apply(%s, %s) failed, %s", c.doc, c.patch, err.Error())
+			continue
+		}
+		if !sameJSON(t, doc, []byte(c.want)) {
+			t.Errorf("Apply(%s, %s) = %s, want %s", c.doc, c.patch, doc, c.want)
+		}
+	}
+}
+
+func TestApplyError(t *testing.T) {
+	cases := []struct {
+		doc, patch string
+	}{
+		{`{"a":1}`, `[{"op":"remove","path":"/b"}]`},
+		{`{"a":1}`, `[{"op":"replace","path":"/b","value":1}]`},
+		{`{"a":1}`, `[{"op":"replace","path":"/b/c","value":1}]`},
+		{`[1]`, `[{"op":"remove","path":"/1"}]`},
+		{`[1]`, `[{"op":"remove","path":"/-"}]`},
+		{`[1]`, `[{"op":"replace","path":"/x","value":1}]`},
+		{`[1]`, `[{"op":"add","path":"/2","value":1}]`},
+		{`{"a":1}`, `[{"op":"add","path":"/a/b","value":1}]`},
+		{`{"a":1}`, `[{"op":"add","path":"a","value":1}]`},
+		{`{"a":1}`, `[{"op":"test","path":"/a","value":2}]`},
+		{`{"a":1}`, `[{"op":"test","path":"/b","value":1}]`},
+		{`{"a":1}`, `[{"op":"move","from":"/b","path":"/c"}]`},
+		{`{"a":1}`, `[{"op":"copy","from":"/b","path":"/c"}]`},
+		{`{"a":1}`, `[{"op":"unknown","path":"/a"}]`},
+		{`{"a":1}`, `{"op":"remove","path":"/a"}`},
+		{`{"a":`, `[]`},
+	}
+	for _, c := range cases {
+		if doc, err := Apply([]byte(c.doc), []byte(c.patch)); err == nil {
+			t.Errorf("Apply(%s, %s) = %s, want error", c.doc, c.patch, doc)
+		}
+	}
+}
@@ -0,0 +1,302 @@
+package lainlettest
+
+import (
+	"encoding/json"
+	"fmt"
+	"path"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/laincloud/lainlet/conf"
+	"github.com/laincloud/lainlet/spec"
+	"github.com/laincloud/lainlet/store"
+	"github.com/mijia/adoc"
+)
+
+// the node the pods are deployed on if no node was added
+const (
+	DefaultNodeName = "node1"
+	DefaultNodeIP   = "192.168.77.21"
+)
+
+// Proc is a proc of the app deployed by AddApp, the podgroup is named by "<appname>.<Type>.<Name>" like deployd does
+type Proc struct {
+	Type       string // the proc type, like "web" and "worker"
+	Name       string // the proc name, the same as Type by default
+	Instances  int    // the number of the pods, 1 by default
+	Image      string // "registry.lain.local/<appname>:release-<Version>" by default
+	Version    string // the release version of the app, "1" by default
+	Expose     int    // the port exposed by the containers
+	Env        []string
+	Annotation string
+}
+
+func (p Proc) name() string {
+	if p.Name == "" {
+		return p.Type
+	}
+	return p.Name
+}
+
+// podGroupKey return the key of the podgroup in store, podname is like "hello.web.web"
+func podGroupKey(podname string) string {
+	return path.Join(conf.Current().Keys.PodGroups, strings.SplitN(podname, ".", 2)[0], podname)
+}
+
+// AddApp deploy the procs of the app, each pod has a container running on the nodes in turn, with a allocated ip.
+// The podgroups of the app are replaced, so it can be called again to upgrade the app.
+func (s *Store) AddApp(appname string, procs ...Proc) error {
+	s.deployLock.Lock()
+	defer s.deployLock.Unlock()
+	for _, p := range procs {
+		if p.Type == "" {
+			return fmt.Errorf("the type of the proc of %s is empty", appname)
+		}
+		if p.Instances <= 0 {
+			p.Instances = 1
+		}
+		if p.Version == "" {
+			p.Version = "1"
+		}
+		if p.Image == "" {
+			p.Image = fmt.Sprintf("registry.lain.local/%s:release-%s", appname, p.Version)
+		}
+		podname := fmt.Sprintf("%s.%s.%s", appname, p.Type, p.name())
+		env := append([]string{
+			"LAIN_APPNAME=" + appname,
+			"LAIN_PROCNAME=" + p.name(),
+			"LAIN_APP_RELEASE_VERSION=" + p.Version,
+		}, p.Env...)
+		now := time.Now()
+		pg := &spec.PodGroupWithSpec{
+			Spec: spec.PodGroupSpec{
+				ImSpec: spec.ImSpec{Name: podname, Namespace: appname, Version: 1, CreatedAt: now, UpdatedAt: now},
+				Pod: spec.PodSpec{
+					ImSpec: spec.ImSpec{Name: podname, Namespace: appname, Version: 1, CreatedAt: now, UpdatedAt: now},
+					Containers: []spec.ContainerSpec{{
+						ImSpec: spec.ImSpec{Name: podname, Namespace: appname, Version: 1, CreatedAt: now, UpdatedAt: now},
+						Image:  p.Image,
+						Env:    env,
+						Expose: p.Expose,
+					}},
+					Annotation: p.Annotation,
+				},
+				NumInstances: p.Instances,
+			},
+		}
+		pg.State = spec.RunStateSuccess
+		if err := s.scale(pg, p.Instances); err != nil {
+			return err
+		}
+		if err := s.putPodGroup(pg); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// RemoveApp delete all the podgroups of the app
+func (s *Store) RemoveApp(appname string) error {
+	return s.Delete(path.Join(conf.Current().Keys.PodGroups, appname), true)
+}
+
+// ScaleProc change the number of the pods of the proc, the new pods are deployed like AddApp, the last pods are removed
+func (s *Store) ScaleProc(podname string, instances int) error {
+	return s.updatePodGroup(podname, func(pg *spec.PodGroupWithSpec) error {
+		pg.Spec.NumInstances = instances
+		return s.scale(pg, instances)
+	})
+}
+
+// SetContainerIP change the ip of the container of the pod, the instance numbers start from 1
+func (s *Store) SetContainerIP(podname string, instanceNo int, ip string) error {
+	return s.updatePod(podname, instanceNo, func(pod *spec.Pod) {
+		pod.Containers[0].ContainerIp = ip
+	})
+}
+
+// SetPodState change the state of the pod, like spec.RunStateExit
+func (s *Store) SetPodState(podname string, instanceNo int, state spec.RunState) error {
+	return s.updatePod(podname, instanceNo, func(pod *spec.Pod) {
+		pod.State = state
+	})
+}
+
+// PodGroup return the podgroup in store
+func (s *Store) PodGroup(podname string) (*spec.PodGroupWithSpec, error) {
+	kv, err := s.Get(podGroupKey(podname))
+	if err != nil {
+		return nil, err
+	}
+	var pg spec.PodGroupWithSpec
+	if err := json.Unmarshal(kv.Value, &pg); err != nil {
+		return nil, err
+	}
+	return &pg, nil
+}
+
+// AddNode add a node, the pods deployed later run on the nodes in turn
+func (s *Store) AddNode(name, ip string) error {
+	content, err := json.Marshal(map[string]interface{}{"name": name, "ip": ip, "ssh_port": 22})
+	if err != nil {
+		return err
+	}
+	return s.Put(path.Join(conf.Current().Keys.Nodes, fmt.Sprintf("%s:%s:22", name, ip)), content)
+}
+
+// RemoveNode delete the node, the pods on it become missing and their containers lose the ips
+func (s *Store) RemoveNode(name string) error {
+	s.deployLock.Lock()
+	defer s.deployLock.Unlock()
+	key, err := s.nodeKey(name)
+	if err != nil {
+		return err
+	}
+	if err := s.Delete(key, false); err != nil {
+		return err
+	}
+	pairs, err := s.GetTree(conf.Current().Keys.PodGroups)
+	if err == store.ErrKeyNotFound {
+		return nil
+	} else if err != nil {
+		return err
+	}
+	for _, kv := range pairs {
+		var pg spec.PodGroupWithSpec
+		if err := json.Unmarshal(kv.Value, &pg); err != nil {
+			return err
+		}
+		changed := false
+		for i := range pg.Pods {
+			pod := &pg.Pods[i]
+			if len(pod.Containers) > 0 && pod.Containers[0].NodeName == name {
+				pod.State = spec.RunStateMissing
+				pod.Containers[0].ContainerIp = ""
+				changed = true
+			}
+		}
+		if changed {
+			if err := s.putPodGroup(&pg); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// SetConfig set the value of the config key, like "vips" or "dnsmasq_addresses"
+func (s *Store) SetConfig(key, value string) error {
+	return s.Put(path.Join(conf.Current().Keys.Config, key), []byte(value))
+}
+
+// DeleteConfig delete the config key
+func (s *Store) DeleteConfig(key string) error {
+	return s.Delete(path.Join(conf.Current().Keys.Config, key), true)
+}
+
+// nodeKey return the key of the node by name, the nodes are stored by "<name>:<ip>:<ssh port>"
+func (s *Store) nodeKey(name string) (string, error) {
+	keys, err := s.List(conf.Current().Keys.Nodes)
+	if err != nil && err != store.ErrKeyNotFound {
+		return "", err
+	}
+	for _, key := range keys {
+		if strings.SplitN(path.Base(key), ":", 2)[0] == name {
+			return key, nil
+		}
+	}
+	return "", fmt.Errorf("node %s not found", name)
+}
+
+// nodes return the names and ips of the nodes sorted by name, the default node is added if there is none
+func (s *Store) nodes() ([][2]string, error) {
+	keys, err := s.List(conf.Current().Keys.Nodes)
+	if err != nil && err != store.ErrKeyNotFound {
+		return nil, err
+	}
+	var ret [][2]string
+	for _, key := range keys {
+		if fields := strings.Split(path.Base(key), ":"); len(fields) >= 2 {
+			ret = append(ret, [2]string{fields[0], fields[1]})
+		}
+	}
+	if len(ret) == 0 {
+		if err := s.AddNode(DefaultNodeName, DefaultNodeIP); err != nil {
+			return nil, err
+		}
+		ret = append(ret, [2]string{DefaultNodeName, DefaultNodeIP})
+	}
+	sort.Slice(ret, func(i, j int) bool { return ret[i][0] < ret[j][0] })
+	return ret, nil
+}
+
+// scale add or remove the pods of the podgroup, s.deployLock should be held
+func (s *Store) scale(pg *spec.PodGroupWithSpec, instances int) error {
+	if instances < len(pg.Pods) {
+		pg.Pods = pg.Pods[:instances]
+		return nil
+	}
+	nodes, err := s.nodes()
+	if err != nil {
+		return err
+	}
+	cs := pg.Spec.Pod.Containers[0]
+	for i := len(pg.Pods); i < instances; i++ {
+		n := s.containers
+		s.containers++
+		node := nodes[n%len(nodes)]
+		pod := spec.Pod{
+			InstanceNo: i + 1,
+			Containers: []spec.Container{{
+				Id:            fmt.Sprintf("%064x", s.containers),
+				Runtime:       adoc.ContainerDetail{Config: &adoc.ContainerConfig{Env: cs.Env}},
+				NodeName:      node[0],
+				NodeIp:        node[1],
+				ContainerIp:   fmt.Sprintf("172.20.%d.%d", n/250, n%250+2),
+				ContainerPort: cs.Expose,
+				Protocol:      "tcp",
+			}},
+		}
+		pod.State = spec.RunStateSuccess
+		pod.Healthst = spec.HealthStateHealthy
+		pod.UpdatedAt = time.Now()
+		pg.Pods = append(pg.Pods, pod)
+	}
+	return nil
+}
+
+// updatePodGroup read the podgroup, change it by fn and write it back
+func (s *Store) updatePodGroup(podname string, fn func(pg *spec.PodGroupWithSpec) error) error {
+	s.deployLock.Lock()
+	defer s.deployLock.Unlock()
+	pg, err := s.PodGroup(podname)
+	if err != nil {
+		return fmt.Errorf("fail to read the podgroup %s, %s", podname, err.Error())
+	}
+	if err := fn(pg); err != nil {
+		return err
+	}
+	return s.putPodGroup(pg)
+}
+
+func (s *Store) updatePod(podname string, instanceNo int, fn func(pod *spec.Pod)) error {
+	return s.updatePodGroup(podname, func(pg *spec.PodGroupWithSpec) error {
+		for i := range pg.Pods {
+			if pg.Pods[i].InstanceNo == instanceNo {
+				fn(&pg.Pods[i])
+				pg.Pods[i].UpdatedAt = time.Now()
+				return nil
+			}
+		}
+		return fmt.Errorf("instance %d of %s not found", instanceNo, podname)
+	})
+}
+
+func (s *Store) putPodGroup(pg *spec.PodGroupWithSpec) error {
+	content, err := json.Marshal(pg)
+	if err != nil {
+		return err
+	}
+	return s.Put(podGroupKey(pg.Spec.Name), content)
+}
@@ -0,0 +1,206 @@
+package lainlettest_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/laincloud/lainlet/client"
+	"github.com/laincloud/lainlet/lainlettest"
+	pb "github.com/laincloud/lainlet/message"
+	"golang.org/x/net/context"
+)
+
+// next receive the next value from the watcher of grpc, it fails the test on error or timeout
+func next(t *testing.T, what string, fn func() (interface{}, error)) interface{} {
+	type result struct {
+		v   interface{}
+		err error
+	}
+	ch := make(chan result, 1)
+	go func() {
+		v, err := fn()
+		ch <- result{v, err}
+	}()
+	select {
+	case r := <-ch:
+		if r.err != nil {
+			t.Fatalf("the grpc watch of %s stopped, %s", what, r.err.Error())
+		}
+		return r.v
+	case <-time.After(5 * time.Second):
+		t.Fatalf("no %s received by grpc", what)
+	}
+	return nil
+}
+
+func newServer(t *testing.T) *lainlettest.Server {
+	srv, err := lainlettest.NewServer(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return srv
+}
+
+func TestServerConfig(t *testing.T) {
+	srv := newServer(t)
+	defer srv.Close()
+	if err := srv.SetConfig("vips", "a"); err != nil {
+		t.Fatal(err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	configs := srv.Client().WatchConfig(ctx, "vips")
+	cli, err := srv.GRPCClient()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer cli.Close()
+	wch, err := cli.ConfigWatchContext(ctx, "vips")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, vips := range []string{"a", "b"} {
+		if vips != "a" {
+			if err := srv.SetConfig("vips", vips); err != nil {
+				t.Fatal(err)
+			}
+		}
+		select {
+		case config := <-configs:
+			if config["vips"] != vips {
+				t.Errorf("got the config %v by http, want the vips %q", config, vips)
+			}
+		case <-time.After(5 * time.Second):
+			t.Fatalf("no config received by http, want the vips %q", vips)
+		}
+		rpl := next(t, "config", func() (interface{}, error) {
+			rpl, err := wch.Next()
+			return rpl, err
+		})
+		if data := rpl.(*pb.ConfigReply).Data; data["vips"] != vips {
+			t.Errorf("got the config %v by grpc, want the vips %q", data, vips)
+		}
+	}
+}
+
+// containers count the containers of the coreinfos
+func containers(infos client.CoreInfos) int {
+	n := 0
+	for _, info := range infos {
+		for _, pod := range info.PodInfos {
+			n += len(pod.Containers)
+		}
+	}
+	return n
+}
+
+func TestServerCoreInfo(t *testing.T) {
+	srv := newServer(t)
+	defer srv.Close()
+	srv.AddNode("node1", "10.0.0.1")
+	if err := srv.AddApp("hello", lainlettest.Proc{Type: "web", Instances: 2, Expose: 8080}); err != nil {
+		t.Fatal(err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	infos := srv.Client().WatchCoreInfo(ctx, "hello")
+	cli, err := srv.GRPCClient()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer cli.Close()
+	wch, err := cli.CoreinfoWatchContext(ctx, "hello")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, n := range []int{2, 3} {
+		if n != 2 {
+			if err := srv.ScaleProc("hello.web.web", n); err != nil {
+				t.Fatal(err)
+			}
+		}
+		select {
+		case info := <-infos:
+			if got := containers(info); got != n {
+				t.Errorf("got %d containers by http, want %d", got, n)
+			}
+		case <-time.After(5 * time.Second):
+			t.Fatalf("no coreinfo received by http, want %d containers", n)
+		}
+		rpl := next(t, "coreinfo", func() (interface{}, error) {
+			rpl, err := wch.Next()
+			return rpl, err
+		})
+		got := 0
+		for _, info := range rpl.(*pb.CoreinfoReply).Data {
+			for _, pod := range info.PodInfos {
+				got += len(pod.Containers)
+			}
+		}
+		if got != n {
+			t.Errorf("got %d containers by grpc, want %d", got, n)
+		}
+	}
+}
+
+func TestServerClose(t *testing.T) {
+	srv := newServer(t)
+	closed := false
+	defer func() {
+		if !closed {
+			srv.Close()
+		}
+	}()
+	if err := srv.SetConfig("vips", "a"); err != nil {
+		t.Fatal(err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	responses, err := srv.Client().Watch("/v2/configwatcher?target=vips", ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cli, err := srv.GRPCClient()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer cli.Close()
+	wch, err := cli.ConfigWatchContext(ctx, "vips")
+	if err != nil {
+		t.Fatal(err)
+	}
+	next(t, "config", func() (interface{}, error) {
+		rpl, err := wch.Next()
+		return rpl, err
+	})
+
+	// the watches of both http and grpc are ended by Close
+	srv.Close()
+	closed = true
+	timeout := time.After(5 * time.Second)
+	for open := true; open; {
+		select {
+		case _, open = <-responses:
+		case <-timeout:
+			t.Fatal("the http watch is not ended after the server was closed")
+		}
+	}
+	done := make(chan error, 1)
+	go func() {
+		_, err := wch.Next()
+		done <- err
+	}()
+	select {
+	case err := <-done:
+		if err == nil {
+			t.Error("the grpc watch should fail after the server was closed")
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("the grpc watch is not ended after the server was closed")
+	}
+}
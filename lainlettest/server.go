@@ -0,0 +1,245 @@
+// Package lainlettest run a lainlet in the process for the unit tests of its clients, like webrouter and rebellion.
+// The http and grpc servers are the real ones, listening on the ephemeral ports of 127.0.0.1,
+// and the data is read from a in-memory store, which the tests change by the helpers writing the data like deployd.
+//
+//	srv, err := lainlettest.NewServer(nil)
+//	if err != nil {
+//		t.Fatal(err)
+//	}
+//	defer srv.Close()
+//	srv.AddApp("hello", lainlettest.Proc{Type: "web", Instances: 2, Expose: 8080})
+//	infos := srv.Client().WatchCoreInfo(ctx, "hello")
+package lainlettest
+
+import (
+	"crypto/tls"
+	"fmt"
+	"net"
+	"sync"
+	"time"
+
+	"github.com/laincloud/lainlet/api"
+	"github.com/laincloud/lainlet/api/v2"
+	"github.com/laincloud/lainlet/auth"
+	"github.com/laincloud/lainlet/client"
+	"github.com/laincloud/lainlet/conf"
+	"github.com/laincloud/lainlet/grpcclient"
+	grpcserver "github.com/laincloud/lainlet/server"
+	"github.com/laincloud/lainlet/store"
+	"github.com/laincloud/lainlet/version"
+	"github.com/laincloud/lainlet/watcher"
+	"github.com/laincloud/lainlet/watcher/watchers"
+	"golang.org/x/net/context"
+)
+
+// syncTimeout is how long NewServer waits for the watchers reading the store
+const syncTimeout = 10 * time.Second
+
+// Options is the options of the server, the zero values mean the defaults
+type Options struct {
+	// check the requests by the access control like a real lainlet, all the requests are allowed by default
+	Auth bool
+	// the ip of the node lainlet running on, used by the apis like localspecwatcher, "127.0.0.1" by default
+	IP string
+	// the store with the data prepared, a empty one is created by default
+	Store *Store
+	// serve https instead of http by the tls config, like the one created by api.NewTLSConfig
+	TLS *tls.Config
+}
+
+// Server is a lainlet serving http on HTTPAddr and grpc on GRPCAddr, the data can be changed by the methods of Store
+type Server struct {
+	*Store
+	HTTPAddr string
+	GRPCAddr string
+
+	https     bool
+	env       *api.Env
+	cancel    context.CancelFunc
+	grpcSrv   *grpcserver.Server
+	watchers  map[string]watcher.Watcher
+	listeners []*trackListener
+}
+
+// NewServer start a lainlet, the servers start after the watchers read the data in store
+func NewServer(opts *Options) (*Server, error) {
+	var o Options
+	if opts != nil {
+		o = *opts
+	}
+	if o.IP == "" {
+		o.IP = "127.0.0.1"
+	}
+	if o.Store == nil {
+		o.Store = NewStore()
+	}
+	cfg := *conf.Current()
+	cfg.NoAuth = !o.Auth
+	cfg.IP = o.IP
+
+	ctx, cancel := context.WithCancel(context.Background())
+	srv := &Server{Store: o.Store, env: api.NewEnv(&cfg), cancel: cancel, https: o.TLS != nil}
+	ok := false
+	defer func() {
+		if !ok {
+			srv.Close()
+		}
+	}()
+
+	authorizer, err := auth.New(o.Store.view(), ctx, &cfg)
+	if err != nil {
+		return nil, err
+	}
+	watchers, err := watchers.New(func() store.Store { return o.Store.view() }, ctx)
+	srv.watchers = watchers
+	if err != nil {
+		return nil, err
+	}
+	apis := v2.APIs(o.IP)
+
+	httpSrv, err := api.New(o.IP, version.Version, watchers, authorizer, srv.env)
+	if err != nil {
+		return nil, err
+	}
+	v2.RegisterHTTP(httpSrv, apis)
+	grpcSrv, err := grpcserver.New("", o.IP, watchers, nil, authorizer, srv.env)
+	if err != nil {
+		return nil, err
+	}
+	srv.grpcSrv = grpcSrv
+	for _, a := range apis {
+		grpcSrv.Register(a)
+	}
+
+	if err := waitSynced(watchers); err != nil {
+		return nil, err
+	}
+	httpLis, err := srv.listen()
+	if err != nil {
+		return nil, err
+	}
+	grpcLis, err := srv.listen()
+	if err != nil {
+		return nil, err
+	}
+	srv.HTTPAddr, srv.GRPCAddr = httpLis.Addr().String(), grpcLis.Addr().String()
+	if o.TLS != nil {
+		go httpSrv.ServeTLS(httpLis, o.TLS)
+	} else {
+		go httpSrv.Serve(httpLis)
+	}
+	go grpcSrv.Serve(grpcLis)
+	ok = true
+	return srv, nil
+}
+
+func waitSynced(watchers map[string]watcher.Watcher) error {
+	deadline := time.Now().Add(syncTimeout)
+	for name, wch := range watchers {
+		for !wch.Status().Synced {
+			if time.Now().After(deadline) {
+				return fmt.Errorf("%s did not read the store in %s", name, syncTimeout)
+			}
+			time.Sleep(10 * time.Millisecond)
+		}
+	}
+	return nil
+}
+
+// Client return a http client of the server, the TLS config of the client should be set if the server serves https
+func (srv *Server) Client() *client.Client {
+	if srv.https {
+		return client.New("https://" + srv.HTTPAddr)
+	}
+	return client.New(srv.HTTPAddr)
+}
+
+// Disconnect close the watches of both http and grpc by the id or the client, like the /admin/watches/disconnect api.
+// It return the number of the watches closed.
+func (srv *Server) Disconnect(id uint64, client string) int {
+	return srv.env.Conns.Disconnect(id, client)
+}
+
+// GRPCClient return a grpc client of the server
+func (srv *Server) GRPCClient() (*grpcclient.Client, error) {
+	return grpcclient.New(&grpcclient.Config{Addr: srv.GRPCAddr})
+}
+
+// Close stop the servers and the watchers, the connections of the clients are closed.
+// It returns after the watchers stopped reading the store.
+func (srv *Server) Close() {
+	if srv.grpcSrv != nil {
+		srv.grpcSrv.Stop()
+	}
+	srv.cancel()
+	for _, lis := range srv.listeners {
+		lis.closeAll()
+	}
+	for _, wch := range srv.watchers {
+		if d, ok := wch.(interface {
+			Done() <-chan struct{}
+		}); ok {
+			<-d.Done()
+		}
+	}
+}
+
+func (srv *Server) listen() (*trackListener, error) {
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		return nil, err
+	}
+	tl := &trackListener{Listener: lis, conns: make(map[net.Conn]struct{})}
+	srv.listeners = append(srv.listeners, tl)
+	return tl, nil
+}
+
+// trackListener keep the accepted connections, so they can be closed with the listener,
+// including the ones hijacked by the event streams
+type trackListener struct {
+	net.Listener
+
+	lock   sync.Mutex
+	conns  map[net.Conn]struct{}
+	closed bool
+}
+
+func (l *trackListener) Accept() (net.Conn, error) {
+	conn, err := l.Listener.Accept()
+	if err != nil {
+		return nil, err
+	}
+	l.lock.Lock()
+	defer l.lock.Unlock()
+	if l.closed {
+		conn.Close()
+		return nil, fmt.Errorf("listener closed")
+	}
+	tc := &trackConn{Conn: conn, lis: l}
+	l.conns[tc] = struct{}{}
+	return tc, nil
+}
+
+func (l *trackListener) closeAll() {
+	l.Listener.Close()
+	l.lock.Lock()
+	l.closed = true
+	conns := l.conns
+	l.conns = make(map[net.Conn]struct{})
+	l.lock.Unlock()
+	for conn := range conns {
+		conn.Close()
+	}
+}
+
+type trackConn struct {
+	net.Conn
+	lis *trackListener
+}
+
+func (c *trackConn) Close() error {
+	c.lis.lock.Lock()
+	delete(c.lis.conns, c)
+	c.lis.lock.Unlock()
+	return c.Conn.Close()
+}
@@ -0,0 +1,348 @@
+package lainlettest
+
+import (
+	"fmt"
+	"path"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/laincloud/lainlet/store"
+	"golang.org/x/net/context"
+)
+
+// maxHistory is the number of the events kept for the watches from a index, like the event history of etcd
+const maxHistory = 1000
+
+// Store is a in-memory store.Store working like etcd v2, the keys are paths and the directories are implied by them.
+// Every change has a increasing modified index, the watches from a index receive the changes after it.
+type Store struct {
+	lock    sync.Mutex
+	index   uint64
+	values  map[string]*store.KVPair
+	history []*store.Event
+	changed chan struct{} // closed and replaced when a event was added
+
+	deployLock sync.Mutex // serialize the helpers changing the podgroups
+	containers int        // the number of the containers deployed, used to allocate the ids and ips
+}
+
+var _ store.Store = (*Store)(nil)
+
+// NewStore create a empty in-memory store
+func NewStore() *Store {
+	return &Store{
+		values:  make(map[string]*store.KVPair),
+		changed: make(chan struct{}),
+	}
+}
+
+func normalize(key string) string {
+	return path.Clean("/" + key)
+}
+
+// under check if key is dir or in the directory dir
+func under(key, dir string) bool {
+	return key == dir || dir == "/" || strings.HasPrefix(key, dir+"/")
+}
+
+// Index return the modified index of the last change
+func (s *Store) Index() uint64 {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	return s.index
+}
+
+// Get a value given its key
+func (s *Store) Get(key string) (*store.KVPair, error) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	kv, ok := s.values[normalize(key)]
+	if !ok {
+		return nil, store.ErrKeyNotFound
+	}
+	return copyPair(kv), nil
+}
+
+// GetTree return the key if it is not a directory, otherwise all the keys in it sorted
+func (s *Store) GetTree(key string) ([]*store.KVPair, error) {
+	pairs, _, err := s.getTree(key)
+	return pairs, err
+}
+
+// getTree return the tree and the index of the store when reading it
+func (s *Store) getTree(key string) ([]*store.KVPair, uint64, error) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	key = normalize(key)
+	if kv, ok := s.values[key]; ok {
+		return []*store.KVPair{copyPair(kv)}, s.index, nil
+	}
+	var pairs []*store.KVPair
+	for k, kv := range s.values {
+		if under(k, key) {
+			pairs = append(pairs, copyPair(kv))
+		}
+	}
+	if len(pairs) == 0 {
+		return nil, s.index, store.ErrKeyNotFound
+	}
+	sort.Slice(pairs, func(i, j int) bool { return pairs[i].Key < pairs[j].Key })
+	return pairs, s.index, nil
+}
+
+// Put set the value of the key
+func (s *Store) Put(key string, value []byte) error {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	key = normalize(key)
+	for k := range s.values {
+		if under(key, k) && k != key {
+			return fmt.Errorf("%s is not a directory", k)
+		}
+	}
+	s.index++
+	kv := &store.KVPair{Key: key, Value: append([]byte(nil), value...), LastIndex: s.index}
+	s.values[key] = kv
+	s.addEvent(&store.Event{
+		Action:        store.UPDATE,
+		Key:           key,
+		ModifiedIndex: s.index,
+		Data:          []*store.KVPair{copyPair(kv)},
+	})
+	return nil
+}
+
+// Delete the key, the directory is deleted only if recursive is true
+func (s *Store) Delete(key string, recursive bool) error {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	key = normalize(key)
+	var deleted []string
+	for k := range s.values {
+		if k == key || (recursive && under(k, key)) {
+			deleted = append(deleted, k)
+		}
+	}
+	if len(deleted) == 0 {
+		for k := range s.values {
+			if under(k, key) {
+				return fmt.Errorf("%s is a directory", key)
+			}
+		}
+		return store.ErrKeyNotFound
+	}
+	for _, k := range deleted {
+		delete(s.values, k)
+	}
+	s.index++
+	s.addEvent(&store.Event{
+		Action:        store.DELETE,
+		Key:           key,
+		ModifiedIndex: s.index,
+		Data:          []*store.KVPair{},
+	})
+	return nil
+}
+
+// Exists check if the key exists
+func (s *Store) Exists(key string) (bool, error) {
+	if _, err := s.Get(key); err != nil {
+		if err == store.ErrKeyNotFound {
+			return false, nil
+		}
+		return false, err
+	}
+	return true, nil
+}
+
+// List return the full keys of the children of the directory
+func (s *Store) List(dir string) ([]string, error) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	dir = normalize(dir)
+	children := make(map[string]bool)
+	for k := range s.values {
+		if k != dir && under(k, dir) {
+			rest := strings.TrimPrefix(k[len(dir):], "/")
+			children[path.Join(dir, strings.SplitN(rest, "/", 2)[0])] = true
+		}
+	}
+	if len(children) == 0 {
+		if _, ok := s.values[dir]; ok {
+			return nil, nil
+		}
+		return nil, store.ErrKeyNotFound
+	}
+	ret := make([]string, 0, len(children))
+	for k := range children {
+		ret = append(ret, k)
+	}
+	sort.Strings(ret)
+	return ret, nil
+}
+
+// Watch the changes of the key after index, or from now if index is 0, in the directory if recursive is true.
+// The deleting of the parent directories is sent too. Like etcd, a ERROR event is sent and the channel is closed
+// if the events after index were cleared from the history, the channel is also closed when ctx is done.
+func (s *Store) Watch(key string, ctx context.Context, recursive bool, index uint64) (<-chan *store.Event, error) {
+	if index == 0 {
+		index = s.Index()
+	}
+	return s.watch(normalize(key), ctx, recursive, index), nil
+}
+
+func (s *Store) watch(key string, ctx context.Context, recursive bool, after uint64) <-chan *store.Event {
+	ch := make(chan *store.Event)
+	go func() {
+		defer close(ch)
+		for {
+			s.lock.Lock()
+			if len(s.history) > 0 && after+1 < s.history[0].ModifiedIndex {
+				s.lock.Unlock()
+				select {
+				case ch <- errorEvent(key, fmt.Errorf("the requested history has been cleared [%d/%d]", s.history[0].ModifiedIndex, after+1)):
+				case <-ctx.Done():
+				}
+				return
+			}
+			start := sort.Search(len(s.history), func(i int) bool { return s.history[i].ModifiedIndex > after })
+			var events []*store.Event
+			for _, e := range s.history[start:] {
+				if under(key, e.Key) || recursive && under(e.Key, key) {
+					events = append(events, e)
+				}
+			}
+			if len(s.history) > 0 {
+				after = s.history[len(s.history)-1].ModifiedIndex
+			}
+			changed := s.changed
+			s.lock.Unlock()
+
+			for _, e := range events {
+				select {
+				case ch <- copyEvent(e):
+				case <-ctx.Done():
+					return
+				}
+			}
+			select {
+			case <-changed:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+	return ch
+}
+
+// WatchTree watch the directory like etcd.WatchTree, the events have all the keys in the directory
+func (s *Store) WatchTree(directory string, ctx context.Context, index uint64) (<-chan *store.Event, error) {
+	directory = normalize(directory)
+	events, err := s.Watch(directory, ctx, true, index)
+	if err != nil {
+		return nil, err
+	}
+	ch := make(chan *store.Event, 1)
+	go func() {
+		defer close(ch)
+		for e := range events {
+			if e.Action == store.ERROR {
+				ch <- e
+				return
+			}
+			if e.Action == store.DELETE && e.Key == directory {
+				ch <- deleteEvent(directory)
+				return
+			}
+			data, err := s.GetTree(directory)
+			if err != nil {
+				ch <- deleteEvent(directory)
+				continue
+			}
+			select {
+			case ch <- &store.Event{Action: e.Action, Key: e.Key, ModifiedIndex: e.ModifiedIndex, Data: data}:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+	return ch, nil
+}
+
+// Close does nothing
+func (s *Store) Close() {}
+
+// addEvent add the event into the history and wake up the watches, s.lock should be held
+func (s *Store) addEvent(e *store.Event) {
+	s.history = append(s.history, e)
+	if len(s.history) > maxHistory {
+		s.history = append([]*store.Event(nil), s.history[len(s.history)-maxHistory:]...)
+	}
+	close(s.changed)
+	s.changed = make(chan struct{})
+}
+
+// view is the store used by one component of the server, the watches from index 0 start from the index of its last GetTree
+// of the key instead of now, so the changes between reading the data and watching it are not lost
+type view struct {
+	*Store
+
+	lock  sync.Mutex
+	reads map[string]uint64
+}
+
+func (s *Store) view() *view {
+	return &view{
+		Store: s,
+		reads: make(map[string]uint64),
+	}
+}
+
+func (v *view) GetTree(key string) ([]*store.KVPair, error) {
+	pairs, index, err := v.Store.getTree(key)
+	v.lock.Lock()
+	v.reads[normalize(key)] = index
+	v.lock.Unlock()
+	return pairs, err
+}
+
+func (v *view) Watch(key string, ctx context.Context, recursive bool, index uint64) (<-chan *store.Event, error) {
+	if index == 0 {
+		v.lock.Lock()
+		read, ok := v.reads[normalize(key)]
+		v.lock.Unlock()
+		if ok {
+			return v.Store.watch(normalize(key), ctx, recursive, read), nil
+		}
+	}
+	return v.Store.Watch(key, ctx, recursive, index)
+}
+
+func copyPair(kv *store.KVPair) *store.KVPair {
+	return &store.KVPair{Key: kv.Key, Value: append([]byte(nil), kv.Value...), LastIndex: kv.LastIndex}
+}
+
+func copyEvent(e *store.Event) *store.Event {
+	ret := &store.Event{Action: e.Action, Key: e.Key, ModifiedIndex: e.ModifiedIndex, Data: make([]*store.KVPair, len(e.Data))}
+	for i, kv := range e.Data {
+		ret.Data[i] = copyPair(kv)
+	}
+	return ret
+}
+
+func deleteEvent(key string) *store.Event {
+	return &store.Event{
+		Action: store.DELETE,
+		Key:    key,
+		Data:   []*store.KVPair{},
+	}
+}
+
+func errorEvent(key string, err error) *store.Event {
+	return &store.Event{
+		Action: store.ERROR,
+		Key:    key,
+		Data:   []*store.KVPair{&store.KVPair{Key: "error", Value: []byte(err.Error())}},
+	}
+}
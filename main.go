@@ -2,41 +2,48 @@ package main
 
 import (
 	"flag"
+	"net"
 	"os"
 	"runtime"
 	"strings"
 
 	"os/signal"
+	"syscall"
 
 	"fmt"
 
 	"github.com/laincloud/lainlet/api"
 	"github.com/laincloud/lainlet/api/v2"
 	"github.com/laincloud/lainlet/auth"
+	"github.com/laincloud/lainlet/conf"
+	"github.com/laincloud/lainlet/proxyproto"
 	grpcserver "github.com/laincloud/lainlet/server"
 	"github.com/laincloud/lainlet/store"
 	_ "github.com/laincloud/lainlet/store/etcd"
+	"github.com/laincloud/lainlet/unixsock"
 	"github.com/laincloud/lainlet/version"
-	"github.com/laincloud/lainlet/watcher"
-	"github.com/laincloud/lainlet/watcher/config"
-	"github.com/laincloud/lainlet/watcher/container"
-	"github.com/laincloud/lainlet/watcher/depends"
-	"github.com/laincloud/lainlet/watcher/nodes"
-	"github.com/laincloud/lainlet/watcher/podgroup"
+	"github.com/laincloud/lainlet/watcher/watchers"
 	"github.com/mijia/sweb/log"
 	"golang.org/x/net/context"
 )
 
 var (
+	configFile            string
 	webAddr, etcdAddr, ip string
 	debug, v, noAuth      bool
 
 	grpcTls                   bool
 	grpcKeyFile, grpcCertFile string
 	grpcAddr                  string
+
+	webCertFile, webKeyFile, webClientCAFile string
+	webRequireClientCert                     bool
+
+	webSocket, grpcSocket string
 )
 
 func init() {
+	flag.StringVar(&configFile, "config", "", "The yaml configuration file, the flags set override it")
 	flag.StringVar(&webAddr, "web", "", "The address lainlet listen")
 	flag.StringVar(&etcdAddr, "etcd", "", "Etcd cluster entry point like http://127.0.0.1:4001")
 	flag.StringVar(&ip, "ip", "", "The ip of server lainlet running on")
@@ -44,43 +51,56 @@ func init() {
 	flag.BoolVar(&v, "v", false, "Print version")
 	flag.BoolVar(&noAuth, "noauth", false, "whether close auth")
 
+	flag.StringVar(&webCertFile, "web.cert", "", "TLS certification file of the web server, serve https if it is set")
+	flag.StringVar(&webKeyFile, "web.key", "", "TLS key file of the web server")
+	flag.StringVar(&webClientCAFile, "web.clientca", "", "CA file verifying the client certificates of the web server")
+	flag.BoolVar(&webRequireClientCert, "web.requireclientcert", false, "reject the web clients without a verified certificate")
+
+	flag.StringVar(&webSocket, "web.socket", "", "The unix socket lainlet listen")
 	flag.StringVar(&grpcAddr, "grpc.addr", "", "grpc address")
+	flag.StringVar(&grpcSocket, "grpc.socket", "", "grpc unix socket")
 	flag.BoolVar(&grpcTls, "grpc.TLS", false, "enable TLS")
 	flag.StringVar(&grpcKeyFile, "grpc.key", "", "TLS key file")
 	flag.StringVar(&grpcCertFile, "grpc.cert", "", "TLS certification file")
 	flag.Parse()
 }
 
-func initWatchers(st store.Store) (map[string]watcher.Watcher, error) {
-	background := context.Background()
-	configWatcher, err := config.New(st, background)
-	if err != nil {
-		return nil, err
-	}
-	containerWatcher, err := container.New(st, background)
-	if err != nil {
-		return nil, err
-	}
-	podgroupWatcher, err := podgroup.New(st, background)
-	if err != nil {
-		return nil, err
-	}
-	dependsWatcher, err := depends.New(st, background)
-	if err != nil {
-		return nil, err
-	}
-	nodesWatcher, err := nodes.New(st, background)
-	if err != nil {
-		return nil, err
-	}
-	ret := map[string]watcher.Watcher{
-		"configwatcher":    configWatcher,
-		"containerwatcher": containerWatcher,
-		"podgroupwatcher":  podgroupWatcher,
-		"dependswatcher":   dependsWatcher,
-		"nodeswatcher":     nodesWatcher,
-	}
-	return ret, nil
+// applyFlags override the configuration by the flags set in the command line
+func applyFlags(cfg *conf.Config) {
+	flag.Visit(func(f *flag.Flag) {
+		switch f.Name {
+		case "web":
+			cfg.Web = webAddr
+		case "web.cert":
+			cfg.WebTLS.Cert = webCertFile
+		case "web.key":
+			cfg.WebTLS.Key = webKeyFile
+		case "web.clientca":
+			cfg.WebTLS.ClientCA = webClientCAFile
+		case "web.requireclientcert":
+			cfg.WebTLS.RequireClientCert = webRequireClientCert
+		case "web.socket":
+			cfg.Unix.Web = webSocket
+		case "grpc.socket":
+			cfg.Unix.GRPC = grpcSocket
+		case "etcd":
+			cfg.Etcd = etcdAddr
+		case "ip":
+			cfg.IP = ip
+		case "debug":
+			cfg.Debug = debug
+		case "noauth":
+			cfg.NoAuth = noAuth
+		case "grpc.addr":
+			cfg.GRPC.Addr = grpcAddr
+		case "grpc.TLS":
+			cfg.GRPC.TLS = grpcTls
+		case "grpc.key":
+			cfg.GRPC.Key = grpcKeyFile
+		case "grpc.cert":
+			cfg.GRPC.Cert = grpcCertFile
+		}
+	})
 }
 
 func main() {
@@ -90,70 +110,127 @@ func main() {
 		fmt.Printf("Go Version: %s\n", runtime.Version())
 		return
 	}
-	if webAddr == "" && grpcAddr == "" {
-		fmt.Println("you should at least specify one of webAddr and grpcAddr.")
+	if err := conf.Load(configFile, applyFlags); err != nil {
+		fmt.Println(err)
+		os.Exit(1)
+	}
+	cfg := conf.Current()
+	if cfg.Web == "" && cfg.GRPC.Addr == "" && cfg.Unix.Web == "" && cfg.Unix.GRPC == "" {
+		fmt.Println("you should at least specify one of webAddr, grpcAddr and the unix sockets.")
 		os.Exit(-1)
 	}
-	if etcdAddr == "" {
+	if cfg.Etcd == "" {
 		flag.Usage()
 		os.Exit(1)
 	}
-	if debug {
+	if cfg.Debug {
 		log.EnableDebug()
 	}
 
-	st, err := store.New("etcd", strings.Split(etcdAddr, ","))
+	st, err := store.New("etcd", strings.Split(cfg.Etcd, ","))
 	if err != nil {
 		panic(err)
 	}
 
-	if err := auth.Init(st, context.Background(), ip, !noAuth); err != nil {
+	authorizer, err := auth.New(st, context.Background(), cfg)
+	if err != nil {
 		panic(err)
 	}
-	watchers, err := initWatchers(st)
+	watchers, err := watchers.New(func() store.Store { return st }, context.Background())
 	if err != nil {
 		panic(err)
 	}
 
-	if webAddr != "" {
-		httpSrv, err := api.New(ip, version.Version, watchers)
+	// the apis served by both the http server and the grpc server
+	apis := v2.APIs(cfg.IP)
+	env := api.NewEnv(cfg)
+
+	socketPolicy := unixsock.Policy{
+		UIDs: cfg.Unix.TrustedUIDs,
+		GIDs: cfg.Unix.TrustedGIDs,
+	}
+
+	if cfg.Web != "" || cfg.Unix.Web != "" {
+		httpSrv, err := api.New(cfg.IP, version.Version, watchers, authorizer, env)
 		if err != nil {
 			panic(err)
 		}
-		httpSrv.Register(new(v2.AppsData))
-		httpSrv.Register(new(v2.GeneralConfig))
-		httpSrv.Register(new(v2.GeneralPodGroup))
-		httpSrv.Register(new(v2.GeneralCoreInfo))
-		httpSrv.Register(new(v2.GeneralNodes))
-		httpSrv.Register(new(v2.GeneralContainers))
-		httpSrv.Register(new(v2.ProxyData))
-		httpSrv.Register(new(v2.Depends))
-		httpSrv.Register(new(v2.WebrouterInfo))
-		httpSrv.Register(new(v2.StreamRouterInfo))
-		httpSrv.Register(new(v2.Ports))
-		httpSrv.Register(new(v2.RebellionAPIProvider))
-		httpSrv.Register(new(v2.CoreInfoForBackupctl))
-		httpSrv.Register(&v2.LocalSpec{
-			LocalIP: ip,
-		})
-		httpSrv.Get("/appname", v2.GetAppNameAPI)
-
-		go httpSrv.RunOnAddr(webAddr)
+		v2.RegisterHTTP(httpSrv, apis)
+
+		if cfg.Unix.Web != "" {
+			lis, err := unixsock.Listen(cfg.Unix.Web, cfg.Unix.FileMode(), socketPolicy, env.Peers)
+			if err != nil {
+				panic(err)
+			}
+			go func() {
+				log.Fatalf("Fail to serve http on %s, %s", cfg.Unix.Web, httpSrv.Serve(lis))
+			}()
+		}
+		if cfg.Web != "" {
+			lis, err := net.Listen("tcp", cfg.Web)
+			if err != nil {
+				panic(err)
+			}
+			if cfg.WebProxy.ProxyProtocol {
+				lis = proxyproto.NewListener(lis, func(addr string) bool {
+					return env.Config().WebProxy.Trusts(addr)
+				})
+			}
+			if cfg.WebTLS.Cert != "" {
+				tlsCfg, err := api.NewTLSConfig(cfg.WebTLS.Cert, cfg.WebTLS.Key, cfg.WebTLS.ClientCA, cfg.WebTLS.RequireClientCert)
+				if err != nil {
+					panic(err)
+				}
+				go func() {
+					log.Fatalf("Fail to serve https, %s", httpSrv.ServeTLS(lis, tlsCfg))
+				}()
+			} else {
+				go func() {
+					log.Fatalf("Fail to serve http on %s, %s", cfg.Web, httpSrv.Serve(lis))
+				}()
+			}
+		}
 	}
 
-	if grpcAddr != "" {
-		var cfg *grpcserver.Config
-		if grpcTls {
-			cfg = grpcserver.NewConfig(grpcAddr, grpcKeyFile, grpcCertFile)
+	if cfg.GRPC.Addr != "" || cfg.Unix.GRPC != "" {
+		var grpcCfg *grpcserver.Config
+		if cfg.GRPC.TLS {
+			grpcCfg = grpcserver.NewConfig(cfg.GRPC.Addr, cfg.GRPC.Key, cfg.GRPC.Cert)
 		}
-		grpcSrv, err := grpcserver.New(grpcAddr, ip, watchers, cfg)
+		grpcSrv, err := grpcserver.New(cfg.GRPC.Addr, cfg.IP, watchers, grpcCfg, authorizer, env)
 		if err != nil {
 			panic(err)
 		}
-		go grpcSrv.Run()
+		for _, a := range apis {
+			grpcSrv.Register(a)
+		}
+		if cfg.Unix.GRPC != "" {
+			lis, err := unixsock.Listen(cfg.Unix.GRPC, cfg.Unix.FileMode(), socketPolicy, env.Peers)
+			if err != nil {
+				panic(err)
+			}
+			go func() {
+				log.Fatalf("Fail to serve grpc on %s, %s", cfg.Unix.GRPC, grpcSrv.Serve(lis))
+			}()
+		}
+		if cfg.GRPC.Addr != "" {
+			go grpcSrv.Run()
+		}
 	}
 
-	ch := make(chan os.Signal)
-	signal.Notify(ch, os.Interrupt, os.Kill)
-	<-ch
+	ch := make(chan os.Signal, 1)
+	signal.Notify(ch, os.Interrupt, syscall.SIGHUP)
+	for sig := range ch {
+		if sig != syscall.SIGHUP {
+			return
+		}
+		if err := conf.Reload(); err != nil {
+			log.Errorf("Fail to reload the configuration, %s", err.Error())
+			continue
+		}
+		env.Reload(conf.Current())
+		if err := authorizer.Reload(conf.Current()); err != nil {
+			log.Errorf("Fail to reload the authorizer, %s", err.Error())
+		}
+	}
 }
@@ -5,9 +5,11 @@
 Package message is a generated protocol buffer package.
 
 It is generated from these files:
+
 	message.proto
 
 It has these top-level messages:
+
 	AppnameRequest
 	AppnameReply
 	AppInfo
@@ -70,12 +72,15 @@ It has these top-level messages:
 	VersionReply
 	WatcherStatus
 	StatusReply
+	SubscribeRequest
+	SubscribeEvent
 */
 package message
 
 import proto "github.com/golang/protobuf/proto"
 import fmt "fmt"
 import math "math"
+import google_protobuf "github.com/golang/protobuf/ptypes/any"
 
 import (
 	context "golang.org/x/net/context"
@@ -114,6 +119,29 @@ func (x NodeInfo_Value_Type) String() string {
 }
 func (NodeInfo_Value_Type) EnumDescriptor() ([]byte, []int) { return fileDescriptor0, []int{28, 0, 0} }
 
+type SubscribeRequest_Operation int32
+
+const (
+	SubscribeRequest_ADD    SubscribeRequest_Operation = 0
+	SubscribeRequest_REMOVE SubscribeRequest_Operation = 1
+)
+
+var SubscribeRequest_Operation_name = map[int32]string{
+	0: "ADD",
+	1: "REMOVE",
+}
+var SubscribeRequest_Operation_value = map[string]int32{
+	"ADD":    0,
+	"REMOVE": 1,
+}
+
+func (x SubscribeRequest_Operation) String() string {
+	return proto.EnumName(SubscribeRequest_Operation_name, int32(x))
+}
+func (SubscribeRequest_Operation) EnumDescriptor() ([]byte, []int) {
+	return fileDescriptor0, []int{62, 0}
+}
+
 type AppnameRequest struct {
 	Ip string `protobuf:"bytes,1,opt,name=ip" json:"ip,omitempty"`
 }
@@ -291,7 +319,8 @@ func (m *BackupctlReply_PodInfoList) GetPods() []*PodInfoForBackupctl {
 }
 
 type BackupctlRequest struct {
-	Appname string `protobuf:"bytes,1,opt,name=Appname" json:"Appname,omitempty"`
+	Appname string   `protobuf:"bytes,1,opt,name=Appname" json:"Appname,omitempty"`
+	Fields  []string `protobuf:"bytes,2,rep,name=Fields" json:"Fields,omitempty"`
 }
 
 func (m *BackupctlRequest) Reset()                    { *m = BackupctlRequest{} }
@@ -306,6 +335,13 @@ func (m *BackupctlRequest) GetAppname() string {
 	return ""
 }
 
+func (m *BackupctlRequest) GetFields() []string {
+	if m != nil {
+		return m.Fields
+	}
+	return nil
+}
+
 type ConfigRequest struct {
 	Target string `protobuf:"bytes,1,opt,name=Target" json:"Target,omitempty"`
 }
@@ -567,6 +603,8 @@ type PodInfo struct {
 	Containers   []*Container  `protobuf:"bytes,2,rep,name=Containers" json:"Containers,omitempty"`
 	Dependencies []*Dependency `protobuf:"bytes,3,rep,name=Dependencies" json:"Dependencies,omitempty"`
 	InstanceNo   int32         `protobuf:"varint,4,opt,name=InstanceNo" json:"InstanceNo,omitempty"`
+	Healthst     int32         `protobuf:"varint,5,opt,name=Healthst" json:"Healthst,omitempty"`
+	State        int32         `protobuf:"varint,6,opt,name=State" json:"State,omitempty"`
 }
 
 func (m *PodInfo) Reset()                    { *m = PodInfo{} }
@@ -602,6 +640,20 @@ func (m *PodInfo) GetInstanceNo() int32 {
 	return 0
 }
 
+func (m *PodInfo) GetHealthst() int32 {
+	if m != nil {
+		return m.Healthst
+	}
+	return 0
+}
+
+func (m *PodInfo) GetState() int32 {
+	if m != nil {
+		return m.State
+	}
+	return 0
+}
+
 type CoreInfo struct {
 	PodInfos []*PodInfo `protobuf:"bytes,1,rep,name=PodInfos" json:"PodInfos,omitempty"`
 }
@@ -635,7 +687,8 @@ func (m *CoreinfoReply) GetData() map[string]*CoreInfo {
 }
 
 type CoreinfoRequest struct {
-	Appname string `protobuf:"bytes,1,opt,name=Appname" json:"Appname,omitempty"`
+	Appname string   `protobuf:"bytes,1,opt,name=Appname" json:"Appname,omitempty"`
+	Fields  []string `protobuf:"bytes,2,rep,name=Fields" json:"Fields,omitempty"`
 }
 
 func (m *CoreinfoRequest) Reset()                    { *m = CoreinfoRequest{} }
@@ -650,6 +703,13 @@ func (m *CoreinfoRequest) GetAppname() string {
 	return ""
 }
 
+func (m *CoreinfoRequest) GetFields() []string {
+	if m != nil {
+		return m.Fields
+	}
+	return nil
+}
+
 type ContainerInfo struct {
 	ContainerID string `protobuf:"bytes,1,opt,name=ContainerID" json:"ContainerID,omitempty"`
 	NodeIP      string `protobuf:"bytes,2,opt,name=NodeIP" json:"NodeIP,omitempty"`
@@ -763,7 +823,8 @@ func (m *DependsReply) GetData() map[string]*DependsNodeMap {
 }
 
 type DependsRequest struct {
-	Target string `protobuf:"bytes,1,opt,name=Target" json:"Target,omitempty"`
+	Target string   `protobuf:"bytes,1,opt,name=Target" json:"Target,omitempty"`
+	Fields []string `protobuf:"bytes,2,rep,name=Fields" json:"Fields,omitempty"`
 }
 
 func (m *DependsRequest) Reset()                    { *m = DependsRequest{} }
@@ -778,6 +839,13 @@ func (m *DependsRequest) GetTarget() string {
 	return ""
 }
 
+func (m *DependsRequest) GetFields() []string {
+	if m != nil {
+		return m.Fields
+	}
+	return nil
+}
+
 type LocalspecReply struct {
 	Data    []string `protobuf:"bytes,1,rep,name=Data" json:"Data,omitempty"`
 	LocalIP string   `protobuf:"bytes,2,opt,name=LocalIP" json:"LocalIP,omitempty"`
@@ -1052,7 +1120,8 @@ func (m *ProxyReply) GetData() map[string]*ProcInfo {
 }
 
 type ProxyRequest struct {
-	Appname string `protobuf:"bytes,1,opt,name=Appname" json:"Appname,omitempty"`
+	Appname string   `protobuf:"bytes,1,opt,name=Appname" json:"Appname,omitempty"`
+	Fields  []string `protobuf:"bytes,2,rep,name=Fields" json:"Fields,omitempty"`
 }
 
 func (m *ProxyRequest) Reset()                    { *m = ProxyRequest{} }
@@ -1067,6 +1136,13 @@ func (m *ProxyRequest) GetAppname() string {
 	return ""
 }
 
+func (m *ProxyRequest) GetFields() []string {
+	if m != nil {
+		return m.Fields
+	}
+	return nil
+}
+
 type PodInfoForRebellion struct {
 	Annotation string `protobuf:"bytes,1,opt,name=Annotation" json:"Annotation,omitempty"`
 	AppVersion string `protobuf:"bytes,2,opt,name=AppVersion" json:"AppVersion,omitempty"`
@@ -1452,7 +1528,8 @@ func (m *WebrouterWebprocsReply) GetData() map[string]*CoreInfoForWebrouter {
 }
 
 type WebrouterWebprocsRequest struct {
-	Appname string `protobuf:"bytes,1,opt,name=Appname" json:"Appname,omitempty"`
+	Appname string   `protobuf:"bytes,1,opt,name=Appname" json:"Appname,omitempty"`
+	Fields  []string `protobuf:"bytes,2,rep,name=Fields" json:"Fields,omitempty"`
 }
 
 func (m *WebrouterWebprocsRequest) Reset()                    { *m = WebrouterWebprocsRequest{} }
@@ -1467,6 +1544,13 @@ func (m *WebrouterWebprocsRequest) GetAppname() string {
 	return ""
 }
 
+func (m *WebrouterWebprocsRequest) GetFields() []string {
+	if m != nil {
+		return m.Fields
+	}
+	return nil
+}
+
 type EmptyRequest struct {
 }
 
@@ -1563,6 +1647,94 @@ func (m *StatusReply) GetStatus() map[string]*WatcherStatus {
 	return nil
 }
 
+type SubscribeRequest struct {
+	Op      SubscribeRequest_Operation `protobuf:"varint,1,opt,name=Op,enum=message.SubscribeRequest_Operation" json:"Op,omitempty"`
+	Tag     string                     `protobuf:"bytes,2,opt,name=Tag" json:"Tag,omitempty"`
+	Service string                     `protobuf:"bytes,3,opt,name=Service" json:"Service,omitempty"`
+	Request *google_protobuf.Any       `protobuf:"bytes,4,opt,name=Request" json:"Request,omitempty"`
+}
+
+func (m *SubscribeRequest) Reset()                    { *m = SubscribeRequest{} }
+func (m *SubscribeRequest) String() string            { return proto.CompactTextString(m) }
+func (*SubscribeRequest) ProtoMessage()               {}
+func (*SubscribeRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{62} }
+
+func (m *SubscribeRequest) GetOp() SubscribeRequest_Operation {
+	if m != nil {
+		return m.Op
+	}
+	return SubscribeRequest_ADD
+}
+
+func (m *SubscribeRequest) GetTag() string {
+	if m != nil {
+		return m.Tag
+	}
+	return ""
+}
+
+func (m *SubscribeRequest) GetService() string {
+	if m != nil {
+		return m.Service
+	}
+	return ""
+}
+
+func (m *SubscribeRequest) GetRequest() *google_protobuf.Any {
+	if m != nil {
+		return m.Request
+	}
+	return nil
+}
+
+type SubscribeEvent struct {
+	Tag    string               `protobuf:"bytes,1,opt,name=Tag" json:"Tag,omitempty"`
+	ID     uint64               `protobuf:"varint,2,opt,name=ID" json:"ID,omitempty"`
+	Action string               `protobuf:"bytes,3,opt,name=Action" json:"Action,omitempty"`
+	Reply  *google_protobuf.Any `protobuf:"bytes,4,opt,name=Reply" json:"Reply,omitempty"`
+	Error  string               `protobuf:"bytes,5,opt,name=Error" json:"Error,omitempty"`
+}
+
+func (m *SubscribeEvent) Reset()                    { *m = SubscribeEvent{} }
+func (m *SubscribeEvent) String() string            { return proto.CompactTextString(m) }
+func (*SubscribeEvent) ProtoMessage()               {}
+func (*SubscribeEvent) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{63} }
+
+func (m *SubscribeEvent) GetTag() string {
+	if m != nil {
+		return m.Tag
+	}
+	return ""
+}
+
+func (m *SubscribeEvent) GetID() uint64 {
+	if m != nil {
+		return m.ID
+	}
+	return 0
+}
+
+func (m *SubscribeEvent) GetAction() string {
+	if m != nil {
+		return m.Action
+	}
+	return ""
+}
+
+func (m *SubscribeEvent) GetReply() *google_protobuf.Any {
+	if m != nil {
+		return m.Reply
+	}
+	return nil
+}
+
+func (m *SubscribeEvent) GetError() string {
+	if m != nil {
+		return m.Error
+	}
+	return ""
+}
+
 func init() {
 	proto.RegisterType((*AppnameRequest)(nil), "message.AppnameRequest")
 	proto.RegisterType((*AppnameReply)(nil), "message.AppnameReply")
@@ -1628,7 +1800,10 @@ func init() {
 	proto.RegisterType((*VersionReply)(nil), "message.VersionReply")
 	proto.RegisterType((*WatcherStatus)(nil), "message.WatcherStatus")
 	proto.RegisterType((*StatusReply)(nil), "message.StatusReply")
+	proto.RegisterType((*SubscribeRequest)(nil), "message.SubscribeRequest")
+	proto.RegisterType((*SubscribeEvent)(nil), "message.SubscribeEvent")
 	proto.RegisterEnum("message.NodeInfo_Value_Type", NodeInfo_Value_Type_name, NodeInfo_Value_Type_value)
+	proto.RegisterEnum("message.SubscribeRequest_Operation", SubscribeRequest_Operation_name, SubscribeRequest_Operation_value)
 }
 
 // Reference imports to suppress errors if they are not otherwise used.
@@ -3489,156 +3664,266 @@ var _Lainlet_serviceDesc = grpc.ServiceDesc{
 	Metadata: "message.proto",
 }
 
+// Client API for Subscription service
+
+type SubscriptionClient interface {
+	Subscribe(ctx context.Context, opts ...grpc.CallOption) (Subscription_SubscribeClient, error)
+}
+
+type subscriptionClient struct {
+	cc *grpc.ClientConn
+}
+
+func NewSubscriptionClient(cc *grpc.ClientConn) SubscriptionClient {
+	return &subscriptionClient{cc}
+}
+
+func (c *subscriptionClient) Subscribe(ctx context.Context, opts ...grpc.CallOption) (Subscription_SubscribeClient, error) {
+	stream, err := grpc.NewClientStream(ctx, &_Subscription_serviceDesc.Streams[0], c.cc, "/message.Subscription/Subscribe", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &subscriptionSubscribeClient{stream}
+	return x, nil
+}
+
+type Subscription_SubscribeClient interface {
+	Send(*SubscribeRequest) error
+	Recv() (*SubscribeEvent, error)
+	grpc.ClientStream
+}
+
+type subscriptionSubscribeClient struct {
+	grpc.ClientStream
+}
+
+func (x *subscriptionSubscribeClient) Send(m *SubscribeRequest) error {
+	return x.ClientStream.SendMsg(m)
+}
+
+func (x *subscriptionSubscribeClient) Recv() (*SubscribeEvent, error) {
+	m := new(SubscribeEvent)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+// Server API for Subscription service
+
+type SubscriptionServer interface {
+	Subscribe(Subscription_SubscribeServer) error
+}
+
+func RegisterSubscriptionServer(s *grpc.Server, srv SubscriptionServer) {
+	s.RegisterService(&_Subscription_serviceDesc, srv)
+}
+
+func _Subscription_Subscribe_Handler(srv interface{}, stream grpc.ServerStream) error {
+	return srv.(SubscriptionServer).Subscribe(&subscriptionSubscribeServer{stream})
+}
+
+type Subscription_SubscribeServer interface {
+	Send(*SubscribeEvent) error
+	Recv() (*SubscribeRequest, error)
+	grpc.ServerStream
+}
+
+type subscriptionSubscribeServer struct {
+	grpc.ServerStream
+}
+
+func (x *subscriptionSubscribeServer) Send(m *SubscribeEvent) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func (x *subscriptionSubscribeServer) Recv() (*SubscribeRequest, error) {
+	m := new(SubscribeRequest)
+	if err := x.ServerStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+var _Subscription_serviceDesc = grpc.ServiceDesc{
+	ServiceName: "message.Subscription",
+	HandlerType: (*SubscriptionServer)(nil),
+	Methods:     []grpc.MethodDesc{},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "Subscribe",
+			Handler:       _Subscription_Subscribe_Handler,
+			ServerStreams: true,
+			ClientStreams: true,
+		},
+	},
+	Metadata: "message.proto",
+}
+
 func init() { proto.RegisterFile("message.proto", fileDescriptor0) }
 
 var fileDescriptor0 = []byte{
-	// 2355 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xb4, 0x5a, 0x4b, 0x6f, 0x1b, 0xc9,
-	0xf1, 0x67, 0xf3, 0x21, 0x99, 0x25, 0x52, 0x7f, 0xb9, 0x2d, 0xcb, 0xf3, 0x67, 0xbc, 0x5e, 0xb9,
-	0x9d, 0x5d, 0x7b, 0xbd, 0x8e, 0x96, 0xa6, 0xdf, 0x36, 0xbc, 0x86, 0x2c, 0xbf, 0x98, 0xb5, 0xb4,
-	0x93, 0x91, 0x56, 0x3e, 0x6c, 0x2e, 0x63, 0xb2, 0x57, 0x21, 0x4c, 0xce, 0x74, 0x66, 0x86, 0x82,
-	0xb9, 0x48, 0xb0, 0x97, 0x1c, 0x12, 0x04, 0x48, 0x10, 0x20, 0x7b, 0xcb, 0x77, 0x08, 0x82, 0x20,
-	0x39, 0x24, 0x1f, 0x22, 0x97, 0x1c, 0x73, 0xc8, 0x29, 0xdf, 0x22, 0x40, 0xd0, 0x8f, 0x99, 0xe9,
-	0x9e, 0x19, 0x92, 0x92, 0xa3, 0x9c, 0x3c, 0xdd, 0xac, 0xaa, 0xfe, 0xd5, 0xb3, 0xab, 0x5a, 0x86,
-	0xe6, 0x88, 0x86, 0xa1, 0x7b, 0x40, 0x37, 0x58, 0xe0, 0x47, 0x3e, 0x5e, 0x54, 0x4b, 0xb2, 0x0e,
-	0xcb, 0x9b, 0x8c, 0x79, 0xee, 0x88, 0x3a, 0xf4, 0xc7, 0x63, 0x1a, 0x46, 0x78, 0x19, 0xca, 0x03,
-	0x66, 0xa1, 0x75, 0x74, 0xa5, 0xee, 0x94, 0x07, 0x8c, 0xfc, 0x04, 0x1a, 0x09, 0x05, 0x1b, 0x4e,
-	0xf0, 0x0d, 0xa8, 0x3e, 0x71, 0x23, 0xd7, 0x42, 0xeb, 0x95, 0x2b, 0x4b, 0x9d, 0xf7, 0x37, 0x62,
-	0xc1, 0x3a, 0xd1, 0x06, 0xa7, 0x78, 0xea, 0x45, 0xc1, 0xc4, 0x11, 0xc4, 0xad, 0x3b, 0x50, 0x4f,
-	0xb6, 0xf0, 0x0a, 0x54, 0xde, 0xd0, 0x89, 0x3a, 0x82, 0x7f, 0xe2, 0x55, 0xa8, 0x1d, 0xba, 0xc3,
-	0x31, 0xb5, 0xca, 0x62, 0x4f, 0x2e, 0xee, 0x97, 0xef, 0x22, 0x72, 0x09, 0x16, 0x37, 0x19, 0xeb,
-	0x7a, 0x5f, 0xf9, 0xd8, 0x12, 0x9f, 0xfc, 0x0c, 0xc5, 0x1a, 0x2f, 0xc9, 0xcf, 0x11, 0xd4, 0x37,
-	0x19, 0x0b, 0x25, 0xc0, 0xb6, 0x01, 0xf0, 0xbc, 0x0e, 0x30, 0x2c, 0x46, 0xd7, 0x9d, 0x8d, 0xee,
-	0x43, 0x1d, 0xdd, 0x52, 0x67, 0x45, 0x97, 0xc8, 0x91, 0xe9, 0x78, 0x9b, 0xb0, 0x24, 0xcf, 0x11,
-	0xc6, 0x24, 0x6f, 0xe0, 0xec, 0x96, 0xef, 0x45, 0xee, 0xc0, 0xa3, 0xc1, 0x33, 0x3f, 0x78, 0xec,
-	0xf6, 0xde, 0x8c, 0x59, 0x2f, 0x1a, 0x72, 0x2b, 0x77, 0xfb, 0xb1, 0x95, 0xbb, 0x7d, 0xb1, 0x66,
-	0x4a, 0xfd, 0x72, 0x97, 0xe1, 0x35, 0x58, 0xd8, 0xf1, 0xfb, 0xb4, 0xcb, 0xac, 0x8a, 0xd8, 0x53,
-	0x2b, 0xdc, 0x82, 0x53, 0xfc, 0x6b, 0x87, 0x5b, 0xa1, 0x2a, 0x7e, 0x49, 0xd6, 0xe4, 0x5b, 0x04,
-	0x67, 0x6c, 0xbf, 0xcf, 0x21, 0x19, 0x67, 0x5d, 0x00, 0xd8, 0xf4, 0x3c, 0x3f, 0x72, 0xa3, 0x81,
-	0xef, 0xa9, 0x33, 0xb5, 0x1d, 0xfc, 0x29, 0x40, 0x02, 0x32, 0xb4, 0xca, 0xc2, 0x6c, 0x17, 0x12,
-	0x25, 0x0b, 0xf1, 0x3b, 0x1a, 0x07, 0x97, 0xdf, 0xf5, 0xc2, 0xc8, 0xf5, 0x7a, 0x74, 0xc7, 0x17,
-	0x78, 0x6b, 0x8e, 0xb6, 0x43, 0xfe, 0x85, 0x60, 0x39, 0xe5, 0x14, 0x3e, 0xba, 0x65, 0xf8, 0xe8,
-	0x62, 0x72, 0x98, 0x49, 0x96, 0x73, 0xd4, 0x23, 0x58, 0x52, 0x0a, 0xbe, 0x1c, 0x84, 0x11, 0xf7,
-	0x34, 0xf3, 0xfb, 0x61, 0xce, 0xd3, 0x05, 0x46, 0x70, 0x04, 0x65, 0xeb, 0x87, 0xb3, 0x3d, 0x7d,
-	0xcf, 0xf4, 0xf4, 0xa5, 0x69, 0xb8, 0x34, 0x10, 0xba, 0xf3, 0xaf, 0xc1, 0x8a, 0x46, 0x28, 0xd3,
-	0x69, 0x7a, 0xd4, 0x5e, 0x86, 0xe6, 0x96, 0xef, 0x7d, 0x35, 0x38, 0x88, 0x49, 0xd7, 0x60, 0x61,
-	0xcf, 0x0d, 0x0e, 0x68, 0xa4, 0x28, 0xd5, 0x8a, 0x7c, 0x0d, 0x4b, 0x31, 0x21, 0xb7, 0x5d, 0xc7,
-	0xb0, 0x9d, 0xe1, 0xa8, 0x98, 0xe6, 0xe4, 0xf2, 0xef, 0xef, 0x08, 0xaa, 0x5a, 0xf6, 0xed, 0x98,
-	0x7a, 0xf0, 0xa5, 0x08, 0x2f, 0xc6, 0xf6, 0x69, 0x10, 0xf2, 0xf0, 0x2a, 0xab, 0xf0, 0x4a, 0x76,
-	0x78, 0xc8, 0xda, 0x81, 0xdf, 0x13, 0xac, 0x32, 0x98, 0x93, 0xf5, 0xac, 0x70, 0x4e, 0x52, 0xc0,
-	0xb6, 0x6a, 0x5a, 0x0a, 0xd8, 0x22, 0x55, 0x6c, 0x6b, 0x41, 0xa5, 0x8a, 0x8d, 0x31, 0x54, 0x6d,
-	0x3f, 0x88, 0xac, 0x45, 0x11, 0x78, 0xe2, 0x3b, 0x13, 0x92, 0xa7, 0x72, 0x21, 0xf9, 0x1b, 0x04,
-	0xff, 0x97, 0x46, 0xb0, 0xb4, 0xeb, 0x6d, 0xc3, 0xae, 0x24, 0x9f, 0x00, 0x53, 0xaa, 0xc7, 0xb3,
-	0xd9, 0xb6, 0xbd, 0x64, 0xc6, 0x54, 0x33, 0x91, 0x9b, 0x2d, 0x1d, 0x9f, 0xc0, 0x69, 0xfd, 0x28,
-	0x19, 0x13, 0xca, 0x40, 0x5a, 0xfc, 0x24, 0x6b, 0xf2, 0x6f, 0x04, 0xf5, 0x84, 0x83, 0x3b, 0x68,
-	0xcb, 0x1f, 0x8d, 0x5c, 0xaf, 0x2f, 0x34, 0xa8, 0x3b, 0xf1, 0x52, 0xd5, 0x9a, 0x72, 0xa6, 0xd6,
-	0x54, 0x92, 0x5a, 0xb3, 0x02, 0x95, 0x2d, 0x36, 0x16, 0xf6, 0xaf, 0x39, 0xfc, 0x93, 0xef, 0x3c,
-	0xf5, 0x0e, 0xad, 0x9a, 0x90, 0xc3, 0x3f, 0xb9, 0x33, 0x9e, 0xbe, 0x65, 0x7e, 0x48, 0x85, 0xe1,
-	0x6b, 0x8e, 0x5a, 0xf1, 0xc8, 0xe9, 0x8e, 0xdc, 0x03, 0x2a, 0xac, 0x5f, 0x77, 0xe4, 0x82, 0x53,
-	0x6f, 0xd3, 0x91, 0x1f, 0x4c, 0x84, 0xe9, 0x2b, 0x8e, 0x5a, 0x69, 0x55, 0xad, 0x3e, 0xb5, 0xaa,
-	0x41, 0x26, 0x0c, 0x2c, 0x58, 0xdc, 0xf7, 0x87, 0xe3, 0x11, 0x0d, 0xad, 0x25, 0xa9, 0x97, 0x5a,
-	0x92, 0x4f, 0x01, 0x9e, 0x50, 0x46, 0xbd, 0x3e, 0xf5, 0x7a, 0x13, 0x4e, 0x67, 0xfb, 0x7d, 0x3d,
-	0x40, 0xd5, 0x92, 0x9f, 0x6a, 0xfb, 0xc3, 0x41, 0x6f, 0x22, 0x6c, 0x50, 0x73, 0xd4, 0x8a, 0xfc,
-	0x19, 0x09, 0x16, 0x11, 0xde, 0xf3, 0x6a, 0x64, 0xa7, 0xa0, 0x46, 0xe2, 0x7c, 0x88, 0x18, 0x75,
-	0xf1, 0x0e, 0x34, 0x12, 0x7c, 0x03, 0x1a, 0x5a, 0x15, 0xc1, 0x75, 0x26, 0xe1, 0x4a, 0xc1, 0x3b,
-	0x06, 0x61, 0x26, 0x7a, 0xab, 0xb9, 0xe8, 0xbd, 0x0b, 0xa7, 0xb6, 0xfc, 0x80, 0x0a, 0xe0, 0xd7,
-	0xe0, 0x94, 0xd2, 0x21, 0xae, 0x83, 0x2b, 0xd9, 0x3a, 0xe8, 0x24, 0x14, 0x3c, 0xee, 0x9b, 0x9c,
-	0x75, 0xc0, 0xb7, 0x45, 0xd4, 0xdf, 0x34, 0xa2, 0x7e, 0x5d, 0x53, 0x49, 0xa3, 0xca, 0xc5, 0xfc,
-	0xf7, 0x67, 0xc7, 0xfc, 0x65, 0x33, 0xe6, 0x4f, 0x1b, 0x52, 0xb3, 0x71, 0xff, 0x31, 0x4f, 0xc5,
-	0xf8, 0xb0, 0x79, 0x45, 0x73, 0x24, 0x8a, 0xa6, 0xb4, 0xb0, 0xd0, 0x7f, 0x5d, 0x14, 0x47, 0xb5,
-	0xf1, 0x44, 0x91, 0xeb, 0x5b, 0x5a, 0x1d, 0x29, 0x17, 0xd4, 0x91, 0x4a, 0xae, 0x8e, 0x54, 0xd3,
-	0x3a, 0x42, 0x28, 0x2c, 0x49, 0xcf, 0x84, 0xdd, 0x88, 0x8e, 0xe6, 0x46, 0xc9, 0xed, 0x82, 0x28,
-	0x59, 0xcb, 0x47, 0x89, 0xb0, 0x80, 0x46, 0x49, 0x7e, 0x8b, 0xa0, 0xa9, 0xce, 0xd9, 0x64, 0x6c,
-	0xdb, 0x65, 0xdc, 0x2d, 0x2e, 0x63, 0x61, 0xce, 0x2d, 0x06, 0x95, 0x68, 0x69, 0x94, 0x5b, 0x38,
-	0x75, 0x6b, 0x5b, 0xf6, 0x41, 0xd3, 0xdc, 0x72, 0xd5, 0x74, 0xcb, 0x6a, 0x56, 0x2a, 0xd7, 0x51,
-	0xf7, 0xcc, 0xef, 0x10, 0x2c, 0xab, 0x9f, 0xb8, 0xcd, 0x38, 0xae, 0xbb, 0x50, 0xf3, 0xfc, 0x3e,
-	0x0d, 0x73, 0x55, 0xd2, 0xa4, 0xdb, 0xe0, 0xff, 0x2a, 0x68, 0x92, 0xa1, 0x65, 0x03, 0xa4, 0x9b,
-	0x05, 0xe0, 0xae, 0x99, 0xe0, 0xd6, 0x8a, 0x55, 0xd6, 0xe1, 0x7d, 0x8b, 0xe2, 0x04, 0x0b, 0x67,
-	0xb7, 0xa6, 0x3a, 0x51, 0x2e, 0x94, 0xed, 0xd9, 0xa1, 0xfc, 0x3d, 0x13, 0xd6, 0xb9, 0x29, 0x0a,
-	0xeb, 0xb8, 0xae, 0x24, 0x56, 0x9b, 0x77, 0xb3, 0xef, 0xc0, 0xf2, 0x4b, 0xbf, 0xe7, 0x0e, 0x43,
-	0x46, 0x7b, 0x52, 0x05, 0xac, 0xa9, 0x50, 0x97, 0x08, 0x79, 0x36, 0x08, 0xaa, 0x24, 0x82, 0xe3,
-	0xa5, 0xea, 0xd5, 0x2b, 0x49, 0xaf, 0x7e, 0x15, 0x56, 0x34, 0x79, 0xc9, 0xd9, 0x1c, 0x63, 0xd2,
-	0xd3, 0xab, 0x15, 0xf9, 0x67, 0x59, 0x16, 0x5d, 0x91, 0x45, 0x1f, 0x02, 0x3a, 0x54, 0x66, 0xb3,
-	0x12, 0x0d, 0xe3, 0x5f, 0x37, 0xf6, 0xa5, 0xbd, 0xd0, 0x61, 0xeb, 0x1f, 0x08, 0x6a, 0xfb, 0x5c,
-	0x51, 0xdc, 0x81, 0xda, 0x7e, 0x34, 0x61, 0x32, 0x41, 0x97, 0xb5, 0xe6, 0x2b, 0xe5, 0xe2, 0x74,
-	0x1b, 0x7b, 0x13, 0x46, 0x1d, 0x49, 0xca, 0x95, 0x0b, 0x0f, 0xdd, 0xa1, 0xd2, 0x42, 0x7c, 0xf3,
-	0x4e, 0x70, 0xc4, 0xf7, 0x2a, 0x99, 0x4e, 0x30, 0x23, 0x66, 0xfb, 0xd0, 0x1d, 0x2a, 0xaf, 0x71,
-	0x72, 0xde, 0xd0, 0x24, 0x5b, 0xc7, 0x6a, 0x68, 0xbe, 0x03, 0x55, 0x0e, 0x09, 0x03, 0x2c, 0xec,
-	0xee, 0x39, 0xdd, 0x9d, 0xe7, 0x2b, 0x25, 0xbc, 0x08, 0x95, 0xed, 0x4d, 0x7b, 0x05, 0xb5, 0xb6,
-	0x61, 0x61, 0xff, 0xd8, 0x81, 0x60, 0x22, 0xd5, 0xcf, 0xfa, 0x25, 0x52, 0x31, 0x2f, 0x7d, 0x7b,
-	0xdd, 0x08, 0xcf, 0xf7, 0x0c, 0x01, 0xe1, 0xc9, 0xd6, 0xd9, 0x18, 0x93, 0x8e, 0x86, 0x40, 0x43,
-	0x9d, 0x24, 0x03, 0x03, 0x43, 0x55, 0xbb, 0x2d, 0xc5, 0x37, 0xa1, 0x50, 0xb1, 0xfd, 0x7e, 0xe6,
-	0x02, 0x42, 0xd9, 0x0b, 0x48, 0x95, 0xce, 0x72, 0xae, 0x74, 0x56, 0xb4, 0x16, 0x4c, 0x6f, 0xfb,
-	0xaa, 0x66, 0xdb, 0x47, 0xe4, 0xa5, 0xf5, 0x3c, 0xf0, 0xc7, 0x0c, 0xaf, 0x73, 0xde, 0xa4, 0x89,
-	0x6f, 0xe8, 0x97, 0x97, 0x23, 0x7e, 0x21, 0xb7, 0xa1, 0x69, 0xfb, 0xfd, 0x03, 0x4e, 0x2d, 0x0d,
-	0xf9, 0x81, 0x61, 0xc8, 0xd3, 0x3a, 0x8b, 0x90, 0x29, 0x8d, 0xc7, 0x2f, 0x96, 0x94, 0x6f, 0xde,
-	0xc5, 0xf2, 0xa5, 0xd6, 0x7d, 0x3d, 0xf3, 0x03, 0x3b, 0xf0, 0xdf, 0x4e, 0xcc, 0xcb, 0x85, 0xe5,
-	0x2f, 0x17, 0x86, 0xbf, 0xab, 0xdd, 0x47, 0xc2, 0x04, 0xb2, 0xc5, 0x30, 0x37, 0xc9, 0x33, 0x69,
-	0x0b, 0x91, 0x6a, 0xf7, 0x8d, 0x3b, 0x42, 0xaa, 0xd0, 0x2a, 0x9c, 0xb6, 0x04, 0x06, 0xe3, 0x9e,
-	0xe0, 0x01, 0x25, 0x77, 0x67, 0x06, 0x54, 0x4a, 0x72, 0x62, 0x01, 0x15, 0xc3, 0x37, 0xeb, 0x5c,
-	0x43, 0x9d, 0x34, 0xcf, 0xb8, 0x63, 0x7d, 0x30, 0x75, 0xe8, 0x6b, 0x3a, 0x1c, 0xf2, 0xeb, 0x72,
-	0xde, 0x75, 0x3a, 0x6f, 0xb2, 0x98, 0x37, 0x78, 0xda, 0xb0, 0x1a, 0x37, 0x1c, 0xc6, 0xb9, 0x77,
-	0x73, 0x3d, 0x53, 0xd1, 0xec, 0x98, 0xd0, 0x6b, 0xfd, 0xd3, 0x1f, 0x11, 0x58, 0xc9, 0xbe, 0x28,
-	0xb5, 0x2c, 0xf0, 0x7b, 0x2a, 0xbf, 0x1f, 0x19, 0xee, 0xf8, 0x38, 0x11, 0x39, 0x8d, 0x21, 0xe7,
-	0x9c, 0xfd, 0xd9, 0xce, 0xb9, 0x61, 0x3a, 0xe7, 0xbd, 0x5c, 0x57, 0x65, 0x80, 0xd6, 0x1c, 0x75,
-	0x1b, 0x5a, 0x85, 0x18, 0xe6, 0xb9, 0xed, 0x26, 0x58, 0xbb, 0x51, 0x40, 0xdd, 0x51, 0xe0, 0x8f,
-	0x23, 0x19, 0xca, 0x47, 0xe0, 0xba, 0x06, 0x6b, 0x05, 0x5c, 0xd9, 0xcb, 0xad, 0xa6, 0x92, 0xf4,
-	0x09, 0x2c, 0x4b, 0xea, 0x2f, 0x58, 0x28, 0xfe, 0xe5, 0x54, 0x2f, 0xfc, 0x30, 0xbe, 0x2a, 0xc5,
-	0x77, 0xc6, 0xd3, 0xe5, 0x9c, 0xa7, 0xbf, 0x81, 0xa6, 0x94, 0xb2, 0x4b, 0x83, 0xc3, 0x41, 0x8f,
-	0x62, 0x02, 0x8d, 0x58, 0xa0, 0x48, 0x4b, 0x59, 0xc3, 0x8c, 0x3d, 0x2e, 0x94, 0x4f, 0xf0, 0xd4,
-	0xd3, 0x12, 0x57, 0xdb, 0xe1, 0x40, 0x76, 0xa9, 0xd7, 0x57, 0xf7, 0xab, 0xf8, 0x56, 0x73, 0x10,
-	0xed, 0x45, 0xaa, 0xa6, 0xa9, 0x95, 0xc8, 0x4c, 0x89, 0x80, 0x67, 0x4a, 0x51, 0x6d, 0xc5, 0xb7,
-	0xa0, 0x1e, 0x1f, 0x1f, 0xf7, 0x86, 0xe9, 0x25, 0x62, 0xda, 0xc0, 0x49, 0x29, 0x71, 0x07, 0x4e,
-	0x29, 0xa5, 0xe2, 0x09, 0x62, 0x2d, 0xc3, 0xa5, 0x7e, 0x76, 0x12, 0x3a, 0xf2, 0x20, 0x36, 0x2a,
-	0x07, 0x23, 0x9e, 0x4a, 0x3e, 0x82, 0x9a, 0x70, 0xba, 0x0a, 0xce, 0x33, 0x19, 0x11, 0x9c, 0xce,
-	0x91, 0x14, 0xc4, 0x96, 0xc5, 0x9c, 0x7b, 0x78, 0x37, 0xe8, 0xb1, 0xd4, 0x7a, 0xf1, 0x92, 0xff,
-	0xf2, 0x24, 0x8c, 0x58, 0x6a, 0xb5, 0x78, 0xc9, 0xef, 0x5d, 0x3b, 0xf0, 0x23, 0x5f, 0xd9, 0x4c,
-	0x2e, 0xc8, 0x75, 0x3d, 0xcf, 0xf9, 0x40, 0x2c, 0x62, 0x42, 0x41, 0x69, 0x6a, 0xa9, 0x17, 0x44,
-	0x8e, 0xfc, 0x8d, 0xfc, 0x09, 0xc1, 0x79, 0x3d, 0x8a, 0xe4, 0xb7, 0x96, 0x6c, 0x5b, 0x46, 0xb2,
-	0x7d, 0x92, 0xd1, 0xa7, 0x98, 0xe9, 0xc4, 0x7a, 0x3f, 0xd3, 0xb8, 0x7a, 0xaa, 0xdd, 0x87, 0x0b,
-	0x53, 0x11, 0xcc, 0x4b, 0x9c, 0x47, 0xe6, 0x63, 0xe1, 0x2b, 0xfa, 0x5a, 0x0a, 0x51, 0xd7, 0x2d,
-	0x4a, 0xae, 0xdb, 0x74, 0x18, 0x2f, 0xeb, 0xc3, 0xb8, 0x59, 0x66, 0x53, 0xf6, 0x93, 0x7b, 0xff,
-	0x4b, 0x64, 0x1a, 0xb7, 0x92, 0x59, 0x66, 0xd3, 0x73, 0x8f, 0x56, 0x66, 0x53, 0x99, 0x69, 0x99,
-	0xfd, 0x3d, 0x82, 0xb5, 0x64, 0xff, 0x15, 0x7d, 0xad, 0xf9, 0xfd, 0xa1, 0xe1, 0xf7, 0x8f, 0x12,
-	0x81, 0xc5, 0xe4, 0xff, 0x8b, 0x12, 0x9b, 0x02, 0xd6, 0xfc, 0x7e, 0x13, 0xac, 0x02, 0x04, 0xf3,
-	0x3c, 0xbe, 0x0c, 0x8d, 0xa7, 0x23, 0x16, 0xc5, 0x37, 0x28, 0x79, 0x01, 0x0d, 0x75, 0xb7, 0x49,
-	0x65, 0x2d, 0x58, 0x8c, 0x6f, 0x3f, 0xc5, 0xa9, 0x5d, 0x7d, 0x9b, 0x76, 0x37, 0x7b, 0x35, 0x26,
-	0x3b, 0xe4, 0xd7, 0x08, 0x9a, 0xaf, 0xdc, 0xa8, 0xf7, 0x23, 0x1e, 0x83, 0x6e, 0x34, 0x0e, 0x79,
-	0x45, 0xdc, 0x19, 0x8f, 0x1c, 0xda, 0xa3, 0x83, 0x43, 0xd9, 0x79, 0x88, 0x8a, 0xa8, 0xef, 0x71,
-	0xa9, 0x5f, 0xb0, 0xbe, 0x1b, 0xd1, 0xbd, 0xc1, 0x48, 0xda, 0xa0, 0xe2, 0x68, 0x3b, 0xf8, 0x3c,
-	0xd4, 0x5f, 0xba, 0x61, 0xf4, 0xf4, 0x90, 0x7a, 0xb2, 0xd9, 0x6b, 0x38, 0xe9, 0x06, 0xff, 0x75,
-	0xcf, 0x8f, 0xdc, 0xe1, 0x67, 0x74, 0x12, 0xaa, 0x29, 0x3a, 0xdd, 0x20, 0x7f, 0x41, 0xb0, 0x24,
-	0xa1, 0x48, 0xdd, 0x2e, 0x00, 0x3c, 0xf7, 0xb9, 0xc1, 0x06, 0x1e, 0x8d, 0xd1, 0x68, 0x3b, 0xf8,
-	0x2e, 0x2c, 0x48, 0x72, 0x15, 0x91, 0xeb, 0x5a, 0xf6, 0x25, 0x52, 0xd4, 0xb7, 0xf4, 0xb0, 0xa2,
-	0x6f, 0xfd, 0x20, 0x3e, 0xe8, 0xd8, 0xa3, 0xa6, 0x61, 0x31, 0xcd, 0xbd, 0x9d, 0xc7, 0x89, 0x0b,
-	0xf1, 0x1d, 0xa8, 0x3c, 0xa7, 0x11, 0x3e, 0x97, 0xff, 0xc3, 0x87, 0xf0, 0x61, 0xeb, 0x6c, 0xe1,
-	0x5f, 0x44, 0x48, 0xa9, 0xc3, 0xa0, 0xca, 0x87, 0x73, 0x7c, 0x5d, 0x0a, 0x58, 0xcd, 0xfc, 0x61,
-	0x42, 0x72, 0xe3, 0xfc, 0x9f, 0x2b, 0x48, 0x09, 0xdf, 0x82, 0x9a, 0x80, 0x76, 0x1c, 0xa6, 0x36,
-	0xea, 0xfc, 0x02, 0x41, 0x3d, 0xfd, 0x33, 0xc0, 0x03, 0x79, 0xee, 0xff, 0x17, 0x3d, 0x6a, 0x4b,
-	0x39, 0xe7, 0xa6, 0xbc, 0x77, 0x93, 0x12, 0x7e, 0x14, 0x23, 0x78, 0x27, 0xf6, 0x36, 0xea, 0x7c,
-	0x0d, 0x0b, 0xf2, 0x81, 0x1a, 0xdf, 0x92, 0x38, 0xd6, 0x72, 0x0f, 0xd7, 0x52, 0xca, 0x6a, 0xd1,
-	0x83, 0x36, 0x29, 0xe1, 0x7b, 0x31, 0x82, 0x63, 0x32, 0xb6, 0x51, 0xe7, 0x57, 0x48, 0xaf, 0x70,
-	0xf8, 0xa1, 0x04, 0xd0, 0x2a, 0x7c, 0xe1, 0x95, 0xb2, 0xac, 0x69, 0xaf, 0xbf, 0xa4, 0x84, 0x37,
-	0x63, 0x20, 0xef, 0x28, 0xa0, 0x8d, 0x3a, 0x3f, 0x43, 0xf2, 0x05, 0x6f, 0xc0, 0x07, 0x82, 0x7b,
-	0x12, 0x8e, 0x55, 0xf0, 0xf4, 0x26, 0x65, 0xad, 0x15, 0x3f, 0xca, 0x91, 0x12, 0x7e, 0x18, 0x43,
-	0x79, 0x07, 0xe6, 0x36, 0xea, 0x7c, 0x03, 0x8b, 0xea, 0xa1, 0x22, 0x1f, 0xd5, 0xe6, 0x0b, 0x86,
-	0x16, 0xd5, 0xfa, 0x63, 0x0a, 0x29, 0xe1, 0x07, 0x31, 0x84, 0x63, 0xb3, 0xb6, 0x51, 0xe7, 0x05,
-	0xd4, 0x93, 0xf7, 0x8a, 0x7c, 0x7c, 0x66, 0x9f, 0x32, 0xb4, 0x00, 0x33, 0x5f, 0x4d, 0x48, 0xa9,
-	0x33, 0x86, 0x9a, 0x18, 0x6e, 0xf1, 0x0d, 0x29, 0xe5, 0x6c, 0x76, 0xba, 0x96, 0x12, 0xce, 0x14,
-	0x0c, 0xdd, 0xa4, 0x84, 0xef, 0xc4, 0x4a, 0x1c, 0x8b, 0x4d, 0x39, 0x32, 0x9e, 0x31, 0xf3, 0x8e,
-	0xcc, 0x4c, 0x9f, 0x9a, 0x2f, 0x8c, 0x79, 0xb6, 0xd8, 0x91, 0x47, 0x66, 0x6e, 0x23, 0xae, 0xbd,
-	0x1c, 0x58, 0x73, 0xda, 0xeb, 0x03, 0x9a, 0xa6, 0x46, 0x3a, 0x21, 0x16, 0x6b, 0x7f, 0x04, 0xb6,
-	0x36, 0xea, 0xfc, 0x15, 0xc1, 0x99, 0x82, 0xc1, 0x02, 0x7f, 0x2e, 0x51, 0x5c, 0x9a, 0x3d, 0x01,
-	0x49, 0xe1, 0x17, 0xe7, 0x8e, 0x49, 0xa4, 0x84, 0x77, 0x63, 0x84, 0x27, 0x26, 0xb2, 0x8d, 0x3a,
-	0x7f, 0x40, 0x70, 0x3a, 0x37, 0xa8, 0xe0, 0xcf, 0x24, 0xf6, 0x8b, 0x85, 0x0d, 0xa5, 0x3e, 0x01,
-	0xb5, 0xde, 0x9f, 0x45, 0x22, 0x71, 0x7f, 0x1e, 0xe3, 0x3e, 0x11, 0x71, 0x6d, 0xd4, 0xf9, 0x1b,
-	0x82, 0x73, 0x53, 0xfa, 0x4b, 0xfc, 0x4a, 0x22, 0xbf, 0x3c, 0xbf, 0x15, 0x96, 0x07, 0x7e, 0x70,
-	0xa4, 0x9e, 0x99, 0x94, 0xf0, 0x97, 0xb1, 0x16, 0x27, 0x2e, 0x5a, 0x79, 0x21, 0xd7, 0x39, 0xe5,
-	0xbd, 0x30, 0xad, 0xb9, 0xd2, 0xcc, 0x56, 0xdc, 0x01, 0x16, 0x7b, 0xe1, 0xbf, 0x10, 0xd7, 0x46,
-	0x9d, 0x9f, 0xc2, 0xe2, 0x4b, 0x77, 0xe0, 0x0d, 0x69, 0x84, 0xef, 0x25, 0x1d, 0x9a, 0x96, 0x3d,
-	0x7a, 0x4f, 0xa7, 0x95, 0x3f, 0xbd, 0xb5, 0x13, 0x69, 0xa7, 0x1a, 0x96, 0x69, 0x9c, 0xab, 0x45,
-	0x1d, 0x0f, 0x29, 0x3d, 0xbe, 0x0a, 0xd6, 0xc0, 0xdf, 0x38, 0x08, 0x58, 0x6f, 0x83, 0xbe, 0x75,
-	0x47, 0x6c, 0x48, 0xc3, 0x98, 0xf2, 0x71, 0x63, 0x5b, 0x7e, 0x88, 0xc1, 0xcb, 0x46, 0xaf, 0x17,
-	0xc4, 0xff, 0xf7, 0xb8, 0xf1, 0x9f, 0x00, 0x00, 0x00, 0xff, 0xff, 0x63, 0x4c, 0x25, 0x0b, 0x00,
-	0x22, 0x00, 0x00,
+	// 2583 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xb4, 0x59, 0x4b, 0x6f, 0x1b, 0xc9,
+	0xf1, 0x67, 0xf3, 0x21, 0x89, 0xa5, 0x87, 0xe9, 0xb6, 0x2c, 0xcd, 0xf2, 0xef, 0xb5, 0xe5, 0xf6,
+	0x7f, 0xd7, 0x5e, 0xaf, 0x43, 0xd3, 0xf4, 0xfa, 0x0d, 0xaf, 0x43, 0x4b, 0xb2, 0xcd, 0xac, 0x25,
+	0x31, 0x23, 0x59, 0x3e, 0x6c, 0x2e, 0x23, 0xb2, 0xad, 0x25, 0x4c, 0xce, 0x4c, 0x66, 0x86, 0x82,
+	0xb9, 0x48, 0xb0, 0x97, 0x1c, 0x12, 0x24, 0x48, 0x10, 0x20, 0x7b, 0xcb, 0x77, 0x08, 0x82, 0x20,
+	0x97, 0xe4, 0x9c, 0x73, 0x2e, 0x39, 0xe6, 0x90, 0x53, 0x90, 0x2f, 0x11, 0x20, 0xe8, 0xc7, 0xcc,
+	0x74, 0xcf, 0x0c, 0x49, 0x4b, 0x51, 0x4e, 0x9c, 0x6e, 0x56, 0x55, 0xff, 0xba, 0x5e, 0x5d, 0xd5,
+	0x0d, 0x8b, 0x03, 0xea, 0xfb, 0xd6, 0x21, 0xad, 0xb9, 0x9e, 0x13, 0x38, 0x78, 0x56, 0x0e, 0xab,
+	0x1f, 0x1c, 0x3a, 0xce, 0x61, 0x9f, 0xde, 0xe4, 0xd3, 0x07, 0xc3, 0x37, 0x37, 0x2d, 0x7b, 0x24,
+	0x68, 0xc8, 0x1a, 0x2c, 0x35, 0x5d, 0xd7, 0xb6, 0x06, 0xd4, 0xa4, 0x3f, 0x1c, 0x52, 0x3f, 0xc0,
+	0x4b, 0x90, 0xef, 0xb9, 0x06, 0x5a, 0x43, 0xd7, 0xca, 0x66, 0xbe, 0xe7, 0x92, 0x1f, 0xc1, 0x42,
+	0x44, 0xe1, 0xf6, 0x47, 0xf8, 0x36, 0x14, 0x37, 0xac, 0xc0, 0x32, 0xd0, 0x5a, 0xe1, 0xda, 0x7c,
+	0xe3, 0x52, 0x2d, 0x5c, 0x53, 0x25, 0xaa, 0x31, 0x8a, 0x4d, 0x3b, 0xf0, 0x46, 0x26, 0x27, 0xae,
+	0xde, 0x83, 0x72, 0x34, 0x85, 0x2b, 0x50, 0x78, 0x4b, 0x47, 0x72, 0x09, 0xf6, 0x89, 0x97, 0xa1,
+	0x74, 0x64, 0xf5, 0x87, 0xd4, 0xc8, 0xf3, 0x39, 0x31, 0x78, 0x98, 0xbf, 0x8f, 0xc8, 0x15, 0x98,
+	0x6d, 0xba, 0x6e, 0xcb, 0x7e, 0xe3, 0x60, 0x03, 0x66, 0xe5, 0x1a, 0x92, 0x35, 0x1c, 0x92, 0x9f,
+	0x22, 0x28, 0x37, 0x5d, 0xd7, 0x17, 0x00, 0xeb, 0x1a, 0xc0, 0x0b, 0x2a, 0x40, 0x3f, 0x1b, 0x5d,
+	0x6b, 0x32, 0xba, 0x8f, 0x55, 0x74, 0xf3, 0x8d, 0x8a, 0x2a, 0x91, 0x21, 0x53, 0xf1, 0x2e, 0xc2,
+	0xbc, 0x58, 0x87, 0x2b, 0x93, 0xbc, 0x85, 0xf3, 0xeb, 0x8e, 0x1d, 0x58, 0x3d, 0x9b, 0x7a, 0xcf,
+	0x1c, 0xef, 0xa9, 0xd5, 0x79, 0x3b, 0x74, 0x3b, 0x41, 0x9f, 0x69, 0xb9, 0xd5, 0x0d, 0xb5, 0xdc,
+	0xea, 0xf2, 0xb1, 0x2b, 0xb7, 0x9f, 0x6f, 0xb9, 0x78, 0x05, 0x66, 0xb6, 0x9d, 0x2e, 0x6d, 0xb9,
+	0x46, 0x81, 0xcf, 0xc9, 0x11, 0xae, 0xc2, 0x1c, 0xfb, 0xda, 0x66, 0x5a, 0x28, 0xf2, 0x7f, 0xa2,
+	0x31, 0xf9, 0x16, 0xc1, 0xb9, 0xb6, 0xd3, 0x65, 0x90, 0xb4, 0xb5, 0x2e, 0x02, 0x34, 0x6d, 0xdb,
+	0x09, 0xac, 0xa0, 0xe7, 0xd8, 0x72, 0x4d, 0x65, 0x06, 0x7f, 0x0e, 0x10, 0x81, 0xf4, 0x8d, 0x3c,
+	0x57, 0xdb, 0xc5, 0x68, 0x93, 0x99, 0xf8, 0x4d, 0x85, 0x83, 0xc9, 0x6f, 0xd9, 0x7e, 0x60, 0xd9,
+	0x1d, 0xba, 0xed, 0x70, 0xbc, 0x25, 0x53, 0x99, 0x21, 0xff, 0x44, 0xb0, 0x14, 0x73, 0x72, 0x1b,
+	0xdd, 0xd1, 0x6c, 0x74, 0x39, 0x5a, 0x4c, 0x27, 0x4b, 0x19, 0xea, 0x09, 0xcc, 0xcb, 0x0d, 0xbe,
+	0xec, 0xf9, 0x01, 0xb3, 0xb4, 0xeb, 0x74, 0xfd, 0x94, 0xa5, 0x33, 0x94, 0x60, 0x72, 0xca, 0xea,
+	0x0f, 0x26, 0x5b, 0xfa, 0x81, 0x6e, 0xe9, 0x2b, 0xe3, 0x70, 0x29, 0x20, 0x54, 0xe3, 0x6f, 0x40,
+	0x45, 0x21, 0x14, 0xe1, 0x34, 0xd6, 0x6b, 0x99, 0x89, 0x9f, 0xf5, 0x68, 0xbf, 0x2b, 0x54, 0x5e,
+	0x36, 0xe5, 0x88, 0x5c, 0x85, 0xc5, 0x75, 0xc7, 0x7e, 0xd3, 0x3b, 0x0c, 0x45, 0xac, 0xc0, 0xcc,
+	0x9e, 0xe5, 0x1d, 0xd2, 0x40, 0x4a, 0x90, 0x23, 0xf2, 0x35, 0xcc, 0x87, 0x84, 0x4c, 0xa7, 0x0d,
+	0x4d, 0xa7, 0x9a, 0x01, 0x43, 0x9a, 0xd3, 0x8b, 0xcb, 0xbf, 0x21, 0x28, 0x2a, 0x51, 0xb9, 0xad,
+	0xef, 0x8f, 0x0d, 0xb9, 0xdb, 0xb9, 0xee, 0x3e, 0xf5, 0x7c, 0xe6, 0x76, 0x79, 0xe9, 0x76, 0xd1,
+	0x0c, 0x73, 0xe5, 0xb6, 0xe7, 0x74, 0x38, 0xab, 0x70, 0xf2, 0x68, 0x3c, 0xc9, 0xcd, 0xa3, 0xd0,
+	0x68, 0x1b, 0x25, 0x25, 0x34, 0xda, 0x3c, 0x84, 0xda, 0xc6, 0x8c, 0x0c, 0xa1, 0x36, 0xc6, 0x50,
+	0x6c, 0x3b, 0x5e, 0x60, 0xcc, 0x72, 0x87, 0xe4, 0xdf, 0x09, 0x57, 0x9d, 0x4b, 0xb9, 0xea, 0xaf,
+	0x11, 0x9c, 0x89, 0x3d, 0x5b, 0xe8, 0xf5, 0xae, 0xa6, 0x57, 0x92, 0x0e, 0x8c, 0x31, 0x59, 0xe5,
+	0xd9, 0x64, 0xdd, 0x5e, 0xd1, 0x7d, 0x6d, 0x31, 0x92, 0x9b, 0x4c, 0x29, 0x37, 0xe1, 0xac, 0xba,
+	0x94, 0xf0, 0x09, 0xa9, 0x20, 0xc5, 0xaf, 0xa2, 0x31, 0xf9, 0x37, 0x82, 0x72, 0xc4, 0xc1, 0x0c,
+	0xb4, 0xee, 0x0c, 0x06, 0x96, 0xdd, 0xe5, 0x3b, 0x28, 0x9b, 0xe1, 0x50, 0xe6, 0xa0, 0x7c, 0x22,
+	0x07, 0x15, 0xa2, 0x1c, 0x54, 0x81, 0xc2, 0xba, 0x3b, 0xe4, 0xfa, 0x2f, 0x99, 0xec, 0x93, 0xcd,
+	0x6c, 0xda, 0x47, 0x46, 0x89, 0xcb, 0x61, 0x9f, 0xcc, 0x18, 0x9b, 0xef, 0x5c, 0xc7, 0xa7, 0x5c,
+	0xf1, 0x25, 0x53, 0x8e, 0x98, 0xe7, 0xb4, 0x06, 0xd6, 0x21, 0xe5, 0xda, 0x2f, 0x9b, 0x62, 0xc0,
+	0xa8, 0xb7, 0xe8, 0xc0, 0xf1, 0x46, 0x5c, 0xf5, 0x05, 0x53, 0x8e, 0x94, 0x6c, 0x57, 0x1e, 0x9b,
+	0xed, 0x20, 0xe1, 0x06, 0x06, 0xcc, 0xee, 0x3b, 0xfd, 0xe1, 0x80, 0xfa, 0xc6, 0xbc, 0xd8, 0x97,
+	0x1c, 0x92, 0xcf, 0x01, 0x36, 0xa8, 0x4b, 0xed, 0x2e, 0xb5, 0x3b, 0x23, 0x46, 0xd7, 0x76, 0xba,
+	0xaa, 0x83, 0xca, 0x21, 0x5b, 0xb5, 0xed, 0xf4, 0x7b, 0x9d, 0x11, 0xd7, 0x41, 0xc9, 0x94, 0x23,
+	0xf2, 0x2f, 0xc4, 0x59, 0xb8, 0x7b, 0x4f, 0xcb, 0x9d, 0x8d, 0x8c, 0xdc, 0x89, 0xd3, 0x2e, 0xa2,
+	0xe5, 0xcb, 0x7b, 0xb0, 0x10, 0xe1, 0xeb, 0x51, 0xdf, 0x28, 0x70, 0xae, 0x73, 0x11, 0x57, 0x0c,
+	0xde, 0xd4, 0x08, 0x13, 0xde, 0x5b, 0x4c, 0x7a, 0x2f, 0x53, 0xd7, 0x0b, 0x6a, 0xf5, 0x83, 0xaf,
+	0xfc, 0x80, 0xc7, 0x46, 0xc9, 0x8c, 0xc6, 0xcc, 0x20, 0xbb, 0x81, 0x15, 0x84, 0x76, 0x12, 0x03,
+	0x72, 0x1f, 0xe6, 0xd6, 0x1d, 0x8f, 0xf2, 0xad, 0xde, 0x80, 0x39, 0xb9, 0xeb, 0x30, 0xa3, 0x56,
+	0x92, 0x19, 0xd5, 0x8c, 0x28, 0x58, 0xa4, 0x2c, 0x32, 0xd6, 0x1e, 0x9b, 0xe6, 0x71, 0xf2, 0x99,
+	0x16, 0x27, 0x6b, 0x8a, 0x12, 0x14, 0xaa, 0x54, 0x94, 0x7c, 0x6f, 0x72, 0x94, 0x5c, 0xd5, 0xa3,
+	0xe4, 0xac, 0x26, 0x35, 0x19, 0x29, 0xeb, 0x70, 0x26, 0x5e, 0xec, 0xa4, 0xe9, 0x77, 0x00, 0x8b,
+	0x91, 0xad, 0xb8, 0x5e, 0xd6, 0x60, 0x3e, 0x9e, 0xd8, 0x90, 0x62, 0xd4, 0x29, 0x25, 0x23, 0xe5,
+	0x33, 0x32, 0x52, 0x21, 0x95, 0x91, 0x8a, 0x71, 0x46, 0x22, 0x14, 0xe6, 0x85, 0x8d, 0xfd, 0x56,
+	0x40, 0x07, 0x53, 0xfd, 0xed, 0x6e, 0x86, 0xbf, 0xad, 0xa4, 0xfd, 0x8d, 0x6b, 0x46, 0xa1, 0x24,
+	0xbf, 0x41, 0xb0, 0x28, 0xd7, 0x69, 0xba, 0xee, 0x96, 0xe5, 0x32, 0x73, 0x59, 0xae, 0xeb, 0xa7,
+	0xcc, 0xa5, 0x51, 0xf1, 0xa2, 0x49, 0x9a, 0x8b, 0x51, 0x57, 0xb7, 0xa0, 0x1c, 0x4d, 0x65, 0x98,
+	0xeb, 0xba, 0x6e, 0xae, 0xe5, 0xa4, 0x54, 0xb6, 0x47, 0xd5, 0x62, 0xbf, 0x45, 0xb0, 0x24, 0xff,
+	0x62, 0x3a, 0x63, 0xb8, 0xee, 0x43, 0xc9, 0x76, 0xba, 0xd4, 0x4f, 0xe5, 0x5b, 0x9d, 0xae, 0xc6,
+	0x7e, 0x25, 0x34, 0xc1, 0x50, 0x6d, 0x03, 0xc4, 0x93, 0x19, 0xe0, 0x6e, 0xe8, 0xe0, 0x56, 0xb2,
+	0xb7, 0xac, 0xc2, 0xfb, 0x16, 0x85, 0xa1, 0xea, 0x4f, 0x2e, 0x7e, 0x55, 0xa2, 0x94, 0x8b, 0xb7,
+	0x27, 0xbb, 0xf8, 0x77, 0x74, 0x58, 0xab, 0x63, 0x36, 0xac, 0xe2, 0xfa, 0x6e, 0xa4, 0xb5, 0x29,
+	0x35, 0xc2, 0x58, 0x2f, 0xdf, 0x86, 0xa5, 0x97, 0x4e, 0xc7, 0xea, 0xfb, 0x2e, 0xed, 0x88, 0xad,
+	0x61, 0x65, 0x6b, 0x65, 0x81, 0x9c, 0x45, 0x0f, 0xa7, 0x8a, 0x3c, 0x3b, 0x1c, 0xca, 0x2e, 0xa1,
+	0x10, 0x75, 0x09, 0xd7, 0xa1, 0xa2, 0xc8, 0x8b, 0x30, 0x31, 0xec, 0x51, 0x37, 0x21, 0x47, 0xe4,
+	0x1f, 0x79, 0x91, 0xd6, 0x79, 0x74, 0x7d, 0x0c, 0xe8, 0x48, 0xaa, 0xd3, 0x88, 0x76, 0x1e, 0xfe,
+	0x5b, 0xdb, 0x17, 0x7a, 0x44, 0x47, 0xd5, 0xbf, 0x23, 0x28, 0xed, 0x33, 0x05, 0xe0, 0x06, 0x94,
+	0xf6, 0x83, 0x91, 0x2b, 0x02, 0x7a, 0xa9, 0x71, 0x21, 0x83, 0x8b, 0xd1, 0xd5, 0xf6, 0x46, 0x2e,
+	0x35, 0x05, 0x29, 0xdb, 0x9c, 0x7f, 0x64, 0xf5, 0xe5, 0x2e, 0xf8, 0x37, 0xab, 0x41, 0x07, 0x6c,
+	0xae, 0x90, 0xa8, 0x41, 0x13, 0x62, 0xb6, 0x8e, 0xac, 0xbe, 0xb4, 0x26, 0x23, 0x67, 0x25, 0x53,
+	0x34, 0x75, 0xac, 0x92, 0xe9, 0xff, 0xa0, 0xc8, 0x20, 0x61, 0x80, 0x99, 0xdd, 0x3d, 0xb3, 0xb5,
+	0xfd, 0xbc, 0x92, 0xc3, 0xb3, 0x50, 0xd8, 0x6a, 0xb6, 0x2b, 0xa8, 0xba, 0x05, 0x33, 0xfb, 0xc7,
+	0x76, 0x10, 0x1d, 0xa9, 0xba, 0xd6, 0xcf, 0x91, 0x8c, 0x05, 0x61, 0xdb, 0x5b, 0x9a, 0xdb, 0x7e,
+	0xa8, 0x09, 0xf0, 0x4f, 0x37, 0x2f, 0x87, 0x98, 0x54, 0x34, 0x04, 0x16, 0xe4, 0x4a, 0xc2, 0x31,
+	0x30, 0x14, 0x95, 0xf3, 0x98, 0x7f, 0x13, 0x0a, 0x85, 0xb6, 0xd3, 0x4d, 0x1c, 0x71, 0x28, 0x75,
+	0xc4, 0x89, 0x94, 0x9a, 0x4f, 0xa5, 0xd4, 0x82, 0x52, 0xe4, 0xa9, 0x85, 0x65, 0x51, 0x2f, 0x2c,
+	0x89, 0x38, 0xe4, 0x9e, 0x7b, 0xce, 0xd0, 0xc5, 0x6b, 0x8c, 0x37, 0x6a, 0x1f, 0x16, 0xd4, 0xc3,
+	0xce, 0xe4, 0xff, 0x90, 0xbb, 0xb0, 0xd8, 0x76, 0xba, 0x87, 0x8c, 0x5a, 0x28, 0xf2, 0x23, 0x4d,
+	0x91, 0x67, 0x55, 0x16, 0x2e, 0x53, 0x28, 0x8f, 0x7c, 0x0a, 0x67, 0x62, 0xbe, 0x29, 0x07, 0x11,
+	0xf9, 0x52, 0xa9, 0xef, 0x9e, 0x39, 0x5e, 0xdb, 0x73, 0xde, 0x8d, 0xf4, 0x43, 0xc7, 0x4d, 0x1f,
+	0x3a, 0x2e, 0xfe, 0x7f, 0xe5, 0x9c, 0xe2, 0x2a, 0x10, 0x45, 0x8c, 0x3e, 0x49, 0x9e, 0x09, 0x5d,
+	0xf0, 0x50, 0x7b, 0xa8, 0x9d, 0x1d, 0x62, 0x0b, 0xd5, 0xcc, 0x3e, 0x8f, 0x63, 0xd0, 0xce, 0x0f,
+	0xe6, 0x50, 0x62, 0x76, 0xa2, 0x43, 0xc5, 0x24, 0xa7, 0xe6, 0x50, 0x21, 0x7c, 0x3d, 0xff, 0x2d,
+	0xc8, 0x95, 0x4e, 0x7a, 0xca, 0x0f, 0xd5, 0x56, 0xd9, 0xa4, 0x07, 0xb4, 0xdf, 0x67, 0xc7, 0xeb,
+	0xb4, 0xe3, 0x77, 0x5a, 0x4f, 0x33, 0xad, 0x15, 0x6e, 0xc3, 0x72, 0x58, 0xb8, 0x68, 0xeb, 0xde,
+	0x4f, 0xd5, 0x5e, 0x59, 0xdd, 0x6c, 0x44, 0xaf, 0xd4, 0x61, 0x7f, 0x40, 0x60, 0x44, 0xf3, 0x3c,
+	0x05, 0xbb, 0x9e, 0xd3, 0x91, 0x71, 0xff, 0x44, 0x33, 0xd3, 0xa7, 0x91, 0xc8, 0x71, 0x0c, 0x29,
+	0xa3, 0xed, 0x4f, 0x36, 0xda, 0x6d, 0xdd, 0x68, 0x1f, 0xa6, 0xaa, 0x33, 0x0d, 0xb4, 0x62, 0xc0,
+	0xbb, 0x50, 0xcd, 0xc4, 0x30, 0x2d, 0x56, 0x3e, 0x03, 0x63, 0x37, 0xf0, 0xa8, 0x35, 0xf0, 0x9c,
+	0x61, 0x20, 0x5c, 0xfc, 0x3d, 0xb8, 0x6e, 0xc0, 0x4a, 0x06, 0x57, 0xf2, 0xd0, 0x2b, 0xc9, 0xe0,
+	0xdd, 0x80, 0x25, 0x41, 0xfd, 0xca, 0xf5, 0xf9, 0x2f, 0xa3, 0x7a, 0xe1, 0xf8, 0xe1, 0xd1, 0xca,
+	0xbf, 0x13, 0x96, 0xce, 0xa7, 0x2c, 0xfd, 0x0d, 0x2c, 0x0a, 0x29, 0xbb, 0xd4, 0x3b, 0xea, 0x75,
+	0x28, 0x26, 0xb0, 0x10, 0x0a, 0xe4, 0xe1, 0x2a, 0x72, 0x9b, 0x36, 0xc7, 0x84, 0xb2, 0x3b, 0x05,
+	0x6a, 0x2b, 0x01, 0xad, 0xcc, 0x30, 0x20, 0xbb, 0xd4, 0xee, 0xca, 0x73, 0x97, 0x7f, 0xcb, 0x0e,
+	0x8c, 0x76, 0x02, 0x99, 0xeb, 0xe4, 0x88, 0x47, 0xac, 0x40, 0xc0, 0x22, 0x28, 0x2b, 0xe7, 0xe2,
+	0x3b, 0x50, 0x0e, 0x97, 0x0f, 0x6b, 0xc9, 0xf8, 0x70, 0xd1, 0x75, 0x60, 0xc6, 0x94, 0xb8, 0x01,
+	0x73, 0x72, 0x53, 0x61, 0xef, 0xb2, 0x92, 0xe0, 0x92, 0x7f, 0x9b, 0x11, 0x1d, 0x79, 0x04, 0x4b,
+	0x31, 0x18, 0xb6, 0x23, 0xfc, 0x09, 0x94, 0xb8, 0xd1, 0x0d, 0x94, 0x68, 0x7f, 0x62, 0x3a, 0x53,
+	0x50, 0x90, 0xb6, 0x48, 0xf2, 0xcc, 0xc2, 0xbb, 0x5e, 0xc7, 0x8d, 0xb5, 0x17, 0x0e, 0xd9, 0x3f,
+	0x1b, 0x7e, 0xe0, 0xc6, 0x5a, 0x0b, 0x87, 0xec, 0x3c, 0x6e, 0xb3, 0x9b, 0x4e, 0xa9, 0x33, 0x31,
+	0x20, 0xb7, 0xd4, 0x38, 0x67, 0xad, 0x38, 0xf7, 0x09, 0x09, 0x65, 0x51, 0x09, 0x3d, 0x2f, 0x30,
+	0xc5, 0x7f, 0xe4, 0x8f, 0x08, 0x2e, 0xa8, 0x5e, 0x24, 0xbe, 0x95, 0x60, 0x5b, 0xd7, 0x82, 0xed,
+	0x66, 0x62, 0x3f, 0xd9, 0x4c, 0xa7, 0x56, 0x2b, 0xea, 0xca, 0x55, 0x43, 0xed, 0x21, 0x5c, 0x1c,
+	0x8b, 0x60, 0x5a, 0xe0, 0x3c, 0xd1, 0xaf, 0x2f, 0x5f, 0xd3, 0x03, 0x21, 0x44, 0x1e, 0xc3, 0x28,
+	0x3a, 0x86, 0xe3, 0x6b, 0x80, 0xbc, 0x7a, 0x0d, 0xa0, 0xa7, 0xd9, 0x98, 0xfd, 0xf4, 0x6e, 0x24,
+	0x23, 0x99, 0xda, 0x69, 0xa5, 0xa7, 0xd9, 0x78, 0xdd, 0xf7, 0x4b, 0xb3, 0xb1, 0xcc, 0x38, 0xcd,
+	0xfe, 0x0e, 0xc1, 0x4a, 0x34, 0xff, 0x9a, 0x1e, 0x28, 0x76, 0x7f, 0xac, 0xd9, 0xfd, 0x93, 0x48,
+	0x60, 0x36, 0xf9, 0xff, 0x22, 0xc5, 0xc6, 0x80, 0x15, 0xbb, 0xbf, 0x04, 0x23, 0x03, 0xc1, 0x49,
+	0xcf, 0xcb, 0x25, 0x58, 0xd8, 0x1c, 0xb8, 0x41, 0x78, 0xe2, 0x92, 0x17, 0xb0, 0x20, 0xcf, 0x3c,
+	0xa1, 0x04, 0x76, 0x1b, 0x23, 0xc6, 0xa1, 0x44, 0xe5, 0x48, 0x6c, 0xb6, 0x5b, 0xc9, 0x23, 0x33,
+	0x9a, 0x21, 0xbf, 0x42, 0xb0, 0xf8, 0xda, 0x0a, 0x3a, 0x5f, 0x31, 0xdf, 0xb4, 0x82, 0xa1, 0xcf,
+	0x32, 0xe5, 0xf6, 0x70, 0x60, 0xd2, 0x0e, 0xed, 0x1d, 0x89, 0x4a, 0x85, 0x67, 0x4a, 0x75, 0x8e,
+	0x49, 0x7d, 0xe5, 0x76, 0xad, 0x80, 0xee, 0xf5, 0x06, 0x42, 0x37, 0x05, 0x53, 0x99, 0xc1, 0x17,
+	0xa0, 0xfc, 0xd2, 0xf2, 0x83, 0xcd, 0x23, 0x6a, 0x8b, 0xe2, 0x70, 0xc1, 0x8c, 0x27, 0xd8, 0xbf,
+	0x7b, 0x4e, 0x60, 0xf5, 0xbf, 0xa0, 0x23, 0x5f, 0x76, 0xe3, 0xf1, 0x04, 0xf9, 0x13, 0x82, 0x79,
+	0x01, 0x45, 0xec, 0xed, 0x22, 0xc0, 0x73, 0x87, 0x29, 0xb2, 0x67, 0xd3, 0x10, 0x8d, 0x32, 0x83,
+	0xef, 0xc3, 0x8c, 0x20, 0x37, 0xf2, 0x89, 0x5e, 0x5a, 0x91, 0x22, 0xbf, 0x85, 0xe5, 0x25, 0x7d,
+	0xf5, 0xfb, 0x30, 0xaf, 0x4c, 0x1f, 0xa7, 0x65, 0xd5, 0x34, 0xa6, 0x9a, 0xfd, 0x2f, 0x08, 0x2a,
+	0xbb, 0xc3, 0x03, 0xbf, 0xe3, 0xf5, 0x0e, 0xa2, 0x37, 0x9d, 0xdb, 0x90, 0xdf, 0x71, 0x65, 0xbf,
+	0x14, 0x5f, 0x6a, 0x27, 0xc9, 0x6a, 0x3b, 0x2e, 0xf5, 0x78, 0x00, 0x9a, 0xf9, 0x1d, 0x7e, 0xfd,
+	0xb7, 0x67, 0x1d, 0x4a, 0x8b, 0xb1, 0x4f, 0x9e, 0x7f, 0x45, 0x42, 0x97, 0xd9, 0x34, 0x1c, 0xe2,
+	0x1a, 0xcc, 0x4a, 0x21, 0x46, 0x51, 0x76, 0xfe, 0xe2, 0xcd, 0xa9, 0x16, 0xbe, 0x39, 0xd5, 0x9a,
+	0xf6, 0xc8, 0x0c, 0x89, 0xc8, 0x1a, 0x94, 0xa3, 0xc5, 0x58, 0x13, 0xd4, 0xdc, 0xd8, 0xa8, 0xe4,
+	0x58, 0x67, 0x64, 0x6e, 0x6e, 0xed, 0xec, 0x6f, 0x56, 0x10, 0xf9, 0x05, 0x82, 0xa5, 0x08, 0xa0,
+	0xb0, 0x9a, 0x04, 0x84, 0x62, 0x40, 0x2c, 0x0d, 0x6d, 0x70, 0x84, 0x45, 0x33, 0x2f, 0x2e, 0x62,
+	0x9a, 0x1d, 0x9e, 0x53, 0xe4, 0xab, 0x89, 0x18, 0xb1, 0x6b, 0x09, 0x6e, 0x84, 0x89, 0xe0, 0x04,
+	0x09, 0x3b, 0x30, 0x36, 0x3d, 0xcf, 0xf1, 0xe4, 0xed, 0xb2, 0x18, 0x34, 0x9e, 0x46, 0x11, 0x83,
+	0xef, 0x41, 0xe1, 0x39, 0x0d, 0xf0, 0x6a, 0xfa, 0xe5, 0x8b, 0xef, 0xad, 0x7a, 0x3e, 0xf3, 0x49,
+	0x8c, 0xe4, 0x1a, 0x2e, 0x14, 0xd9, 0xdd, 0x09, 0xbe, 0x25, 0x04, 0x2c, 0x27, 0x5e, 0xa6, 0x04,
+	0x37, 0x4e, 0xbf, 0x57, 0x91, 0x1c, 0xbe, 0x03, 0x25, 0x6e, 0xf1, 0xe3, 0x30, 0xd5, 0x51, 0xe3,
+	0x67, 0x08, 0xca, 0xf1, 0x3b, 0xd0, 0x23, 0xb1, 0xee, 0x07, 0x59, 0xaf, 0x1a, 0x42, 0xce, 0xea,
+	0x98, 0x07, 0x0f, 0x92, 0xc3, 0x4f, 0x42, 0x04, 0x27, 0x62, 0xaf, 0xa3, 0xc6, 0xd7, 0x30, 0x23,
+	0x5e, 0x22, 0xf0, 0x1d, 0x81, 0x63, 0x25, 0xf5, 0x42, 0x21, 0xa4, 0x2c, 0x67, 0xbd, 0x5c, 0x90,
+	0x1c, 0x7e, 0x10, 0x22, 0x38, 0x26, 0x63, 0x1d, 0x35, 0x7e, 0x89, 0xd4, 0x03, 0x05, 0x3f, 0x16,
+	0x00, 0xaa, 0x99, 0x57, 0xf9, 0x42, 0x96, 0x31, 0xee, 0x9a, 0x9f, 0xe4, 0x70, 0x33, 0x04, 0x72,
+	0x42, 0x01, 0x75, 0xd4, 0xf8, 0x09, 0x82, 0xb9, 0xf0, 0xaa, 0x12, 0x3f, 0x10, 0x70, 0x8c, 0x8c,
+	0x1b, 0x53, 0x21, 0x6b, 0x25, 0xfb, 0x2e, 0x95, 0xe4, 0xf0, 0xe3, 0x10, 0xca, 0x09, 0x98, 0xeb,
+	0xa8, 0xf1, 0x0d, 0xcc, 0xca, 0x7b, 0xa4, 0xb4, 0x57, 0xeb, 0x17, 0x4c, 0xd5, 0xf3, 0xe9, 0x3f,
+	0x04, 0x84, 0x47, 0x21, 0x84, 0x63, 0xb3, 0xd6, 0x51, 0xe3, 0x05, 0x94, 0xa3, 0x6b, 0xa3, 0xb4,
+	0x7f, 0x26, 0x6f, 0x94, 0xaa, 0xab, 0x59, 0x7f, 0x89, 0xe0, 0x1a, 0x42, 0x89, 0xdf, 0x31, 0xe0,
+	0xdb, 0x42, 0xca, 0xf9, 0xe4, 0x25, 0x87, 0x90, 0x70, 0x2e, 0xe3, 0xee, 0x83, 0xe4, 0xf0, 0xbd,
+	0x70, 0x13, 0xc7, 0x62, 0x93, 0x86, 0x0c, 0x5b, 0xfd, 0xb4, 0x21, 0x13, 0x97, 0x00, 0xd5, 0x95,
+	0x8c, 0x7f, 0xc6, 0x1a, 0xf2, 0xbd, 0x99, 0xeb, 0x88, 0xed, 0x5e, 0xdc, 0x1b, 0xa4, 0x76, 0xaf,
+	0xf6, 0xc9, 0xd5, 0x73, 0xc9, 0xe9, 0xb1, 0xbb, 0x7f, 0x0f, 0xb6, 0x3a, 0x6a, 0xfc, 0x19, 0xc1,
+	0xb9, 0x8c, 0x3e, 0x0e, 0xef, 0x08, 0x14, 0x57, 0x26, 0x37, 0x9c, 0x42, 0xf8, 0xe5, 0xa9, 0x5d,
+	0x29, 0xc9, 0xe1, 0xdd, 0x10, 0xe1, 0xa9, 0x89, 0xac, 0xa3, 0xc6, 0xef, 0x11, 0x9c, 0x4d, 0xf5,
+	0x85, 0xf8, 0x0b, 0x81, 0xfd, 0x72, 0x66, 0xfd, 0xae, 0x36, 0x9c, 0xd5, 0x4b, 0x93, 0x48, 0x04,
+	0xee, 0x9d, 0x10, 0xf7, 0xa9, 0x88, 0xab, 0xa3, 0xc6, 0x5f, 0x11, 0xac, 0x8e, 0x29, 0xe7, 0xf1,
+	0x6b, 0x81, 0xfc, 0xea, 0xf4, 0xce, 0x43, 0x2c, 0xf8, 0xd1, 0x7b, 0xb5, 0x28, 0x24, 0x87, 0xbf,
+	0x0c, 0x77, 0x71, 0xea, 0xa2, 0xa5, 0x15, 0x52, 0x85, 0x6a, 0xda, 0x0a, 0xe3, 0x6a, 0xd9, 0xea,
+	0xa5, 0x49, 0x24, 0x63, 0xad, 0xf0, 0x5f, 0x88, 0xab, 0xa3, 0xc6, 0x8f, 0x61, 0xf6, 0xa5, 0xd5,
+	0xb3, 0xfb, 0x34, 0xc0, 0x0f, 0xa2, 0xc2, 0x57, 0x89, 0x1e, 0xb5, 0x54, 0x56, 0xd2, 0x9f, 0x5a,
+	0x31, 0xf3, 0xb0, 0x93, 0x75, 0xe0, 0x38, 0xce, 0xe5, 0xac, 0x42, 0x92, 0xe4, 0x1a, 0xaf, 0x60,
+	0x41, 0x96, 0x46, 0x2e, 0x2f, 0x6f, 0x36, 0xa1, 0x1c, 0x95, 0x4a, 0x4a, 0xfa, 0x4c, 0xd6, 0x77,
+	0xd5, 0xd5, 0xf4, 0x5f, 0xbc, 0xb2, 0x22, 0xb9, 0x6b, 0xa8, 0x8e, 0x9e, 0x5e, 0x07, 0xa3, 0xe7,
+	0xd4, 0x0e, 0x3d, 0xb7, 0x53, 0xa3, 0xef, 0xac, 0x81, 0xdb, 0xa7, 0x7e, 0xc8, 0xf0, 0x74, 0x61,
+	0x4b, 0x7c, 0xf0, 0xf6, 0xb9, 0x8d, 0x0e, 0x66, 0x78, 0xe9, 0x74, 0xfb, 0x3f, 0x03, 0x00, 0x27,
+	0x1c, 0x5d, 0xbe, 0x73, 0x24, 0x00, 0x00,
 }
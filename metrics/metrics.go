@@ -0,0 +1,317 @@
+// Package metrics implements the counters, gauges and histograms of lainlet,
+// and exports them in the prometheus text format.
+package metrics
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"math"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// DefBuckets is the default buckets of histogram, in seconds
+var DefBuckets = []float64{.005, .01, .025, .05, .1, .25, .5, 1, 2.5, 5, 10}
+
+var (
+	mu       sync.Mutex
+	families []family
+)
+
+type family interface {
+	name() string
+	write(w io.Writer)
+}
+
+func register(f family) {
+	mu.Lock()
+	defer mu.Unlock()
+	for _, item := range families {
+		if item.name() == f.name() {
+			panic("duplicated metric " + f.name())
+		}
+	}
+	families = append(families, f)
+}
+
+// WriteTo write all the metrics into w in the prometheus text format.
+func WriteTo(w io.Writer) error {
+	mu.Lock()
+	list := make([]family, len(families))
+	copy(list, families)
+	mu.Unlock()
+	sort.Slice(list, func(i, j int) bool { return list[i].name() < list[j].name() })
+
+	bw := bufio.NewWriter(w)
+	for _, f := range list {
+		f.write(bw)
+	}
+	return bw.Flush()
+}
+
+// Handler return a http handler serving the metrics.
+func Handler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/plain; version=0.0.4")
+		WriteTo(w)
+	})
+}
+
+// desc is the common part of all the metrics
+type desc struct {
+	fqName string
+	help   string
+	typ    string
+	labels []string
+}
+
+func (d *desc) name() string {
+	return d.fqName
+}
+
+func (d *desc) writeHeader(w io.Writer) {
+	fmt.Fprintf(w, "# HELP %s %s\n", d.fqName, strings.Replace(d.help, "\n", " ", -1))
+	fmt.Fprintf(w, "# TYPE %s %s\n", d.fqName, d.typ)
+}
+
+// key join the label values as the key of a series
+func (d *desc) key(values []string) string {
+	if len(values) != len(d.labels) {
+		panic(fmt.Sprintf("metric %s wants %d label values, got %d", d.fqName, len(d.labels), len(values)))
+	}
+	return strings.Join(values, "\xff")
+}
+
+// series format the series name with the labels, extra is a additional `name="value"` label.
+func (d *desc) series(suffix, key, extra string) string {
+	pairs := make([]string, 0, len(d.labels)+1)
+	if len(d.labels) > 0 {
+		for i, value := range strings.Split(key, "\xff") {
+			pairs = append(pairs, d.labels[i]+`="`+labelEscaper.Replace(value)+`"`)
+		}
+	}
+	if extra != "" {
+		pairs = append(pairs, extra)
+	}
+	if len(pairs) == 0 {
+		return d.fqName + suffix
+	}
+	return d.fqName + suffix + "{" + strings.Join(pairs, ",") + "}"
+}
+
+var labelEscaper = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`)
+
+func formatFloat(v float64) string {
+	switch {
+	case math.IsInf(v, 1):
+		return "+Inf"
+	case math.IsInf(v, -1):
+		return "-Inf"
+	case math.IsNaN(v):
+		return "NaN"
+	}
+	return strconv.FormatFloat(v, 'g', -1, 64)
+}
+
+func sortedKeys(m map[string]float64) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// Counter is a metric only going up, with a value for each set of label values.
+type Counter struct {
+	desc
+	mu     sync.Mutex
+	values map[string]float64
+}
+
+// NewCounter create and register a counter.
+func NewCounter(name, help string, labels ...string) *Counter {
+	c := &Counter{
+		desc:   desc{fqName: name, help: help, typ: "counter", labels: labels},
+		values: make(map[string]float64),
+	}
+	register(c)
+	return c
+}
+
+// Inc increase the counter by 1.
+func (c *Counter) Inc(labelValues ...string) {
+	c.Add(1, labelValues...)
+}
+
+// Add increase the counter by v, v must not be negative.
+func (c *Counter) Add(v float64, labelValues ...string) {
+	if v < 0 {
+		panic("counter can not decrease")
+	}
+	key := c.key(labelValues)
+	c.mu.Lock()
+	c.values[key] += v
+	c.mu.Unlock()
+}
+
+func (c *Counter) write(w io.Writer) {
+	c.writeHeader(w)
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for _, key := range sortedKeys(c.values) {
+		fmt.Fprintf(w, "%s %s\n", c.series("", key, ""), formatFloat(c.values[key]))
+	}
+}
+
+// Gauge is a metric can go up and down, with a value for each set of label values.
+type Gauge struct {
+	desc
+	mu     sync.Mutex
+	values map[string]float64
+}
+
+// NewGauge create and register a gauge.
+func NewGauge(name, help string, labels ...string) *Gauge {
+	g := &Gauge{
+		desc:   desc{fqName: name, help: help, typ: "gauge", labels: labels},
+		values: make(map[string]float64),
+	}
+	register(g)
+	return g
+}
+
+// Set the gauge to v.
+func (g *Gauge) Set(v float64, labelValues ...string) {
+	key := g.key(labelValues)
+	g.mu.Lock()
+	g.values[key] = v
+	g.mu.Unlock()
+}
+
+// Add v to the gauge, v can be negative.
+func (g *Gauge) Add(v float64, labelValues ...string) {
+	key := g.key(labelValues)
+	g.mu.Lock()
+	g.values[key] += v
+	g.mu.Unlock()
+}
+
+// Inc increase the gauge by 1.
+func (g *Gauge) Inc(labelValues ...string) {
+	g.Add(1, labelValues...)
+}
+
+// Dec decrease the gauge by 1.
+func (g *Gauge) Dec(labelValues ...string) {
+	g.Add(-1, labelValues...)
+}
+
+func (g *Gauge) write(w io.Writer) {
+	g.writeHeader(w)
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	for _, key := range sortedKeys(g.values) {
+		fmt.Fprintf(w, "%s %s\n", g.series("", key, ""), formatFloat(g.values[key]))
+	}
+}
+
+// GaugeFunc is a gauge whose values are collected by a function when the metrics are exported.
+type GaugeFunc struct {
+	desc
+	collect func(set func(v float64, labelValues ...string))
+}
+
+// NewGaugeFunc create and register a GaugeFunc, collect should call set for each set of label values.
+func NewGaugeFunc(name, help string, collect func(set func(v float64, labelValues ...string)), labels ...string) *GaugeFunc {
+	g := &GaugeFunc{
+		desc:    desc{fqName: name, help: help, typ: "gauge", labels: labels},
+		collect: collect,
+	}
+	register(g)
+	return g
+}
+
+func (g *GaugeFunc) write(w io.Writer) {
+	values := make(map[string]float64)
+	g.collect(func(v float64, labelValues ...string) {
+		values[g.key(labelValues)] = v
+	})
+	g.writeHeader(w)
+	for _, key := range sortedKeys(values) {
+		fmt.Fprintf(w, "%s %s\n", g.series("", key, ""), formatFloat(values[key]))
+	}
+}
+
+// Histogram counts the observed values in buckets, with a histogram for each set of label values.
+type Histogram struct {
+	desc
+	buckets []float64
+	mu      sync.Mutex
+	values  map[string]*histogramValue
+}
+
+type histogramValue struct {
+	counts []uint64 // cumulative counts of each bucket
+	count  uint64
+	sum    float64
+}
+
+// NewHistogram create and register a histogram, buckets are the upper bounds of the buckets, DefBuckets is used if it is empty.
+func NewHistogram(name, help string, buckets []float64, labels ...string) *Histogram {
+	if len(buckets) == 0 {
+		buckets = DefBuckets
+	}
+	buckets = append([]float64(nil), buckets...)
+	sort.Float64s(buckets)
+	h := &Histogram{
+		desc:    desc{fqName: name, help: help, typ: "histogram", labels: labels},
+		buckets: buckets,
+		values:  make(map[string]*histogramValue),
+	}
+	register(h)
+	return h
+}
+
+// Observe add a value into the histogram.
+func (h *Histogram) Observe(v float64, labelValues ...string) {
+	key := h.key(labelValues)
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	hv, ok := h.values[key]
+	if !ok {
+		hv = &histogramValue{counts: make([]uint64, len(h.buckets))}
+		h.values[key] = hv
+	}
+	for i, upper := range h.buckets {
+		if v <= upper {
+			hv.counts[i]++
+		}
+	}
+	hv.count++
+	hv.sum += v
+}
+
+func (h *Histogram) write(w io.Writer) {
+	h.writeHeader(w)
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	keys := make([]string, 0, len(h.values))
+	for k := range h.values {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		hv := h.values[key]
+		for i, upper := range h.buckets {
+			fmt.Fprintf(w, "%s %d\n", h.series("_bucket", key, `le="`+formatFloat(upper)+`"`), hv.counts[i])
+		}
+		fmt.Fprintf(w, "%s %d\n", h.series("_bucket", key, `le="+Inf"`), hv.count)
+		fmt.Fprintf(w, "%s %s\n", h.series("_sum", key, ""), formatFloat(hv.sum))
+		fmt.Fprintf(w, "%s %d\n", h.series("_count", key, ""), hv.count)
+	}
+}
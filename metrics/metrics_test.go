@@ -0,0 +1,153 @@
+package metrics
+
+import (
+	"bytes"
+	"math"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+// exported return the lines of the metric name in the output of WriteTo
+func exported(t *testing.T, name string) string {
+	var buf bytes.Buffer
+	if err := WriteTo(&buf); err != nil {
+		t.Fatal(err)
+	}
+	var lines []string
+	for _, line := range strings.Split(buf.String(), "\n") {
+		fields := strings.Fields(line)
+		switch {
+		case len(fields) >= 3 && fields[0] == "#" && fields[2] == name:
+		case strings.HasPrefix(line, name+" "), strings.HasPrefix(line, name+"{"), strings.HasPrefix(line, name+"_"):
+		default:
+			continue
+		}
+		lines = append(lines, line)
+	}
+	return strings.Join(lines, "\n")
+}
+
+// forget unregister the metrics of a test, so the tests can run again by -count
+func forget(names ...string) {
+	mu.Lock()
+	defer mu.Unlock()
+	var kept []family
+	for _, f := range families {
+		found := false
+		for _, name := range names {
+			found = found || f.name() == name
+		}
+		if !found {
+			kept = append(kept, f)
+		}
+	}
+	families = kept
+}
+
+func expect(t *testing.T, name, want string) {
+	if got := exported(t, name); got != strings.TrimSpace(want) {
+		t.Errorf("the exported %s:\n%s\nwant:\n%s", name, got, strings.TrimSpace(want))
+	}
+}
+
+func TestCounter(t *testing.T) {
+	defer forget("test_requests_total")
+	c := NewCounter("test_requests_total", "Number of the requests,\nby api.", "api", "code")
+	c.Inc("/nodes", "200")
+	c.Add(2, "/nodes", "200")
+	c.Inc(`/a"b\c`, "500")
+	expect(t, "test_requests_total", `
+# HELP test_requests_total Number of the requests, by api.
+# TYPE test_requests_total counter
+test_requests_total{api="/a\"b\\c",code="500"} 1
+test_requests_total{api="/nodes",code="200"} 3
+`)
+
+	defer func() {
+		if recover() == nil {
+			t.Error("a counter should not decrease")
+		}
+	}()
+	c.Add(-1, "/nodes", "200")
+}
+
+func TestGauge(t *testing.T) {
+	defer forget("test_connections", "test_keys")
+	g := NewGauge("test_connections", "Number of the connections.")
+	g.Set(3)
+	g.Inc()
+	g.Dec()
+	g.Add(-0.5)
+	expect(t, "test_connections", `
+# HELP test_connections Number of the connections.
+# TYPE test_connections gauge
+test_connections 2.5
+`)
+
+	NewGaugeFunc("test_keys", "Number of the keys.", func(set func(float64, ...string)) {
+		set(math.Inf(1), "b")
+		set(1e6, "a")
+	}, "watcher")
+	expect(t, "test_keys", `
+# HELP test_keys Number of the keys.
+# TYPE test_keys gauge
+test_keys{watcher="a"} 1e+06
+test_keys{watcher="b"} +Inf
+`)
+}
+
+func TestHistogram(t *testing.T) {
+	defer forget("test_duration_seconds", "test_default_seconds")
+	h := NewHistogram("test_duration_seconds", "Time spent.", []float64{1, 0.1}, "api")
+	h.Observe(0.05, "/nodes")
+	h.Observe(0.5, "/nodes")
+	h.Observe(2, "/nodes")
+	expect(t, "test_duration_seconds", `
+# HELP test_duration_seconds Time spent.
+# TYPE test_duration_seconds histogram
+test_duration_seconds_bucket{api="/nodes",le="0.1"} 1
+test_duration_seconds_bucket{api="/nodes",le="1"} 2
+test_duration_seconds_bucket{api="/nodes",le="+Inf"} 3
+test_duration_seconds_sum{api="/nodes"} 2.55
+test_duration_seconds_count{api="/nodes"} 3
+`)
+
+	if h := NewHistogram("test_default_seconds", "Default buckets.", nil); len(h.buckets) != len(DefBuckets) {
+		t.Errorf("got %d buckets, want the %d DefBuckets", len(h.buckets), len(DefBuckets))
+	}
+}
+
+func TestWriteTo(t *testing.T) {
+	defer forget("test_order_a", "test_order_b")
+	NewCounter("test_order_b", "B.")
+	NewCounter("test_order_a", "A.")
+	rec := httptest.NewRecorder()
+	Handler().ServeHTTP(rec, httptest.NewRequest("GET", "/metrics", nil))
+	if ct := rec.Header().Get("Content-Type"); ct != "text/plain; version=0.0.4" {
+		t.Errorf("got the content type %q", ct)
+	}
+	body := rec.Body.String()
+	a, b := strings.Index(body, "# HELP test_order_a "), strings.Index(body, "# HELP test_order_b ")
+	if a < 0 || b < 0 || a > b {
+		t.Errorf("the metrics are not sorted by name:\n%s", body)
+	}
+
+	defer func() {
+		if recover() == nil {
+			t.Error("a duplicated metric should not be registered")
+		}
+	}()
+	NewGauge("test_order_a", "A again.")
+}
+
+func TestLabelValues(t *testing.T) {
+	defer forget("test_labels_total")
+	c := NewCounter("test_labels_total", "Labels.", "a", "b")
+	defer func() {
+		if recover() == nil {
+			t.Error("the wrong number of label values should panic")
+		}
+	}()
+	c.Inc("1")
+}
@@ -0,0 +1,189 @@
+// Package proxyproto implements the listener accepting the PROXY protocol header (version 1 and 2) from the trusted proxies,
+// like haproxy or a load balancer, the connections accepted have the address of the original client as their remote address.
+// See https://www.haproxy.org/download/1.8/doc/proxy-protocol.txt
+package proxyproto
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"net"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/mijia/sweb/log"
+)
+
+// HeaderTimeout is the time to wait for the header from a trusted proxy
+var HeaderTimeout = 10 * time.Second
+
+var (
+	v1Prefix    = []byte("PROXY ")
+	v2Signature = []byte("\r\n\r\n\x00\r\nQUIT\n")
+)
+
+const v1MaxLength = 107 // the max length of a version 1 header, including the CRLF
+
+// Listener wrap a listener, the header is read from the connections of the trusted proxies.
+type Listener struct {
+	net.Listener
+	trusted func(addr string) bool
+}
+
+// NewListener create a Listener on l, trusted check if the remote address of a connection is a trusted proxy,
+// it is called for each connection, so the trusted proxies can be changed at any time.
+func NewListener(l net.Listener, trusted func(addr string) bool) *Listener {
+	return &Listener{Listener: l, trusted: trusted}
+}
+
+// Accept implements net.Listener, the header is read when the connection is read or its remote address is asked at first,
+// so a slow proxy does not block accepting.
+func (l *Listener) Accept() (net.Conn, error) {
+	conn, err := l.Listener.Accept()
+	if err != nil {
+		return nil, err
+	}
+	return &Conn{
+		Conn:    conn,
+		reader:  bufio.NewReader(conn),
+		trusted: l.trusted(conn.RemoteAddr().String()),
+	}, nil
+}
+
+// Conn is a connection accepted by Listener
+type Conn struct {
+	net.Conn
+	reader  *bufio.Reader
+	trusted bool
+
+	once       sync.Once
+	remoteAddr net.Addr // the address of the client in the header, nil if there is no header
+	err        error    // the error reading the header
+}
+
+// Read implements net.Conn, it fail if the header from the trusted proxy is invalid.
+func (c *Conn) Read(b []byte) (int, error) {
+	c.once.Do(c.readHeader)
+	if c.err != nil {
+		return 0, c.err
+	}
+	return c.reader.Read(b)
+}
+
+// RemoteAddr return the address of the client in the header, or the address of the peer if there is no header.
+func (c *Conn) RemoteAddr() net.Addr {
+	c.once.Do(c.readHeader)
+	if c.remoteAddr != nil {
+		return c.remoteAddr
+	}
+	return c.Conn.RemoteAddr()
+}
+
+// ProxyAddr return the address of the proxy if the connection has a header, otherwise nil.
+func (c *Conn) ProxyAddr() net.Addr {
+	c.once.Do(c.readHeader)
+	if c.remoteAddr != nil {
+		return c.Conn.RemoteAddr()
+	}
+	return nil
+}
+
+// readHeader read the header if the peer is a trusted proxy, the connection without header is used as it is.
+func (c *Conn) readHeader() {
+	if !c.trusted {
+		return
+	}
+	c.Conn.SetReadDeadline(time.Now().Add(HeaderTimeout))
+	defer c.Conn.SetReadDeadline(time.Time{})
+
+	var addr net.Addr
+	prefix, err := c.reader.Peek(len(v1Prefix))
+	switch {
+	case err == nil && bytes.Equal(prefix, v1Prefix):
+		addr, err = readV1(c.reader)
+	case err == nil && prefix[0] == v2Signature[0]:
+		if signature, _ := c.reader.Peek(len(v2Signature)); bytes.Equal(signature, v2Signature) {
+			addr, err = readV2(c.reader)
+		}
+	case err == io.EOF:
+		err = nil // the proxy checking health, like a tcp probe
+	}
+	if err != nil {
+		log.Warnf("Invalid PROXY protocol header from %s, %s", c.Conn.RemoteAddr(), err.Error())
+		c.err = fmt.Errorf("invalid PROXY protocol header, %s", err.Error())
+		return
+	}
+	c.remoteAddr = addr
+}
+
+// readV1 read the human-readable header, like "PROXY TCP4 192.168.0.1 192.168.0.11 56324 443\r\n".
+// The returned address is nil for "PROXY UNKNOWN", which means the address of the peer should be used.
+func readV1(r *bufio.Reader) (net.Addr, error) {
+	var line []byte
+	for len(line) < v1MaxLength {
+		b, err := r.ReadByte()
+		if err != nil {
+			return nil, err
+		}
+		line = append(line, b)
+		if b == '\n' {
+			break
+		}
+	}
+	if !bytes.HasSuffix(line, []byte("\r\n")) {
+		return nil, fmt.Errorf("header too long")
+	}
+	fields := strings.Split(string(line[:len(line)-2]), " ")
+	if len(fields) >= 2 && fields[1] == "UNKNOWN" {
+		return nil, nil
+	}
+	if len(fields) != 6 || (fields[1] != "TCP4" && fields[1] != "TCP6") {
+		return nil, fmt.Errorf("malformed header %q", line)
+	}
+	ip := net.ParseIP(fields[2])
+	port, err := strconv.ParseUint(fields[4], 10, 16)
+	if ip == nil || err != nil || (fields[1] == "TCP4") != (ip.To4() != nil) {
+		return nil, fmt.Errorf("malformed header %q", line)
+	}
+	return &net.TCPAddr{IP: ip, Port: int(port)}, nil
+}
+
+// readV2 read the binary header, only the source address of TCP over IPv4 and IPv6 is used.
+// The returned address is nil for the LOCAL command and the other protocols, which means the address of the peer should be used.
+func readV2(r *bufio.Reader) (net.Addr, error) {
+	header := make([]byte, 16)
+	if _, err := io.ReadFull(r, header); err != nil {
+		return nil, err
+	}
+	if header[12]>>4 != 2 {
+		return nil, fmt.Errorf("unsupported version %d", header[12]>>4)
+	}
+	payload := make([]byte, binary.BigEndian.Uint16(header[14:]))
+	if _, err := io.ReadFull(r, payload); err != nil {
+		return nil, err
+	}
+	command, family := header[12]&0x0f, header[13]
+	if command == 0 { // LOCAL, the connection is made by the proxy itself
+		return nil, nil
+	}
+	if command != 1 {
+		return nil, fmt.Errorf("unsupported command %d", command)
+	}
+	switch family {
+	case 0x11: // TCP over IPv4
+		if len(payload) < 12 {
+			return nil, fmt.Errorf("address too short")
+		}
+		return &net.TCPAddr{IP: net.IP(payload[:4]), Port: int(binary.BigEndian.Uint16(payload[8:]))}, nil
+	case 0x21: // TCP over IPv6
+		if len(payload) < 36 {
+			return nil, fmt.Errorf("address too short")
+		}
+		return &net.TCPAddr{IP: net.IP(payload[:16]), Port: int(binary.BigEndian.Uint16(payload[32:]))}, nil
+	}
+	return nil, nil
+}
@@ -0,0 +1,155 @@
+package proxyproto
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/binary"
+	"io/ioutil"
+	"net"
+	"strings"
+	"testing"
+	"time"
+)
+
+// v2Header build a version 2 header with the command, the address family and the payload
+func v2Header(command, family byte, payload []byte) []byte {
+	header := append([]byte(nil), v2Signature...)
+	header = append(header, 0x20|command, family, 0, 0)
+	binary.BigEndian.PutUint16(header[14:], uint16(len(payload)))
+	return append(header, payload...)
+}
+
+// v2Addr build the addresses of TCP in the payload, the destination is always port 443 of the zero ip
+func v2Addr(ip string, port uint16) []byte {
+	src := net.ParseIP(ip)
+	if v4 := src.To4(); v4 != nil {
+		src = v4
+	}
+	payload := append(append([]byte(nil), src...), make([]byte, len(src))...)
+	payload = append(payload, byte(port>>8), byte(port), 1, 187)
+	return payload
+}
+
+// pipeConn return the connection accepted from a peer sending data
+func pipeConn(trusted bool, data []byte) *Conn {
+	server, client := net.Pipe()
+	go func() {
+		client.Write(data)
+		client.Close()
+	}()
+	return &Conn{Conn: server, reader: bufio.NewReader(server), trusted: trusted}
+}
+
+func TestHeader(t *testing.T) {
+	oversize := "PROXY TCP4 " + strings.Repeat("1", 100) + "\r\n"
+	cases := []struct {
+		name    string
+		trusted bool
+		data    []byte
+		addr    string // empty means the address of the peer
+		ok      bool
+	}{
+		{"v1 tcp4", true, []byte("PROXY TCP4 192.168.0.1 192.168.0.11 56324 443\r\nGET"), "192.168.0.1:56324", true},
+		{"v1 tcp6", true, []byte("PROXY TCP6 fd00:20::2 fd00:20::1 56324 443\r\nGET"), "[fd00:20::2]:56324", true},
+		{"v1 unknown", true, []byte("PROXY UNKNOWN\r\nGET"), "", true},
+		{"v1 unknown with addresses", true, []byte("PROXY UNKNOWN fd00:20::2 fd00:20::1 56324 443\r\nGET"), "", true},
+		{"v1 tcp4 with ipv6", true, []byte("PROXY TCP4 fd00:20::2 fd00:20::1 56324 443\r\nGET"), "", false},
+		{"v1 tcp6 with ipv4", true, []byte("PROXY TCP6 192.168.0.1 192.168.0.11 56324 443\r\nGET"), "", false},
+		{"v1 unknown protocol", true, []byte("PROXY UDP4 192.168.0.1 192.168.0.11 56324 443\r\nGET"), "", false},
+		{"v1 invalid ip", true, []byte("PROXY TCP4 192.168.0 192.168.0.11 56324 443\r\nGET"), "", false},
+		{"v1 invalid port", true, []byte("PROXY TCP4 192.168.0.1 192.168.0.11 65536 443\r\nGET"), "", false},
+		{"v1 missing fields", true, []byte("PROXY TCP4 192.168.0.1 192.168.0.11 56324\r\nGET"), "", false},
+		{"v1 without cr", true, []byte("PROXY TCP4 192.168.0.1 192.168.0.11 56324 443\nGET"), "", false},
+		{"v1 oversize", true, []byte(oversize + "GET"), "", false},
+		{"v1 truncated", true, []byte("PROXY TCP4 192.168.0.1"), "", false},
+		{"v2 tcp4", true, append(v2Header(1, 0x11, v2Addr("192.168.0.1", 56324)), "GET"...), "192.168.0.1:56324", true},
+		{"v2 tcp6", true, append(v2Header(1, 0x21, v2Addr("fd00:20::2", 56324)), "GET"...), "[fd00:20::2]:56324", true},
+		{"v2 tcp4 with tlvs", true, append(v2Header(1, 0x11, append(v2Addr("192.168.0.1", 56324), 0x04, 0, 1, 'x')), "GET"...), "192.168.0.1:56324", true},
+		{"v2 local", true, append(v2Header(0, 0x11, v2Addr("192.168.0.1", 56324)), "GET"...), "", true},
+		{"v2 unspecified family", true, append(v2Header(1, 0x00, nil), "GET"...), "", true},
+		{"v2 udp", true, append(v2Header(1, 0x12, v2Addr("192.168.0.1", 56324)), "GET"...), "", true},
+		{"v2 tcp4 address too short", true, append(v2Header(1, 0x11, make([]byte, 8)), "GET"...), "", false},
+		{"v2 tcp6 address too short", true, append(v2Header(1, 0x21, v2Addr("192.168.0.1", 56324)), "GET"...), "", false},
+		{"v2 truncated payload", true, v2Header(1, 0x11, v2Addr("192.168.0.1", 56324))[:20], "", false},
+		{"v2 truncated header", true, v2Header(1, 0x11, nil)[:14], "", false},
+		{"v2 unknown version", true, append(append(append([]byte(nil), v2Signature...), 0x31, 0x11, 0, 0), "GET"...), "", false},
+		{"v2 unknown command", true, append(v2Header(2, 0x11, v2Addr("192.168.0.1", 56324)), "GET"...), "", false},
+		{"no header from trusted proxy", true, []byte("GET"), "", true},
+		{"health check of trusted proxy", true, nil, "", true},
+		{"v1 from untrusted peer", false, []byte("PROXY TCP4 192.168.0.1 192.168.0.11 56324 443\r\nGET"), "", true},
+		{"v2 from untrusted peer", false, append(v2Header(1, 0x11, v2Addr("192.168.0.1", 56324)), "GET"...), "", true},
+	}
+	for _, c := range cases {
+		conn := pipeConn(c.trusted, c.data)
+		addr, proxyAddr := conn.RemoteAddr(), conn.ProxyAddr()
+		data, err := ioutil.ReadAll(conn)
+		conn.Close()
+		if !c.ok {
+			if err == nil {
+				t.Errorf("%s: the header should be rejected, read %q", c.name, data)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("%s: fail to read, %s", c.name, err.Error())
+			continue
+		}
+		if c.addr == "" {
+			if addr.String() != "pipe" || proxyAddr != nil {
+				t.Errorf("%s: got the address %s by the proxy %v, want the address of the peer", c.name, addr, proxyAddr)
+			}
+		} else if addr.String() != c.addr || proxyAddr == nil || proxyAddr.String() != "pipe" {
+			t.Errorf("%s: got the address %s by the proxy %v, want %s", c.name, addr, proxyAddr, c.addr)
+		}
+
+		want := []byte("GET")
+		if !c.trusted {
+			want = c.data // the header of a untrusted peer is not parsed
+		} else if len(c.data) == 0 {
+			want = []byte{}
+		}
+		if !bytes.Equal(data, want) {
+			t.Errorf("%s: read %q, want %q", c.name, data, want)
+		}
+	}
+}
+
+func TestHeaderTimeout(t *testing.T) {
+	defer func(d time.Duration) { HeaderTimeout = d }(HeaderTimeout)
+	HeaderTimeout = 50 * time.Millisecond
+	server, client := net.Pipe()
+	defer client.Close()
+	go client.Write([]byte("PROXY TCP4 "))
+	conn := &Conn{Conn: server, reader: bufio.NewReader(server), trusted: true}
+	if _, err := conn.Read(make([]byte, 1)); err == nil {
+		t.Error("the slow header should time out")
+	}
+}
+
+func TestListener(t *testing.T) {
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer lis.Close()
+	l := NewListener(lis, func(addr string) bool { return true })
+	go func() {
+		conn, err := net.Dial("tcp", lis.Addr().String())
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+		conn.Write([]byte("PROXY TCP4 192.168.0.1 192.168.0.11 56324 443\r\nGET"))
+	}()
+	conn, err := l.Accept()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+	if addr := conn.RemoteAddr().String(); addr != "192.168.0.1:56324" {
+		t.Errorf("the remote address is %s, want the client in the header", addr)
+	}
+	if proxy := conn.(*Conn).ProxyAddr(); proxy == nil || !strings.HasPrefix(proxy.String(), "127.0.0.1:") {
+		t.Errorf("the proxy address is %v, want the peer", proxy)
+	}
+}
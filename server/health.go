@@ -0,0 +1,83 @@
+package server
+
+import (
+	"time"
+
+	"github.com/mijia/sweb/log"
+	"google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+)
+
+// healthCheckInterval is the interval checking whether the watchers are synced
+var healthCheckInterval = time.Second
+
+const (
+	lainletServiceName      = "message.Lainlet"
+	appnameServiceName      = "message.Appname"
+	subscriptionServiceName = "message.Subscription"
+)
+
+// newHealthServer create the server of the standard grpc health checking protocol.
+// Each api service is NOT_SERVING until its watcher is synced from store, and the whole server, whose name is "",
+// the Appname and Subscription services are NOT_SERVING until all the watchers are synced. The Lainlet service is SERVING
+// until the server is stopped, then all the services are NOT_SERVING.
+func (srv *Server) newHealthServer() *health.Server {
+	hs := health.NewServer()
+	hs.SetServingStatus(lainletServiceName, healthpb.HealthCheckResponse_SERVING)
+	synced := srv.updateHealth(hs)
+	go func() {
+		ticker := time.NewTicker(healthCheckInterval)
+		defer ticker.Stop()
+		tick := ticker.C
+		if synced {
+			tick = nil
+		}
+		for {
+			select {
+			case <-srv.done:
+				srv.setNotServing(hs)
+				return
+			case <-tick:
+				if srv.updateHealth(hs) {
+					log.Infof("All the watchers are synced, the grpc server is serving")
+					tick = nil
+				}
+			}
+		}
+	}()
+	return hs
+}
+
+// setNotServing set all the services NOT_SERVING, it is called after the server was stopped.
+func (srv *Server) setNotServing(hs *health.Server) {
+	for name := range srv.apis {
+		hs.SetServingStatus(name, healthpb.HealthCheckResponse_NOT_SERVING)
+	}
+	for _, name := range []string{lainletServiceName, appnameServiceName, subscriptionServiceName, ""} {
+		hs.SetServingStatus(name, healthpb.HealthCheckResponse_NOT_SERVING)
+	}
+}
+
+// updateHealth set the status of the services by their watchers, and return whether all the watchers are synced.
+func (srv *Server) updateHealth(hs *health.Server) bool {
+	synced := make(map[string]bool, len(srv.watchers))
+	all := true
+	for name, wch := range srv.watchers {
+		synced[name] = wch.Status().Synced
+		all = all && synced[name]
+	}
+	for name, a := range srv.apis {
+		hs.SetServingStatus(name, servingStatus(synced[a.WatcherName()+"watcher"]))
+	}
+	hs.SetServingStatus(appnameServiceName, servingStatus(all))
+	hs.SetServingStatus(subscriptionServiceName, servingStatus(all))
+	hs.SetServingStatus("", servingStatus(all))
+	return all
+}
+
+func servingStatus(serving bool) healthpb.HealthCheckResponse_ServingStatus {
+	if serving {
+		return healthpb.HealthCheckResponse_SERVING
+	}
+	return healthpb.HealthCheckResponse_NOT_SERVING
+}
@@ -0,0 +1,122 @@
+package server
+
+import (
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/laincloud/lainlet/api"
+	"github.com/laincloud/lainlet/watcher"
+	"golang.org/x/net/context"
+	"google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+)
+
+// syncWatcher is a watcher synced after sync is called
+type syncWatcher struct {
+	watcher.Watcher
+	synced int32
+}
+
+func (w *syncWatcher) sync() { atomic.StoreInt32(&w.synced, 1) }
+
+func (w *syncWatcher) Status() watcher.Status {
+	return watcher.Status{Synced: atomic.LoadInt32(&w.synced) == 1}
+}
+
+// watcherAPI is a api using the watcher of the name
+type watcherAPI struct {
+	api.API
+	name string
+}
+
+func (a watcherAPI) WatcherName() string { return a.name }
+
+// expectHealth wait until the status of the services are the wanted ones
+func expectHealth(t *testing.T, hs *health.Server, want map[string]healthpb.HealthCheckResponse_ServingStatus) {
+	deadline := time.Now().Add(5 * time.Second)
+	for {
+		got := make(map[string]healthpb.HealthCheckResponse_ServingStatus, len(want))
+		matched := true
+		for name, status := range want {
+			resp, err := hs.Check(context.Background(), &healthpb.HealthCheckRequest{Service: name})
+			if err != nil {
+				t.Fatalf("check %q: %v", name, err)
+			}
+			got[name] = resp.Status
+			matched = matched && resp.Status == status
+		}
+		if matched {
+			return
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("got the status %v, want %v", got, want)
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+func TestHealth(t *testing.T) {
+	defer func(interval time.Duration) { healthCheckInterval = interval }(healthCheckInterval)
+	healthCheckInterval = 10 * time.Millisecond
+
+	config, nodes := &syncWatcher{}, &syncWatcher{}
+	srv := &Server{
+		watchers: map[string]watcher.Watcher{"configwatcher": config, "nodeswatcher": nodes},
+		apis: map[string]api.API{
+			"message.Config": watcherAPI{name: "config"},
+			"message.Nodes":  watcherAPI{name: "nodes"},
+		},
+		done: make(chan struct{}),
+	}
+	const serving, notServing = healthpb.HealthCheckResponse_SERVING, healthpb.HealthCheckResponse_NOT_SERVING
+	hs := srv.newHealthServer()
+	expectHealth(t, hs, map[string]healthpb.HealthCheckResponse_ServingStatus{
+		"": notServing, lainletServiceName: serving, appnameServiceName: notServing, subscriptionServiceName: notServing,
+		"message.Config": notServing, "message.Nodes": notServing,
+	})
+
+	// each api is serving after its watcher is synced, the others after all the watchers are synced
+	config.sync()
+	expectHealth(t, hs, map[string]healthpb.HealthCheckResponse_ServingStatus{
+		"": notServing, lainletServiceName: serving, appnameServiceName: notServing, subscriptionServiceName: notServing,
+		"message.Config": serving, "message.Nodes": notServing,
+	})
+	nodes.sync()
+	expectHealth(t, hs, map[string]healthpb.HealthCheckResponse_ServingStatus{
+		"": serving, lainletServiceName: serving, appnameServiceName: serving, subscriptionServiceName: serving,
+		"message.Config": serving, "message.Nodes": serving,
+	})
+
+	srv.Stop()
+	srv.Stop()
+	expectHealth(t, hs, map[string]healthpb.HealthCheckResponse_ServingStatus{
+		"": notServing, lainletServiceName: notServing, appnameServiceName: notServing, subscriptionServiceName: notServing,
+		"message.Config": notServing, "message.Nodes": notServing,
+	})
+}
+
+func TestHealthStoppedBeforeSynced(t *testing.T) {
+	defer func(interval time.Duration) { healthCheckInterval = interval }(healthCheckInterval)
+	healthCheckInterval = 10 * time.Millisecond
+
+	config := &syncWatcher{}
+	srv := &Server{
+		watchers: map[string]watcher.Watcher{"configwatcher": config},
+		apis:     map[string]api.API{"message.Config": watcherAPI{name: "config"}},
+		done:     make(chan struct{}),
+	}
+	const notServing = healthpb.HealthCheckResponse_NOT_SERVING
+	hs := srv.newHealthServer()
+	srv.Stop()
+	want := map[string]healthpb.HealthCheckResponse_ServingStatus{"": notServing, lainletServiceName: notServing, "message.Config": notServing}
+	expectHealth(t, hs, want)
+
+	// the status is not changed by the watchers synced after the server was stopped
+	config.sync()
+	time.Sleep(5 * healthCheckInterval)
+	expectHealth(t, hs, want)
+
+	// the health server created after the server was stopped is not serving
+	expectHealth(t, srv.newHealthServer(), want)
+}
@@ -0,0 +1,213 @@
+package server
+
+import (
+	"fmt"
+	"runtime/debug"
+	"strings"
+	"time"
+
+	"github.com/laincloud/lainlet/api"
+	"github.com/laincloud/lainlet/endpoints"
+	"github.com/laincloud/lainlet/metrics"
+	"github.com/mijia/sweb/log"
+	"golang.org/x/net/context"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+var (
+	grpcCallsCounter = metrics.NewCounter(
+		"lainlet_grpc_calls_total",
+		"Number of the finished grpc calls, code is the grpc status code.",
+		"method", "code",
+	)
+	grpcCallHistogram = metrics.NewHistogram(
+		"lainlet_grpc_call_duration_seconds",
+		"Time spent to serve the unary grpc calls.",
+		nil,
+		"method",
+	)
+	grpcPanicsCounter = metrics.NewCounter(
+		"lainlet_grpc_panics_total",
+		"Number of the grpc calls recovered from panic.",
+		"method",
+	)
+)
+
+// The interceptors of the server, in order from the outermost:
+//   recovery: turn a panic of the call into a Internal error instead of crashing lainlet
+//   logging: log each call and record the calls by method and code
+//   identity: identify the client, the endpoints get it by endpoints.RequestFromContext
+//   authorization: reject the invalid tokens, and return PermissionDenied when the api denied the client
+//   metrics: record the metrics of the apis, the same as the http server
+
+func (srv *Server) unaryInterceptors() []grpc.UnaryServerInterceptor {
+	return []grpc.UnaryServerInterceptor{
+		func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (reply interface{}, err error) {
+			defer recovery(info.FullMethod, &err)
+			return handler(ctx, req)
+		},
+		func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+			start := time.Now()
+			reply, err := handler(ctx, req)
+			grpcCallHistogram.Observe(time.Since(start).Seconds(), info.FullMethod)
+			logCall(ctx, info.FullMethod, start, err)
+			return reply, err
+		},
+		func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+			ctx, err := srv.identify(ctx)
+			if err != nil {
+				return nil, err
+			}
+			return handler(ctx, req)
+		},
+		func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+			if err := authenticated(ctx); err != nil {
+				return nil, err
+			}
+			reply, err := handler(ctx, req)
+			return reply, authorizationStatus(err)
+		},
+		func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (reply interface{}, err error) {
+			if a, ok := srv.apiOf(info.FullMethod); ok {
+				done := api.Track(a, api.ProtocolGRPC, false)
+				defer func() { done(err) }()
+			}
+			return handler(ctx, req)
+		},
+	}
+}
+
+func (srv *Server) streamInterceptors() []grpc.StreamServerInterceptor {
+	return []grpc.StreamServerInterceptor{
+		func(s interface{}, stream grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) (err error) {
+			defer recovery(info.FullMethod, &err)
+			return handler(s, stream)
+		},
+		func(s interface{}, stream grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+			start := time.Now()
+			log.Infof("grpc stream started, method=%s remote=%s", info.FullMethod, endpoints.RemoteAddr(stream.Context()))
+			err := handler(s, stream)
+			logCall(stream.Context(), info.FullMethod, start, err)
+			return err
+		},
+		func(s interface{}, stream grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+			ctx, err := srv.identify(stream.Context())
+			if err != nil {
+				return err
+			}
+			return handler(s, &serverStream{ServerStream: stream, ctx: ctx})
+		},
+		func(s interface{}, stream grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+			if err := authenticated(stream.Context()); err != nil {
+				return err
+			}
+			return authorizationStatus(handler(s, stream))
+		},
+		func(s interface{}, stream grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) (err error) {
+			if a, ok := srv.apiOf(info.FullMethod); ok {
+				done := api.Track(a, api.ProtocolGRPC, true)
+				defer func() { done(err) }()
+			}
+			return handler(s, stream)
+		},
+	}
+}
+
+// chainUnary chain the interceptors into one, the first one is the outermost
+func chainUnary(interceptors []grpc.UnaryServerInterceptor) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		for i := len(interceptors) - 1; i >= 0; i-- {
+			interceptor, next := interceptors[i], handler
+			handler = func(ctx context.Context, req interface{}) (interface{}, error) {
+				return interceptor(ctx, req, info, next)
+			}
+		}
+		return handler(ctx, req)
+	}
+}
+
+// chainStream chain the interceptors into one, the first one is the outermost
+func chainStream(interceptors []grpc.StreamServerInterceptor) grpc.StreamServerInterceptor {
+	return func(s interface{}, stream grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		for i := len(interceptors) - 1; i >= 0; i-- {
+			interceptor, next := interceptors[i], handler
+			handler = func(s interface{}, stream grpc.ServerStream) error {
+				return interceptor(s, stream, info, next)
+			}
+		}
+		return handler(s, stream)
+	}
+}
+
+// serverStream replace the context of the stream
+type serverStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (s *serverStream) Context() context.Context {
+	return s.ctx
+}
+
+// identify return the context carrying the client identified by the authorizer of the server
+func (srv *Server) identify(ctx context.Context) (context.Context, error) {
+	r, err := endpoints.Identify(ctx, srv.auth, srv.env.Peers)
+	if err != nil {
+		return nil, status.Error(codes.Unauthenticated, err.Error())
+	}
+	return endpoints.NewContext(ctx, r), nil
+}
+
+// apiOf return the api served by the method, like "/message.Containers/Get"
+func (srv *Server) apiOf(fullMethod string) (api.API, bool) {
+	service := strings.TrimPrefix(fullMethod, "/")
+	if index := strings.LastIndexByte(service, '/'); index >= 0 {
+		service = service[:index]
+	}
+	a, ok := srv.apis[service]
+	return a, ok
+}
+
+// authenticated reject the client with a invalid token
+func authenticated(ctx context.Context) error {
+	if r, ok := endpoints.RequestFromContext(ctx); ok && r.TokenErr != nil {
+		return status.Error(codes.Unauthenticated, r.TokenErr.Error())
+	}
+	return nil
+}
+
+// authorizationStatus return the PermissionDenied status if err is returned by a failed authorization
+func authorizationStatus(err error) error {
+	if api.IsAuthError(err) {
+		return status.Error(codes.PermissionDenied, err.Error())
+	}
+	return err
+}
+
+// recovery recover the panic of the call to method, and set *err to a Internal error
+func recovery(method string, err *error) {
+	if p := recover(); p != nil {
+		grpcPanicsCounter.Inc(method)
+		log.Errorf("Panic in grpc %s, %v\n%s", method, p, debug.Stack())
+		*err = status.Error(codes.Internal, fmt.Sprintf("internal error, %v", p))
+	}
+}
+
+// logCall log the finished call and record it in metrics
+func logCall(ctx context.Context, method string, start time.Time, err error) {
+	code := codes.OK
+	if err != nil {
+		code = codes.Unknown
+		if s, ok := status.FromError(err); ok {
+			code = s.Code()
+		}
+	}
+	grpcCallsCounter.Inc(method, code.String())
+	if err != nil {
+		log.Infof("grpc call finished, method=%s remote=%s code=%s duration=%s error=%q", method, endpoints.RemoteAddr(ctx), code, time.Since(start), err.Error())
+	} else {
+		log.Infof("grpc call finished, method=%s remote=%s code=%s duration=%s", method, endpoints.RemoteAddr(ctx), code, time.Since(start))
+	}
+}
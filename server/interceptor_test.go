@@ -0,0 +1,234 @@
+package server
+
+import (
+	"bytes"
+	"fmt"
+	"net"
+	"reflect"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/laincloud/lainlet/api"
+	"github.com/laincloud/lainlet/auth"
+	"github.com/laincloud/lainlet/conf"
+	"github.com/laincloud/lainlet/endpoints"
+	"github.com/laincloud/lainlet/metrics"
+	"golang.org/x/net/context"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+)
+
+// tokenAuth accept only the token "good" of the app hello, and allow everything
+type tokenAuth struct{}
+
+func (tokenAuth) IPSubject(remoteAddr string) auth.Subject { return auth.Subject{Addr: remoteAddr} }
+func (tokenAuth) AppName(remoteIP string) (string, error)  { return "", fmt.Errorf("unknown address") }
+func (tokenAuth) VerifyToken(token string) (*auth.Claims, error) {
+	if token != "good" {
+		return nil, fmt.Errorf("authorize failed, invalid token")
+	}
+	return &auth.Claims{App: "hello"}, nil
+}
+func (tokenAuth) Evaluate(s auth.Subject, api, key string) auth.Decision {
+	return auth.Decision{Allowed: true, Rule: -1}
+}
+func (a tokenAuth) Explain(s auth.Subject, api, key string) *auth.Explanation {
+	return &auth.Explanation{Subject: s, API: api, Key: key, Decision: a.Evaluate(s, api, key)}
+}
+
+func newTestServer() *Server {
+	return &Server{auth: tokenAuth{}, env: api.NewEnv(conf.Default()), apis: make(map[string]api.API)}
+}
+
+// peerContext return the context of a call from addr, with the authorization metadata if token is not empty
+func peerContext(addr, token string) context.Context {
+	tcpAddr, _ := net.ResolveTCPAddr("tcp", addr)
+	ctx := peer.NewContext(context.Background(), &peer.Peer{Addr: tcpAddr})
+	if token != "" {
+		ctx = metadata.NewIncomingContext(ctx, metadata.Pairs("authorization", "Bearer "+token))
+	}
+	return ctx
+}
+
+// testStream is a server stream of the context
+type testStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (s *testStream) Context() context.Context { return s.ctx }
+
+// counted return the value of the counter of the label values in the exported metrics, 0 if it is not exported
+func counted(t *testing.T, name string, labels string) float64 {
+	var buf bytes.Buffer
+	if err := metrics.WriteTo(&buf); err != nil {
+		t.Fatal(err)
+	}
+	prefix := name + "{" + labels + "} "
+	for _, line := range strings.Split(buf.String(), "\n") {
+		if strings.HasPrefix(line, prefix) {
+			v, err := strconv.ParseFloat(line[len(prefix):], 64)
+			if err != nil {
+				t.Fatal(err)
+			}
+			return v
+		}
+	}
+	return 0
+}
+
+func expectCode(t *testing.T, what string, err error, code codes.Code, msg string) {
+	s, ok := status.FromError(err)
+	if !ok || s.Code() != code || !strings.Contains(s.Message(), msg) {
+		t.Errorf("%s returned %v, want %s with %q", what, err, code, msg)
+	}
+}
+
+func TestUnaryInterceptors(t *testing.T) {
+	srv := newTestServer()
+	interceptor := chainUnary(srv.unaryInterceptors())
+	call := func(ctx context.Context, method string, handler grpc.UnaryHandler) (interface{}, error) {
+		return interceptor(ctx, "request", &grpc.UnaryServerInfo{FullMethod: method}, handler)
+	}
+
+	// the panic is recovered as a Internal error and counted
+	method := "/test.Interceptor/Panic"
+	panics := counted(t, "lainlet_grpc_panics_total", `method="`+method+`"`)
+	_, err := call(peerContext("172.20.0.2:1234", ""), method, func(ctx context.Context, req interface{}) (interface{}, error) {
+		panic("boom")
+	})
+	expectCode(t, "the panicking handler", err, codes.Internal, "internal error, boom")
+	if n := counted(t, "lainlet_grpc_panics_total", `method="`+method+`"`); n != panics+1 {
+		t.Errorf("got %v panics of %s, want %v", n, method, panics+1)
+	}
+
+	// the handler get the identified client, and the reply of the handler is returned
+	method = "/test.Interceptor/Get"
+	calls := counted(t, "lainlet_grpc_calls_total", `method="`+method+`",code="OK"`)
+	reply, err := call(peerContext("172.20.0.2:1234", "good"), method, func(ctx context.Context, req interface{}) (interface{}, error) {
+		r, ok := endpoints.RequestFromContext(ctx)
+		if !ok || r.RemoteAddr != "172.20.0.2:1234" || r.Protocol != api.ProtocolGRPC || r.Token == nil || r.Token.App != "hello" {
+			t.Errorf("the handler got the request %+v", r)
+		}
+		return "reply of " + req.(string), nil
+	})
+	if err != nil || reply != "reply of request" {
+		t.Errorf("got the reply %v, %v", reply, err)
+	}
+	if n := counted(t, "lainlet_grpc_calls_total", `method="`+method+`",code="OK"`); n != calls+1 {
+		t.Errorf("got %v calls of %s, want %v", n, method, calls+1)
+	}
+
+	// the calls with a bad token or without a peer are not handled
+	notCalled := func(ctx context.Context, req interface{}) (interface{}, error) {
+		t.Error("the handler should not be called")
+		return nil, nil
+	}
+	_, err = call(peerContext("172.20.0.2:1234", "bad"), method, notCalled)
+	expectCode(t, "the call with a bad token", err, codes.Unauthenticated, "invalid token")
+	_, err = call(context.Background(), method, notCalled)
+	expectCode(t, "the call without a peer", err, codes.Unauthenticated, "failed to get peer")
+
+	// the authorization errors of the apis are PermissionDenied, the others are returned as they are
+	_, err = call(peerContext("172.20.0.2:1234", ""), method, func(ctx context.Context, req interface{}) (interface{}, error) {
+		return nil, fmt.Errorf("authorize failed, no permission")
+	})
+	expectCode(t, "the denied call", err, codes.PermissionDenied, "authorize failed, no permission")
+	_, err = call(peerContext("172.20.0.2:1234", ""), method, func(ctx context.Context, req interface{}) (interface{}, error) {
+		return nil, status.Error(codes.NotFound, "not found")
+	})
+	expectCode(t, "the failed call", err, codes.NotFound, "not found")
+}
+
+func TestStreamInterceptors(t *testing.T) {
+	srv := newTestServer()
+	interceptor := chainStream(srv.streamInterceptors())
+	call := func(ctx context.Context, method string, handler grpc.StreamHandler) error {
+		return interceptor("request", &testStream{ctx: ctx}, &grpc.StreamServerInfo{FullMethod: method, IsServerStream: true}, handler)
+	}
+
+	method := "/test.Interceptor/PanicWatch"
+	panics := counted(t, "lainlet_grpc_panics_total", `method="`+method+`"`)
+	err := call(peerContext("172.20.0.2:1234", ""), method, func(s interface{}, stream grpc.ServerStream) error {
+		panic("boom")
+	})
+	expectCode(t, "the panicking stream", err, codes.Internal, "internal error, boom")
+	if n := counted(t, "lainlet_grpc_panics_total", `method="`+method+`"`); n != panics+1 {
+		t.Errorf("got %v panics of %s, want %v", n, method, panics+1)
+	}
+
+	// the context of the stream carry the identified client
+	method = "/test.Interceptor/Watch"
+	err = call(peerContext("[fd00::2]:1234", ""), method, func(s interface{}, stream grpc.ServerStream) error {
+		if r, ok := endpoints.RequestFromContext(stream.Context()); !ok || r.RemoteAddr != "[fd00::2]:1234" {
+			t.Errorf("the stream got the request %+v", r)
+		}
+		return nil
+	})
+	if err != nil {
+		t.Errorf("the stream returned %v", err)
+	}
+
+	err = call(peerContext("172.20.0.2:1234", "bad"), method, func(s interface{}, stream grpc.ServerStream) error {
+		t.Error("the handler should not be called")
+		return nil
+	})
+	expectCode(t, "the stream with a bad token", err, codes.Unauthenticated, "invalid token")
+	err = call(peerContext("172.20.0.2:1234", ""), method, func(s interface{}, stream grpc.ServerStream) error {
+		return fmt.Errorf("authorize failed, no permission")
+	})
+	expectCode(t, "the denied stream", err, codes.PermissionDenied, "authorize failed, no permission")
+}
+
+func TestChainOrder(t *testing.T) {
+	var calls []string
+	unary := func(name string) grpc.UnaryServerInterceptor {
+		return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+			calls = append(calls, name+" "+req.(string))
+			reply, err := handler(ctx, req.(string)+name)
+			calls = append(calls, name+" done")
+			return reply, err
+		}
+	}
+	reply, err := chainUnary([]grpc.UnaryServerInterceptor{unary("a"), unary("b"), unary("c")})(context.Background(), "", nil, func(ctx context.Context, req interface{}) (interface{}, error) {
+		calls = append(calls, "handler "+req.(string))
+		return "reply", nil
+	})
+	want := []string{"a ", "b a", "c ab", "handler abc", "c done", "b done", "a done"}
+	if err != nil || reply != "reply" || !reflect.DeepEqual(calls, want) {
+		t.Errorf("the unary interceptors are called in %q, want %q", calls, want)
+	}
+
+	// the stream replaced by a interceptor is passed to the later ones
+	calls = nil
+	type key struct{}
+	stream := func(name string) grpc.StreamServerInterceptor {
+		return func(s interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+			prev, _ := ss.Context().Value(key{}).(string)
+			calls = append(calls, name+" "+prev)
+			err := handler(s, &testStream{ctx: context.WithValue(ss.Context(), key{}, prev+name)})
+			calls = append(calls, name+" done")
+			return err
+		}
+	}
+	err = chainStream([]grpc.StreamServerInterceptor{stream("a"), stream("b"), stream("c")})(nil, &testStream{ctx: context.Background()}, nil, func(s interface{}, ss grpc.ServerStream) error {
+		calls = append(calls, "handler "+ss.Context().Value(key{}).(string))
+		return nil
+	})
+	if err != nil || !reflect.DeepEqual(calls, want) {
+		t.Errorf("the stream interceptors are called in %q, want %q", calls, want)
+	}
+
+	// the chain can be called again
+	calls = nil
+	chainUnary([]grpc.UnaryServerInterceptor{unary("a")})(context.Background(), "", nil, func(ctx context.Context, req interface{}) (interface{}, error) {
+		return nil, nil
+	})
+	if len(calls) != 2 {
+		t.Errorf("got the calls %q", calls)
+	}
+}
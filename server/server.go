@@ -3,13 +3,21 @@ package server
 import (
 	"fmt"
 	"net"
+	"sync"
 
+	"github.com/laincloud/lainlet/api"
+	"github.com/laincloud/lainlet/auth"
+	"github.com/laincloud/lainlet/conf"
 	"github.com/laincloud/lainlet/endpoints"
 	pb "github.com/laincloud/lainlet/message"
 	"github.com/laincloud/lainlet/watcher"
 	"github.com/mijia/sweb/log"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/keepalive"
+	"google.golang.org/grpc/reflection"
 )
 
 type Config struct {
@@ -24,13 +32,19 @@ type Server struct {
 	localIp string
 	cfg     *Config
 
-	watchers map[string]watcher.Watcher
+	watchers  map[string]watcher.Watcher
+	endpoints []*endpoints.APIEndpoint
+	apis      map[string]api.API // the apis by the grpc service name
+	auth      auth.Authorizer
+	env       *api.Env // the configuration, the limits and the watch connections, shared with the http server
 
-	configWatcher    watcher.Watcher
-	containerWatcher watcher.Watcher
-	podgroupWatcher  watcher.Watcher
-	dependsWatcher   watcher.Watcher
-	nodesWatcher     watcher.Watcher
+	healthOnce sync.Once
+	health     *health.Server // shared by the grpc servers on the tcp address and the unix socket
+
+	serversLock sync.Mutex
+	servers     []*grpc.Server // the grpc servers created, stopped by Stop
+	stopped     bool
+	done        chan struct{} // closed by Stop
 }
 
 func NewConfig(addr, key, cert string) *Config {
@@ -41,7 +55,8 @@ func NewConfig(addr, key, cert string) *Config {
 	}
 }
 
-func New(addr string, ip string, watchers map[string]watcher.Watcher, cfg *Config) (*Server, error) {
+// New create a grpc server, the requests are authorized by a, and env is the state shared with the http server.
+func New(addr string, ip string, watchers map[string]watcher.Watcher, cfg *Config, a auth.Authorizer, env *api.Env) (*Server, error) {
 	if cfg != nil {
 		if cfg.keyFile == "" || cfg.certFile == "" {
 			return nil, fmt.Errorf("keyfile or certfile can't be empty when TLS is enabled.")
@@ -53,16 +68,30 @@ func New(addr string, ip string, watchers map[string]watcher.Watcher, cfg *Confi
 		cfg:     cfg,
 
 		watchers: watchers,
-
-		configWatcher:    watchers["configwatcher"],
-		containerWatcher: watchers["containerwatcher"],
-		podgroupWatcher:  watchers["podgroupwatcher"],
-		dependsWatcher:   watchers["dependswatcher"],
-		nodesWatcher:     watchers["nodeswatcher"],
+		apis:     make(map[string]api.API),
+		auth:     a,
+		env:      env,
+		done:     make(chan struct{}),
 	}
 	return srv, nil
 }
 
+// Register a api, it will be served as a grpc service when the server running.
+func (srv *Server) Register(a api.API) {
+	wch, ok := srv.watchers[a.WatcherName()+"watcher"]
+	if !ok {
+		panic("unknown watcher " + a.WatcherName())
+	}
+	ed, err := endpoints.NewAPIEndpoint(a, wch, srv.env)
+	if err != nil {
+		panic(err)
+	}
+	name := ed.ServiceDesc().ServiceName
+	log.Infof("New grpc service, name=%s", name)
+	srv.endpoints = append(srv.endpoints, ed)
+	srv.apis[name] = a
+}
+
 func (srv *Server) Run() {
 	lis, err := net.Listen("tcp", srv.addr)
 	if err != nil {
@@ -76,25 +105,79 @@ func (srv *Server) Run() {
 		}
 		opts = []grpc.ServerOption{grpc.Creds(creds)}
 	}
+	srv.newGRPCServer(opts...).Serve(lis)
+}
+
+// Serve serve the apis on the listener without TLS, like a unix socket listener, it blocks until the listener fail.
+func (srv *Server) Serve(lis net.Listener) error {
+	log.Infof("grpc listening on %s", lis.Addr())
+	return srv.newGRPCServer().Serve(lis)
+}
+
+// newGRPCServer create a grpc server serving all the apis, the calls are intercepted by the interceptors in interceptor.go
+func (srv *Server) newGRPCServer(opts ...grpc.ServerOption) *grpc.Server {
+	opts = append(opts,
+		grpc.UnaryInterceptor(chainUnary(srv.unaryInterceptors())),
+		grpc.StreamInterceptor(chainStream(srv.streamInterceptors())),
+	)
+	opts = append(opts, connOptions(srv.env.Config().GRPC)...)
 	grpcServer := grpc.NewServer(opts...)
 
 	pb.RegisterAppnameServer(grpcServer, endpoints.NewAppnameEndpoint())
 	pb.RegisterLainletServer(grpcServer, endpoints.NewLainletEndpoint(srv.watchers))
+	pb.RegisterSubscriptionServer(grpcServer, endpoints.NewSubscriptionEndpoint(srv.endpoints, srv.env))
+
+	for _, ed := range srv.endpoints {
+		grpcServer.RegisterService(ed.ServiceDesc(), ed)
+	}
+
+	srv.healthOnce.Do(func() { srv.health = srv.newHealthServer() })
+	healthpb.RegisterHealthServer(grpcServer, srv.health)
+	reflection.Register(grpcServer)
+
+	srv.serversLock.Lock()
+	defer srv.serversLock.Unlock()
+	if srv.stopped { // Serve returns at once after Stop
+		grpcServer.Stop()
+	}
+	srv.servers = append(srv.servers, grpcServer)
+	return grpcServer
+}
+
+// Stop stop the grpc servers, the listeners and the connections are closed, and the pending calls are canceled.
+func (srv *Server) Stop() {
+	srv.serversLock.Lock()
+	servers := srv.servers
+	if !srv.stopped {
+		close(srv.done)
+	}
+	srv.servers, srv.stopped = nil, true
+	srv.serversLock.Unlock()
+	for _, s := range servers {
+		s.Stop()
+	}
+}
 
-	pb.RegisterAppsServer(grpcServer, endpoints.NewAppsEndpoint(srv.podgroupWatcher))
-	pb.RegisterBackupctlServer(grpcServer, endpoints.NewBackupctlEndpoint(srv.podgroupWatcher))
-	pb.RegisterConfigServer(grpcServer, endpoints.NewConfigEndpoint(srv.configWatcher))
-	pb.RegisterContainersServer(grpcServer, endpoints.NewContainersEndpoint(srv.containerWatcher))
-	pb.RegisterCoreinfoServer(grpcServer, endpoints.NewCoreinfoEndpoint(srv.podgroupWatcher))
-	pb.RegisterDependsServer(grpcServer, endpoints.NewDependsEndpoint(srv.dependsWatcher))
-	pb.RegisterLocalspecServer(grpcServer, endpoints.NewLocalspecEndpoint(srv.containerWatcher, srv.localIp))
-	pb.RegisterNodesServer(grpcServer, endpoints.NewNodesEndpoint(srv.nodesWatcher))
-	pb.RegisterPodgroupServer(grpcServer, endpoints.NewPodgroupEndpoint(srv.podgroupWatcher))
-	pb.RegisterProxyServer(grpcServer, endpoints.NewProxyEndpoint(srv.podgroupWatcher))
-	pb.RegisterRebellionLocalprocsServer(grpcServer, endpoints.NewRebellionLocalprocsEndpoint(srv.podgroupWatcher))
-	pb.RegisterStreamrouterPortsServer(grpcServer, endpoints.NewStreamrouterPortsEndpoint(srv.podgroupWatcher))
-	pb.RegisterStreamrouterStreamprocsServer(grpcServer, endpoints.NewStreamrouterStreamprocsEndpoint(srv.podgroupWatcher))
-	pb.RegisterWebrouterWebprocsServer(grpcServer, endpoints.NewWebrouterWebprocsEndpoint(srv.podgroupWatcher))
-
-	grpcServer.Serve(lis)
+// connOptions return the options of the keepalive, connection age and message size in the configuration
+func connOptions(cfg conf.GRPCConfig) []grpc.ServerOption {
+	opts := []grpc.ServerOption{
+		grpc.KeepaliveParams(keepalive.ServerParameters{
+			Time:                  cfg.KeepaliveTime.D(),
+			Timeout:               cfg.KeepaliveTimeout.D(),
+			MaxConnectionAge:      cfg.MaxConnectionAge.D(),
+			MaxConnectionAgeGrace: cfg.MaxConnectionAgeGrace.D(),
+		}),
+		// the clients may ping when there is no stream, to keep the connection through the NATs
+		grpc.KeepaliveEnforcementPolicy(keepalive.EnforcementPolicy{
+			MinTime:             cfg.KeepaliveMinTime.D(),
+			PermitWithoutStream: true,
+		}),
+	}
+	if cfg.MaxRecvMsgSize > 0 {
+		opts = append(opts, grpc.MaxRecvMsgSize(cfg.MaxRecvMsgSize))
+	}
+	if cfg.MaxSendMsgSize > 0 {
+		opts = append(opts, grpc.MaxSendMsgSize(cfg.MaxSendMsgSize))
+	}
+	return opts
 }
@@ -131,8 +131,30 @@ type BaseRuntime struct {
 
 type HealthState int
 
+// the health states, the same as deployd
+const (
+	HealthStateNone HealthState = iota
+	HealthStateStarting
+	HealthStateHealthy
+	HealthStateUnHealthy
+)
+
 type RunState int
 
+// the run states, the same as deployd
+const (
+	RunStatePending RunState = iota
+	RunStateDrift
+	RunStateSuccess
+	RunStateExit
+	RunStateFail
+	RunStateInconsistent
+	RunStateMissing
+	RunStateRemoved
+	RunStatePaused
+	RunStateError
+)
+
 type ExpectState int
 
 type SharedPodWithSpec struct {
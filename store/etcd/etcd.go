@@ -3,6 +3,7 @@ package etcd
 import (
 	"crypto/tls"
 	etcd "github.com/coreos/etcd/client"
+	"github.com/laincloud/lainlet/metrics"
 	"github.com/laincloud/lainlet/store"
 	"golang.org/x/net/context"
 	"log"
@@ -30,6 +31,17 @@ var (
 		"expire":           store.DELETE,
 		"compareAndDelete": store.DELETE,
 	}
+
+	requestErrorsCounter = metrics.NewCounter(
+		"lainlet_etcd_request_errors_total",
+		"Number of the failed requests to etcd, key not found is not counted.",
+		"op",
+	)
+	watchErrorsCounter = metrics.NewCounter(
+		"lainlet_etcd_watch_errors_total",
+		"Number of the etcd watches stopped by error.",
+		"op",
+	)
 )
 
 func init() {
@@ -129,6 +141,7 @@ func (s *Etcd) Get(key string) (pair *store.KVPair, err error) {
 		if keyNotFound(err) {
 			return nil, store.ErrKeyNotFound
 		}
+		requestErrorsCounter.Inc("get")
 		return nil, err
 	}
 
@@ -152,6 +165,7 @@ func (s *Etcd) List(dir string) ([]string, error) {
 		if keyNotFound(err) {
 			return nil, store.ErrKeyNotFound
 		}
+		requestErrorsCounter.Inc("list")
 		return nil, err
 	}
 
@@ -182,6 +196,9 @@ func (s *Etcd) Watch(key string, ctx context.Context, recursive bool, index uint
 			result, err := watcher.Next(ctx)
 
 			if err != nil {
+				if ctx.Err() == nil { // canceled by the caller is not a error
+					watchErrorsCounter.Inc("watch")
+				}
 				// Push error value through the channel.
 				watchCh <- errorEvent(key, err)
 				return
@@ -221,6 +238,9 @@ func (s *Etcd) WatchTree(directory string, ctx context.Context, index uint64) (<
 			resp, err := watcher.Next(ctx)
 
 			if err != nil {
+				if ctx.Err() == nil { // canceled by the caller is not a error
+					watchErrorsCounter.Inc("watchtree")
+				}
 				watchCh <- errorEvent(directory, err)
 				return
 			}
@@ -269,6 +289,7 @@ func (s *Etcd) GetTree(directory string) ([]*store.KVPair, error) {
 		if keyNotFound(err) {
 			return nil, store.ErrKeyNotFound
 		}
+		requestErrorsCounter.Inc("gettree")
 		return nil, err
 	}
 	if !resp.Node.Dir { // it is a key, not a directory
@@ -288,6 +309,9 @@ func (s *Etcd) GetTree(directory string) ([]*store.KVPair, error) {
 func (s *Etcd) Put(key string, value []byte) error {
 	setOpts := &etcd.SetOptions{}
 	_, err := s.client.Set(context.Background(), s.normalize(key), string(value), setOpts)
+	if err != nil {
+		requestErrorsCounter.Inc("put")
+	}
 	return err
 }
 
@@ -301,6 +325,9 @@ func (s *Etcd) Delete(key string, recursive bool) error {
 	if keyNotFound(err) {
 		return store.ErrKeyNotFound
 	}
+	if err != nil {
+		requestErrorsCounter.Inc("delete")
+	}
 	return err
 }
 
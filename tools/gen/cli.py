@@ -13,9 +13,14 @@ from string import Template
 code_tpl = """
 //CODE GENERATION ${name} START
 func (cli *Client) ${name}Get(${param_str}) (*pb.${name}Reply, error) {
-	req := &pb.${name}Request{${req_init}}
 	ctx, cancel := context.WithTimeout(context.Background(), cli.timeout)
 	defer cancel()
+	return cli.${name}GetContext(ctx${param_args})
+}
+
+// ${name}GetContext is ${name}Get with a context, the timeout of the client is not applied.
+func (cli *Client) ${name}GetContext(ctx context.Context${param_sep}${param_str}) (*pb.${name}Reply, error) {
+	req := &pb.${name}Request{${req_init}}
 	rpl, err := cli.${client}.Get(ctx, req)
 	return rpl, err
 }
@@ -34,8 +39,13 @@ func (wch *${name}Watcher) Next() (*pb.${name}Reply, error) {
 	return rpl, err
 }
 func (cli *Client) ${name}Watch(${param_str}) (*${name}Watcher, error) {
+	return cli.${name}WatchContext(context.Background()${param_args})
+}
+
+// ${name}WatchContext is ${name}Watch with a context, the stream is closed when ctx is done.
+func (cli *Client) ${name}WatchContext(ctx context.Context${param_sep}${param_str}) (*${name}Watcher, error) {
 	req := &pb.${name}Request{${req_init}}
-	stream, err := cli.${client}.Watch(context.Background(), req)
+	stream, err := cli.${client}.Watch(ctx, req)
 	if err != nil {
 		return nil, err
 	}
@@ -57,6 +67,39 @@ func (cli *Client) ${name}Watch(${param_str}) (*${name}Watcher, error) {
 	}()
 	return wch, nil
 }
+
+// ${name}ReconnectWatcher is the watcher reconnecting when the stream was broken, see ReconnectWatcher.
+type ${name}ReconnectWatcher struct {
+	*ReconnectWatcher
+}
+
+// Next return the next reply, the error is not nil only when the watcher stopped.
+func (wch *${name}ReconnectWatcher) Next() (*pb.${name}Reply, error) {
+	rpl, err := wch.next()
+	if err != nil {
+		return nil, err
+	}
+	return rpl.(*pb.${name}Reply), nil
+}
+
+// ${name}WatchReconnect watch like ${name}Watch, and reconnect with backoff until ctx is done, opts can be nil.
+func (cli *Client) ${name}WatchReconnect(ctx context.Context${param_sep}${param_str}, opts *ReconnectOptions) *${name}ReconnectWatcher {
+	req := &pb.${name}Request{${req_init}}
+	return &${name}ReconnectWatcher{cli.newReconnectWatcher(ctx, opts, func(ctx context.Context) (func() (proto.Message, error), error) {
+		stream, err := cli.${client}.Watch(ctx, req)
+		if err != nil {
+			return nil, err
+		}
+		return func() (proto.Message, error) { return stream.Recv() }, nil
+	})}
+}
+
+// ${name}Informer create the informer caching the Data of the ${name}Reply, all the items are resynced every resync period if it is positive.
+func (cli *Client) ${name}Informer(${param_str}${param_sep}resync time.Duration) *Informer {
+	return newInformer(resync, func(ctx context.Context) *ReconnectWatcher {
+		return cli.${name}WatchReconnect(ctx${param_args}, nil).ReconnectWatcher
+	})
+}
 //CODE GENERATION ${name} END
 """
 
@@ -71,9 +114,13 @@ def gen_client():
     if len(sys.argv) == 3:
         param = sys.argv[2]
     param_str = ""
+    param_args = ""
+    param_sep = ""
     req_init = ""
     if param is not None:
         param_str = "%s string" % param
+        param_args = ", %s" % param
+        param_sep = ", "
         req_init = "%s: %s" % (param.capitalize(), param)
     fname = os.getenv("GOFILE")
     print("generate client for ", name)
@@ -82,6 +129,8 @@ def gen_client():
         d = {
             "name": name,
             "param_str": param_str,
+            "param_args": param_args,
+            "param_sep": param_sep,
             "req_init": req_init,
             "client": firstCharLower(name) + "Client",
         }
@@ -0,0 +1,62 @@
+// Command token mint a bearer token for the clients outside the container network, like:
+//
+//	token -key /etc/lainlet/token.key -app webrouter -scopes app:hello,app:world -ttl 720h
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/laincloud/lainlet/auth"
+)
+
+func main() {
+	var (
+		keyFile, app, scopes string
+		ttl                  time.Duration
+	)
+	flag.StringVar(&keyFile, "key", "", "The file of the token key, the same as the token_key_file of lainlet")
+	flag.StringVar(&app, "app", "", "The app the token holder acting as")
+	flag.StringVar(&scopes, "scopes", "", `The additional grants separated by comma, "super" or "app:<appname>"`)
+	flag.DurationVar(&ttl, "ttl", 24*time.Hour, "The lifetime of the token, 0 means never expire")
+	flag.Parse()
+
+	if keyFile == "" || app == "" {
+		flag.Usage()
+		os.Exit(1)
+	}
+	key, err := auth.ReadTokenKey(keyFile)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+
+	now := time.Now()
+	claims := &auth.Claims{
+		App:      app,
+		IssuedAt: now.Unix(),
+	}
+	if ttl > 0 {
+		claims.ExpiresAt = now.Add(ttl).Unix()
+	}
+	for _, scope := range strings.Split(scopes, ",") {
+		if scope = strings.TrimSpace(scope); scope == "" {
+			continue
+		}
+		if scope != auth.ScopeSuper && !strings.HasPrefix(scope, auth.ScopeAppPrefix) {
+			fmt.Fprintf(os.Stderr, "unknown scope %s\n", scope)
+			os.Exit(1)
+		}
+		claims.Scopes = append(claims.Scopes, scope)
+	}
+
+	token, err := auth.SignToken(key, claims)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+	fmt.Println(token)
+}
@@ -0,0 +1,29 @@
+// +build linux
+
+package unixsock
+
+import (
+	"net"
+	"syscall"
+)
+
+// peerCred read the credential of the peer process by SO_PEERCRED
+func peerCred(conn *net.UnixConn) (*Cred, error) {
+	raw, err := conn.SyscallConn()
+	if err != nil {
+		return nil, err
+	}
+	var (
+		ucred *syscall.Ucred
+		uerr  error
+	)
+	if err := raw.Control(func(fd uintptr) {
+		ucred, uerr = syscall.GetsockoptUcred(int(fd), syscall.SOL_SOCKET, syscall.SO_PEERCRED)
+	}); err != nil {
+		return nil, err
+	}
+	if uerr != nil {
+		return nil, uerr
+	}
+	return &Cred{PID: ucred.Pid, UID: ucred.Uid, GID: ucred.Gid}, nil
+}
@@ -0,0 +1,13 @@
+// +build !linux
+
+package unixsock
+
+import (
+	"fmt"
+	"net"
+)
+
+// peerCred is only supported on linux, the peers are never trusted on other systems
+func peerCred(conn *net.UnixConn) (*Cred, error) {
+	return nil, fmt.Errorf("SO_PEERCRED is not supported")
+}
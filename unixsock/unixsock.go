@@ -0,0 +1,171 @@
+// Package unixsock implements the unix socket listener of lainlet, which identify the peer processes by SO_PEERCRED.
+// The connections accepted have a unique remote address, by which the servers can look up the peer.
+package unixsock
+
+import (
+	"fmt"
+	"net"
+	"os"
+	"sync"
+	"sync/atomic"
+
+	"github.com/mijia/sweb/log"
+)
+
+// Cred is the credential of the peer process
+type Cred struct {
+	PID int32  `json:"pid"`
+	UID uint32 `json:"uid"`
+	GID uint32 `json:"gid"`
+}
+
+// Policy decide which peers are trusted, a peer is trusted if its uid or gid is in the lists.
+type Policy struct {
+	UIDs []uint32
+	GIDs []uint32
+}
+
+// Trusted check if the peer having the credential is trusted, cred can be nil, which is never trusted.
+func (p Policy) Trusted(cred *Cred) bool {
+	if cred == nil {
+		return false
+	}
+	for _, uid := range p.UIDs {
+		if uid == cred.UID {
+			return true
+		}
+	}
+	for _, gid := range p.GIDs {
+		if gid == cred.GID {
+			return true
+		}
+	}
+	return false
+}
+
+// Peer is the peer of a unix socket connection
+type Peer struct {
+	Path    string // the path of the socket
+	Cred    *Cred  // nil if the credential can not be read
+	Trusted bool   // whether the peer is trusted by the policy of the listener
+}
+
+// Peers is the table of the peers of the connections accepted by the listeners sharing it, by their remote addresses.
+type Peers struct {
+	lastID uint64 // the first field is 64-bit aligned for the atomic operations
+	lock   sync.RWMutex
+	peers  map[string]*Peer
+}
+
+// NewPeers create a empty peer table
+func NewPeers() *Peers {
+	return &Peers{peers: make(map[string]*Peer)}
+}
+
+// Lookup return the peer of the connection by its remote address, nil if it is not a unix socket connection accepted by the listeners.
+// The table can be nil, which has no peers.
+func (p *Peers) Lookup(remoteAddr string) *Peer {
+	if p == nil {
+		return nil
+	}
+	p.lock.RLock()
+	defer p.lock.RUnlock()
+	return p.peers[remoteAddr]
+}
+
+// Listener is a unix socket listener, each connection accepted records its peer in the peer table until it was closed.
+type Listener struct {
+	*net.UnixListener
+	path   string
+	policy Policy
+	peers  *Peers
+}
+
+// Listen listen on the unix socket path, the stale socket file is removed, and the socket file mode is set to mode.
+// The peers of the connections are recorded in peers.
+func Listen(path string, mode os.FileMode, policy Policy, peers *Peers) (*Listener, error) {
+	if fi, err := os.Stat(path); err == nil && fi.Mode()&os.ModeSocket != 0 {
+		if err := os.Remove(path); err != nil {
+			return nil, err
+		}
+	}
+	l, err := net.ListenUnix("unix", &net.UnixAddr{Name: path, Net: "unix"})
+	if err != nil {
+		return nil, err
+	}
+	if err := os.Chmod(path, mode); err != nil {
+		l.Close()
+		return nil, err
+	}
+	return &Listener{
+		UnixListener: l,
+		path:         path,
+		policy:       policy,
+		peers:        peers,
+	}, nil
+}
+
+// Accept implements net.Listener
+func (l *Listener) Accept() (net.Conn, error) {
+	conn, err := l.AcceptUnix()
+	if err != nil {
+		return nil, err
+	}
+	peer := &Peer{Path: l.path}
+	if peer.Cred, err = peerCred(conn); err != nil {
+		log.Warnf("Fail to get the credential of the peer on %s, %s", l.path, err.Error())
+	}
+	peer.Trusted = l.policy.Trusted(peer.Cred)
+
+	c := &Conn{
+		UnixConn: conn,
+		addr:     &Addr{id: atomic.AddUint64(&l.peers.lastID, 1), peer: peer},
+		peers:    l.peers,
+	}
+	l.peers.lock.Lock()
+	l.peers.peers[c.addr.String()] = peer
+	l.peers.lock.Unlock()
+	return c, nil
+}
+
+// Conn is a connection accepted by Listener
+type Conn struct {
+	*net.UnixConn
+	addr  *Addr
+	peers *Peers
+	close sync.Once
+}
+
+// RemoteAddr return the unique address of the connection
+func (c *Conn) RemoteAddr() net.Addr {
+	return c.addr
+}
+
+// Close close the connection and forget its peer
+func (c *Conn) Close() error {
+	c.close.Do(func() {
+		c.peers.lock.Lock()
+		delete(c.peers.peers, c.addr.String())
+		c.peers.lock.Unlock()
+	})
+	return c.UnixConn.Close()
+}
+
+// Addr is the remote address of a Conn, which is unique for each connection
+type Addr struct {
+	id   uint64
+	peer *Peer
+}
+
+// Network implements net.Addr
+func (a *Addr) Network() string {
+	return "unix"
+}
+
+// String implements net.Addr, it's like "unix:/path/to/socket#1,pid=1,uid=0,gid=0".
+func (a *Addr) String() string {
+	if a.peer.Cred == nil {
+		return fmt.Sprintf("unix:%s#%d", a.peer.Path, a.id)
+	}
+	return fmt.Sprintf("unix:%s#%d,pid=%d,uid=%d,gid=%d", a.peer.Path, a.id, a.peer.Cred.PID, a.peer.Cred.UID, a.peer.Cred.GID)
+}
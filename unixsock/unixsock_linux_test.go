@@ -0,0 +1,130 @@
+// +build linux
+
+package unixsock
+
+import (
+	"fmt"
+	"io/ioutil"
+	"net"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/laincloud/lainlet/conf"
+)
+
+// accept dial the listener and return the connection accepted by it
+func accept(t *testing.T, l *Listener) (net.Conn, net.Conn) {
+	accepted := make(chan net.Conn, 1)
+	go func() {
+		conn, err := l.Accept()
+		if err != nil {
+			t.Error(err)
+		}
+		accepted <- conn
+	}()
+	client, err := net.Dial("unix", l.path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	select {
+	case conn := <-accepted:
+		if conn == nil {
+			t.FailNow()
+		}
+		return client, conn
+	case <-time.After(5 * time.Second):
+		t.Fatal("no connection accepted")
+	}
+	return nil, nil
+}
+
+func TestListen(t *testing.T) {
+	dir, err := ioutil.TempDir("", "lainlet-unixsock")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	uid, gid := uint32(os.Getuid()), uint32(os.Getgid())
+	want := &Cred{PID: int32(os.Getpid()), UID: uid, GID: gid}
+
+	cases := []struct {
+		name    string
+		policy  Policy
+		trusted bool
+	}{
+		{"default", Policy{UIDs: conf.Default().Unix.TrustedUIDs, GIDs: conf.Default().Unix.TrustedGIDs}, uid == 0},
+		{"uid", Policy{UIDs: []uint32{uid + 1, uid}}, true},
+		{"gid", Policy{GIDs: []uint32{gid}}, true},
+		{"others", Policy{UIDs: []uint32{uid + 1}, GIDs: []uint32{gid + 1}}, false},
+	}
+	peers := NewPeers()
+	for i, c := range cases {
+		path := filepath.Join(dir, fmt.Sprintf("lainlet%d.sock", i))
+		l, err := Listen(path, 0600, c.policy, peers)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if fi, err := os.Stat(path); err != nil || fi.Mode()&os.ModePerm != 0600 {
+			t.Errorf("%s: the mode of the socket is %v, %v, want 0600", c.name, fi.Mode(), err)
+		}
+		client, conn := accept(t, l)
+
+		addr := conn.RemoteAddr().String()
+		if wantAddr := fmt.Sprintf("unix:%s#%d,pid=%d,uid=%d,gid=%d", path, conn.(*Conn).addr.id, want.PID, uid, gid); addr != wantAddr {
+			t.Errorf("%s: got the address %q, want %q", c.name, addr, wantAddr)
+		}
+		peer := peers.Lookup(addr)
+		if peer == nil {
+			t.Fatalf("%s: the peer of %s is not found", c.name, addr)
+		}
+		if peer.Path != path || peer.Cred == nil || *peer.Cred != *want || peer.Trusted != c.trusted {
+			t.Errorf("%s: got the peer %+v of %+v, want the credential %+v, trusted %v", c.name, peer, peer.Cred, want, c.trusted)
+		}
+
+		// the peer is forgotten after the connection was closed, and the addresses are not reused
+		conn.Close()
+		if peers.Lookup(addr) != nil {
+			t.Errorf("%s: the peer of %s is still found after the connection was closed", c.name, addr)
+		}
+		client.Close()
+		client, conn = accept(t, l)
+		if conn.RemoteAddr().String() == addr {
+			t.Errorf("%s: the address %s is reused", c.name, addr)
+		}
+		conn.Close()
+		client.Close()
+		l.Close()
+	}
+	if peers.Lookup("127.0.0.1:1234") != nil {
+		t.Error("found the peer of a tcp address")
+	}
+	if len(peers.peers) != 0 {
+		t.Errorf("got the peers %v after all the connections were closed", peers.peers)
+	}
+}
+
+func TestTrusted(t *testing.T) {
+	root, user := &Cred{UID: 0, GID: 0}, &Cred{UID: 1000, GID: 100}
+	cases := []struct {
+		policy Policy
+		cred   *Cred
+		want   bool
+	}{
+		{Policy{UIDs: []uint32{0}}, root, true},
+		{Policy{UIDs: []uint32{0}}, user, false},
+		{Policy{UIDs: []uint32{0}}, nil, false},
+		{Policy{GIDs: []uint32{100}}, user, true},
+		{Policy{UIDs: []uint32{1001}, GIDs: []uint32{101}}, user, false},
+		{Policy{}, root, false},
+	}
+	for _, c := range cases {
+		if got := c.policy.Trusted(c.cred); got != c.want {
+			t.Errorf("%+v.Trusted(%+v) = %v, want %v", c.policy, c.cred, got, c.want)
+		}
+	}
+	if p := conf.Default().Unix; len(p.TrustedUIDs) != 1 || p.TrustedUIDs[0] != 0 || len(p.TrustedGIDs) != 0 {
+		t.Errorf("the default trusted uids and gids are %v and %v, want only the uid 0", p.TrustedUIDs, p.TrustedGIDs)
+	}
+}
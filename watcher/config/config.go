@@ -3,29 +3,29 @@ package config
 import (
 	"path"
 
+	"github.com/laincloud/lainlet/conf"
 	"github.com/laincloud/lainlet/store"
 	"github.com/laincloud/lainlet/watcher"
 	"golang.org/x/net/context"
 )
 
-const (
-	// KEY represents the key path in store
-	KEY = "/lain/config"
-)
-
-// New create a new watcher which watch KEY in backend store
+// New create a new watcher which watch the config key in backend store
 func New(s store.Store, ctx context.Context) (watcher.Watcher, error) {
-	return watcher.New(s, ctx, KEY, convert, invertKey)
+	k := storeKey(conf.Current().Keys.Config)
+	return watcher.New(s, ctx, watcher.CONFIG, string(k), k.convert, k.invertKey)
 }
 
-func invertKey(key string) string {
-	return path.Join(KEY, key)
+// storeKey is the key path in store watched, it is read from the configuration when New was called
+type storeKey string
+
+func (k storeKey) invertKey(key string) string {
+	return path.Join(string(k), key)
 }
 
-func convert(pairs []*store.KVPair) (map[string]interface{}, error) {
+func (k storeKey) convert(pairs []*store.KVPair) (map[string]interface{}, error) {
 	ret := make(map[string]interface{})
 	for _, kv := range pairs {
-		ret[kv.Key[len(KEY)+1:]] = string(kv.Value)
+		ret[kv.Key[len(k)+1:]] = string(kv.Value)
 	}
 	return ret, nil
 }
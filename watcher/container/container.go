@@ -5,6 +5,8 @@ import (
 	"fmt"
 	"strings"
 
+	"github.com/laincloud/lainlet/conf"
+	"github.com/laincloud/lainlet/ipaddr"
 	"github.com/laincloud/lainlet/spec"
 	"github.com/laincloud/lainlet/store"
 	"github.com/laincloud/lainlet/watcher"
@@ -12,11 +14,6 @@ import (
 	"golang.org/x/net/context"
 )
 
-const (
-	// KEY represents the key path in store
-	KEY = "/lain/deployd/pod_groups"
-)
-
 // PodGroup is actually from the deployd engine, it actually engine.PodGroupWithSpec
 type PodGroup spec.PodGroupWithSpec
 
@@ -33,7 +30,7 @@ type Info struct {
 }
 
 type ContainerWatcher struct {
-	watcher.BaseWatcher
+	*watcher.BaseWatcher
 	invertsTable map[string]string
 }
 
@@ -42,11 +39,11 @@ func New(s store.Store, ctx context.Context) (watcher.Watcher, error) {
 	ret := &ContainerWatcher{
 		invertsTable: make(map[string]string),
 	}
-	base, err := watcher.New(s, ctx, KEY, ret.convert, ret.invertKey)
+	base, err := watcher.New(s, ctx, watcher.CONTAINER, conf.Current().Keys.PodGroups, ret.convert, ret.invertKey)
 	if err != nil {
 		return nil, err
 	}
-	ret.BaseWatcher = *base
+	ret.BaseWatcher = base
 	return ret, nil
 }
 
@@ -97,7 +94,7 @@ func (wch *ContainerWatcher) convert(pairs []*store.KVPair) (map[string]interfac
 		for _, pod := range pg.Pods {
 			for _, container := range pod.Containers {
 				k1 := fmt.Sprintf("%s/%s", container.NodeName, container.Id)
-				k2 := fmt.Sprintf("%s/%s", container.NodeIp, container.Id)
+				k2 := fmt.Sprintf("%s/%s", ipaddr.Normalize(container.NodeIp), container.Id)
 				ci := Info{
 					AppName:    pg.Spec.Namespace,
 					AppVersion: appVersion,
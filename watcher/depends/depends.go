@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"path"
 
+	"github.com/laincloud/lainlet/conf"
 	"github.com/laincloud/lainlet/spec"
 	"github.com/laincloud/lainlet/store"
 	"github.com/laincloud/lainlet/watcher"
@@ -12,24 +13,23 @@ import (
 	"golang.org/x/net/context"
 )
 
-const (
-	// KEY represents the key path in store
-	KEY = "/lain/deployd/depends/pods"
-)
-
 // Depends represents the data type returned by this watcher. in fact, it's a type to represents map[nodename]map[appname]SharedPodWithSpec
 type Depends map[string]map[string]spec.SharedPodWithSpec
 
 // New create a new watcher which used to watch depends data
 func New(s store.Store, ctx context.Context) (watcher.Watcher, error) {
-	return watcher.New(s, ctx, KEY, convert, invertKey)
+	k := storeKey(conf.Current().Keys.Depends)
+	return watcher.New(s, ctx, watcher.DEPENDS, string(k), k.convert, k.invertKey)
 }
 
-func invertKey(key string) string {
-	return path.Join(KEY, key)
+// storeKey is the key path in store watched, it is read from the configuration when New was called
+type storeKey string
+
+func (k storeKey) invertKey(key string) string {
+	return path.Join(string(k), key)
 }
 
-func convert(pairs []*store.KVPair) (map[string]interface{}, error) {
+func (k storeKey) convert(pairs []*store.KVPair) (map[string]interface{}, error) {
 	ret := make(map[string]interface{})
 	for _, kv := range pairs {
 		var dp Depends
@@ -38,7 +38,7 @@ func convert(pairs []*store.KVPair) (map[string]interface{}, error) {
 			log.Errorf("JSON unmarshal error: %s", err.Error())
 			return nil, fmt.Errorf("a KVPair unmarshal failed")
 		}
-		ret[kv.Key[len(KEY)+1:]] = dp
+		ret[kv.Key[len(k)+1:]] = dp
 	}
 	return ret, nil
 }
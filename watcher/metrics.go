@@ -0,0 +1,86 @@
+package watcher
+
+import (
+	"sync"
+	"time"
+
+	"github.com/laincloud/lainlet/metrics"
+)
+
+var (
+	registryMu sync.Mutex
+	registry   []*BaseWatcher // the running watchers created by New, used to collect metrics
+
+	storeEventsCounter = metrics.NewCounter(
+		"lainlet_watcher_store_events_total",
+		"Number of the events received from store by watcher.",
+		"watcher", "action",
+	)
+	retriesCounter = metrics.NewCounter(
+		"lainlet_watcher_retries_total",
+		"Number of the retries to refresh or watch the store after failure.",
+		"watcher", "reason",
+	)
+	droppedEventsCounter = metrics.NewCounter(
+		"lainlet_watcher_dropped_events_total",
+		"Number of the events dropped by Sender.Broadcast because the receiver is blocked.",
+		"watcher",
+	)
+	broadcastHistogram = metrics.NewHistogram(
+		"lainlet_watcher_broadcast_duration_seconds",
+		"Time spent by Sender.Broadcast to send a event to all the receivers.",
+		nil,
+		"watcher",
+	)
+)
+
+func init() {
+	metrics.NewGaugeFunc(
+		"lainlet_watcher_keys",
+		"Number of the keys cached by watcher.",
+		func(set func(float64, ...string)) {
+			for _, w := range registered() {
+				set(float64(w.Count()), w.name)
+			}
+		},
+		"watcher",
+	)
+	metrics.NewGaugeFunc(
+		"lainlet_watcher_receivers",
+		"Number of the receivers watching to watcher.",
+		func(set func(float64, ...string)) {
+			for _, w := range registered() {
+				set(float64(w.NumReceivers()), w.name)
+			}
+		},
+		"watcher",
+	)
+}
+
+func register(w *BaseWatcher) {
+	registryMu.Lock()
+	defer registryMu.Unlock()
+	registry = append(registry, w)
+}
+
+// unregister remove the watcher after it stopped, so it is not collected any more
+func unregister(w *BaseWatcher) {
+	registryMu.Lock()
+	defer registryMu.Unlock()
+	for i, item := range registry {
+		if item == w {
+			registry = append(registry[:i], registry[i+1:]...)
+			return
+		}
+	}
+}
+
+func registered() []*BaseWatcher {
+	registryMu.Lock()
+	defer registryMu.Unlock()
+	return append([]*BaseWatcher(nil), registry...)
+}
+
+func observeBroadcast(name string, start time.Time) {
+	broadcastHistogram.Observe(time.Since(start).Seconds(), name)
+}
@@ -0,0 +1,71 @@
+package watcher
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/laincloud/lainlet/metrics"
+	"github.com/laincloud/lainlet/store"
+	"golang.org/x/net/context"
+)
+
+// emptyStore have no keys, and its watches send nothing until ctx is done
+type emptyStore struct {
+	store.Store
+}
+
+func (emptyStore) GetTree(key string) ([]*store.KVPair, error) { return nil, store.ErrKeyNotFound }
+
+func (emptyStore) Watch(key string, ctx context.Context, recursive bool, index uint64) (<-chan *store.Event, error) {
+	ch := make(chan *store.Event)
+	go func() {
+		<-ctx.Done()
+		close(ch)
+	}()
+	return ch, nil
+}
+
+func isRegistered(w *BaseWatcher) bool {
+	for _, item := range registered() {
+		if item == w {
+			return true
+		}
+	}
+	return false
+}
+
+func exported(t *testing.T) string {
+	var buf bytes.Buffer
+	if err := metrics.WriteTo(&buf); err != nil {
+		t.Fatal(err)
+	}
+	return buf.String()
+}
+
+func TestUnregister(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	convert := func([]*store.KVPair) (map[string]interface{}, error) { return map[string]interface{}{}, nil }
+	w, err := New(emptyStore{}, ctx, "testwatcher", "/test", convert, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	line := `lainlet_watcher_keys{watcher="testwatcher"} 0`
+	if !isRegistered(w) || !strings.Contains(exported(t), line+"\n") {
+		t.Fatalf("the running watcher is not collected, want %q", line)
+	}
+
+	cancel()
+	deadline := time.Now().Add(5 * time.Second)
+	for isRegistered(w) {
+		if time.Now().After(deadline) {
+			t.Fatal("the watcher is not unregistered after its ctx was done")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	if strings.Contains(exported(t), line) {
+		t.Errorf("the stopped watcher is still collected, got %q", line)
+	}
+}
@@ -4,30 +4,30 @@ import (
 	"encoding/json"
 	"path"
 
+	"github.com/laincloud/lainlet/conf"
 	"github.com/laincloud/lainlet/store"
 	"github.com/laincloud/lainlet/watcher"
 	"github.com/mijia/sweb/log"
 	"golang.org/x/net/context"
 )
 
-const (
-	// KEY represents the key path in store
-	KEY = "/lain/nodes/nodes"
-)
-
 // NodeInfo represents the data type returned by this watcher. it's represents map[nodeip/nodename]string(or map)
 type NodeInfo map[string]interface{}
 
 // New create a new watcher which used to watch node info
 func New(s store.Store, ctx context.Context) (watcher.Watcher, error) {
-	return watcher.New(s, ctx, KEY, convert, invertKey)
+	k := storeKey(conf.Current().Keys.Nodes)
+	return watcher.New(s, ctx, watcher.NODES, string(k), k.convert, k.invertKey)
 }
 
-func invertKey(key string) string {
-	return path.Join(KEY, key)
+// storeKey is the key path in store watched, it is read from the configuration when New was called
+type storeKey string
+
+func (k storeKey) invertKey(key string) string {
+	return path.Join(string(k), key)
 }
 
-func convert(pairs []*store.KVPair) (map[string]interface{}, error) {
+func (k storeKey) convert(pairs []*store.KVPair) (map[string]interface{}, error) {
 	ret := make(map[string]interface{})
 	for _, kv := range pairs {
 		var tmp NodeInfo
@@ -35,7 +35,7 @@ func convert(pairs []*store.KVPair) (map[string]interface{}, error) {
 			log.Errorf("Fail to unmarshal nodes data %v", string(kv.Value))
 			continue
 		}
-		ret[kv.Key[len(KEY)+1:]] = tmp
+		ret[kv.Key[len(k)+1:]] = tmp
 	}
 	return ret, nil
 }
@@ -4,6 +4,7 @@ import (
 	"encoding/json"
 	"fmt"
 
+	"github.com/laincloud/lainlet/conf"
 	"github.com/laincloud/lainlet/spec"
 	"github.com/laincloud/lainlet/store"
 	"github.com/laincloud/lainlet/watcher"
@@ -12,24 +13,23 @@ import (
 	"path"
 )
 
-const (
-	// KEY represents the key path in store
-	KEY = "/lain/deployd/pod_groups"
-)
-
 // PodGroup represents the data type stored in backend for each pod. watcher will return data whose type is map[string]PodGroup.
 type PodGroup spec.PodGroupWithSpec
 
 // New create a new watcher which used to watch podgroup data
 func New(s store.Store, ctx context.Context) (watcher.Watcher, error) {
-	return watcher.New(s, ctx, KEY, convert, invertKey)
+	k := storeKey(conf.Current().Keys.PodGroups)
+	return watcher.New(s, ctx, watcher.PODGROUP, string(k), k.convert, k.invertKey)
 }
 
-func invertKey(key string) string {
-	return path.Join(KEY, key)
+// storeKey is the key path in store watched, it is read from the configuration when New was called
+type storeKey string
+
+func (k storeKey) invertKey(key string) string {
+	return path.Join(string(k), key)
 }
 
-func convert(pairs []*store.KVPair) (map[string]interface{}, error) {
+func (k storeKey) convert(pairs []*store.KVPair) (map[string]interface{}, error) {
 	ret := make(map[string]interface{})
 	for _, kv := range pairs {
 		var pg PodGroup
@@ -38,7 +38,7 @@ func convert(pairs []*store.KVPair) (map[string]interface{}, error) {
 			log.Errorf("JSON unmarshal error: %s", err.Error())
 			return nil, fmt.Errorf("a KVPair unmarshal failed")
 		}
-		ret[kv.Key[len(KEY)+1:]] = pg
+		ret[kv.Key[len(k)+1:]] = pg
 	}
 	return ret, nil
 }
@@ -6,6 +6,8 @@ import (
 	"github.com/laincloud/lainlet/store"
 	"strings"
 	"sync"
+	"sync/atomic"
+	"time"
 )
 
 // TODO: give a Release() function to release all the receiver,
@@ -14,6 +16,7 @@ import (
 type Sender struct {
 	sync.Mutex
 	*Cacher
+	name      string // the watcher name, used by metrics
 	receivers []*Receiver
 	// ctx context.Context // use a context to control release
 }
@@ -24,6 +27,24 @@ type Receiver struct {
 	ctx     context.Context
 	ch      chan *Event
 	counter uint64
+	stats   *ReceiverStats
+}
+
+// ReceiverStats is the statistics of a receiver
+type ReceiverStats struct {
+	dropped uint64
+}
+
+// Dropped return the number of the events dropped because the receiver was blocked
+func (rs *ReceiverStats) Dropped() uint64 {
+	return atomic.LoadUint64(&rs.dropped)
+}
+
+type receiverStatsKey struct{}
+
+// WithReceiverStats return a copy of ctx carrying stats, the receiver created by watching with the returned context records its statistics into stats.
+func WithReceiverStats(ctx context.Context, stats *ReceiverStats) context.Context {
+	return context.WithValue(ctx, receiverStatsKey{}, stats)
 }
 
 // NewSender create a sender and initialze it's cacher by the given data
@@ -40,6 +61,7 @@ func NewSender(data map[string]interface{}) *Sender {
 func (s *Sender) Broadcast(keys []string, action store.Action) {
 	s.Lock()
 	defer s.Unlock()
+	defer observeBroadcast(s.name, time.Now())
 	nilCounter := 0
 
 	if len(s.receivers) > 0 {
@@ -71,6 +93,10 @@ func (s *Sender) Broadcast(keys []string, action store.Action) {
 					receiver.counter++
 				default:
 					log.Warnf("Sender fail to send event to the %s receiver, may closed or blocked", receiver.key)
+					droppedEventsCounter.Inc(s.name)
+					if receiver.stats != nil {
+						atomic.AddUint64(&receiver.stats.dropped, 1)
+					}
 				}
 				break // in case of noticing multi times
 			}
@@ -95,10 +121,18 @@ func (s *Sender) Watch(key string, ctx context.Context) <-chan *Event {
 		ch:      make(chan *Event, 1),
 		counter: 2,
 	}
+	r.stats, _ = ctx.Value(receiverStatsKey{}).(*ReceiverStats)
 	s.receivers = append(s.receivers, r)
 	return (<-chan *Event)(r.ch)
 }
 
+// NumReceivers return the number of the receivers
+func (s *Sender) NumReceivers() int {
+	s.Lock()
+	defer s.Unlock()
+	return len(s.receivers)
+}
+
 func (s *Sender) clearNilReceivers() {
 	s.Lock()
 	defer s.Unlock()
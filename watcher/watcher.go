@@ -3,8 +3,11 @@ package watcher
 import (
 	"fmt"
 	"strings"
+	"sync"
+	"sync/atomic"
 	"time"
 
+	"github.com/laincloud/lainlet/conf"
 	"github.com/laincloud/lainlet/store"
 	"github.com/mijia/sweb/log"
 	"golang.org/x/net/context"
@@ -44,12 +47,18 @@ type Watcher interface {
 // BaseWatcher having a sender, store, and convert function.
 // It read and watch data from store, and use convert() to convert, then use sender to cache data and broadcast the event.
 type BaseWatcher struct {
+	name      string
 	key       string
 	convert   ConvertFunc
-	status    Status
 	ckey2skey func(string) string
-	Store     store.Store
-	Ctx       context.Context
+	synced    int32         // 1 after the data was read from store at the first time
+	done      chan struct{} // closed after the watcher stopped watching the store
+
+	statusLock sync.Mutex
+	status     Status // the last event from store and when
+
+	Store store.Store
+	Ctx   context.Context
 	*Sender
 }
 
@@ -59,21 +68,26 @@ type Status struct {
 	UpdateTime   time.Time
 	LastEvent    store.Event
 	TotalKeys    int
+	Synced       bool // whether the data was read from store, the cache is empty before it
 }
 
 // ConvertFunc convert the data from store into a general type
 type ConvertFunc func([]*store.KVPair) (map[string]interface{}, error)
 
-// New create a new watcher
-func New(s store.Store, ctx context.Context, key string, convert ConvertFunc, ckey2skey func(string) string) (*BaseWatcher, error) {
+// New create a new watcher, name is used to identify the watcher in metrics
+func New(s store.Store, ctx context.Context, name, key string, convert ConvertFunc, ckey2skey func(string) string) (*BaseWatcher, error) {
 	watcher := &BaseWatcher{
+		name:      name,
 		key:       key,
 		convert:   convert,
 		ckey2skey: ckey2skey,
 		Store:     s,
 		Ctx:       ctx,
 		Sender:    NewSender(nil),
+		done:      make(chan struct{}),
 	}
+	watcher.Sender.name = name
+	register(watcher)
 	go watcher.watchStore(key)
 	return watcher, nil
 }
@@ -92,11 +106,29 @@ func (w *BaseWatcher) refresh() error {
 		return err
 	}
 	w.Reset(data)
+	atomic.StoreInt32(&w.synced, 1)
 	return nil
 }
 
+// Done return a channel closed after the watcher stopped watching the store, which is after ctx was canceled
+func (w *BaseWatcher) Done() <-chan struct{} {
+	return w.done
+}
+
+// wait the retry interval, it return false if ctx was canceled
+func (w *BaseWatcher) wait() bool {
+	select {
+	case <-w.Ctx.Done():
+		return false
+	case <-time.After(conf.Current().RetryInterval.D()):
+		return true
+	}
+}
+
 // a general watch function
 func (w *BaseWatcher) watchStore(key string) {
+	defer close(w.done)
+	defer unregister(w)
 	keys := make([]string, 0, 10)
 	var (
 		lastIndex             uint64
@@ -106,7 +138,10 @@ func (w *BaseWatcher) watchStore(key string) {
 	START:
 		if err := w.refresh(); err != nil {
 			log.Errorf("Fail to refresh data for %s, %s", w.key, err.Error())
-			time.Sleep(time.Second * 3)
+			retriesCounter.Inc(w.name, "refresh")
+			if !w.wait() {
+				return
+			}
 			continue
 		}
 		if broadcastAfterRefresh {
@@ -117,8 +152,11 @@ func (w *BaseWatcher) watchStore(key string) {
 		log.Infof("A watcher starting to watch %s, from index %d", key, lastIndex)
 		eventCh, err := w.Store.Watch(key, w.Ctx, true, lastIndex)
 		if err != nil {
-			log.Errorf("Fail to watch etcd, %s, retry watching after 3 seconds", err.Error())
-			time.Sleep(time.Second * 3)
+			log.Errorf("Fail to watch etcd, %s, retry watching after %s", err.Error(), conf.Current().RetryInterval)
+			retriesCounter.Inc(w.name, "watch")
+			if !w.wait() {
+				return
+			}
 			continue
 		}
 		for {
@@ -128,15 +166,21 @@ func (w *BaseWatcher) watchStore(key string) {
 				return
 			case event, ok := <-eventCh:
 				if !ok {
-					time.Sleep(time.Second * 3)
+					retriesCounter.Inc(w.name, "closed")
+					if !w.wait() {
+						return
+					}
 					broadcastAfterRefresh = true
 					goto START
 				}
 				log.Debugf("BaseWatcher get a store event, %s %s", event.Action, event.Key)
+				storeEventsCounter.Inc(w.name, event.Action.String())
 
 				// update watcher status
+				w.statusLock.Lock()
 				w.status.LastEvent = *event
 				w.status.UpdateTime = time.Now()
+				w.statusLock.Unlock()
 
 				switch event.Action {
 				case store.SET, store.UPDATE:
@@ -168,8 +212,8 @@ func (w *BaseWatcher) watchStore(key string) {
 				keys = keys[:0]
 			}
 		}
-		log.Errorf("Etcd watche channel was closed by mistake, retry watching after 3 seconds")
-		time.Sleep(time.Second * 3)
+		log.Errorf("Etcd watche channel was closed by mistake, retry watching after %s", conf.Current().RetryInterval)
+		time.Sleep(conf.Current().RetryInterval.D())
 	}
 }
 
@@ -202,7 +246,11 @@ func (w *BaseWatcher) Get(prefix string) (map[string]interface{}, error) {
 
 // Status return the watcher stats
 func (w *BaseWatcher) Status() Status {
-	w.status.NumReceivers = len(w.receivers)
-	w.status.TotalKeys = w.Sender.Count()
-	return w.status
+	w.statusLock.Lock()
+	status := w.status
+	w.statusLock.Unlock()
+	status.NumReceivers = w.NumReceivers()
+	status.TotalKeys = w.Sender.Count()
+	status.Synced = atomic.LoadInt32(&w.synced) == 1
+	return status
 }
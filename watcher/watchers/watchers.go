@@ -0,0 +1,39 @@
+// Package watchers create all the watchers served by lainlet, it is shared by lainlet and the lainlettest package.
+package watchers
+
+import (
+	"github.com/laincloud/lainlet/store"
+	"github.com/laincloud/lainlet/watcher"
+	"github.com/laincloud/lainlet/watcher/config"
+	"github.com/laincloud/lainlet/watcher/container"
+	"github.com/laincloud/lainlet/watcher/depends"
+	"github.com/laincloud/lainlet/watcher/nodes"
+	"github.com/laincloud/lainlet/watcher/podgroup"
+	"golang.org/x/net/context"
+)
+
+// the watchers by their names, the apis find their watchers by the names
+var news = []struct {
+	name string
+	new  func(store.Store, context.Context) (watcher.Watcher, error)
+}{
+	{"configwatcher", config.New},
+	{"containerwatcher", container.New},
+	{"podgroupwatcher", podgroup.New},
+	{"dependswatcher", depends.New},
+	{"nodeswatcher", nodes.New},
+}
+
+// New create all the watchers, each watcher reads the store returned by stores, which may return the same store for all of them.
+// On error the watchers created so far are returned too, they stop watching after ctx was canceled.
+func New(stores func() store.Store, ctx context.Context) (map[string]watcher.Watcher, error) {
+	ret := make(map[string]watcher.Watcher, len(news))
+	for _, n := range news {
+		wch, err := n.new(stores(), ctx)
+		if err != nil {
+			return ret, err
+		}
+		ret[n.name] = wch
+	}
+	return ret, nil
+}